@@ -9,11 +9,14 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	DB       DBConfig       `yaml:"db"`
-	Redis    RedisConfig    `yaml:"redis"`
-	BaiduPan BaiduPanConfig `yaml:"baidu_pan"`
-	Log      LogConfig      `yaml:"log"`
+	Server     ServerConfig     `yaml:"server"`
+	DB         DBConfig         `yaml:"db"`
+	Redis      RedisConfig      `yaml:"redis"`
+	BaiduPan   BaiduPanConfig   `yaml:"baidu_pan"`
+	Log        LogConfig        `yaml:"log"`
+	CoreServer CoreServerConfig `yaml:"core_server"`
+	Vault      VaultConfig      `yaml:"vault"`
+	Training   TrainingConfig   `yaml:"training"`
 }
 type LogConfig struct {
 	Path string `yaml:"path"`
@@ -29,6 +32,8 @@ type DBConfig struct {
 	User     string `yaml:"user"`
 	Password string `yaml:"password"`
 	DBName   string `yaml:"dbname"`
+	// PasswordCredentialID 非空时从凭据库读取密码，忽略明文 password。
+	PasswordCredentialID string `yaml:"password_credential_id"`
 }
 
 type RedisConfig struct {
@@ -36,12 +41,77 @@ type RedisConfig struct {
 	Port     int    `yaml:"port"`
 	Password string `yaml:"password"`
 	DB       int    `yaml:"db"`
+	// PasswordCredentialID 非空时从凭据库读取密码，忽略明文 password。
+	PasswordCredentialID string `yaml:"password_credential_id"`
 }
 
 type BaiduPanConfig struct {
 	AccessToken string `yaml:"access_token"`
 	IsSVIP      bool   `yaml:"is_svip"`
 	LogPath     string `yaml:"log_path"`
+	// AccessTokenCredentialID 非空时从凭据库读取 access_token，忽略明文配置。
+	AccessTokenCredentialID string `yaml:"access_token_credential_id"`
+
+	// 以下为 OAuth 刷新配置：配置 refresh_token + app_key/app_secret 后自动续期 access_token。
+	RefreshToken             string `yaml:"refresh_token"`
+	RefreshTokenCredentialID string `yaml:"refresh_token_credential_id"`
+	AppKey                   string `yaml:"app_key"`
+	AppSecret                string `yaml:"app_secret"`
+	AppSecretCredentialID    string `yaml:"app_secret_credential_id"`
+	// TokenExpiresAt 初始 access_token 的过期时间（RFC3339），为空视为未知。
+	TokenExpiresAt string `yaml:"token_expires_at"`
+	// TokenURL OAuth token 地址，默认 https://openapi.baidu.com/oauth/2.0/token。
+	TokenURL string `yaml:"token_url"`
+	// TokenStatePath 刷新后 token 状态的持久化文件。
+	TokenStatePath string `yaml:"token_state_path"`
+	// RefreshBeforeSeconds 距过期多久开始提前刷新，默认 3600 秒。
+	RefreshBeforeSeconds int `yaml:"refresh_before_seconds"`
+	// MaxConcurrentTransfers 同时进行的网盘请求（上传/下载/列目录）上限，默认 4。
+	MaxConcurrentTransfers int `yaml:"max_concurrent_transfers"`
+	// Mirror 后端 weights/datasets 目录到网盘的定时增量镜像。
+	Mirror BaiduMirrorConfig `yaml:"mirror"`
+}
+
+// BaiduMirrorConfig 单向镜像配置：按 size + md5 比对本地与网盘，只上传差异文件。
+type BaiduMirrorConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// IntervalSeconds 两次镜像之间的间隔，默认 3600 秒。
+	IntervalSeconds int `yaml:"interval_seconds"`
+	// MinFileAgeSeconds 修改时间距今不足该值的文件视为仍在写入，本轮跳过，默认 60 秒。
+	MinFileAgeSeconds int `yaml:"min_file_age_seconds"`
+}
+
+// CoreServerConfig 核心服务器心跳相关配置。
+type CoreServerConfig struct {
+	// HeartbeatTTLSeconds 心跳 key 的过期时间，超过该时间未上报即视为离线。
+	HeartbeatTTLSeconds int `yaml:"heartbeat_ttl_seconds"`
+	// RequireHeartbeat 为 true 时，从未上报过心跳的服务器也不能作为上传目标。
+	RequireHeartbeat bool `yaml:"require_heartbeat"`
+}
+
+// TrainingConfig 在核心服务器上启动训练的配置。
+type TrainingConfig struct {
+	// CommandTemplate text/template 格式的训练命令，变量值均已做 shell 转义；为空时使用内置的 Ultralytics 命令。
+	CommandTemplate string `yaml:"command_template"`
+	// RunsRoot 核心服务器上的训练输出根目录，每次训练在其下创建 train_<训练结果ID> 目录。
+	RunsRoot string `yaml:"runs_root"`
+	// Queue 训练任务排队与按 GPU 资源自动派发。
+	Queue TrainingQueueConfig `yaml:"queue"`
+}
+
+// TrainingQueueConfig 训练队列调度配置：按心跳上报的 GPU 数量与显存把排队任务派发到有空闲的核心服务器。
+type TrainingQueueConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// IntervalSeconds 两次调度之间的间隔，默认 30 秒。
+	IntervalSeconds int `yaml:"interval_seconds"`
+	// MaxAttempts 单个任务默认的最大派发次数（含主机故障后的重新排队），默认 3。
+	MaxAttempts int `yaml:"max_attempts"`
+}
+
+// VaultConfig 加密凭据库配置；主密钥优先读取环境变量 LUCKY_VAULT_MASTER_KEY。
+type VaultConfig struct {
+	Path          string `yaml:"path"`
+	MasterKeyFile string `yaml:"master_key_file"`
 }
 
 var AppConfig *Config
@@ -58,6 +128,8 @@ func InitConfig() error {
 		return fmt.Errorf("unmarshal config failed: %v", err)
 	}
 
+	expandSecretEnv(AppConfig)
+
 	if strings.TrimSpace(AppConfig.BaiduPan.LogPath) == "" {
 		AppConfig.BaiduPan.LogPath = "logs/baiduPanSDK.log"
 	}
@@ -67,3 +139,18 @@ func InitConfig() error {
 
 	return nil
 }
+
+// expandSecretEnv 展开敏感配置项中的 ${ENV} 引用；config.yaml 只保留引用，真实值来自环境变量或凭据库。
+// 未设置的环境变量展开为空字符串。
+func expandSecretEnv(cfg *Config) {
+	for _, field := range []*string{
+		&cfg.DB.Password,
+		&cfg.Redis.Password,
+		&cfg.BaiduPan.AccessToken,
+		&cfg.BaiduPan.RefreshToken,
+		&cfg.BaiduPan.AppKey,
+		&cfg.BaiduPan.AppSecret,
+	} {
+		*field = os.ExpandEnv(*field)
+	}
+}
@@ -25,11 +25,16 @@ func InitDB() error {
 		return fmt.Errorf("unsupported db driver: %s", cfg.Driver)
 	}
 
+	password, err := ResolveSecret(cfg.PasswordCredentialID, cfg.Password)
+	if err != nil {
+		return fmt.Errorf("resolve db password failed: %w", err)
+	}
+
 	loc := url.QueryEscape("Asia/Shanghai")
 	dsn := fmt.Sprintf(
 		"%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=%s&timeout=5s&readTimeout=10s&writeTimeout=10s",
 		cfg.User,
-		cfg.Password,
+		password,
 		cfg.Host,
 		cfg.Port,
 		cfg.DBName,
@@ -72,6 +77,15 @@ func ensureTables(db *gorm.DB) error {
 		&entity2.Model{},
 		&entity2.Dataset{},
 		&entity2.ModelTrainingResult{},
+		&entity2.ArtifactReplica{},
+		&entity2.ModelStage{},
+		&entity2.ModelStageTransition{},
+		&entity2.Tag{},
+		&entity2.ArtifactTag{},
+		&entity2.TrainingMetric{},
+		&entity2.TrainingHyperparameters{},
+		&entity2.TrainingResultModel{},
+		&entity2.TrainingJob{},
 	}
 
 	for _, m := range models {
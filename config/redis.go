@@ -28,9 +28,14 @@ func InitRedis() error {
 		port = 6379
 	}
 
+	password, err := ResolveSecret(cfg.PasswordCredentialID, cfg.Password)
+	if err != nil {
+		return fmt.Errorf("resolve redis password failed: %w", err)
+	}
+
 	client := redis.NewClient(&redis.Options{
 		Addr:         fmt.Sprintf("%s:%d", host, port),
-		Password:     cfg.Password,
+		Password:     password,
 		DB:           cfg.DB,
 		DialTimeout:  5 * time.Second,
 		ReadTimeout:  5 * time.Second,
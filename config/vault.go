@@ -0,0 +1,368 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// VaultMasterKeyEnv 主密钥环境变量（base64 或 hex 编码的 32 字节），优先于 master_key_file。
+	VaultMasterKeyEnv = "LUCKY_VAULT_MASTER_KEY"
+
+	defaultVaultPath = "data/credentials.vault.json"
+
+	CredentialKindSSHKey     = "ssh_key"
+	CredentialKindPassword   = "password"
+	CredentialKindBaiduToken = "baidu_token"
+)
+
+var (
+	ErrVaultNotInitialized   = errors.New("credential vault is not initialized")
+	ErrVaultMasterKeyInvalid = errors.New("vault master key must be 32 bytes (base64 or hex encoded)")
+	ErrCredentialNotFound    = errors.New("credential not found")
+	ErrCredentialInvalid     = errors.New("credential is invalid")
+)
+
+// Vault 全局凭据库；未配置主密钥时为 nil。
+var Vault *CredentialVault
+
+// CredentialMeta 凭据元信息，不包含任何密文或明文。
+type CredentialMeta struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Version     int       `json:"version"`
+	Fingerprint string    `json:"fingerprint"`
+	CreatedAt   time.Time `json:"created_at"`
+	RotatedAt   time.Time `json:"rotated_at"`
+}
+
+// vaultEntry 落盘结构，secret 以 AES-256-GCM 加密，AAD 绑定 id+kind。
+type vaultEntry struct {
+	CredentialMeta
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// CredentialVault 基于本地文件的加密凭据库，所有写操作整体原子落盘。
+type CredentialVault struct {
+	path    string
+	aead    cipher.AEAD
+	mu      sync.RWMutex
+	entries map[string]*vaultEntry
+}
+
+// InitVault 按环境变量或 vault.master_key_file 加载主密钥并打开凭据库。
+// 两者都未配置时跳过（Vault 保持 nil），引用凭据 ID 的配置项会在解析时报错。
+func InitVault() error {
+	if AppConfig == nil {
+		return errors.New("app config is not initialized")
+	}
+
+	key, err := loadVaultMasterKey(AppConfig.Vault.MasterKeyFile)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		if AppLogger != nil {
+			AppLogger.Warn("credential vault disabled: master key not configured", "env", VaultMasterKeyEnv)
+		}
+		return nil
+	}
+
+	path := strings.TrimSpace(AppConfig.Vault.Path)
+	if path == "" {
+		path = defaultVaultPath
+	}
+	vault, err := OpenCredentialVault(path, key)
+	if err != nil {
+		return err
+	}
+	Vault = vault
+	return nil
+}
+
+// OpenCredentialVault 打开（或新建）指定路径的凭据库；主密钥不匹配时返回错误。
+func OpenCredentialVault(path string, masterKey []byte) (*CredentialVault, error) {
+	if len(masterKey) != 32 {
+		return nil, ErrVaultMasterKeyInvalid
+	}
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("init vault cipher failed: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("init vault cipher failed: %w", err)
+	}
+
+	vault := &CredentialVault{
+		path:    filepath.Clean(path),
+		aead:    aead,
+		entries: make(map[string]*vaultEntry),
+	}
+
+	data, err := os.ReadFile(vault.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return vault, nil
+		}
+		return nil, fmt.Errorf("read vault file failed: %w", err)
+	}
+	var entries []*vaultEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parse vault file failed: %w", err)
+	}
+	for _, entry := range entries {
+		if _, err := vault.open(entry); err != nil {
+			return nil, fmt.Errorf("decrypt credential %s failed (wrong master key?): %w", entry.ID, err)
+		}
+		vault.entries[entry.ID] = entry
+	}
+	return vault, nil
+}
+
+// Create 新建凭据并返回元信息。
+func (v *CredentialVault) Create(kind, name, description string, secret []byte) (CredentialMeta, error) {
+	kind = strings.TrimSpace(kind)
+	name = strings.TrimSpace(name)
+	if !isValidCredentialKind(kind) {
+		return CredentialMeta{}, fmt.Errorf("%w: unsupported kind %q", ErrCredentialInvalid, kind)
+	}
+	if name == "" {
+		return CredentialMeta{}, fmt.Errorf("%w: name is required", ErrCredentialInvalid)
+	}
+	if len(secret) == 0 {
+		return CredentialMeta{}, fmt.Errorf("%w: secret is required", ErrCredentialInvalid)
+	}
+
+	id, err := newCredentialID()
+	if err != nil {
+		return CredentialMeta{}, err
+	}
+	now := time.Now()
+	entry := &vaultEntry{CredentialMeta: CredentialMeta{
+		ID:          id,
+		Kind:        kind,
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		CreatedAt:   now,
+	}}
+	if err := v.seal(entry, secret, now); err != nil {
+		return CredentialMeta{}, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.entries[id] = entry
+	if err := v.persistLocked(); err != nil {
+		delete(v.entries, id)
+		return CredentialMeta{}, err
+	}
+	return entry.CredentialMeta, nil
+}
+
+// Rotate 替换凭据内容，版本号加一；ID 不变，引用方无需修改。
+func (v *CredentialVault) Rotate(id string, secret []byte) (CredentialMeta, error) {
+	if len(secret) == 0 {
+		return CredentialMeta{}, fmt.Errorf("%w: secret is required", ErrCredentialInvalid)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	entry, ok := v.entries[strings.TrimSpace(id)]
+	if !ok {
+		return CredentialMeta{}, fmt.Errorf("%w: %s", ErrCredentialNotFound, id)
+	}
+	previous := *entry
+	if err := v.seal(entry, secret, time.Now()); err != nil {
+		return CredentialMeta{}, err
+	}
+	if err := v.persistLocked(); err != nil {
+		*entry = previous
+		return CredentialMeta{}, err
+	}
+	return entry.CredentialMeta, nil
+}
+
+// Delete 删除凭据。
+func (v *CredentialVault) Delete(id string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	id = strings.TrimSpace(id)
+	entry, ok := v.entries[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrCredentialNotFound, id)
+	}
+	delete(v.entries, id)
+	if err := v.persistLocked(); err != nil {
+		v.entries[id] = entry
+		return err
+	}
+	return nil
+}
+
+// Get 返回凭据元信息。
+func (v *CredentialVault) Get(id string) (CredentialMeta, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	entry, ok := v.entries[strings.TrimSpace(id)]
+	if !ok {
+		return CredentialMeta{}, fmt.Errorf("%w: %s", ErrCredentialNotFound, id)
+	}
+	return entry.CredentialMeta, nil
+}
+
+// List 按创建时间返回全部凭据元信息。
+func (v *CredentialVault) List() []CredentialMeta {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	result := make([]CredentialMeta, 0, len(v.entries))
+	for _, entry := range v.entries {
+		result = append(result, entry.CredentialMeta)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+// Secret 解密并返回凭据内容，仅供服务内部使用，禁止透出到接口响应。
+func (v *CredentialVault) Secret(id string) ([]byte, CredentialMeta, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	entry, ok := v.entries[strings.TrimSpace(id)]
+	if !ok {
+		return nil, CredentialMeta{}, fmt.Errorf("%w: %s", ErrCredentialNotFound, id)
+	}
+	secret, err := v.open(entry)
+	if err != nil {
+		return nil, CredentialMeta{}, fmt.Errorf("decrypt credential %s failed: %w", id, err)
+	}
+	return secret, entry.CredentialMeta, nil
+}
+
+// ResolveSecret 配置项解析：credentialID 非空时从凭据库读取，否则返回明文配置值。
+func ResolveSecret(credentialID, plain string) (string, error) {
+	credentialID = strings.TrimSpace(credentialID)
+	if credentialID == "" {
+		return plain, nil
+	}
+	if Vault == nil {
+		return "", fmt.Errorf("%w: cannot resolve credential %s", ErrVaultNotInitialized, credentialID)
+	}
+	secret, _, err := Vault.Secret(credentialID)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(secret)), nil
+}
+
+func (v *CredentialVault) seal(entry *vaultEntry, secret []byte, now time.Time) error {
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generate vault nonce failed: %w", err)
+	}
+	entry.Nonce = nonce
+	entry.Ciphertext = v.aead.Seal(nil, nonce, secret, credentialAAD(entry))
+	entry.Version++
+	entry.RotatedAt = now
+	sum := sha256.Sum256(secret)
+	entry.Fingerprint = "sha256:" + hex.EncodeToString(sum[:6])
+	return nil
+}
+
+func (v *CredentialVault) open(entry *vaultEntry) ([]byte, error) {
+	return v.aead.Open(nil, entry.Nonce, entry.Ciphertext, credentialAAD(entry))
+}
+
+// persistLocked 写临时文件后 rename，保证凭据库文件不会半写。
+func (v *CredentialVault) persistLocked() error {
+	entries := make([]*vaultEntry, 0, len(v.entries))
+	for _, entry := range v.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode vault failed: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(v.path), 0o700); err != nil {
+		return fmt.Errorf("create vault dir failed: %w", err)
+	}
+	tmp := v.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write vault file failed: %w", err)
+	}
+	if err := os.Rename(tmp, v.path); err != nil {
+		return fmt.Errorf("replace vault file failed: %w", err)
+	}
+	return nil
+}
+
+func credentialAAD(entry *vaultEntry) []byte {
+	return []byte(entry.ID + "|" + entry.Kind)
+}
+
+func isValidCredentialKind(kind string) bool {
+	switch kind {
+	case CredentialKindSSHKey, CredentialKindPassword, CredentialKindBaiduToken:
+		return true
+	default:
+		return false
+	}
+}
+
+func newCredentialID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate credential id failed: %w", err)
+	}
+	return "cred_" + hex.EncodeToString(buf), nil
+}
+
+// loadVaultMasterKey 读取主密钥：环境变量优先，其次 master_key_file；都未配置时返回 nil。
+func loadVaultMasterKey(keyFile string) ([]byte, error) {
+	raw := strings.TrimSpace(os.Getenv(VaultMasterKeyEnv))
+	if raw == "" && strings.TrimSpace(keyFile) != "" {
+		data, err := os.ReadFile(strings.TrimSpace(keyFile))
+		if err != nil {
+			return nil, fmt.Errorf("read vault master key file failed: %w", err)
+		}
+		raw = strings.TrimSpace(string(data))
+	}
+	if raw == "" {
+		return nil, nil
+	}
+	return DecodeVaultMasterKey(raw)
+}
+
+// DecodeVaultMasterKey 解析 base64 或 hex 编码的 32 字节主密钥。
+func DecodeVaultMasterKey(raw string) ([]byte, error) {
+	raw = strings.TrimSpace(raw)
+	if key, err := hex.DecodeString(raw); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(raw); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	return nil, ErrVaultMasterKeyInvalid
+}
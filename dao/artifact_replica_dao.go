@@ -0,0 +1,129 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"lucky_project/config"
+	entity2 "lucky_project/entity"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ArtifactReplicaDAO struct {
+	DB *gorm.DB
+}
+
+// NewArtifactReplicaDAO 创建 ArtifactReplicaDAO，并注入全局数据库连接。
+func NewArtifactReplicaDAO() *ArtifactReplicaDAO {
+	return &ArtifactReplicaDAO{
+		DB: config.DB,
+	}
+}
+
+// Upsert 按 (artifact_type, file_name, storage_target) 写入副本记录；已存在则覆盖校验结果。
+func (d *ArtifactReplicaDAO) Upsert(ctx context.Context, replica *entity2.ArtifactReplica) error {
+	logger := daoLogger().With("dao", "ArtifactReplicaDAO", "method", "Upsert")
+	if replica == nil {
+		logger.Warn("upsert replica skipped: replica is nil")
+		return ErrNilEntity
+	}
+	replica.ArtifactType = strings.TrimSpace(replica.ArtifactType)
+	replica.FileName = strings.TrimSpace(replica.FileName)
+	replica.StorageTarget = strings.TrimSpace(replica.StorageTarget)
+	if replica.ArtifactType == "" || replica.FileName == "" || replica.StorageTarget == "" {
+		logger.Warn("upsert replica skipped: key is empty")
+		return ErrNilEntity
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("upsert replica failed: with context", "file_name", replica.FileName, "error", err)
+		return fmt.Errorf("upsert artifact replica failed: %w", err)
+	}
+
+	if err := dbConn.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "artifact_type"}, {Name: "file_name"}, {Name: "storage_target"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"remote_path", "local_size", "local_md5", "remote_size", "remote_md5",
+			"status", "status_detail", "rapid_upload", "verified_at", "updated_at",
+		}),
+	}).Create(replica).Error; err != nil {
+		logger.Error("upsert replica failed: create/upsert", "file_name", replica.FileName, "error", err)
+		return fmt.Errorf("upsert artifact replica failed: %w", err)
+	}
+
+	logger.Info(
+		"upsert replica success",
+		"artifact_type", replica.ArtifactType,
+		"file_name", replica.FileName,
+		"storage_target", replica.StorageTarget,
+		"status", replica.Status,
+	)
+	return nil
+}
+
+// FindByFileName 查询某个制品文件在所有存储上的副本记录。
+func (d *ArtifactReplicaDAO) FindByFileName(ctx context.Context, artifactType, fileName string) ([]entity2.ArtifactReplica, error) {
+	logger := daoLogger().With("dao", "ArtifactReplicaDAO", "method", "FindByFileName")
+	name := strings.TrimSpace(fileName)
+	if name == "" {
+		return []entity2.ArtifactReplica{}, nil
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find replicas failed: with context", "file_name", name, "error", err)
+		return nil, fmt.Errorf("find artifact replicas failed: %w", err)
+	}
+
+	var replicas []entity2.ArtifactReplica
+	if err := dbConn.
+		Where("artifact_type = ? AND file_name = ?", strings.TrimSpace(artifactType), name).
+		Order("storage_target ASC").
+		Find(&replicas).Error; err != nil {
+		logger.Error("find replicas failed: db query", "file_name", name, "error", err)
+		return nil, fmt.Errorf("find artifact replicas failed: %w", err)
+	}
+	return replicas, nil
+}
+
+// FindByFileNames 批量查询某个存储上多个制品文件的副本记录。
+func (d *ArtifactReplicaDAO) FindByFileNames(ctx context.Context, artifactType, storageTarget string, fileNames []string) ([]entity2.ArtifactReplica, error) {
+	logger := daoLogger().With("dao", "ArtifactReplicaDAO", "method", "FindByFileNames")
+	names := make([]string, 0, len(fileNames))
+	for _, name := range fileNames {
+		if trimmed := strings.TrimSpace(name); trimmed != "" {
+			names = append(names, trimmed)
+		}
+	}
+	if len(names) == 0 {
+		return []entity2.ArtifactReplica{}, nil
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find replicas failed: with context", "count", len(names), "error", err)
+		return nil, fmt.Errorf("find artifact replicas failed: %w", err)
+	}
+
+	var replicas []entity2.ArtifactReplica
+	if err := dbConn.
+		Where("artifact_type = ? AND storage_target = ? AND file_name IN ?", strings.TrimSpace(artifactType), strings.TrimSpace(storageTarget), names).
+		Find(&replicas).Error; err != nil {
+		logger.Error("find replicas failed: db query", "count", len(names), "error", err)
+		return nil, fmt.Errorf("find artifact replicas failed: %w", err)
+	}
+	return replicas, nil
+}
+
+// deleteReplicasInTx 在删除制品记录的事务内删除该文件的副本记录。
+// 副本按文件名关联，shared 为仍引用同一文件的其他记录数，大于 0 时保留副本记录。
+func deleteReplicasInTx(tx *gorm.DB, artifactType, fileName string, shared int64) error {
+	name := strings.TrimSpace(fileName)
+	if name == "" || shared > 0 {
+		return nil
+	}
+	return tx.Where("artifact_type = ? AND file_name = ?", artifactType, name).Delete(&entity2.ArtifactReplica{}).Error
+}
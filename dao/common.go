@@ -18,6 +18,7 @@ var (
 	ErrNilEntity        = errors.New("实体对象 为 nil")
 	ErrAlreadyExists    = errors.New("记录已经存储在")
 	ErrInvalidAction    = errors.New("invalid action, must be one of: set/add/remove")
+	ErrHasDependents    = errors.New("记录仍被其他记录引用")
 )
 
 const (
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"lucky_project/config"
 	entity2 "lucky_project/entity"
@@ -9,6 +10,7 @@ import (
 	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DatasetDAO struct {
@@ -287,6 +289,71 @@ func (d *DatasetDAO) UpdateSizeByFileName(ctx context.Context, fileName string,
 	return result.RowsAffected, nil
 }
 
+// DeleteByIDWithDependents 在事务内删除数据集及其标签，以及不再被其他数据集引用的文件副本记录；有关联训练结果时，cascade 为 false 返回 ErrHasDependents，
+// 为 true 则一并删除，返回删除的训练结果条数。
+func (d *DatasetDAO) DeleteByIDWithDependents(ctx context.Context, id uint, cascade bool) (int64, error) {
+	logger := daoLogger().With("dao", "DatasetDAO", "method", "DeleteByIDWithDependents")
+	if id == 0 {
+		logger.Warn("delete dataset skipped: invalid id", "id", id)
+		return 0, ErrInvalidID
+	}
+	logger.Info("delete dataset with dependents begin", "id", id, "cascade", cascade)
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("delete dataset failed: with context", "id", id, "error", err)
+		return 0, fmt.Errorf("delete dataset by id failed: %w", err)
+	}
+
+	var deletedResults int64
+	err = dbConn.Transaction(func(tx *gorm.DB) error {
+		var dataset entity2.Dataset
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&dataset, id).Error; err != nil {
+			return err
+		}
+		deleted, err := deleteTrainingResultsInTx(tx, "dataset_id", id, cascade)
+		if err != nil {
+			return err
+		}
+		deletedResults = deleted
+		if err := tx.Where("artifact_type = ? AND artifact_id = ?", entity2.ArtifactTypeDataset, id).Delete(&entity2.ArtifactTag{}).Error; err != nil {
+			return err
+		}
+		var shared int64
+		if err := tx.Model(&entity2.Dataset{}).Where("file_name = ? AND id <> ?", strings.TrimSpace(dataset.FileName), id).Count(&shared).Error; err != nil {
+			return err
+		}
+		if err := deleteReplicasInTx(tx, entity2.ArtifactTypeDataset, dataset.FileName, shared); err != nil {
+			return err
+		}
+		return tx.Delete(&entity2.Dataset{}, id).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrHasDependents) {
+			logger.Warn("delete dataset rejected", "id", id, "error", err)
+			return 0, err
+		}
+		logger.Error("delete dataset failed: transaction", "id", id, "error", err)
+		return 0, fmt.Errorf("delete dataset by id failed: %w", err)
+	}
+
+	logger.Info("delete dataset with dependents success", "id", id, "deleted_training_results", deletedResults)
+	return deletedResults, nil
+}
+
+// CountByFileName 统计引用同一数据集文件的记录数。
+func (d *DatasetDAO) CountByFileName(ctx context.Context, fileName string) (int64, error) {
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		return 0, fmt.Errorf("count datasets by file_name failed: %w", err)
+	}
+	var count int64
+	if err := dbConn.Model(&entity2.Dataset{}).Where("file_name = ?", strings.TrimSpace(fileName)).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count datasets by file_name failed: %w", err)
+	}
+	return count, nil
+}
+
 // DeleteByFileName 根据数据集文件名删除数据集记录。
 func (d *DatasetDAO) DeleteByFileName(ctx context.Context, fileName string) (int64, error) {
 	logger := daoLogger().With("dao", "DatasetDAO", "method", "DeleteByFileName")
@@ -375,6 +442,11 @@ func (d *DatasetDAO) FindAll(ctx context.Context, params entity2.QueryParams) ([
 	if params.NumClasses != nil {
 		dbConn = dbConn.Where("num_classes = ?", *params.NumClasses)
 	}
+	dbConn, err = applyTagFilters(dbConn, entity2.ArtifactTypeDataset, params)
+	if err != nil {
+		logger.Warn("find datasets failed: invalid tag filter", "tags", params.Tags, "labels", params.Labels, "error", err)
+		return nil, 0, err
+	}
 
 	// 3. 获取总数
 	err = dbConn.Count(&total).Error
@@ -430,3 +502,50 @@ func mapDatasetTypeToTaskType(datasetType int8) string {
 		return "unknown"
 	}
 }
+
+// FindByFileNamesOrStorageServer 查询file_name 在 fileNames 中或 storage_server 包含指定服务器的dataset记录，
+// 用于核对远程服务器上的文件清单。
+func (d *DatasetDAO) FindByFileNamesOrStorageServer(ctx context.Context, fileNames []string, storageServer string) ([]entity2.Dataset, error) {
+	logger := daoLogger().With("dao", "DatasetDAO", "method", "FindByFileNamesOrStorageServer")
+	server := strings.TrimSpace(storageServer)
+	names := make([]string, 0, len(fileNames))
+	for _, name := range fileNames {
+		if trimmed := strings.TrimSpace(name); trimmed != "" {
+			names = append(names, trimmed)
+		}
+	}
+	if len(names) == 0 && server == "" {
+		return []entity2.Dataset{}, nil
+	}
+	logger.Info("find datasets by file_name or storage server begin", "file_names", len(names), "storage_server", server)
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find datasets by file_name or storage server failed: with context", "error", err)
+		return nil, fmt.Errorf("find datasets by file_name or storage server failed: %w", err)
+	}
+
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 3)
+	if len(names) > 0 {
+		conditions = append(conditions, "file_name IN ?")
+		args = append(args, names)
+	}
+	if server != "" {
+		conditions = append(conditions, "(storage_server = ?) OR (JSON_VALID(storage_server) AND JSON_CONTAINS(storage_server, JSON_QUOTE(?)))")
+		args = append(args, server, server)
+	}
+
+	var records []entity2.Dataset
+	err = dbConn.Model(&entity2.Dataset{}).
+		Where(strings.Join(conditions, " OR "), args...).
+		Order("id ASC").
+		Find(&records).Error
+	if err != nil {
+		logger.Error("find datasets by file_name or storage server failed: db query", "error", err)
+		return nil, fmt.Errorf("find datasets by file_name or storage server failed: %w", err)
+	}
+
+	logger.Info("find datasets by file_name or storage server success", "returned", len(records))
+	return records, nil
+}
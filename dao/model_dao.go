@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"lucky_project/config"
 	entity2 "lucky_project/entity"
@@ -34,6 +35,38 @@ func (d *ModelDAO) Save(ctx context.Context, model *entity2.Model) error {
 	}
 	logger.Info("save model begin", "name", model.Name, "version", model.Version)
 
+	if err := prepareModelForSave(model); err != nil {
+		logger.Warn("save model skipped: invalid model", "name", model.Name, "error", err)
+		return err
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("save model failed: with context", "name", model.Name, "error", err)
+		return fmt.Errorf("save model failed: %w", err)
+	}
+
+	if err := dbConn.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}, {Name: "version"}},
+		DoUpdates: clause.AssignmentColumns(updatableModelColumns()),
+	}).Create(model).Error; err != nil {
+		logger.Error("save model failed: create/upsert", "name", model.Name, "error", err)
+		return fmt.Errorf("save model failed: %w", err)
+	}
+
+	if strings.TrimSpace(model.Name) != "" {
+		if err := dbConn.Where("name = ? AND version = ?", model.Name, model.Version).First(model).Error; err != nil {
+			logger.Error("save model failed: reload by unique key", "name", model.Name, "version", model.Version, "error", err)
+			return fmt.Errorf("save model failed: %w", err)
+		}
+	}
+
+	logger.Info("save model success", "id", model.ID, "name", model.Name, "version", model.Version)
+	return nil
+}
+
+// prepareModelForSave 推导 weight_name（兼容旧字段 file_name/model_path）并规范 storage_server。
+func prepareModelForSave(model *entity2.Model) error {
 	weightName := strings.TrimSpace(filepath.Base(model.WeightName))
 	if weightName == "" || weightName == "." || weightName == string(filepath.Separator) {
 		legacyFileName := strings.TrimSpace(model.LegacyFileName)
@@ -51,43 +84,77 @@ func (d *ModelDAO) Save(ctx context.Context, model *entity2.Model) error {
 		}
 	}
 	if weightName == "" || weightName == "." || weightName == string(filepath.Separator) {
-		logger.Warn("save model skipped: weight_name is empty")
 		return ErrNilEntity
 	}
 	model.WeightName = weightName
 
 	normalizedStorageServer, err := encodeStorageServerValue(parseStorageServerValue(model.StorageServer))
 	if err != nil {
-		logger.Error("save model failed: normalize storage server", "name", model.Name, "error", err)
-		return fmt.Errorf("save model failed: %w", err)
+		return fmt.Errorf("normalize storage server: %w", err)
 	}
 	model.StorageServer = normalizedStorageServer
+	return nil
+}
 
-	dbConn, err := withContext(d.DB, ctx)
-	if err != nil {
-		logger.Error("save model failed: with context", "name", model.Name, "error", err)
-		return fmt.Errorf("save model failed: %w", err)
+// Insert 仅插入新模型；(name, version) 已存在时返回 ErrAlreadyExists，不会覆盖已有版本。
+func (d *ModelDAO) Insert(ctx context.Context, model *entity2.Model) error {
+	logger := daoLogger().With("dao", "ModelDAO", "method", "Insert")
+	if model == nil {
+		logger.Warn("insert model skipped: model is nil")
+		return ErrNilEntity
 	}
+	logger.Info("insert model begin", "name", model.Name, "version", model.Version)
 
-	if err := dbConn.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "name"}, {Name: "version"}},
-		DoUpdates: clause.AssignmentColumns(updatableModelColumns()),
-	}).Create(model).Error; err != nil {
-		logger.Error("save model failed: create/upsert", "name", model.Name, "error", err)
-		return fmt.Errorf("save model failed: %w", err)
+	if err := prepareModelForSave(model); err != nil {
+		logger.Warn("insert model skipped: invalid model", "name", model.Name, "error", err)
+		return err
 	}
 
-	if strings.TrimSpace(model.Name) != "" {
-		if err := dbConn.Where("name = ? AND version = ?", model.Name, model.Version).First(model).Error; err != nil {
-			logger.Error("save model failed: reload by unique key", "name", model.Name, "version", model.Version, "error", err)
-			return fmt.Errorf("save model failed: %w", err)
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("insert model failed: with context", "name", model.Name, "error", err)
+		return fmt.Errorf("insert model failed: %w", err)
+	}
+
+	if err := dbConn.Create(model).Error; err != nil {
+		if isDuplicateKeyError(err) {
+			logger.Warn("insert model failed: version already exists", "name", model.Name, "version", model.Version)
+			return ErrAlreadyExists
 		}
+		logger.Error("insert model failed: create", "name", model.Name, "error", err)
+		return fmt.Errorf("insert model failed: %w", err)
 	}
 
-	logger.Info("save model success", "id", model.ID, "name", model.Name, "version", model.Version)
+	logger.Info("insert model success", "id", model.ID, "name", model.Name, "version", model.Version)
 	return nil
 }
 
+// FindVersionsByName 查询同名模型的全部版本，按版本从新到旧排序。
+func (d *ModelDAO) FindVersionsByName(ctx context.Context, name string) ([]entity2.Model, error) {
+	logger := daoLogger().With("dao", "ModelDAO", "method", "FindVersionsByName")
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		logger.Warn("find model versions skipped: empty name")
+		return nil, ErrNilEntity
+	}
+	logger.Info("find model versions begin", "name", trimmed)
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find model versions failed: with context", "name", trimmed, "error", err)
+		return nil, fmt.Errorf("find model versions failed: %w", err)
+	}
+
+	var models []entity2.Model
+	if err := dbConn.Where("name = ?", trimmed).Order("version DESC, id DESC").Find(&models).Error; err != nil {
+		logger.Error("find model versions failed: db query", "name", trimmed, "error", err)
+		return nil, fmt.Errorf("find model versions failed: %w", err)
+	}
+
+	logger.Info("find model versions success", "name", trimmed, "returned", len(models))
+	return models, nil
+}
+
 // GetStorageServersByID 查询模型的 storage_server 列并统一返回数组格式。
 func (d *ModelDAO) GetStorageServersByID(ctx context.Context, id uint) ([]string, error) {
 	logger := daoLogger().With("dao", "ModelDAO", "method", "GetStorageServersByID")
@@ -269,6 +336,98 @@ func (d *ModelDAO) DeleteByID(ctx context.Context, id uint) error {
 	return nil
 }
 
+// DeleteByIDWithDependents 在事务内删除模型及其阶段、标签、训练产出注册关联，以及不再被其他模型引用的权重副本记录。
+// 有关联训练结果或以该模型为 base_model_id 的派生模型时，cascade 为 false 返回 ErrHasDependents；
+// 为 true 则删除训练结果，并把派生模型的 base_model_id 改为被删模型自己的 base_model_id（没有时置 0），谱系不留悬空引用。
+// 返回删除的训练结果条数与改挂的派生模型数。阶段变更历史作为审计记录保留。
+func (d *ModelDAO) DeleteByIDWithDependents(ctx context.Context, id uint, cascade bool) (int64, int64, error) {
+	logger := daoLogger().With("dao", "ModelDAO", "method", "DeleteByIDWithDependents")
+	if id == 0 {
+		logger.Warn("delete model skipped: invalid id", "id", id)
+		return 0, 0, ErrInvalidID
+	}
+	logger.Info("delete model with dependents begin", "id", id, "cascade", cascade)
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("delete model failed: with context", "id", id, "error", err)
+		return 0, 0, fmt.Errorf("delete model by id failed: %w", err)
+	}
+
+	var deletedResults, reparented int64
+	err = dbConn.Transaction(func(tx *gorm.DB) error {
+		var model entity2.Model
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model, id).Error; err != nil {
+			return err
+		}
+		var children int64
+		if err := tx.Model(&entity2.Model{}).Where("base_model_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 && !cascade {
+			return fmt.Errorf("%w: %d models derive from model %d, retry with cascade=true", ErrHasDependents, children, id)
+		}
+		deleted, err := deleteTrainingResultsInTx(tx, "model_id", id, cascade)
+		if err != nil {
+			return err
+		}
+		deletedResults = deleted
+		if children > 0 {
+			// 自引用的脏数据不能改挂回自己
+			parentID := model.BaseModelID
+			if parentID == id {
+				parentID = 0
+			}
+			updated := tx.Model(&entity2.Model{}).Where("base_model_id = ?", id).Update("base_model_id", parentID)
+			if updated.Error != nil {
+				return updated.Error
+			}
+			reparented = updated.RowsAffected
+		}
+		if err := tx.Where("model_id = ?", id).Delete(&entity2.ModelStage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("model_id = ?", id).Delete(&entity2.TrainingResultModel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("artifact_type = ? AND artifact_id = ?", entity2.ArtifactTypeModel, id).Delete(&entity2.ArtifactTag{}).Error; err != nil {
+			return err
+		}
+		var shared int64
+		if err := tx.Model(&entity2.Model{}).Where("weight_name = ? AND id <> ?", strings.TrimSpace(model.WeightName), id).Count(&shared).Error; err != nil {
+			return err
+		}
+		if err := deleteReplicasInTx(tx, entity2.ArtifactTypeModel, model.WeightName, shared); err != nil {
+			return err
+		}
+		return tx.Delete(&entity2.Model{}, id).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrHasDependents) {
+			logger.Warn("delete model rejected", "id", id, "error", err)
+			return 0, 0, err
+		}
+		logger.Error("delete model failed: transaction", "id", id, "error", err)
+		return 0, 0, fmt.Errorf("delete model by id failed: %w", err)
+	}
+
+	logger.Info("delete model with dependents success", "id", id, "deleted_training_results", deletedResults, "reparented_models", reparented)
+	return deletedResults, reparented, nil
+}
+
+// CountByWeightName 统计引用同一权重文件的模型记录数。
+func (d *ModelDAO) CountByWeightName(ctx context.Context, weightName string) (int64, error) {
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		return 0, fmt.Errorf("count models by weight_name failed: %w", err)
+	}
+	var count int64
+	if err := dbConn.Model(&entity2.Model{}).Where("weight_name = ?", strings.TrimSpace(weightName)).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count models by weight_name failed: %w", err)
+	}
+	return count, nil
+}
+
 // DeleteByWeightName 根据权重文件名删除模型记录。
 func (d *ModelDAO) DeleteByWeightName(ctx context.Context, weightName string) (int64, error) {
 	logger := daoLogger().With("dao", "ModelDAO", "method", "DeleteByWeightName")
@@ -415,6 +574,11 @@ func (d *ModelDAO) FindAll(ctx context.Context, params entity2.QueryParams) ([]e
 	if params.BaseModelID != nil {
 		dbConn = dbConn.Where("base_model_id = ?", *params.BaseModelID)
 	}
+	dbConn, err = applyTagFilters(dbConn, entity2.ArtifactTypeModel, params)
+	if err != nil {
+		logger.Warn("find models failed: invalid tag filter", "tags", params.Tags, "labels", params.Labels, "error", err)
+		return nil, 0, err
+	}
 
 	// 兼容旧参数（旧表字段已删除），仅记录日志并忽略。
 	if params.DatasetID != nil || params.TrainTaskID != nil {
@@ -498,3 +662,111 @@ func (d *ModelDAO) UpdateMetadataByID(ctx context.Context, id uint, updates map[
 	logger.Info("update model metadata success", "id", id, "updated_fields", len(updates))
 	return &updated, nil
 }
+
+// FindByWeightNamesOrStorageServer 查询weight_name 在 fileNames 中或 storage_server 包含指定服务器的model记录，
+// 用于核对远程服务器上的文件清单。
+func (d *ModelDAO) FindByWeightNamesOrStorageServer(ctx context.Context, fileNames []string, storageServer string) ([]entity2.Model, error) {
+	logger := daoLogger().With("dao", "ModelDAO", "method", "FindByWeightNamesOrStorageServer")
+	server := strings.TrimSpace(storageServer)
+	names := make([]string, 0, len(fileNames))
+	for _, name := range fileNames {
+		if trimmed := strings.TrimSpace(name); trimmed != "" {
+			names = append(names, trimmed)
+		}
+	}
+	if len(names) == 0 && server == "" {
+		return []entity2.Model{}, nil
+	}
+	logger.Info("find models by weight_name or storage server begin", "file_names", len(names), "storage_server", server)
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find models by weight_name or storage server failed: with context", "error", err)
+		return nil, fmt.Errorf("find models by weight_name or storage server failed: %w", err)
+	}
+
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 3)
+	if len(names) > 0 {
+		conditions = append(conditions, "weight_name IN ?")
+		args = append(args, names)
+	}
+	if server != "" {
+		conditions = append(conditions, "(storage_server = ?) OR (JSON_VALID(storage_server) AND JSON_CONTAINS(storage_server, JSON_QUOTE(?)))")
+		args = append(args, server, server)
+	}
+
+	var records []entity2.Model
+	err = dbConn.Model(&entity2.Model{}).
+		Where(strings.Join(conditions, " OR "), args...).
+		Order("id ASC").
+		Find(&records).Error
+	if err != nil {
+		logger.Error("find models by weight_name or storage server failed: db query", "error", err)
+		return nil, fmt.Errorf("find models by weight_name or storage server failed: %w", err)
+	}
+
+	logger.Info("find models by weight_name or storage server success", "returned", len(records))
+	return records, nil
+}
+
+// FindByIDs 批量查询模型，不存在的 ID 忽略。
+func (d *ModelDAO) FindByIDs(ctx context.Context, ids []uint) ([]entity2.Model, error) {
+	logger := daoLogger().With("dao", "ModelDAO", "method", "FindByIDs")
+	filtered := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id != 0 {
+			filtered = append(filtered, id)
+		}
+	}
+	if len(filtered) == 0 {
+		return []entity2.Model{}, nil
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find models by ids failed: with context", "error", err)
+		return nil, fmt.Errorf("find models by ids failed: %w", err)
+	}
+
+	var records []entity2.Model
+	if err := dbConn.Where("id IN ?", filtered).Order("id ASC").Find(&records).Error; err != nil {
+		logger.Error("find models by ids failed: db query", "error", err)
+		return nil, fmt.Errorf("find models by ids failed: %w", err)
+	}
+	return records, nil
+}
+
+// FindByBaseModelIDs 查询 base_model_id 在 baseIDs 中的模型（直接派生的子模型），用于构建血缘树。
+func (d *ModelDAO) FindByBaseModelIDs(ctx context.Context, baseIDs []uint) ([]entity2.Model, error) {
+	logger := daoLogger().With("dao", "ModelDAO", "method", "FindByBaseModelIDs")
+	ids := make([]uint, 0, len(baseIDs))
+	for _, id := range baseIDs {
+		if id != 0 {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return []entity2.Model{}, nil
+	}
+	logger.Info("find models by base_model_id begin", "base_ids", len(ids))
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find models by base_model_id failed: with context", "error", err)
+		return nil, fmt.Errorf("find models by base_model_id failed: %w", err)
+	}
+
+	var records []entity2.Model
+	err = dbConn.Model(&entity2.Model{}).
+		Where("base_model_id IN ?", ids).
+		Order("id ASC").
+		Find(&records).Error
+	if err != nil {
+		logger.Error("find models by base_model_id failed: db query", "error", err)
+		return nil, fmt.Errorf("find models by base_model_id failed: %w", err)
+	}
+
+	logger.Info("find models by base_model_id success", "returned", len(records))
+	return records, nil
+}
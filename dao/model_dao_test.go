@@ -93,6 +93,51 @@ func TestModelDAODeleteByIDInvalidID(t *testing.T) {
 	assert.True(t, errors.Is(err, dao.ErrInvalidID), "id=0 should return ErrInvalidID")
 }
 
+func TestModelDAODeleteByIDWithDependentsReparentsDerivedModels(t *testing.T) {
+	modelDAO := dao.NewModelDAO()
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+
+	root := newTestModel()
+	root.Name = fmt.Sprintf("unittest_lineage_root_%d", suffix)
+	assert.NoError(t, modelDAO.Save(ctx, root), "setup save root should succeed")
+	middle := newTestModel()
+	middle.Name = fmt.Sprintf("unittest_lineage_middle_%d", suffix)
+	middle.BaseModelID = root.ID
+	assert.NoError(t, modelDAO.Save(ctx, middle), "setup save middle should succeed")
+	child := newTestModel()
+	child.Name = fmt.Sprintf("unittest_lineage_child_%d", suffix)
+	child.BaseModelID = middle.ID
+	assert.NoError(t, modelDAO.Save(ctx, child), "setup save child should succeed")
+
+	t.Cleanup(func() {
+		if modelDAO.DB != nil {
+			_ = modelDAO.DB.Delete(&entity.Model{}, []uint{root.ID, middle.ID, child.ID}).Error
+		}
+	})
+
+	_, _, err := modelDAO.DeleteByIDWithDependents(ctx, middle.ID, false)
+	assert.True(t, errors.Is(err, dao.ErrHasDependents), "derived model should block delete without cascade")
+	_, err = modelDAO.FindByID(ctx, middle.ID)
+	assert.NoError(t, err, "rejected delete should keep the model")
+
+	deletedResults, reparented, err := modelDAO.DeleteByIDWithDependents(ctx, middle.ID, true)
+	assert.NoError(t, err, "cascade delete should succeed")
+	assert.Equal(t, int64(0), deletedResults)
+	assert.Equal(t, int64(1), reparented)
+
+	got, err := modelDAO.FindByID(ctx, child.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, root.ID, got.BaseModelID, "derived model should be re-parented to the deleted model's base")
+
+	_, reparented, err = modelDAO.DeleteByIDWithDependents(ctx, root.ID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), reparented)
+	got, err = modelDAO.FindByID(ctx, child.ID)
+	assert.NoError(t, err)
+	assert.Zero(t, got.BaseModelID, "deleting a root model should clear base_model_id")
+}
+
 func TestModelDAOSaveNilEntity(t *testing.T) {
 	modelDAO := dao.NewModelDAO()
 
@@ -0,0 +1,238 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	entity2 "lucky_project/entity"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrModelStageChanged       = errors.New("model stage changed concurrently, please retry")
+	ErrProductionVersionExists = errors.New("another version of this model is already in production")
+)
+
+type ModelStageDAO struct {
+	DB *gorm.DB
+}
+
+// NewModelStageDAO 创建 ModelStageDAO，并注入全局数据库连接。
+func NewModelStageDAO() *ModelStageDAO {
+	return &ModelStageDAO{
+		DB: config.DB,
+	}
+}
+
+// ModelStageChange 一次阶段变更；FromStage 为调用方校验时读到的阶段，事务内不一致则返回 ErrModelStageChanged。
+type ModelStageChange struct {
+	Model     *entity2.Model
+	FromStage string
+	ToStage   string
+	Actor     string
+	Reason    string
+	// ArchiveExisting 晋升 production 时，将同名的现有 production 版本改为 archived；否则返回 ErrProductionVersionExists。
+	ArchiveExisting bool
+}
+
+// FindByModelID 查询模型当前阶段；没有记录时返回 gorm.ErrRecordNotFound。
+func (d *ModelStageDAO) FindByModelID(ctx context.Context, modelID uint) (*entity2.ModelStage, error) {
+	logger := daoLogger().With("dao", "ModelStageDAO", "method", "FindByModelID")
+	if modelID == 0 {
+		return nil, ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find model stage failed: with context", "model_id", modelID, "error", err)
+		return nil, fmt.Errorf("find model stage failed: %w", err)
+	}
+
+	var stage entity2.ModelStage
+	if err := dbConn.Where("model_id = ?", modelID).Take(&stage).Error; err != nil {
+		return nil, err
+	}
+	return &stage, nil
+}
+
+// FindProductionByName 查询同名模型中处于 production 的阶段记录。
+func (d *ModelStageDAO) FindProductionByName(ctx context.Context, name string) (*entity2.ModelStage, error) {
+	logger := daoLogger().With("dao", "ModelStageDAO", "method", "FindProductionByName")
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return nil, ErrNilEntity
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find production stage failed: with context", "name", trimmed, "error", err)
+		return nil, fmt.Errorf("find production stage failed: %w", err)
+	}
+
+	var stage entity2.ModelStage
+	if err := dbConn.Where("production_name = ?", trimmed).Take(&stage).Error; err != nil {
+		return nil, err
+	}
+	return &stage, nil
+}
+
+// ListTransitions 查询模型的阶段变更历史，按时间从新到旧。
+func (d *ModelStageDAO) ListTransitions(ctx context.Context, modelID uint) ([]entity2.ModelStageTransition, error) {
+	logger := daoLogger().With("dao", "ModelStageDAO", "method", "ListTransitions")
+	if modelID == 0 {
+		return nil, ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("list stage transitions failed: with context", "model_id", modelID, "error", err)
+		return nil, fmt.Errorf("list stage transitions failed: %w", err)
+	}
+
+	var transitions []entity2.ModelStageTransition
+	if err := dbConn.Where("model_id = ?", modelID).Order("id DESC").Find(&transitions).Error; err != nil {
+		logger.Error("list stage transitions failed: db query", "model_id", modelID, "error", err)
+		return nil, fmt.Errorf("list stage transitions failed: %w", err)
+	}
+	return transitions, nil
+}
+
+// Transition 在事务内变更阶段并写入历史，返回本次写入的历史记录（含被自动归档的其他版本）。
+// 同名 production 唯一由 production_name 唯一索引兜底，并发晋升时后提交者返回 ErrProductionVersionExists。
+func (d *ModelStageDAO) Transition(ctx context.Context, change ModelStageChange) ([]entity2.ModelStageTransition, error) {
+	logger := daoLogger().With("dao", "ModelStageDAO", "method", "Transition")
+	if change.Model == nil {
+		return nil, ErrNilEntity
+	}
+	if change.Model.ID == 0 {
+		return nil, ErrInvalidID
+	}
+	model := change.Model
+	logger.Info("model stage transition begin", "model_id", model.ID, "from", change.FromStage, "to", change.ToStage, "actor", change.Actor)
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("model stage transition failed: with context", "model_id", model.ID, "error", err)
+		return nil, fmt.Errorf("model stage transition failed: %w", err)
+	}
+
+	var written []entity2.ModelStageTransition
+	err = dbConn.Transaction(func(tx *gorm.DB) error {
+		current := entity2.ModelStageNone
+		var row entity2.ModelStage
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("model_id = ?", model.ID).Take(&row).Error
+		switch {
+		case err == nil:
+			current = row.Stage
+		case errors.Is(err, gorm.ErrRecordNotFound):
+		default:
+			return err
+		}
+		if current != change.FromStage {
+			return ErrModelStageChanged
+		}
+
+		if change.ToStage == entity2.ModelStageProduction {
+			var existing entity2.ModelStage
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("production_name = ? AND model_id <> ?", model.Name, model.ID).
+				Take(&existing).Error
+			switch {
+			case err == nil:
+				if !change.ArchiveExisting {
+					return ErrProductionVersionExists
+				}
+				archived, err := archiveProductionStage(tx, existing, change, model.ID)
+				if err != nil {
+					return err
+				}
+				written = append(written, archived)
+			case errors.Is(err, gorm.ErrRecordNotFound):
+			default:
+				return err
+			}
+		}
+
+		stage := entity2.ModelStage{
+			ModelID:   model.ID,
+			Name:      model.Name,
+			Stage:     change.ToStage,
+			UpdatedBy: change.Actor,
+		}
+		if change.ToStage == entity2.ModelStageProduction {
+			name := model.Name
+			stage.ProductionName = &name
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "model_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "stage", "production_name", "updated_by", "updated_at"}),
+		}).Create(&stage).Error; err != nil {
+			if isDuplicateKeyError(err) {
+				return ErrProductionVersionExists
+			}
+			return err
+		}
+
+		transition := entity2.ModelStageTransition{
+			ModelID:   model.ID,
+			ModelName: model.Name,
+			Version:   model.Version,
+			FromStage: current,
+			ToStage:   change.ToStage,
+			Actor:     change.Actor,
+			Reason:    change.Reason,
+		}
+		if err := tx.Create(&transition).Error; err != nil {
+			return err
+		}
+		written = append([]entity2.ModelStageTransition{transition}, written...)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrModelStageChanged) || errors.Is(err, ErrProductionVersionExists) {
+			logger.Warn("model stage transition rejected", "model_id", model.ID, "error", err)
+			return nil, err
+		}
+		logger.Error("model stage transition failed: transaction", "model_id", model.ID, "error", err)
+		return nil, fmt.Errorf("model stage transition failed: %w", err)
+	}
+
+	logger.Info("model stage transition success", "model_id", model.ID, "to", change.ToStage, "records", len(written))
+	return written, nil
+}
+
+// archiveProductionStage 将被取代的 production 版本改为 archived 并记录历史。
+func archiveProductionStage(tx *gorm.DB, existing entity2.ModelStage, change ModelStageChange, supersededBy uint) (entity2.ModelStageTransition, error) {
+	if err := tx.Model(&entity2.ModelStage{}).
+		Where("model_id = ?", existing.ModelID).
+		Updates(map[string]interface{}{
+			"stage":           entity2.ModelStageArchived,
+			"production_name": nil,
+			"updated_by":      change.Actor,
+		}).Error; err != nil {
+		return entity2.ModelStageTransition{}, err
+	}
+
+	var version float64
+	if err := tx.Model(&entity2.Model{}).Where("id = ?", existing.ModelID).Select("version").Scan(&version).Error; err != nil {
+		return entity2.ModelStageTransition{}, err
+	}
+
+	transition := entity2.ModelStageTransition{
+		ModelID:   existing.ModelID,
+		ModelName: existing.Name,
+		Version:   version,
+		FromStage: entity2.ModelStageProduction,
+		ToStage:   entity2.ModelStageArchived,
+		Actor:     change.Actor,
+		Reason:    fmt.Sprintf("superseded by model #%d", supersededBy),
+	}
+	if err := tx.Create(&transition).Error; err != nil {
+		return entity2.ModelStageTransition{}, err
+	}
+	return transition, nil
+}
@@ -0,0 +1,287 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	entity2 "lucky_project/entity"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	TagMatchAll = "all"
+	TagMatchAny = "any"
+
+	maxTagValueLength = 128
+)
+
+var (
+	ErrInvalidTag      = errors.New("invalid tag or label")
+	ErrInvalidTagMatch = errors.New("invalid tag_match, expected all|any")
+
+	tagKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:/-]{0,63}$`)
+)
+
+// TagSelector 标签匹配条件；AnyValue 为 true 时只比较键（label=env 匹配 env 的任意取值）。
+type TagSelector struct {
+	Key      string
+	Value    string
+	AnyValue bool
+}
+
+type TagDAO struct {
+	DB *gorm.DB
+}
+
+// NewTagDAO 创建 TagDAO，并注入全局数据库连接。
+func NewTagDAO() *TagDAO {
+	return &TagDAO{
+		DB: config.DB,
+	}
+}
+
+// ParseTag 校验普通标签，返回 TagValue 为空的 Tag。
+func ParseTag(raw string) (entity2.Tag, error) {
+	key := strings.TrimSpace(raw)
+	if !tagKeyPattern.MatchString(key) {
+		return entity2.Tag{}, fmt.Errorf("%w: tag %q must match %s", ErrInvalidTag, raw, tagKeyPattern.String())
+	}
+	return entity2.Tag{TagKey: key}, nil
+}
+
+// ParseLabel 校验键值标签 key=value，取值不能为空。
+func ParseLabel(key, value string) (entity2.Tag, error) {
+	tag, err := ParseTag(key)
+	if err != nil {
+		return entity2.Tag{}, err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" || len(value) > maxTagValueLength || strings.ContainsAny(value, ",=") ||
+		strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return entity2.Tag{}, fmt.Errorf("%w: label %s has invalid value %q", ErrInvalidTag, tag.TagKey, value)
+	}
+	tag.TagValue = value
+	return tag, nil
+}
+
+// ParseLabelSelector 解析 label 过滤/删除参数：key=value 精确匹配，key 匹配任意取值。
+func ParseLabelSelector(raw string) (TagSelector, error) {
+	key, value, hasValue := strings.Cut(raw, "=")
+	if !hasValue {
+		tag, err := ParseTag(key)
+		if err != nil {
+			return TagSelector{}, err
+		}
+		return TagSelector{Key: tag.TagKey, AnyValue: true}, nil
+	}
+	tag, err := ParseLabel(key, value)
+	if err != nil {
+		return TagSelector{}, err
+	}
+	return TagSelector{Key: tag.TagKey, Value: tag.TagValue}, nil
+}
+
+// ParseTagSelectors 将 tag/label 参数（可重复，也可逗号分隔）解析为匹配条件。
+func ParseTagSelectors(tags, labels []string) ([]TagSelector, error) {
+	selectors := make([]TagSelector, 0, len(tags)+len(labels))
+	for _, raw := range splitTagParams(tags) {
+		tag, err := ParseTag(raw)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, TagSelector{Key: tag.TagKey})
+	}
+	for _, raw := range splitTagParams(labels) {
+		selector, err := ParseLabelSelector(raw)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
+// NormalizeTagMatch 空值按 all 处理。
+func NormalizeTagMatch(match string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(match))
+	switch value {
+	case "", TagMatchAll:
+		return TagMatchAll, nil
+	case TagMatchAny:
+		return TagMatchAny, nil
+	default:
+		return "", ErrInvalidTagMatch
+	}
+}
+
+func splitTagParams(values []string) []string {
+	items := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// tagSelectorCondition 生成 tags 表上的 OR 条件
+func tagSelectorCondition(selectors []TagSelector) (string, []interface{}) {
+	parts := make([]string, 0, len(selectors))
+	args := make([]interface{}, 0, len(selectors)*2)
+	for _, selector := range selectors {
+		if selector.AnyValue {
+			parts = append(parts, "(t.tag_key = ? AND t.tag_value <> '')")
+			args = append(args, selector.Key)
+			continue
+		}
+		parts = append(parts, "(t.tag_key = ? AND t.tag_value = ?)")
+		args = append(args, selector.Key, selector.Value)
+	}
+	return strings.Join(parts, " OR "), args
+}
+
+// applyTagFilters 按 tag/label 参数过滤制品 ID；all 要求每个条件都命中，any 命中任一即可。
+func applyTagFilters(dbConn *gorm.DB, artifactType string, params entity2.QueryParams) (*gorm.DB, error) {
+	selectors, err := ParseTagSelectors(params.Tags, params.Labels)
+	if err != nil {
+		return nil, err
+	}
+	match, err := NormalizeTagMatch(params.TagMatch)
+	if err != nil {
+		return nil, err
+	}
+	if len(selectors) == 0 {
+		return dbConn, nil
+	}
+
+	subQuery := func(group []TagSelector) *gorm.DB {
+		condition, args := tagSelectorCondition(group)
+		return dbConn.Session(&gorm.Session{NewDB: true}).
+			Table("artifact_tags AS at").
+			Select("at.artifact_id").
+			Joins("JOIN tags AS t ON t.id = at.tag_id").
+			Where("at.artifact_type = ?", artifactType).
+			Where(condition, args...)
+	}
+
+	if match == TagMatchAny {
+		return dbConn.Where("id IN (?)", subQuery(selectors)), nil
+	}
+	for _, selector := range selectors {
+		dbConn = dbConn.Where("id IN (?)", subQuery([]TagSelector{selector}))
+	}
+	return dbConn, nil
+}
+
+// ListByArtifact 查询制品关联的全部标签，按键排序。
+func (d *TagDAO) ListByArtifact(ctx context.Context, artifactType string, artifactID uint) ([]entity2.Tag, error) {
+	logger := daoLogger().With("dao", "TagDAO", "method", "ListByArtifact")
+	if artifactID == 0 {
+		return nil, ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("list artifact tags failed: with context", "artifact_type", artifactType, "artifact_id", artifactID, "error", err)
+		return nil, fmt.Errorf("list artifact tags failed: %w", err)
+	}
+
+	tags := make([]entity2.Tag, 0)
+	if err := dbConn.Table("tags AS t").
+		Select("t.*").
+		Joins("JOIN artifact_tags AS at ON at.tag_id = t.id").
+		Where("at.artifact_type = ? AND at.artifact_id = ?", artifactType, artifactID).
+		Order("t.tag_key ASC, t.tag_value ASC").
+		Find(&tags).Error; err != nil {
+		logger.Error("list artifact tags failed: db query", "artifact_type", artifactType, "artifact_id", artifactID, "error", err)
+		return nil, fmt.Errorf("list artifact tags failed: %w", err)
+	}
+	return tags, nil
+}
+
+// Attach 为制品添加标签；同一个键已有其他取值时替换为新值。
+func (d *TagDAO) Attach(ctx context.Context, artifactType string, artifactID uint, tags []entity2.Tag) error {
+	logger := daoLogger().With("dao", "TagDAO", "method", "Attach")
+	if artifactID == 0 {
+		return ErrInvalidID
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("attach tags failed: with context", "artifact_type", artifactType, "artifact_id", artifactID, "error", err)
+		return fmt.Errorf("attach tags failed: %w", err)
+	}
+
+	err = dbConn.Transaction(func(tx *gorm.DB) error {
+		for _, tag := range tags {
+			record := entity2.Tag{TagKey: tag.TagKey, TagValue: tag.TagValue}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("tag_key = ? AND tag_value = ?", tag.TagKey, tag.TagValue).Take(&record).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Where(
+				"artifact_type = ? AND artifact_id = ? AND tag_id <> ? AND tag_id IN (?)",
+				artifactType, artifactID, record.ID,
+				tx.Session(&gorm.Session{NewDB: true}).Model(&entity2.Tag{}).Select("id").Where("tag_key = ?", tag.TagKey),
+			).Delete(&entity2.ArtifactTag{}).Error; err != nil {
+				return err
+			}
+
+			link := entity2.ArtifactTag{ArtifactType: artifactType, ArtifactID: artifactID, TagID: record.ID}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("attach tags failed: transaction", "artifact_type", artifactType, "artifact_id", artifactID, "error", err)
+		return fmt.Errorf("attach tags failed: %w", err)
+	}
+
+	logger.Info("attach tags success", "artifact_type", artifactType, "artifact_id", artifactID, "count", len(tags))
+	return nil
+}
+
+// Detach 移除制品上匹配的标签，返回移除的关联数；标签字典本身保留。
+func (d *TagDAO) Detach(ctx context.Context, artifactType string, artifactID uint, selectors []TagSelector) (int64, error) {
+	logger := daoLogger().With("dao", "TagDAO", "method", "Detach")
+	if artifactID == 0 {
+		return 0, ErrInvalidID
+	}
+	if len(selectors) == 0 {
+		return 0, nil
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("detach tags failed: with context", "artifact_type", artifactType, "artifact_id", artifactID, "error", err)
+		return 0, fmt.Errorf("detach tags failed: %w", err)
+	}
+
+	condition, args := tagSelectorCondition(selectors)
+	tagIDs := dbConn.Session(&gorm.Session{NewDB: true}).Table("tags AS t").Select("t.id").Where(condition, args...)
+	result := dbConn.Where("artifact_type = ? AND artifact_id = ? AND tag_id IN (?)", artifactType, artifactID, tagIDs).
+		Delete(&entity2.ArtifactTag{})
+	if result.Error != nil {
+		logger.Error("detach tags failed: db delete", "artifact_type", artifactType, "artifact_id", artifactID, "error", result.Error)
+		return 0, fmt.Errorf("detach tags failed: %w", result.Error)
+	}
+
+	logger.Info("detach tags success", "artifact_type", artifactType, "artifact_id", artifactID, "rows_affected", result.RowsAffected)
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,126 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	entity2 "lucky_project/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TrainingHyperparameterDAO struct {
+	DB *gorm.DB
+}
+
+// NewTrainingHyperparameterDAO 创建 TrainingHyperparameterDAO，并注入全局数据库连接。
+func NewTrainingHyperparameterDAO() *TrainingHyperparameterDAO {
+	return &TrainingHyperparameterDAO{
+		DB: config.DB,
+	}
+}
+
+// Upsert 写入训练结果的超参数，已存在时整体覆盖。
+func (d *TrainingHyperparameterDAO) Upsert(ctx context.Context, record *entity2.TrainingHyperparameters) error {
+	logger := daoLogger().With("dao", "TrainingHyperparameterDAO", "method", "Upsert")
+	if record == nil {
+		return ErrNilEntity
+	}
+	if record.TrainingResultID == 0 {
+		return ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("upsert hyperparameters failed: with context", "training_result_id", record.TrainingResultID, "error", err)
+		return fmt.Errorf("upsert hyperparameters failed: %w", err)
+	}
+
+	if err := upsertHyperparametersInTx(dbConn, record); err != nil {
+		logger.Error("upsert hyperparameters failed: db create", "training_result_id", record.TrainingResultID, "error", err)
+		return fmt.Errorf("upsert hyperparameters failed: %w", err)
+	}
+
+	logger.Info("upsert hyperparameters success", "training_result_id", record.TrainingResultID, "source", record.Source)
+	return nil
+}
+
+// FindByTrainingResultID 查询训练结果的超参数，未记录时返回 gorm.ErrRecordNotFound。
+func (d *TrainingHyperparameterDAO) FindByTrainingResultID(ctx context.Context, trainingResultID uint) (*entity2.TrainingHyperparameters, error) {
+	logger := daoLogger().With("dao", "TrainingHyperparameterDAO", "method", "FindByTrainingResultID")
+	if trainingResultID == 0 {
+		return nil, ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find hyperparameters failed: with context", "training_result_id", trainingResultID, "error", err)
+		return nil, fmt.Errorf("find hyperparameters failed: %w", err)
+	}
+
+	var record entity2.TrainingHyperparameters
+	if err := dbConn.Where("training_result_id = ?", trainingResultID).Take(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		logger.Error("find hyperparameters failed: db query", "training_result_id", trainingResultID, "error", err)
+		return nil, fmt.Errorf("find hyperparameters failed: %w", err)
+	}
+	return &record, nil
+}
+
+// FindByTrainingResultIDs 批量查询多条训练结果的超参数，未记录的训练结果不在返回中。
+func (d *TrainingHyperparameterDAO) FindByTrainingResultIDs(ctx context.Context, trainingResultIDs []uint) ([]entity2.TrainingHyperparameters, error) {
+	logger := daoLogger().With("dao", "TrainingHyperparameterDAO", "method", "FindByTrainingResultIDs")
+	if len(trainingResultIDs) == 0 {
+		return []entity2.TrainingHyperparameters{}, nil
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find hyperparameters failed: with context", "count", len(trainingResultIDs), "error", err)
+		return nil, fmt.Errorf("find hyperparameters failed: %w", err)
+	}
+
+	var records []entity2.TrainingHyperparameters
+	if err := dbConn.Where("training_result_id IN ?", trainingResultIDs).Find(&records).Error; err != nil {
+		logger.Error("find hyperparameters failed: db query", "count", len(trainingResultIDs), "error", err)
+		return nil, fmt.Errorf("find hyperparameters failed: %w", err)
+	}
+	return records, nil
+}
+
+// DeleteByTrainingResultID 删除训练结果的超参数，未记录时不报错。
+func (d *TrainingHyperparameterDAO) DeleteByTrainingResultID(ctx context.Context, trainingResultID uint) error {
+	logger := daoLogger().With("dao", "TrainingHyperparameterDAO", "method", "DeleteByTrainingResultID")
+	if trainingResultID == 0 {
+		return ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("delete hyperparameters failed: with context", "training_result_id", trainingResultID, "error", err)
+		return fmt.Errorf("delete hyperparameters failed: %w", err)
+	}
+
+	if err := deleteHyperparametersInTx(dbConn, trainingResultID); err != nil {
+		logger.Error("delete hyperparameters failed: db delete", "training_result_id", trainingResultID, "error", err)
+		return fmt.Errorf("delete hyperparameters failed: %w", err)
+	}
+
+	logger.Info("delete hyperparameters success", "training_result_id", trainingResultID)
+	return nil
+}
+
+func upsertHyperparametersInTx(tx *gorm.DB, record *entity2.TrainingHyperparameters) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "training_result_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"source", "hyperparameters", "update_time"}),
+	}).Create(record).Error
+}
+
+func deleteHyperparametersInTx(tx *gorm.DB, trainingResultID uint) error {
+	return tx.Where("training_result_id = ?", trainingResultID).Delete(&entity2.TrainingHyperparameters{}).Error
+}
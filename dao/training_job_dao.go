@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	entity2 "lucky_project/entity"
+
+	"gorm.io/gorm"
+)
+
+type TrainingJobDAO struct {
+	DB *gorm.DB
+}
+
+// NewTrainingJobDAO 创建 TrainingJobDAO，并注入全局数据库连接。
+func NewTrainingJobDAO() *TrainingJobDAO {
+	return &TrainingJobDAO{
+		DB: config.DB,
+	}
+}
+
+// Create 记录训练结果对应的训练进程；同一训练结果已有记录时返回 ErrAlreadyExists。
+func (d *TrainingJobDAO) Create(ctx context.Context, job *entity2.TrainingJob) error {
+	logger := daoLogger().With("dao", "TrainingJobDAO", "method", "Create")
+	if job == nil {
+		return ErrNilEntity
+	}
+	if job.TrainingResultID == 0 {
+		return ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("create training job failed: with context", "training_result_id", job.TrainingResultID, "error", err)
+		return fmt.Errorf("create training job failed: %w", err)
+	}
+
+	if err := dbConn.Create(job).Error; err != nil {
+		if isDuplicateKeyError(err) {
+			logger.Warn("create training job failed: already exists", "training_result_id", job.TrainingResultID)
+			return ErrAlreadyExists
+		}
+		logger.Error("create training job failed: db create", "training_result_id", job.TrainingResultID, "error", err)
+		return fmt.Errorf("create training job failed: %w", err)
+	}
+
+	logger.Info("create training job success", "training_result_id", job.TrainingResultID, "core_server_key", job.CoreServerKey, "pid", job.PID)
+	return nil
+}
+
+// FindByTrainingResultID 查询训练结果对应的训练进程，不是远程启动的训练返回 gorm.ErrRecordNotFound。
+func (d *TrainingJobDAO) FindByTrainingResultID(ctx context.Context, trainingResultID uint) (*entity2.TrainingJob, error) {
+	logger := daoLogger().With("dao", "TrainingJobDAO", "method", "FindByTrainingResultID")
+	if trainingResultID == 0 {
+		return nil, ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find training job failed: with context", "training_result_id", trainingResultID, "error", err)
+		return nil, fmt.Errorf("find training job failed: %w", err)
+	}
+
+	var job entity2.TrainingJob
+	if err := dbConn.Where("training_result_id = ?", trainingResultID).Take(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		logger.Error("find training job failed: db query", "training_result_id", trainingResultID, "error", err)
+		return nil, fmt.Errorf("find training job failed: %w", err)
+	}
+	return &job, nil
+}
@@ -0,0 +1,109 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"lucky_project/config"
+	entity2 "lucky_project/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// trainingMetricBatchSize 单条 INSERT 写入的指标点数
+const trainingMetricBatchSize = 500
+
+type TrainingMetricDAO struct {
+	DB *gorm.DB
+}
+
+// NewTrainingMetricDAO 创建 TrainingMetricDAO，并注入全局数据库连接。
+func NewTrainingMetricDAO() *TrainingMetricDAO {
+	return &TrainingMetricDAO{
+		DB: config.DB,
+	}
+}
+
+// UpsertBatch 批量写入指标点；(training_result_id, name, step) 已存在时覆盖 value 与 epoch。
+func (d *TrainingMetricDAO) UpsertBatch(ctx context.Context, metrics []entity2.TrainingMetric) error {
+	logger := daoLogger().With("dao", "TrainingMetricDAO", "method", "UpsertBatch")
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("upsert training metrics failed: with context", "error", err)
+		return fmt.Errorf("upsert training metrics failed: %w", err)
+	}
+
+	if err := upsertMetricsInTx(dbConn, metrics); err != nil {
+		logger.Error("upsert training metrics failed: db create", "training_result_id", metrics[0].TrainingResultID, "count", len(metrics), "error", err)
+		return fmt.Errorf("upsert training metrics failed: %w", err)
+	}
+
+	logger.Info("upsert training metrics success", "training_result_id", metrics[0].TrainingResultID, "count", len(metrics))
+	return nil
+}
+
+// ListNames 返回训练结果已上报的全部指标名称，按名称排序。
+func (d *TrainingMetricDAO) ListNames(ctx context.Context, trainingResultID uint) ([]string, error) {
+	logger := daoLogger().With("dao", "TrainingMetricDAO", "method", "ListNames")
+	if trainingResultID == 0 {
+		return nil, ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("list training metric names failed: with context", "training_result_id", trainingResultID, "error", err)
+		return nil, fmt.Errorf("list training metric names failed: %w", err)
+	}
+
+	names := make([]string, 0)
+	if err := dbConn.Model(&entity2.TrainingMetric{}).
+		Where("training_result_id = ?", trainingResultID).
+		Distinct().Order("name ASC").
+		Pluck("name", &names).Error; err != nil {
+		logger.Error("list training metric names failed: db query", "training_result_id", trainingResultID, "error", err)
+		return nil, fmt.Errorf("list training metric names failed: %w", err)
+	}
+	return names, nil
+}
+
+// FindSeries 查询指定指标的全部点，按名称、step 升序。
+func (d *TrainingMetricDAO) FindSeries(ctx context.Context, trainingResultID uint, names []string) ([]entity2.TrainingMetric, error) {
+	logger := daoLogger().With("dao", "TrainingMetricDAO", "method", "FindSeries")
+	if trainingResultID == 0 {
+		return nil, ErrInvalidID
+	}
+	if len(names) == 0 {
+		return []entity2.TrainingMetric{}, nil
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find training metrics failed: with context", "training_result_id", trainingResultID, "error", err)
+		return nil, fmt.Errorf("find training metrics failed: %w", err)
+	}
+
+	metrics := make([]entity2.TrainingMetric, 0)
+	if err := dbConn.Where("training_result_id = ? AND name IN ?", trainingResultID, names).
+		Order("name ASC, step ASC").
+		Find(&metrics).Error; err != nil {
+		logger.Error("find training metrics failed: db query", "training_result_id", trainingResultID, "error", err)
+		return nil, fmt.Errorf("find training metrics failed: %w", err)
+	}
+
+	logger.Info("find training metrics success", "training_result_id", trainingResultID, "names", len(names), "points", len(metrics))
+	return metrics, nil
+}
+
+func upsertMetricsInTx(tx *gorm.DB, metrics []entity2.TrainingMetric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "training_result_id"}, {Name: "name"}, {Name: "step"}},
+		DoUpdates: clause.AssignmentColumns([]string{"epoch", "value"}),
+	}).CreateInBatches(&metrics, trainingMetricBatchSize).Error
+}
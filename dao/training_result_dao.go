@@ -2,11 +2,13 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"lucky_project/config"
 	entity2 "lucky_project/entity"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TrainingResultDAO struct {
@@ -80,6 +82,9 @@ func (d *TrainingResultDAO) FindAll(ctx context.Context, params entity2.QueryPar
 		"training_model_id", params.TrainingModelID,
 		"training_dataset_id", params.TrainingDatasetID,
 		"training_status", params.TrainingStatus,
+		"metric_filters", params.MetricFilters,
+		"sort_by", params.SortBy,
+		"sort_order", params.SortOrder,
 	)
 
 	dbConn, err := withContext(d.DB, ctx)
@@ -100,6 +105,17 @@ func (d *TrainingResultDAO) FindAll(ctx context.Context, params entity2.QueryPar
 	if params.TrainingStatus != nil {
 		dbConn = dbConn.Where("training_status = ?", *params.TrainingStatus)
 	}
+	listKeys := newMetricKeyLister(dbConn)
+	dbConn, err = applyTrainingResultFilters(dbConn, params, listKeys)
+	if err != nil {
+		logger.Warn("find training results failed: invalid filter", "error", err)
+		return nil, 0, err
+	}
+	order, err := trainingResultOrder(params, listKeys)
+	if err != nil {
+		logger.Warn("find training results failed: invalid sort", "error", err)
+		return nil, 0, err
+	}
 
 	// 2. 获取总数
 	err = dbConn.Count(&total).Error
@@ -110,7 +126,7 @@ func (d *TrainingResultDAO) FindAll(ctx context.Context, params entity2.QueryPar
 
 	// 3. 执行分页查询 (默认 ID 降序)
 	offset, limit := pagination(params)
-	err = dbConn.Order("id DESC").Offset(offset).Limit(limit).Find(&results).Error
+	err = dbConn.Order(order).Offset(offset).Limit(limit).Find(&results).Error
 	if err != nil {
 		logger.Error("query training results failed", "error", err)
 		return nil, 0, fmt.Errorf("query training results failed: %w", err)
@@ -119,3 +135,244 @@ func (d *TrainingResultDAO) FindAll(ctx context.Context, params entity2.QueryPar
 	logger.Info("find training results success", "total", total, "returned", len(results))
 	return results, total, err
 }
+
+// FindByDatasetAndStatus 查询数据集上指定状态的全部训练结果（不分页），按 ID 升序。
+func (d *TrainingResultDAO) FindByDatasetAndStatus(ctx context.Context, datasetID uint, status int8) ([]entity2.ModelTrainingResult, error) {
+	logger := daoLogger().With("dao", "TrainingResultDAO", "method", "FindByDatasetAndStatus")
+	if datasetID == 0 {
+		return nil, ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find training results by dataset failed: with context", "dataset_id", datasetID, "error", err)
+		return nil, fmt.Errorf("find training results by dataset failed: %w", err)
+	}
+
+	results := make([]entity2.ModelTrainingResult, 0)
+	if err := dbConn.Where("dataset_id = ? AND training_status = ?", datasetID, status).
+		Order("id ASC").
+		Find(&results).Error; err != nil {
+		logger.Error("find training results by dataset failed: db query", "dataset_id", datasetID, "error", err)
+		return nil, fmt.Errorf("find training results by dataset failed: %w", err)
+	}
+
+	logger.Info("find training results by dataset success", "dataset_id", datasetID, "training_status", status, "returned", len(results))
+	return results, nil
+}
+
+// FindByIDs 按 ID 批量查询训练结果，不存在的 ID 不在返回中，结果按 id 升序。
+func (d *TrainingResultDAO) FindByIDs(ctx context.Context, ids []uint) ([]entity2.ModelTrainingResult, error) {
+	logger := daoLogger().With("dao", "TrainingResultDAO", "method", "FindByIDs")
+	filtered := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id != 0 {
+			filtered = append(filtered, id)
+		}
+	}
+	if len(filtered) == 0 {
+		return []entity2.ModelTrainingResult{}, nil
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find training results by ids failed: with context", "error", err)
+		return nil, fmt.Errorf("find training results by ids failed: %w", err)
+	}
+
+	results := make([]entity2.ModelTrainingResult, 0, len(filtered))
+	if err := dbConn.Where("id IN ?", filtered).Order("id ASC").Find(&results).Error; err != nil {
+		logger.Error("find training results by ids failed: db query", "error", err)
+		return nil, fmt.Errorf("find training results by ids failed: %w", err)
+	}
+	return results, nil
+}
+
+// TrainingResultRelatedWrites 与训练结果列更新在同一事务内写入的关联表数据。
+type TrainingResultRelatedWrites struct {
+	// Hyperparameters 非空时整体覆盖超参数；DeleteHyperparameters 为 true 时删除超参数（两者互斥，Hyperparameters 优先）。
+	Hyperparameters       *entity2.TrainingHyperparameters
+	DeleteHyperparameters bool
+	Metrics               []entity2.TrainingMetric
+}
+
+// UpdateWithLock 在事务内锁定记录，由 build 根据当前记录计算更新字段后写入；build 返回的错误原样透传。
+// 状态机校验放在 build 中，保证并发的状态变更不会基于过期状态。
+func (d *TrainingResultDAO) UpdateWithLock(ctx context.Context, id uint, build func(current *entity2.ModelTrainingResult) (map[string]interface{}, error)) (*entity2.ModelTrainingResult, error) {
+	if build == nil {
+		return nil, ErrNilEntity
+	}
+	return d.UpdateWithRelated(ctx, id, build, TrainingResultRelatedWrites{})
+}
+
+// UpdateWithRelated 同 UpdateWithLock，并在同一事务内写入超参数与过程指标，任一步失败整体回滚。
+// build 为空时只写关联数据（记录仍会被锁定并校验存在）。
+func (d *TrainingResultDAO) UpdateWithRelated(ctx context.Context, id uint, build func(current *entity2.ModelTrainingResult) (map[string]interface{}, error), writes TrainingResultRelatedWrites) (*entity2.ModelTrainingResult, error) {
+	logger := daoLogger().With("dao", "TrainingResultDAO", "method", "UpdateWithRelated")
+	if id == 0 {
+		logger.Warn("update training result skipped: invalid id", "id", id)
+		return nil, ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("update training result failed: with context", "id", id, "error", err)
+		return nil, fmt.Errorf("update training result failed: %w", err)
+	}
+
+	var updated entity2.ModelTrainingResult
+	var updatedFields int
+	err = dbConn.Transaction(func(tx *gorm.DB) error {
+		var current entity2.ModelTrainingResult
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, id).Error; err != nil {
+			return err
+		}
+		if build != nil {
+			updates, err := build(&current)
+			if err != nil {
+				return err
+			}
+			updatedFields = len(updates)
+			if len(updates) > 0 {
+				if err := tx.Model(&entity2.ModelTrainingResult{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+					return fmt.Errorf("update training result failed: %w", err)
+				}
+			}
+		}
+		switch {
+		case writes.Hyperparameters != nil:
+			writes.Hyperparameters.TrainingResultID = id
+			if err := upsertHyperparametersInTx(tx, writes.Hyperparameters); err != nil {
+				return fmt.Errorf("upsert hyperparameters failed: %w", err)
+			}
+		case writes.DeleteHyperparameters:
+			if err := deleteHyperparametersInTx(tx, id); err != nil {
+				return fmt.Errorf("delete hyperparameters failed: %w", err)
+			}
+		}
+		if err := upsertMetricsInTx(tx, writes.Metrics); err != nil {
+			return fmt.Errorf("upsert training metrics failed: %w", err)
+		}
+		return tx.First(&updated, id).Error
+	})
+	if err != nil {
+		logger.Warn("update training result failed", "id", id, "error", err)
+		return nil, err
+	}
+
+	logger.Info(
+		"update training result success",
+		"id", id,
+		"updated_fields", updatedFields,
+		"hyperparameters", writes.Hyperparameters != nil,
+		"delete_hyperparameters", writes.DeleteHyperparameters,
+		"metrics", len(writes.Metrics),
+		"training_status", updated.TrainingStatus,
+	)
+	return &updated, nil
+}
+
+// DeleteByID 根据主键删除训练结果记录及其指标序列、超参数、注册关联与训练任务；
+// 仍在运行且登记了训练任务的记录返回 ErrHasDependents。
+func (d *TrainingResultDAO) DeleteByID(ctx context.Context, id uint) error {
+	logger := daoLogger().With("dao", "TrainingResultDAO", "method", "DeleteByID")
+	if id == 0 {
+		logger.Warn("delete training result skipped: invalid id", "id", id)
+		return ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("delete training result failed: with context", "id", id, "error", err)
+		return fmt.Errorf("delete training result failed: %w", err)
+	}
+
+	err = dbConn.Transaction(func(tx *gorm.DB) error {
+		var current entity2.ModelTrainingResult
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&current, id).Error; err != nil {
+			return err
+		}
+		if err := ensureNoRunningTrainingJobsInTx(tx, []uint{id}); err != nil {
+			return err
+		}
+		result := tx.Delete(&entity2.ModelTrainingResult{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("training_result_id = ?", id).Delete(&entity2.TrainingMetric{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("training_result_id = ?", id).Delete(&entity2.TrainingHyperparameters{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("training_result_id = ?", id).Delete(&entity2.TrainingResultModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("training_result_id = ?", id).Delete(&entity2.TrainingJob{}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrHasDependents) {
+		logger.Warn("delete training result rejected", "id", id, "error", err)
+		return err
+	}
+	if err != nil {
+		logger.Error("delete training result failed: db delete", "id", id, "error", err)
+		return fmt.Errorf("delete training result failed: %w", err)
+	}
+
+	logger.Info("delete training result success", "id", id)
+	return nil
+}
+
+// deleteTrainingResultsInTx 删除 column（model_id / dataset_id）关联的训练结果及其指标序列、超参数、注册关联、训练任务；
+// cascade 为 false 且存在关联，或其中有仍在运行的训练任务时返回 ErrHasDependents。
+func deleteTrainingResultsInTx(tx *gorm.DB, column string, id uint, cascade bool) (int64, error) {
+	var count int64
+	if err := tx.Model(&entity2.ModelTrainingResult{}).Where(column+" = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	if !cascade {
+		return 0, fmt.Errorf("%w: %d training results reference %s %d, retry with cascade=true", ErrHasDependents, count, column, id)
+	}
+	resultIDs := tx.Session(&gorm.Session{NewDB: true}).Model(&entity2.ModelTrainingResult{}).Select("id").Where(column+" = ?", id)
+	if err := ensureNoRunningTrainingJobsInTx(tx, resultIDs); err != nil {
+		return 0, err
+	}
+	if err := tx.Where("training_result_id IN (?)", resultIDs).Delete(&entity2.TrainingMetric{}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Where("training_result_id IN (?)", resultIDs).Delete(&entity2.TrainingHyperparameters{}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Where("training_result_id IN (?)", resultIDs).Delete(&entity2.TrainingResultModel{}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Where("training_result_id IN (?)", resultIDs).Delete(&entity2.TrainingJob{}).Error; err != nil {
+		return 0, err
+	}
+	result := tx.Where(column+" = ?", id).Delete(&entity2.ModelTrainingResult{})
+	return result.RowsAffected, result.Error
+}
+
+// ensureNoRunningTrainingJobsInTx 拒绝删除仍在运行且登记了训练任务的训练结果：任务记录是定位核心服务器上训练进程
+// （core_server_key + pid）的唯一依据，删除后进程无人跟踪并持续占用 GPU，需先结束训练再删除。
+// resultIDs 为训练结果 ID 列表或子查询。
+func ensureNoRunningTrainingJobsInTx(tx *gorm.DB, resultIDs interface{}) error {
+	running := tx.Session(&gorm.Session{NewDB: true}).Model(&entity2.ModelTrainingResult{}).Select("id").
+		Where("id IN (?) AND training_status = ?", resultIDs, entity2.TrainingStatusRunning)
+	var jobs []entity2.TrainingJob
+	if err := tx.Where("training_result_id IN (?)", running).Limit(1).Find(&jobs).Error; err != nil {
+		return err
+	}
+	if len(jobs) > 0 {
+		job := jobs[0]
+		return fmt.Errorf("%w: training result %d is still running on core server %s (pid %d), stop the training first",
+			ErrHasDependents, job.TrainingResultID, job.CoreServerKey, job.PID)
+	}
+	return nil
+}
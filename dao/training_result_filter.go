@@ -0,0 +1,326 @@
+package dao
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	entity2 "lucky_project/entity"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	TrainingSortByID             = "id"
+	TrainingSortByCreateTime     = "create_time"
+	TrainingSortByTrainStartTime = "train_start_time"
+	TrainingSortByTrainEndTime   = "train_end_time"
+	TrainingSortByDuration       = "duration"
+	// TrainingSortByMetricPrefix sort_by=metric.<path> 按 metric_detail 中的指标排序
+	TrainingSortByMetricPrefix = "metric."
+
+	maxMetricFilters = 10
+	// maxResolvedMetricPaths 一个指标路径最多展开的实际键路径数（如 mAP50、map50、MAP_50 同时存在）
+	maxResolvedMetricPaths = 8
+
+	// metric_detail 中的值只有 JSON 数值参与比较；路径作为参数绑定，不拼接进 SQL。%s 为 metricExtractSQL 生成的取值表达式。
+	metricIsNumberSQL = "JSON_TYPE(%s) IN ('INTEGER', 'UNSIGNED INTEGER', 'DOUBLE', 'DECIMAL')"
+	metricValueSQL    = "CAST(JSON_UNQUOTE(%s) AS DECIMAL(65,30))"
+	metricKeysSQL     = "SELECT DISTINCT JSON_KEYS(metric_detail, ?) FROM model_training_results WHERE JSON_TYPE(JSON_EXTRACT(metric_detail, ?)) = 'OBJECT'"
+	durationSQL       = "TIMESTAMPDIFF(SECOND, train_start_time, train_end_time)"
+)
+
+var (
+	ErrInvalidMetricPath     = errors.New("invalid metric path")
+	ErrInvalidTrainingFilter = errors.New("invalid training result filter")
+
+	// 每级键名只允许常见指标名字符，保证生成的 JSON 路径无需转义
+	metricPathSegmentPattern = regexp.MustCompile(`^[A-Za-z0-9_()/:\-]{1,64}$`)
+
+	// 按长度排列，保证 >= 先于 > 匹配
+	metricFilterOperators = []string{">=", "<=", "!=", "=", ">", "<"}
+)
+
+// MetricFilter metric 过滤条件；Operator 为空表示只要求指标存在且为数值。
+type MetricFilter struct {
+	Path     []string
+	Operator string
+	Value    float64
+}
+
+// ParseMetricPath 将 mAP50、val.box_loss 这类指标路径拆成逐级键名。
+func ParseMetricPath(metric string) ([]string, error) {
+	segments := strings.Split(strings.TrimSpace(metric), ".")
+	for _, segment := range segments {
+		if !metricPathSegmentPattern.MatchString(segment) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidMetricPath, metric)
+		}
+	}
+	return segments, nil
+}
+
+// metricJSONPath 生成 MySQL JSON 路径，每级键名加双引号：["val","box_loss"] -> $."val"."box_loss"
+func metricJSONPath(segments []string) string {
+	var builder strings.Builder
+	builder.WriteString("$")
+	for _, segment := range segments {
+		builder.WriteString(`."`)
+		builder.WriteString(segment)
+		builder.WriteString(`"`)
+	}
+	return builder.String()
+}
+
+// NormalizeMetricKey 指标键比较时忽略大小写与 - _，使 map50_95 能匹配 mAP50-95（与排行榜一致）。
+func NormalizeMetricKey(key string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key))
+}
+
+// MetricKeyLister 返回 metric_detail 中 prefix 路径处对象的全部键名（prefix 为空表示顶层）。
+type MetricKeyLister func(prefix []string) ([]string, error)
+
+// ResolveMetricPaths 将用户输入的指标路径按 NormalizeMetricKey 逐级匹配到 metric_detail 中实际存在的键，返回 JSON 路径；
+// MySQL JSON 路径区分大小写，因此先解析出实际键名再查询。同名键完全一致的路径排在最前。
+// 没有任何匹配时返回原样路径（查询结果为空或视为指标缺失）。
+func ResolveMetricPaths(segments []string, listKeys MetricKeyLister) ([]string, error) {
+	prefixes := [][]string{{}}
+	for _, segment := range segments {
+		normalized := NormalizeMetricKey(segment)
+		next := make([][]string, 0, len(prefixes))
+		for _, prefix := range prefixes {
+			keys, err := listKeys(prefix)
+			if err != nil {
+				return nil, err
+			}
+			matched := make([]string, 0, 1)
+			for _, key := range keys {
+				if NormalizeMetricKey(key) == normalized {
+					matched = append(matched, key)
+				}
+			}
+			sort.Slice(matched, func(i, j int) bool {
+				if (matched[i] == segment) != (matched[j] == segment) {
+					return matched[i] == segment
+				}
+				return matched[i] < matched[j]
+			})
+			for _, key := range matched {
+				if !metricPathSegmentPattern.MatchString(key) {
+					continue
+				}
+				path := append(append(make([]string, 0, len(prefix)+1), prefix...), key)
+				next = append(next, path)
+			}
+		}
+		if len(next) == 0 {
+			return []string{metricJSONPath(segments)}, nil
+		}
+		if len(next) > maxResolvedMetricPaths {
+			next = next[:maxResolvedMetricPaths]
+		}
+		prefixes = next
+	}
+
+	paths := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		paths = append(paths, metricJSONPath(prefix))
+	}
+	return paths, nil
+}
+
+// metricExtractSQL 生成按顺序取第一个存在路径的表达式与绑定参数。
+func metricExtractSQL(paths []string) (string, []interface{}) {
+	parts := make([]string, 0, len(paths))
+	vars := make([]interface{}, 0, len(paths))
+	for _, path := range paths {
+		parts = append(parts, "JSON_EXTRACT(metric_detail, ?)")
+		vars = append(vars, path)
+	}
+	if len(parts) == 1 {
+		return parts[0], vars
+	}
+	return "COALESCE(" + strings.Join(parts, ", ") + ")", vars
+}
+
+// newMetricKeyLister 查询训练结果 metric_detail 中出现过的键名，同一前缀只查询一次。
+func newMetricKeyLister(dbConn *gorm.DB) MetricKeyLister {
+	cache := make(map[string][]string)
+	return func(prefix []string) ([]string, error) {
+		path := metricJSONPath(prefix)
+		if keys, ok := cache[path]; ok {
+			return keys, nil
+		}
+		var rows []sql.NullString
+		if err := dbConn.Session(&gorm.Session{NewDB: true}).Raw(metricKeysSQL, path, path).Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("list metric keys failed: %w", err)
+		}
+		seen := make(map[string]struct{})
+		keys := make([]string, 0)
+		for _, row := range rows {
+			if !row.Valid {
+				continue
+			}
+			var rowKeys []string
+			if err := json.Unmarshal([]byte(row.String), &rowKeys); err != nil {
+				continue
+			}
+			for _, key := range rowKeys {
+				if _, ok := seen[key]; !ok {
+					seen[key] = struct{}{}
+					keys = append(keys, key)
+				}
+			}
+		}
+		cache[path] = keys
+		return keys, nil
+	}
+}
+
+// ParseMetricFilter 解析 metric 参数：mAP50>=0.6、metric.val.box_loss<1.2，或只写路径表示指标存在。
+func ParseMetricFilter(raw string) (MetricFilter, error) {
+	expr := strings.TrimPrefix(strings.TrimSpace(raw), TrainingSortByMetricPrefix)
+	index := strings.IndexAny(expr, "<>!=")
+	if index < 0 {
+		path, err := ParseMetricPath(expr)
+		if err != nil {
+			return MetricFilter{}, fmt.Errorf("%w: %v", ErrInvalidTrainingFilter, err)
+		}
+		return MetricFilter{Path: path}, nil
+	}
+
+	path, err := ParseMetricPath(expr[:index])
+	if err != nil {
+		return MetricFilter{}, fmt.Errorf("%w: %v", ErrInvalidTrainingFilter, err)
+	}
+	rest := expr[index:]
+	for _, operator := range metricFilterOperators {
+		if !strings.HasPrefix(rest, operator) {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(rest[len(operator):]), 64)
+		if err != nil {
+			return MetricFilter{}, fmt.Errorf("%w: metric %q must compare with a number", ErrInvalidTrainingFilter, raw)
+		}
+		return MetricFilter{Path: path, Operator: operator, Value: value}, nil
+	}
+	return MetricFilter{}, fmt.Errorf("%w: metric %q has unknown operator", ErrInvalidTrainingFilter, raw)
+}
+
+// parseFilterTime 支持 RFC3339 与 2006-01-02（按本地时区零点）。
+func parseFilterTime(raw, field string) (*time.Time, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+	if parsed, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return &parsed, nil
+	}
+	return nil, fmt.Errorf("%w: %s must be RFC3339 or YYYY-MM-DD", ErrInvalidTrainingFilter, field)
+}
+
+// applyTrainingResultFilters 追加指标、时间范围与时长过滤；指标键名经 listKeys 解析为实际存在的键。
+func applyTrainingResultFilters(dbConn *gorm.DB, params entity2.QueryParams, listKeys MetricKeyLister) (*gorm.DB, error) {
+	rawFilters := splitTagParams(params.MetricFilters)
+	if len(rawFilters) > maxMetricFilters {
+		return nil, fmt.Errorf("%w: at most %d metric filters", ErrInvalidTrainingFilter, maxMetricFilters)
+	}
+	for _, raw := range rawFilters {
+		filter, err := ParseMetricFilter(raw)
+		if err != nil {
+			return nil, err
+		}
+		paths, err := ResolveMetricPaths(filter.Path, listKeys)
+		if err != nil {
+			return nil, err
+		}
+		extract, vars := metricExtractSQL(paths)
+		dbConn = dbConn.Where(fmt.Sprintf(metricIsNumberSQL, extract), vars...)
+		if filter.Operator != "" {
+			dbConn = dbConn.Where(fmt.Sprintf(metricValueSQL, extract)+" "+filter.Operator+" ?", append(vars, filter.Value)...)
+		}
+	}
+
+	timeRanges := []struct {
+		column, field, raw string
+		operator           string
+	}{
+		{column: "train_start_time", field: "train_start_from", raw: params.TrainStartFrom, operator: ">="},
+		{column: "train_start_time", field: "train_start_to", raw: params.TrainStartTo, operator: "<"},
+		{column: "train_end_time", field: "train_end_from", raw: params.TrainEndFrom, operator: ">="},
+		{column: "train_end_time", field: "train_end_to", raw: params.TrainEndTo, operator: "<"},
+	}
+	for _, item := range timeRanges {
+		value, err := parseFilterTime(item.raw, item.field)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			dbConn = dbConn.Where(item.column+" "+item.operator+" ?", *value)
+		}
+	}
+
+	if params.MinDurationSeconds != nil {
+		dbConn = dbConn.Where(durationSQL+" >= ?", *params.MinDurationSeconds)
+	}
+	if params.MaxDurationSeconds != nil {
+		dbConn = dbConn.Where(durationSQL+" <= ?", *params.MaxDurationSeconds)
+	}
+	return dbConn, nil
+}
+
+// trainingResultOrder 生成排序表达式；缺少排序值（时间为空、指标缺失）的记录始终排在最后，同值按 id 降序。
+func trainingResultOrder(params entity2.QueryParams, listKeys MetricKeyLister) (clause.OrderBy, error) {
+	direction := "DESC"
+	switch strings.ToLower(strings.TrimSpace(params.SortOrder)) {
+	case "", "desc":
+	case "asc":
+		direction = "ASC"
+	default:
+		return clause.OrderBy{}, fmt.Errorf("%w: sort_order must be asc or desc", ErrInvalidTrainingFilter)
+	}
+
+	sortBy := strings.TrimSpace(params.SortBy)
+	switch sortBy {
+	case "", TrainingSortByID:
+		return orderByExpr(clause.Expr{SQL: "id " + direction}), nil
+	case TrainingSortByCreateTime, TrainingSortByTrainStartTime, TrainingSortByTrainEndTime:
+		return orderByExpr(clause.Expr{SQL: fmt.Sprintf("%s IS NULL, %s %s, id DESC", sortBy, sortBy, direction)}), nil
+	case TrainingSortByDuration:
+		return orderByExpr(clause.Expr{SQL: fmt.Sprintf("%s IS NULL, %s %s, id DESC", durationSQL, durationSQL, direction)}), nil
+	}
+
+	if !strings.HasPrefix(sortBy, TrainingSortByMetricPrefix) {
+		return clause.OrderBy{}, fmt.Errorf("%w: unsupported sort_by %q", ErrInvalidTrainingFilter, sortBy)
+	}
+	segments, err := ParseMetricPath(strings.TrimPrefix(sortBy, TrainingSortByMetricPrefix))
+	if err != nil {
+		return clause.OrderBy{}, fmt.Errorf("%w: %v", ErrInvalidTrainingFilter, err)
+	}
+	paths, err := ResolveMetricPaths(segments, listKeys)
+	if err != nil {
+		return clause.OrderBy{}, err
+	}
+	extract, vars := metricExtractSQL(paths)
+	isNumber := fmt.Sprintf(metricIsNumberSQL, extract)
+	exprVars := make([]interface{}, 0, 3*len(vars))
+	for i := 0; i < 3; i++ {
+		exprVars = append(exprVars, vars...)
+	}
+	return orderByExpr(clause.Expr{
+		SQL:  fmt.Sprintf("CASE WHEN %s THEN 0 ELSE 1 END, CASE WHEN %s THEN %s END %s, id DESC", isNumber, isNumber, fmt.Sprintf(metricValueSQL, extract), direction),
+		Vars: exprVars,
+	}), nil
+}
+
+func orderByExpr(expr clause.Expr) clause.OrderBy {
+	return clause.OrderBy{Expression: expr}
+}
@@ -0,0 +1,72 @@
+package dao_test
+
+import (
+	"errors"
+	"lucky_project/dao"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMetricFilter(t *testing.T) {
+	filter, err := dao.ParseMetricFilter("metric.mAP50>=0.6")
+	assert.NoError(t, err)
+	assert.Equal(t, dao.MetricFilter{Path: []string{"mAP50"}, Operator: ">=", Value: 0.6}, filter)
+
+	filter, err = dao.ParseMetricFilter("val.box_loss < 1.2")
+	assert.NoError(t, err)
+	assert.Equal(t, dao.MetricFilter{Path: []string{"val", "box_loss"}, Operator: "<", Value: 1.2}, filter)
+
+	filter, err = dao.ParseMetricFilter("mAP50-95")
+	assert.NoError(t, err)
+	assert.Equal(t, dao.MetricFilter{Path: []string{"mAP50-95"}}, filter)
+
+	for _, raw := range []string{"", "mAP50>=", "mAP50=>0.6", "mAP50>=abc", `a"b>1`, "a..b>1", "$.x>1", "mAP50 >= 0.6 OR 1=1"} {
+		_, err := dao.ParseMetricFilter(raw)
+		assert.True(t, errors.Is(err, dao.ErrInvalidTrainingFilter), "raw=%q", raw)
+	}
+}
+
+func TestNormalizeMetricKey(t *testing.T) {
+	assert.Equal(t, dao.NormalizeMetricKey("mAP50-95"), dao.NormalizeMetricKey("map50_95"))
+	assert.Equal(t, "map50", dao.NormalizeMetricKey("mAP50"))
+	assert.NotEqual(t, dao.NormalizeMetricKey("mAP50"), dao.NormalizeMetricKey("mAP50-95"))
+}
+
+func TestResolveMetricPaths(t *testing.T) {
+	keys := map[string][]string{
+		`$`:           {"mAP50", "MAP_50", "mAP50-95", "val"},
+		`$."val"`:     {"Box_Loss", "cls_loss"},
+		`$."missing"`: nil,
+	}
+	listKeys := func(prefix []string) ([]string, error) {
+		path := "$"
+		for _, segment := range prefix {
+			path += `."` + segment + `"`
+		}
+		return keys[path], nil
+	}
+
+	paths, err := dao.ResolveMetricPaths([]string{"map50"}, listKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`$."MAP_50"`, `$."mAP50"`}, paths)
+
+	// 与输入完全一致的键优先
+	paths, err = dao.ResolveMetricPaths([]string{"mAP50"}, listKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`$."mAP50"`, `$."MAP_50"`}, paths)
+
+	paths, err = dao.ResolveMetricPaths([]string{"VAL", "box-loss"}, listKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`$."val"."Box_Loss"`}, paths)
+
+	// 没有匹配的键时保留原路径
+	paths, err = dao.ResolveMetricPaths([]string{"recall"}, listKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`$."recall"`}, paths)
+
+	_, err = dao.ResolveMetricPaths([]string{"mAP50"}, func(prefix []string) ([]string, error) {
+		return nil, errors.New("db down")
+	})
+	assert.Error(t, err)
+}
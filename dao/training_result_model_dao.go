@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	entity2 "lucky_project/entity"
+
+	"gorm.io/gorm"
+)
+
+type TrainingResultModelDAO struct {
+	DB *gorm.DB
+}
+
+// NewTrainingResultModelDAO 创建 TrainingResultModelDAO，并注入全局数据库连接。
+func NewTrainingResultModelDAO() *TrainingResultModelDAO {
+	return &TrainingResultModelDAO{
+		DB: config.DB,
+	}
+}
+
+// Create 记录训练结果注册出的模型；训练结果已注册过时返回 ErrAlreadyExists。
+func (d *TrainingResultModelDAO) Create(ctx context.Context, link *entity2.TrainingResultModel) error {
+	logger := daoLogger().With("dao", "TrainingResultModelDAO", "method", "Create")
+	if link == nil {
+		return ErrNilEntity
+	}
+	if link.TrainingResultID == 0 || link.ModelID == 0 {
+		return ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("create training result model link failed: with context", "training_result_id", link.TrainingResultID, "error", err)
+		return fmt.Errorf("create training result model link failed: %w", err)
+	}
+
+	if err := dbConn.Create(link).Error; err != nil {
+		if isDuplicateKeyError(err) {
+			logger.Warn("create training result model link failed: already registered", "training_result_id", link.TrainingResultID)
+			return ErrAlreadyExists
+		}
+		logger.Error("create training result model link failed: db create", "training_result_id", link.TrainingResultID, "error", err)
+		return fmt.Errorf("create training result model link failed: %w", err)
+	}
+
+	logger.Info("create training result model link success", "training_result_id", link.TrainingResultID, "model_id", link.ModelID)
+	return nil
+}
+
+// FindByTrainingResultID 查询训练结果注册出的模型，未注册时返回 gorm.ErrRecordNotFound。
+func (d *TrainingResultModelDAO) FindByTrainingResultID(ctx context.Context, trainingResultID uint) (*entity2.TrainingResultModel, error) {
+	logger := daoLogger().With("dao", "TrainingResultModelDAO", "method", "FindByTrainingResultID")
+	if trainingResultID == 0 {
+		return nil, ErrInvalidID
+	}
+
+	dbConn, err := withContext(d.DB, ctx)
+	if err != nil {
+		logger.Error("find training result model link failed: with context", "training_result_id", trainingResultID, "error", err)
+		return nil, fmt.Errorf("find training result model link failed: %w", err)
+	}
+
+	var link entity2.TrainingResultModel
+	if err := dbConn.Where("training_result_id = ?", trainingResultID).Take(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		logger.Error("find training result model link failed: db query", "training_result_id", trainingResultID, "error", err)
+		return nil, fmt.Errorf("find training result model link failed: %w", err)
+	}
+	return &link, nil
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+const (
+	ArtifactTypeModel   = "model"
+	ArtifactTypeDataset = "dataset"
+
+	ReplicaStatusVerified   = "verified"
+	ReplicaStatusCorrupt    = "corrupt"
+	ReplicaStatusUnverified = "unverified"
+)
+
+// ArtifactReplica 制品文件在某个存储位置（如 baidu_netdisk）上的副本及其校验结果。
+// 按 (artifact_type, file_name, storage_target) 唯一，与 models.weight_name / datasets.file_name 对应。
+type ArtifactReplica struct {
+	ID            uint       `gorm:"primaryKey;column:id" json:"id"`
+	ArtifactType  string     `gorm:"column:artifact_type;type:varchar(16);uniqueIndex:uk_artifact_replica" json:"artifact_type"`   // model｜dataset
+	FileName      string     `gorm:"column:file_name;type:varchar(255);uniqueIndex:uk_artifact_replica" json:"file_name"`          // 制品文件名
+	StorageTarget string     `gorm:"column:storage_target;type:varchar(64);uniqueIndex:uk_artifact_replica" json:"storage_target"` // 副本所在存储
+	RemotePath    string     `gorm:"column:remote_path" json:"remote_path"`
+	LocalSize     int64      `gorm:"column:local_size" json:"local_size"`
+	LocalMD5      string     `gorm:"column:local_md5" json:"local_md5"`
+	RemoteSize    int64      `gorm:"column:remote_size" json:"remote_size"`
+	RemoteMD5     string     `gorm:"column:remote_md5" json:"remote_md5"`
+	Status        string     `gorm:"column:status;type:varchar(16)" json:"status"` // verified｜corrupt｜unverified
+	StatusDetail  string     `gorm:"column:status_detail" json:"status_detail"`
+	RapidUpload   bool       `gorm:"column:rapid_upload" json:"rapid_upload"` // 是否通过秒传完成
+	VerifiedAt    *time.Time `gorm:"column:verified_at" json:"verified_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (ArtifactReplica) TableName() string {
+	return "artifact_replicas"
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+const (
+	ModelStageNone       = "none"
+	ModelStageStaging    = "staging"
+	ModelStageProduction = "production"
+	ModelStageArchived   = "archived"
+)
+
+// ModelStage 模型当前所处的生命周期阶段，每个模型至多一行；没有记录视为 none。
+// ProductionName 仅在 stage=production 时等于模型名称，其余为 NULL，借助唯一索引保证同名模型至多一个 production 版本。
+type ModelStage struct {
+	ModelID        uint      `gorm:"primaryKey;autoIncrement:false;column:model_id" json:"model_id"`
+	Name           string    `gorm:"column:name;type:varchar(128);index:idx_model_stage_name" json:"name"`
+	Stage          string    `gorm:"column:stage;type:varchar(16)" json:"stage"` // none｜staging｜production｜archived
+	ProductionName *string   `gorm:"column:production_name;type:varchar(128);uniqueIndex:uk_model_stage_production" json:"-"`
+	UpdatedBy      string    `gorm:"column:updated_by;type:varchar(128)" json:"updated_by"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (ModelStage) TableName() string {
+	return "model_stages"
+}
+
+// ModelStageTransition 阶段变更历史：谁在什么时候因为什么把哪个版本从 from 改到 to。
+type ModelStageTransition struct {
+	ID         uint      `gorm:"primaryKey;column:id" json:"id"`
+	ModelID    uint      `gorm:"column:model_id;index:idx_model_stage_transition_model" json:"model_id"`
+	ModelName  string    `gorm:"column:model_name;type:varchar(128)" json:"model_name"`
+	Version    float64   `gorm:"column:version" json:"version"`
+	FromStage  string    `gorm:"column:from_stage;type:varchar(16)" json:"from_stage"`
+	ToStage    string    `gorm:"column:to_stage;type:varchar(16)" json:"to_stage"`
+	Actor      string    `gorm:"column:actor;type:varchar(128)" json:"actor"`
+	Reason     string    `gorm:"column:reason" json:"reason"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"create_time"`
+}
+
+func (ModelStageTransition) TableName() string {
+	return "model_stage_transitions"
+}
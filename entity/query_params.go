@@ -16,6 +16,11 @@ type QueryParams struct {
 	BaseModelID   *uint  `form:"base_model_id"`
 	SizeSort      string `form:"size_sort"` // weight_size_mb 排序: asc|desc
 
+	// 标签过滤（models / datasets）：可重复传参或逗号分隔
+	Tags     []string `form:"tag"`       // 普通标签，如 tag=helmet
+	Labels   []string `form:"label"`     // 键值标签，label=env=prod 精确匹配，label=env 匹配任意取值
+	TagMatch string   `form:"tag_match"` // all（默认，全部命中）｜any（任一命中）
+
 	// 兼容旧参数
 	Algorithm   string `form:"algorithm"`     // 兼容映射到 algorithm_id
 	ImplType    string `form:"impl_type"`     // 兼容映射到 algorithm_id
@@ -38,6 +43,17 @@ type QueryParams struct {
 	TrainingModelID   *uint `form:"training_model_id"`   // 训练关联的模型ID
 	TrainingDatasetID *uint `form:"training_dataset_id"` // 训练关联的数据集ID
 	TrainingStatus    *int8 `form:"training_status"`     // 训练状态
+
+	// 训练结果指标 / 时间过滤与排序
+	MetricFilters      []string `form:"metric"`               // metric_detail 过滤，如 metric=mAP50>=0.6，可重复，多级键用 . 分隔
+	TrainStartFrom     string   `form:"train_start_from"`     // train_start_time >= ，RFC3339 或 YYYY-MM-DD
+	TrainStartTo       string   `form:"train_start_to"`       // train_start_time <
+	TrainEndFrom       string   `form:"train_end_from"`       // train_end_time >=
+	TrainEndTo         string   `form:"train_end_to"`         // train_end_time <
+	MinDurationSeconds *int64   `form:"min_duration_seconds"` // 训练时长（结束 - 开始）下限
+	MaxDurationSeconds *int64   `form:"max_duration_seconds"` // 训练时长上限
+	SortBy             string   `form:"sort_by"`              // id｜create_time｜train_start_time｜train_end_time｜duration｜metric.<path>
+	SortOrder          string   `form:"sort_order"`           // asc｜desc（默认）
 }
 
 // GetOffset 计算数据库偏移量
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// Tag 标签字典：TagValue 为空表示普通标签（如 helmet），非空表示键值标签（如 env=prod）。
+type Tag struct {
+	ID         uint      `gorm:"primaryKey;column:id" json:"id"`
+	TagKey     string    `gorm:"column:tag_key;type:varchar(64);uniqueIndex:uk_tag" json:"key"`
+	TagValue   string    `gorm:"column:tag_value;type:varchar(128);uniqueIndex:uk_tag" json:"value"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"create_time"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// ArtifactTag 制品（model/dataset）与标签的多对多关联；同一制品的每个键至多关联一个取值。
+type ArtifactTag struct {
+	ArtifactType string    `gorm:"primaryKey;column:artifact_type;type:varchar(16)" json:"artifact_type"` // model｜dataset
+	ArtifactID   uint      `gorm:"primaryKey;autoIncrement:false;column:artifact_id" json:"artifact_id"`
+	TagID        uint      `gorm:"primaryKey;autoIncrement:false;column:tag_id;index:idx_artifact_tag_tag" json:"tag_id"`
+	CreateTime   time.Time `gorm:"column:create_time;autoCreateTime" json:"create_time"`
+}
+
+func (ArtifactTag) TableName() string {
+	return "artifact_tags"
+}
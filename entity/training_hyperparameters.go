@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 超参数来源
+const (
+	HyperparameterSourceUltralyticsArgs = "ultralytics_args"
+	HyperparameterSourceLaunchArgs      = "launch_args"
+	HyperparameterSourceManual          = "manual"
+)
+
+// TrainingHyperparameters 训练结果对应的超参数（如 Ultralytics args.yaml），每条训练结果一行。
+// 单独建表而不是给 model_training_results 加列：启动时的 ensureTables 只创建缺失的表，
+// 不会给已有表补列，新增列在已部署的库上需要手工迁移，新表则会自动创建。
+type TrainingHyperparameters struct {
+	TrainingResultID uint            `gorm:"primaryKey;autoIncrement:false;column:training_result_id" json:"training_result_id"`
+	Source           string          `gorm:"column:source;type:varchar(32)" json:"source"`
+	Hyperparameters  json.RawMessage `gorm:"column:hyperparameters;type:json" json:"hyperparameters"`
+	UpdateTime       time.Time       `gorm:"column:update_time;autoUpdateTime" json:"update_time"`
+}
+
+func (TrainingHyperparameters) TableName() string {
+	return "training_hyperparameters"
+}
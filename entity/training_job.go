@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// TrainingJob 在核心服务器上启动的训练进程，每条训练结果最多一个。
+type TrainingJob struct {
+	TrainingResultID uint      `gorm:"primaryKey;autoIncrement:false;column:training_result_id" json:"training_result_id"`
+	CoreServerKey    string    `gorm:"column:core_server_key;type:varchar(128);index:idx_training_job_server" json:"core_server_key"`
+	PID              int       `gorm:"column:pid" json:"pid"`
+	RunDir           string    `gorm:"column:run_dir;type:varchar(512)" json:"run_dir"`
+	LogPath          string    `gorm:"column:log_path;type:varchar(512)" json:"log_path"`
+	Command          string    `gorm:"column:command;type:text" json:"command"`
+	CreateTime       time.Time `gorm:"column:create_time;autoCreateTime" json:"create_time"`
+}
+
+func (TrainingJob) TableName() string {
+	return "training_jobs"
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// TrainingMetric 训练过程中的单个指标点（如每个 epoch 的 train/box_loss），按 (training_result_id, name, step) 唯一，重复上报覆盖旧值。
+type TrainingMetric struct {
+	ID               uint      `gorm:"primaryKey;column:id" json:"-"`
+	TrainingResultID uint      `gorm:"column:training_result_id;uniqueIndex:uk_training_metric,priority:1" json:"training_result_id"`
+	Name             string    `gorm:"column:name;type:varchar(128);uniqueIndex:uk_training_metric,priority:2" json:"name"`
+	Step             int64     `gorm:"column:step;uniqueIndex:uk_training_metric,priority:3" json:"step"`
+	Epoch            *int      `gorm:"column:epoch" json:"epoch,omitempty"`
+	Value            float64   `gorm:"column:value" json:"value"`
+	CreateTime       time.Time `gorm:"column:create_time;autoCreateTime" json:"-"`
+}
+
+func (TrainingMetric) TableName() string {
+	return "training_metrics"
+}
@@ -5,18 +5,31 @@ import (
 	"time"
 )
 
+// 训练状态：pending 为已登记未开始；success / failed / interrupted 为终态。
+const (
+	TrainingStatusPending     int8 = 0
+	TrainingStatusRunning     int8 = 1
+	TrainingStatusSuccess     int8 = 2
+	TrainingStatusFailed      int8 = 3
+	TrainingStatusInterrupted int8 = 4
+)
+
 type ModelTrainingResult struct {
 	ID             uint            `gorm:"primaryKey;column:id" json:"id"`
 	ModelID        uint            `gorm:"column:model_id" json:"model_id"`                     // 模型ID
 	DatasetID      uint            `gorm:"column:dataset_id" json:"dataset_id"`                 // 数据集ID
 	DatasetVersion float64         `gorm:"column:dataset_version" json:"dataset_version"`       // 数据集版本
-	TrainingStatus int8            `gorm:"column:training_status" json:"training_status"`       // 1:训练中｜2:成功｜3:失败｜4:中断
+	TrainingStatus int8            `gorm:"column:training_status" json:"training_status"`       // 0:待开始｜1:训练中｜2:成功｜3:失败｜4:中断
 	MetricDetail   json.RawMessage `gorm:"column:metric_detail;type:json" json:"metric_detail"` // 评估指标JSON
 	WeightPath     string          `gorm:"column:weight_path" json:"weight_path"`               // 训练产出权重文件路径
 	CometLogURL    string          `gorm:"column:comet_log_url" json:"comet_log_url"`           // Comet 实验日志URL
 	TrainStartTime *time.Time      `gorm:"column:train_start_time" json:"train_start_time"`
 	TrainEndTime   *time.Time      `gorm:"column:train_end_time" json:"train_end_time"`
 	CreateTime     time.Time       `gorm:"column:create_time;autoCreateTime" json:"create_time"`
+
+	DurationSeconds *int64 `gorm:"-" json:"duration_seconds"` // 训练时长（结束 - 开始），开始或结束时间为空时为 null
+	// Hyperparameters 超参数JSON对象，存于 training_hyperparameters 表；创建/更新时可传，单条查询时返回
+	Hyperparameters json.RawMessage `gorm:"-" json:"hyperparameters,omitempty"`
 }
 
 func (ModelTrainingResult) TableName() string {
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// TrainingResultModel 训练结果产出的权重注册成的模型版本，每条训练结果最多注册一次。
+type TrainingResultModel struct {
+	TrainingResultID uint      `gorm:"primaryKey;autoIncrement:false;column:training_result_id" json:"training_result_id"`
+	ModelID          uint      `gorm:"column:model_id;index:idx_training_result_model" json:"model_id"`
+	CreateTime       time.Time `gorm:"column:create_time;autoCreateTime" json:"create_time"`
+}
+
+func (TrainingResultModel) TableName() string {
+	return "training_result_models"
+}
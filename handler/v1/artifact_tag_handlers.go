@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"lucky_project/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 模型与数据集共用的标签接口实现，artifactType 为 model｜dataset。
+
+func getArtifactTags(ctx *gin.Context, tagService *service.ArtifactTagService, artifactType string) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := tagService.Get(ctx.Request.Context(), artifactType, id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func addArtifactTags(ctx *gin.Context, tagService *service.ArtifactTagService, artifactType string) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req service.ArtifactTagsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := tagService.Add(ctx.Request.Context(), artifactType, id, req)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func removeArtifactTags(ctx *gin.Context, tagService *service.ArtifactTagService, artifactType string) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := tagService.Remove(ctx.Request.Context(), artifactType, id, ctx.QueryArray("tag"), ctx.QueryArray("label"))
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
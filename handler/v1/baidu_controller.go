@@ -6,6 +6,7 @@ import (
 	"lucky_project/dao"
 	"lucky_project/service"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -16,6 +17,10 @@ type BaiduController struct {
 	modelService    *service.ModelService
 	datasetService  *service.DatasetService
 	pathService     *service.ArtifactPathService
+	tokenManager    *service.BaiduTokenManager
+	browseService   *service.BaiduFileBrowseService
+	importService   *service.BaiduImportService
+	mirrorService   *service.BaiduMirrorService
 }
 
 type BaiduDownloadRequest struct {
@@ -37,6 +42,10 @@ func NewBaiduController() *BaiduController {
 		modelService:    service.NewModelService(),
 		datasetService:  service.NewDatasetService(),
 		pathService:     service.NewArtifactPathService(),
+		tokenManager:    service.DefaultBaiduTokenManager(),
+		browseService:   service.NewBaiduFileBrowseService(),
+		importService:   service.NewBaiduImportService(),
+		mirrorService:   service.DefaultBaiduMirrorService(),
 	}
 }
 
@@ -240,3 +249,128 @@ func (c *BaiduController) syncRecordStorageServer(ctx *gin.Context, target stora
 		return nil, nil
 	}
 }
+
+// GetTokenStatus handles GET /v1/baidu/token/status
+// 返回 access_token 是否存在、过期时间与最近一次刷新结果，不返回 token 内容。
+func (c *BaiduController) GetTokenStatus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.tokenManager.Status())
+}
+
+// RefreshToken handles POST /v1/baidu/token/refresh
+// 立即用 refresh_token 换取新的 access_token 并持久化。
+func (c *BaiduController) RefreshToken(ctx *gin.Context) {
+	if _, err := c.tokenManager.ForceRefresh(ctx.Request.Context()); err != nil {
+		switch {
+		case errors.Is(err, service.ErrBaiduTokenRefreshNotConfigured):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrBaiduTokenRefreshFailed):
+			ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrBaiduTokenRefreshNeedsVault):
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		default:
+			writeHTTPError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.tokenManager.Status())
+}
+
+// ListFiles handles GET /v1/baidu/files?dir=&keyword=&page=&page_size=
+// 仅允许浏览 weights/datasets 根目录，并标注已登记为模型/数据集的文件。
+func (c *BaiduController) ListFiles(ctx *gin.Context) {
+	var query service.BaiduFileListQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.browseService.ListFiles(ctx.Request.Context(), query)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrBaiduDirOutsideRoots),
+			errors.Is(err, service.ErrInvalidBaiduRemoteDir),
+			errors.Is(err, service.ErrBaiduPanAccessTokenRequired):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			writeHTTPError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ImportDirectory handles POST /v1/baidu/import
+// 校验目录后创建后台导入任务，返回 202 与任务信息；通过 GET /v1/baidu/import/:id 查询进度与报告。
+func (c *BaiduController) ImportDirectory(ctx *gin.Context) {
+	var req service.BaiduImportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := c.importService.StartImport(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrBaiduDirOutsideRoots),
+			errors.Is(err, service.ErrInvalidBaiduRemoteDir):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrBaiduImportRunning):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			writeHTTPError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+// GetImportJob handles GET /v1/baidu/import/:id
+// 返回导入任务状态；任务结束后 result 为 created/skipped/failed 报告。
+func (c *BaiduController) GetImportJob(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid import job id"})
+		return
+	}
+
+	job, err := c.importService.GetImportJob(id)
+	if err != nil {
+		if errors.Is(err, service.ErrBaiduImportJobNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+// GetMirrorStatus handles GET /v1/baidu/mirror/status
+// 返回定时镜像的最近一次运行结果、待同步文件数与同步滞后。
+func (c *BaiduController) GetMirrorStatus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.mirrorService.Status())
+}
+
+// RunMirror handles POST /v1/baidu/mirror/run
+// 立即执行一次镜像并返回本次报告；已有镜像在执行时返回 409。
+func (c *BaiduController) RunMirror(ctx *gin.Context) {
+	report, err := c.mirrorService.RunOnce(ctx.Request.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrBaiduMirrorRunning):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrBaiduMirrorNotReady):
+			writeHTTPError(ctx, err)
+		default:
+			// 部分目录失败时仍返回报告，便于查看已完成的部分。
+			ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "report": report})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
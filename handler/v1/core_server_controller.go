@@ -1,20 +1,26 @@
 package v1
 
 import (
+	"errors"
 	"lucky_project/service"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-type CoreServerController struct{}
+type CoreServerController struct {
+	inventorySvc *service.CoreServerInventoryService
+}
 
 func NewCoreServerController() *CoreServerController {
-	return &CoreServerController{}
+	return &CoreServerController{
+		inventorySvc: service.NewCoreServerInventoryService(service.NewSSHArtifactTransferService()),
+	}
 }
 
 // ListCoreServers handles GET /v1/core-servers
-// 返回 list，每项仅包含 key/ip/port 三个字段。
+// 返回 list，每项包含 key/ip/port 以及心跳推导出的 status/last_seen/heartbeat。
 func (c *CoreServerController) ListCoreServers(ctx *gin.Context) {
 	result, err := service.ListCoreServers(ctx.Request.Context())
 	if err != nil {
@@ -24,3 +30,70 @@ func (c *CoreServerController) ListCoreServers(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, result)
 }
+
+// ReportHeartbeat handles POST /v1/core-servers/:key/heartbeat
+// 由核心服务器（或探测脚本）定期上报 GPU、磁盘与负载信息。
+func (c *CoreServerController) ReportHeartbeat(ctx *gin.Context) {
+	key := strings.TrimSpace(ctx.Param("key"))
+
+	var heartbeat service.CoreServerHeartbeat
+	if err := ctx.ShouldBindJSON(&heartbeat); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	server, err := service.ReportCoreServerHeartbeat(ctx.Request.Context(), key, heartbeat)
+	if err != nil {
+		writeCoreServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server)
+}
+
+// ListArtifacts handles GET /v1/core-servers/:key/artifacts
+// 通过 SFTP 列出核心服务器 weights/datasets 根目录，并按文件名与 models/datasets 记录核对：
+// 标出无记录的未知文件，以及 storage_server 声明在该服务器但文件缺失的记录。
+// 可选 query: ssh_user / credential_id（凭据库中的 SSH 凭据，服务器已登记 credential_id 时不可指定其他凭据）。
+func (c *CoreServerController) ListArtifacts(ctx *gin.Context) {
+	logger := handlerLogger().With("controller", "CoreServerController", "method", "ListArtifacts")
+	key := strings.TrimSpace(ctx.Param("key"))
+
+	coreServer, err := service.GetCoreServerByKey(ctx.Request.Context(), key)
+	if err != nil {
+		writeCoreServerError(ctx, err)
+		return
+	}
+	if coreServer.Status == service.CoreServerStatusOffline {
+		writeCoreServerError(ctx, service.EnsureCoreServerAvailable(coreServer))
+		return
+	}
+
+	credential := service.SSHCredential{User: ctx.Query("ssh_user"), CredentialID: ctx.Query("credential_id")}
+	inventory, err := c.inventorySvc.ListArtifacts(ctx.Request.Context(), coreServer, credential)
+	if err != nil {
+		logger.Error("list core server artifacts failed", "core_server_key", coreServer.Key, "error", err)
+		writeCoreServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, inventory)
+}
+
+func writeCoreServerError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCoreServerKeyRequired),
+		errors.Is(err, service.ErrCoreServerHeartbeatInvalid),
+		errors.Is(err, service.ErrSSHCredentialOverrideForbidden),
+		errors.Is(err, service.ErrCredentialNotFound),
+		errors.Is(err, service.ErrCredentialInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCoreServerNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCoreServerOffline),
+		errors.Is(err, service.ErrVaultNotInitialized):
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		writeHTTPError(ctx, err)
+	}
+}
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"errors"
+	"lucky_project/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CredentialController struct {
+	credentialService *service.CredentialService
+}
+
+func NewCredentialController() *CredentialController {
+	return &CredentialController{
+		credentialService: service.NewCredentialService(),
+	}
+}
+
+// CreateCredential handles POST /v1/credentials
+// 请求体包含 kind(ssh_key/password/baidu_token)/name/description/secret，响应只返回元信息。
+func (c *CredentialController) CreateCredential(ctx *gin.Context) {
+	var req service.CredentialCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	meta, err := c.credentialService.CreateCredential(ctx.Request.Context(), req)
+	if err != nil {
+		writeCredentialError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, meta)
+}
+
+// ListCredentials handles GET /v1/credentials
+func (c *CredentialController) ListCredentials(ctx *gin.Context) {
+	list, err := c.credentialService.ListCredentials(ctx.Request.Context())
+	if err != nil {
+		writeCredentialError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"total": len(list), "list": list})
+}
+
+// GetCredential handles GET /v1/credentials/:id
+func (c *CredentialController) GetCredential(ctx *gin.Context) {
+	meta, err := c.credentialService.GetCredential(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		writeCredentialError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, meta)
+}
+
+// RotateCredential handles POST /v1/credentials/:id/rotate
+// 替换凭据内容并递增 version，ID 保持不变。
+func (c *CredentialController) RotateCredential(ctx *gin.Context) {
+	var req service.CredentialRotateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	meta, err := c.credentialService.RotateCredential(ctx.Request.Context(), ctx.Param("id"), req)
+	if err != nil {
+		writeCredentialError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, meta)
+}
+
+// DeleteCredential handles DELETE /v1/credentials/:id
+func (c *CredentialController) DeleteCredential(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if err := c.credentialService.DeleteCredential(ctx.Request.Context(), id); err != nil {
+		writeCredentialError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"id": id, "deleted": true})
+}
+
+func writeCredentialError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCredentialInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCredentialNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCredentialInUse):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrVaultNotInitialized):
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		writeHTTPError(ctx, err)
+	}
+}
@@ -17,6 +17,9 @@ type DatasetController struct {
 	datasetService  *service.DatasetService
 	uploadService   *service.UploadService
 	downloadService *service.BaiduDownloadService
+	replicaService  *service.ArtifactReplicaService
+	tagService      *service.ArtifactTagService
+	leaderboard     *service.DatasetLeaderboardService
 }
 
 func NewDatasetController() *DatasetController {
@@ -24,6 +27,9 @@ func NewDatasetController() *DatasetController {
 		datasetService:  service.NewDatasetService(),
 		uploadService:   service.NewUploadService(),
 		downloadService: service.NewBaiduDownloadService(),
+		replicaService:  service.NewArtifactReplicaService(),
+		tagService:      service.NewArtifactTagService(),
+		leaderboard:     service.NewDatasetLeaderboardService(),
 	}
 }
 
@@ -60,6 +66,73 @@ func (c *DatasetController) GetAllDatasets(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, result)
 }
 
+// GetDataset handles GET /v1/datasets/:id
+func (c *DatasetController) GetDataset(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dataset, err := c.datasetService.GetByID(ctx.Request.Context(), id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dataset)
+}
+
+// GetDatasetLeaderboard handles GET /v1/datasets/:id/leaderboard?metric=map50_95&order=desc&limit=20
+func (c *DatasetController) GetDatasetLeaderboard(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var query service.LeaderboardQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	leaderboard, err := c.leaderboard.GetLeaderboard(ctx.Request.Context(), id, query)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidLeaderboardQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, leaderboard)
+}
+
+// DeleteDataset handles DELETE /v1/datasets/:id[?cascade=true&keep_file=true]
+// 有训练结果引用时默认返回 409，cascade=true 一并删除；数据集文件仅在无其他数据集引用时删除。
+func (c *DatasetController) DeleteDataset(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, err := parseArtifactDeleteOptions(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.datasetService.DeleteByID(ctx.Request.Context(), id, opts)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
 // GetDatasetStorageServers handles GET /v1/datasets/:id/storage-server
 func (c *DatasetController) GetDatasetStorageServers(ctx *gin.Context) {
 	id, err := parseUintPathParam(ctx, "id")
@@ -139,24 +212,67 @@ func (c *DatasetController) UploadDatasetFile(ctx *gin.Context) {
 		return
 	}
 
+	if result.BaiduReceipt != nil {
+		// 文件已上传成功，副本记录写入失败只记日志，仍返回上传回执。
+		if _, err := c.replicaService.RecordBaiduUpload(ctx.Request.Context(), entity2.ArtifactTypeDataset, result.FileName, *result.BaiduReceipt); err != nil {
+			handlerLogger().With("controller", "DatasetController", "method", "UploadDatasetFile").
+				Error("record baidu replica failed", "file_name", result.FileName, "error", err)
+		}
+	}
+
 	ctx.JSON(http.StatusCreated, gin.H{
-		"message":         "upload success",
-		"file_name":       result.FileName,
-		"resolved_path":   result.ResolvedPath,
-		"saved_path":      result.SavedPath,
-		"paths":           result.Paths,
-		"size":            result.Size,
-		"size_mb":         sizeMB,
-		"mysql_updated":   affectedRows > 0,
-		"mysql_affected":  affectedRows,
-		"storage_server":  result.StorageServer,
-		"storage_target":  result.StorageTarget,
-		"upload_to_baidu": result.UploadToBaidu,
-		"baidu_uploaded":  result.BaiduUploaded,
-		"baidu_path":      result.BaiduPath,
+		"message":            "upload success",
+		"file_name":          result.FileName,
+		"resolved_path":      result.ResolvedPath,
+		"saved_path":         result.SavedPath,
+		"paths":              result.Paths,
+		"size":               result.Size,
+		"size_mb":            sizeMB,
+		"mysql_updated":      affectedRows > 0,
+		"mysql_affected":     affectedRows,
+		"storage_server":     result.StorageServer,
+		"storage_target":     result.StorageTarget,
+		"upload_to_baidu":    result.UploadToBaidu,
+		"baidu_uploaded":     result.BaiduUploaded,
+		"baidu_path":         result.BaiduPath,
+		"baidu_verification": result.BaiduReceipt,
 	})
 }
 
+// GetDatasetReplicas handles GET /v1/datasets/:id/replicas
+// 返回数据集文件在各存储上的副本及校验状态（verified/corrupt/unverified）。
+func (c *DatasetController) GetDatasetReplicas(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	replicas, err := c.replicaService.ListByDatasetID(ctx.Request.Context(), id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"id": id, "replicas": replicas})
+}
+
+// GetDatasetTags handles GET /v1/datasets/:id/tags
+func (c *DatasetController) GetDatasetTags(ctx *gin.Context) {
+	getArtifactTags(ctx, c.tagService, entity2.ArtifactTypeDataset)
+}
+
+// AddDatasetTags handles POST /v1/datasets/:id/tags
+// 请求体 {"tags": [...], "labels": {"key": "value"}}，同一键的已有取值会被覆盖。
+func (c *DatasetController) AddDatasetTags(ctx *gin.Context) {
+	addArtifactTags(ctx, c.tagService, entity2.ArtifactTypeDataset)
+}
+
+// RemoveDatasetTags handles DELETE /v1/datasets/:id/tags?tag=...&label=...
+func (c *DatasetController) RemoveDatasetTags(ctx *gin.Context) {
+	removeArtifactTags(ctx, c.tagService, entity2.ArtifactTypeDataset)
+}
+
 // DownloadDatasetFile handles GET /v1/datasets/:id/download.
 func (c *DatasetController) DownloadDatasetFile(ctx *gin.Context) {
 	id, err := parseUintPathParam(ctx, "id")
@@ -341,4 +341,47 @@ func TestDatasetAPI(t *testing.T) {
 		_, err = datasetService.GetByID(context.Background(), created.ID)
 		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
 	})
+	t.Run("Dataset Leaderboard", func(t *testing.T) {
+		body, _ := json.Marshal(entity2.Dataset{
+			Name:          fmt.Sprintf("LeaderboardDataset_%d", time.Now().UnixNano()),
+			StorageServer: "backend",
+			TaskType:      "detect",
+			DatasetFormat: "yolo",
+			Version:       "v2.0.0",
+		})
+		w := performRequest(testRouter, "POST", "/v1/datasets", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var dataset entity2.Dataset
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &dataset))
+
+		for _, detail := range []string{`{"mAP50-95":0.52}`, `{"mAP50-95":0.61}`, `{"mAP50":0.8}`} {
+			body, _ = json.Marshal(entity2.ModelTrainingResult{
+				ModelID:        1,
+				DatasetID:      dataset.ID,
+				DatasetVersion: 2,
+				TrainingStatus: entity2.TrainingStatusSuccess,
+				MetricDetail:   json.RawMessage(detail),
+			})
+			w = performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+			assert.Equal(t, http.StatusCreated, w.Code)
+		}
+
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/datasets/%d/leaderboard?metric=map50_95", dataset.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var leaderboard service.DatasetLeaderboard
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &leaderboard))
+		assert.Equal(t, "v2.0.0", leaderboard.DatasetVersion)
+		assert.Equal(t, 2, leaderboard.Total)
+		if assert.Len(t, leaderboard.Entries, 2) {
+			assert.Equal(t, 0.61, leaderboard.Entries[0].Value)
+			assert.Equal(t, 1, leaderboard.Entries[0].Rank)
+			assert.Equal(t, "mAP50-95", leaderboard.Entries[0].MetricKey)
+		}
+		assert.Len(t, leaderboard.Missing, 1)
+
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/datasets/%d/leaderboard?order=sideways", dataset.ID), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(testRouter, "GET", "/v1/datasets/99999999/leaderboard", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
 }
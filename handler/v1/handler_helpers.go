@@ -26,10 +26,12 @@ func writeHTTPError(ctx *gin.Context, err error) {
 	)
 
 	switch {
-	case errors.Is(err, dao.ErrInvalidID), errors.Is(err, dao.ErrNilEntity), errors.Is(err, dao.ErrInvalidAction):
+	case errors.Is(err, dao.ErrInvalidID), errors.Is(err, dao.ErrNilEntity), errors.Is(err, dao.ErrInvalidAction),
+		errors.Is(err, dao.ErrInvalidTag), errors.Is(err, dao.ErrInvalidTagMatch),
+		errors.Is(err, dao.ErrInvalidTrainingFilter):
 		logger.Warn("request failed", "status", http.StatusBadRequest, "error", err)
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-	case errors.Is(err, dao.ErrAlreadyExists):
+	case errors.Is(err, dao.ErrAlreadyExists), errors.Is(err, dao.ErrHasDependents):
 		logger.Warn("request failed", "status", http.StatusConflict, "error", err)
 		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 	case errors.Is(err, gorm.ErrRecordNotFound):
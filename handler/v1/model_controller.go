@@ -2,7 +2,6 @@ package v1
 
 import (
 	"errors"
-	"fmt"
 	"lucky_project/dao"
 	entity2 "lucky_project/entity"
 	"lucky_project/service"
@@ -20,6 +19,9 @@ type ModelController struct {
 	uploadService   *service.UploadService
 	downloadService *service.BaiduDownloadService
 	sshUploadSvc    *service.SSHArtifactTransferService
+	replicaService  *service.ArtifactReplicaService
+	stageService    *service.ModelStageService
+	tagService      *service.ArtifactTagService
 }
 
 func NewModelController() *ModelController {
@@ -28,18 +30,36 @@ func NewModelController() *ModelController {
 		uploadService:   service.NewUploadService(),
 		downloadService: service.NewBaiduDownloadService(),
 		sshUploadSvc:    service.NewSSHArtifactTransferService(),
+		replicaService:  service.NewArtifactReplicaService(),
+		stageService:    service.NewModelStageService(),
+		tagService:      service.NewArtifactTagService(),
 	}
 }
 
-// CreateModel handles POST /v1/models
+// CreateModel handles POST /v1/models[?version_bump=major|minor]
+// 传 version_bump 时忽略请求体中的 version，按同名最新版本自动递增（无同名记录时为 1.00）。
 func (c *ModelController) CreateModel(ctx *gin.Context) {
 	var model entity2.Model
 	if err := ctx.ShouldBindJSON(&model); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	bump, err := service.NormalizeVersionBump(ctx.Query("version_bump"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	if err := c.modelService.CreateModel(ctx.Request.Context(), &model); err != nil {
+	if bump != "" {
+		err = c.modelService.CreateModelWithNextVersion(ctx.Request.Context(), &model, bump)
+	} else {
+		err = c.modelService.CreateModel(ctx.Request.Context(), &model)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrModelVersionOverflow) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		writeHTTPError(ctx, err)
 		return
 	}
@@ -47,6 +67,41 @@ func (c *ModelController) CreateModel(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, model)
 }
 
+// GetLatestModelByName handles GET /v1/models/by-name/:name/latest
+func (c *ModelController) GetLatestModelByName(ctx *gin.Context) {
+	name := strings.TrimSpace(ctx.Param("name"))
+	if name == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	model, err := c.modelService.GetLatestByName(ctx.Request.Context(), name)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model)
+}
+
+// ListModelVersionsByName handles GET /v1/models/by-name/:name/versions
+// 按版本从新到旧返回同名模型的全部记录。
+func (c *ModelController) ListModelVersionsByName(ctx *gin.Context) {
+	name := strings.TrimSpace(ctx.Param("name"))
+	if name == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	result, err := c.modelService.ListVersionsByName(ctx.Request.Context(), name)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
 // GetAllModels handles GET /v1/models
 func (c *ModelController) GetAllModels(ctx *gin.Context) {
 	var params entity2.QueryParams
@@ -64,6 +119,46 @@ func (c *ModelController) GetAllModels(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, result)
 }
 
+// GetModel handles GET /v1/models/:id
+func (c *ModelController) GetModel(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	model, err := c.modelService.GetByID(ctx.Request.Context(), id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model)
+}
+
+// DeleteModel handles DELETE /v1/models/:id[?cascade=true&keep_file=true]
+// 有训练结果引用时默认返回 409，cascade=true 一并删除；权重文件仅在无其他模型引用时删除。
+func (c *ModelController) DeleteModel(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, err := parseArtifactDeleteOptions(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.modelService.DeleteByID(ctx.Request.Context(), id, opts)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
 // GetModelStorageServers handles GET /v1/models/:id/storage-server
 func (c *ModelController) GetModelStorageServers(ctx *gin.Context) {
 	id, err := parseUintPathParam(ctx, "id")
@@ -199,8 +294,14 @@ func (c *ModelController) UploadModelFile(ctx *gin.Context) {
 				errors.Is(err, service.ErrCoreServerNotFound),
 				errors.Is(err, service.ErrSSHServerPortInvalid),
 				errors.Is(err, service.ErrSSHFilePathRequired),
+				errors.Is(err, service.ErrSSHCredentialOverrideForbidden),
+				errors.Is(err, service.ErrCredentialNotFound),
+				errors.Is(err, service.ErrCredentialInvalid),
 				errors.Is(err, service.ErrInvalidStorageTarget):
 				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, service.ErrCoreServerOffline),
+				errors.Is(err, service.ErrVaultNotInitialized):
+				ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
 			case errors.Is(err, service.ErrRedisNotInitialized):
 				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			default:
@@ -243,21 +344,29 @@ func (c *ModelController) UploadModelFile(ctx *gin.Context) {
 		)
 	}
 
+	if result.BaiduReceipt != nil {
+		// 文件已上传成功，副本记录写入失败只记日志，仍返回上传回执。
+		if _, err := c.replicaService.RecordBaiduUpload(ctx.Request.Context(), entity2.ArtifactTypeModel, result.FileName, *result.BaiduReceipt); err != nil {
+			logger.Error("record baidu replica failed", "file_name", result.FileName, "error", err)
+		}
+	}
+
 	resp := gin.H{
-		"message":         "upload success",
-		"file_name":       result.FileName,
-		"resolved_path":   result.ResolvedPath,
-		"saved_path":      result.SavedPath,
-		"paths":           result.Paths,
-		"size":            result.Size,
-		"storage_server":  result.StorageServer,
-		"storage_target":  result.StorageTarget,
-		"upload_to_baidu": result.UploadToBaidu,
-		"baidu_uploaded":  result.BaiduUploaded,
-		"baidu_path":      result.BaiduPath,
-		"weight_size_mb":  weightSizeMB,
-		"mysql_updated":   affectedRows > 0,
-		"mysql_affected":  affectedRows,
+		"message":            "upload success",
+		"file_name":          result.FileName,
+		"resolved_path":      result.ResolvedPath,
+		"saved_path":         result.SavedPath,
+		"paths":              result.Paths,
+		"size":               result.Size,
+		"storage_server":     result.StorageServer,
+		"storage_target":     result.StorageTarget,
+		"upload_to_baidu":    result.UploadToBaidu,
+		"baidu_uploaded":     result.BaiduUploaded,
+		"baidu_path":         result.BaiduPath,
+		"baidu_verification": result.BaiduReceipt,
+		"weight_size_mb":     weightSizeMB,
+		"mysql_updated":      affectedRows > 0,
+		"mysql_affected":     affectedRows,
 	}
 
 	if coreTransfer != nil && coreServer != nil {
@@ -265,6 +374,7 @@ func (c *ModelController) UploadModelFile(ctx *gin.Context) {
 		resp["core_server_key"] = coreServer.Key
 		resp["core_server_ip"] = coreServer.IP
 		resp["core_server_port"] = coreServer.Port
+		resp["core_server_status"] = coreServer.Status
 		resp["core_remote_path"] = coreTransfer.TargetPath
 	} else {
 		resp["core_uploaded"] = false
@@ -273,6 +383,138 @@ func (c *ModelController) UploadModelFile(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, resp)
 }
 
+// GetModelReplicas handles GET /v1/models/:id/replicas
+// 返回权重文件在各存储上的副本及校验状态（verified/corrupt/unverified）。
+func (c *ModelController) GetModelReplicas(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	replicas, err := c.replicaService.ListByModelID(ctx.Request.Context(), id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"id": id, "replicas": replicas})
+}
+
+// GetModelTags handles GET /v1/models/:id/tags
+func (c *ModelController) GetModelTags(ctx *gin.Context) {
+	getArtifactTags(ctx, c.tagService, entity2.ArtifactTypeModel)
+}
+
+// AddModelTags handles POST /v1/models/:id/tags
+// 请求体 {"tags": [...], "labels": {"key": "value"}}，同一键的已有取值会被覆盖。
+func (c *ModelController) AddModelTags(ctx *gin.Context) {
+	addArtifactTags(ctx, c.tagService, entity2.ArtifactTypeModel)
+}
+
+// RemoveModelTags handles DELETE /v1/models/:id/tags?tag=...&label=...
+func (c *ModelController) RemoveModelTags(ctx *gin.Context) {
+	removeArtifactTags(ctx, c.tagService, entity2.ArtifactTypeModel)
+}
+
+// TransitionModelStage handles POST /v1/models/:id/transition
+// 变更生命周期阶段（none/staging/production/archived），同名模型至多一个 production 版本。
+func (c *ModelController) TransitionModelStage(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req service.ModelStageTransitionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.stageService.Transition(ctx.Request.Context(), id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidModelStage),
+			errors.Is(err, service.ErrInvalidStageTransition),
+			errors.Is(err, service.ErrStageTransitionNoActor):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, dao.ErrProductionVersionExists),
+			errors.Is(err, dao.ErrModelStageChanged):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			writeHTTPError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetModelStageHistory handles GET /v1/models/:id/stage-history
+func (c *ModelController) GetModelStageHistory(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := c.stageService.GetHistory(ctx.Request.Context(), id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
+// GetProductionModelByName handles GET /v1/models/by-name/:name/production
+// 部署脚本按名称拉取当前 production 版本，无需硬编码 id。
+func (c *ModelController) GetProductionModelByName(ctx *gin.Context) {
+	name := strings.TrimSpace(ctx.Param("name"))
+	if name == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	model, err := c.stageService.GetProductionByName(ctx.Request.Context(), name)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model)
+}
+
+// GetModelLineage handles GET /v1/models/:id/lineage?format=json|mermaid|dot
+// 返回祖先链与子孙树；format=mermaid/dot 时直接返回渲染文本，便于粘贴到文档或 graphviz。
+func (c *ModelController) GetModelLineage(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format, err := service.NormalizeLineageFormat(ctx.Query("format"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lineage, err := c.modelService.GetLineage(ctx.Request.Context(), id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	switch format {
+	case service.ModelLineageFormatMermaid:
+		ctx.String(http.StatusOK, lineage.Mermaid)
+	case service.ModelLineageFormatDOT:
+		ctx.String(http.StatusOK, lineage.DOT)
+	default:
+		ctx.JSON(http.StatusOK, lineage)
+	}
+}
+
 func (c *ModelController) uploadModelToCoreServer(ctx *gin.Context, coreServerKey, fileName, localPath string) (service.CoreServer, service.SSHTransferResult, error) {
 	logger := handlerLogger().With("controller", "ModelController", "method", "uploadModelToCoreServer")
 	if c.sshUploadSvc == nil {
@@ -290,34 +532,13 @@ func (c *ModelController) uploadModelToCoreServer(ctx *gin.Context, coreServerKe
 		logger.Error("resolve core server failed", "core_server_key", coreServerKey, "error", err)
 		return service.CoreServer{}, service.SSHTransferResult{}, err
 	}
-
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
+	if err := service.EnsureCoreServerAvailable(coreServer); err != nil {
+		logger.Warn("core server unavailable", "core_server_key", coreServer.Key, "status", coreServer.Status, "last_seen", coreServer.LastSeen)
 		return service.CoreServer{}, service.SSHTransferResult{}, err
 	}
-	privateKeyPath := strings.TrimSpace(ctx.PostForm("ssh_private_key_path"))
-	if privateKeyPath == "" {
-		privateKeyPath, err = resolveDefaultSSHPrivateKeyPath(homeDir)
-		if err != nil {
-			logger.Error("resolve ssh private key path failed", "error", err)
-			return service.CoreServer{}, service.SSHTransferResult{}, err
-		}
-	}
-	sshUser := strings.TrimSpace(ctx.PostForm("ssh_user"))
-	if sshUser == "" {
-		sshUser = service.DefaultSSHServerUser
-	}
 
-	if err := c.sshUploadSvc.SetServerConfig(coreServer.Key, service.SSHServerConfig{
-		Name:           coreServer.Key,
-		IP:             coreServer.IP,
-		Port:           coreServer.Port,
-		User:           sshUser,
-		PrivateKeyPath: privateKeyPath,
-	}); err != nil {
-		logger.Error("set ssh server config failed", "core_server_key", coreServer.Key, "error", err)
-		return service.CoreServer{}, service.SSHTransferResult{}, err
-	}
+	// 服务器已登记 credential_id 时只能使用凭据库中的凭据，表单指定私钥路径会被拒绝
+	credential := service.SSHCredential{User: ctx.PostForm("ssh_user"), PrivateKeyPath: ctx.PostForm("ssh_private_key_path")}
 
 	pathService := service.NewArtifactPathService()
 	remotePath, err := pathService.BuildPath(service.ArtifactCategoryWeights, service.StorageTargetOtherLocal, fileName)
@@ -331,11 +552,9 @@ func (c *ModelController) uploadModelToCoreServer(ctx *gin.Context, coreServerKe
 		"core_server_key", coreServer.Key,
 		"core_server_ip", coreServer.IP,
 		"core_server_port", coreServer.Port,
-		"ssh_user", sshUser,
-		"private_key_path", privateKeyPath,
 		"remote_path", remotePath,
 	)
-	transfer, err := c.sshUploadSvc.UploadFileByPathWithPort(localPath, remotePath, coreServer.Key, coreServer.Port)
+	transfer, err := c.sshUploadSvc.UploadFileByPathWithCredential(localPath, remotePath, coreServer.Key, coreServer.Port, credential)
 	if err != nil {
 		logger.Error("ssh upload failed", "core_server_key", coreServer.Key, "error", err)
 		return service.CoreServer{}, service.SSHTransferResult{}, err
@@ -532,27 +751,6 @@ func shouldAutoUploadToCoreByStorageServer(storageServer string) bool {
 	return true
 }
 
-func resolveDefaultSSHPrivateKeyPath(homeDir string) (string, error) {
-	sshDir := filepath.Join(homeDir, ".ssh")
-	candidates := []string{
-		filepath.Join(sshDir, "id_rsa"),
-		filepath.Join(sshDir, "id_ed25519"),
-	}
-
-	for _, path := range candidates {
-		info, err := os.Stat(path)
-		if err != nil {
-			continue
-		}
-		if info.IsDir() {
-			continue
-		}
-		return path, nil
-	}
-
-	return "", fmt.Errorf("no usable ssh private key found under %s (tried id_rsa, id_ed25519)", sshDir)
-}
-
 func pickFirstNonEmpty(values ...string) string {
 	for _, value := range values {
 		trimmed := strings.TrimSpace(value)
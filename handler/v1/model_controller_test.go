@@ -11,6 +11,7 @@ import (
 	"lucky_project/service"
 	"math"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -86,6 +87,107 @@ func TestModelAPI(t *testing.T) {
 		assert.Equal(t, model2.WeightName, resp2.WeightName)
 	})
 
+	t.Run("Create Model Auto Version And Resolve Latest", func(t *testing.T) {
+		name := fmt.Sprintf("AutoVersionModel_%d", time.Now().UnixNano())
+		create := func(bump string, weightName string) entity2.Model {
+			body, _ := json.Marshal(entity2.Model{
+				Name:         name,
+				Version:      9.99,
+				WeightName:   weightName,
+				WeightSizeMB: 1,
+				TaskType:     "detect",
+			})
+			w := performRequest(testRouter, "POST", "/v1/models?version_bump="+bump, bytes.NewBuffer(body))
+			assert.Equal(t, http.StatusCreated, w.Code)
+			var resp entity2.Model
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			return resp
+		}
+
+		first := create("major", "auto_v1.pt")
+		second := create("minor", "auto_v1_01.pt")
+		third := create("major", "auto_v2.pt")
+		assert.InDelta(t, 1.00, first.Version, 1e-9)
+		assert.InDelta(t, 1.01, second.Version, 1e-9)
+		assert.InDelta(t, 2.00, third.Version, 1e-9)
+
+		w := performRequest(testRouter, "GET", "/v1/models/by-name/"+name+"/latest", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var latest entity2.Model
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &latest))
+		assert.Equal(t, third.ID, latest.ID)
+
+		w = performRequest(testRouter, "GET", "/v1/models/by-name/"+name+"/versions", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var versions service.ModelVersionList
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &versions))
+		assert.Equal(t, 3, versions.Total)
+		assert.InDelta(t, 2.00, versions.LatestVersion, 1e-9)
+		if assert.Len(t, versions.Versions, 3) {
+			assert.Equal(t, first.ID, versions.Versions[2].ID)
+		}
+
+		w = performRequest(testRouter, "GET", "/v1/models/by-name/"+name+"_missing/latest", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = performRequest(testRouter, "POST", "/v1/models?version_bump=patch", bytes.NewBufferString(`{"name":"x","weight_name":"x.pt"}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Model Stage Promotion Workflow", func(t *testing.T) {
+		name := fmt.Sprintf("StageModel_%d", time.Now().UnixNano())
+		register := func(version float64) entity2.Model {
+			body, _ := json.Marshal(entity2.Model{Name: name, Version: version, WeightName: "stage.pt", WeightSizeMB: 1, TaskType: "detect"})
+			w := performRequest(testRouter, "POST", "/v1/models", bytes.NewBuffer(body))
+			assert.Equal(t, http.StatusCreated, w.Code)
+			var resp entity2.Model
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			return resp
+		}
+		transition := func(id uint, payload string) *httptest.ResponseRecorder {
+			return performRequest(testRouter, "POST", fmt.Sprintf("/v1/models/%d/transition", id), bytes.NewBufferString(payload))
+		}
+
+		v1 := register(1.00)
+		v2 := register(2.00)
+
+		w := transition(v1.ID, `{"stage":"production","actor":"alice","reason":"passed eval"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = performRequest(testRouter, "GET", "/v1/models/by-name/"+name+"/production", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var production entity2.Model
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &production))
+		assert.Equal(t, v1.ID, production.ID)
+
+		w = transition(v2.ID, `{"stage":"production","actor":"bob"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = transition(v2.ID, `{"stage":"production","actor":"bob","reason":"better mAP","archive_existing":true}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var result service.ModelStageTransitionResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, entity2.ModelStageNone, result.FromStage)
+		if assert.Len(t, result.Archived, 1) {
+			assert.Equal(t, v1.ID, result.Archived[0].ModelID)
+		}
+
+		w = transition(v1.ID, `{"stage":"production","actor":"bob"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = transition(v1.ID, `{"stage":"staging"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/models/%d/stage-history", v1.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var history service.ModelStageHistory
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+		assert.Equal(t, entity2.ModelStageArchived, history.Stage)
+		if assert.Len(t, history.History, 2) {
+			assert.Equal(t, "bob", history.History[0].Actor)
+			assert.Equal(t, "alice", history.History[1].Actor)
+		}
+	})
+
 	t.Run("Update Model Metadata", func(t *testing.T) {
 		algorithmID := "algo_before_update"
 		model := entity2.Model{
@@ -218,6 +320,60 @@ func TestModelAPI(t *testing.T) {
 		assert.True(t, result.Total >= 1)
 	})
 
+	t.Run("Tag Models And Filter By Tags", func(t *testing.T) {
+		suffix := time.Now().UnixNano()
+		register := func(name string) entity2.Model {
+			body, _ := json.Marshal(entity2.Model{Name: name, Version: 1.00, WeightName: "tag.pt", WeightSizeMB: 1, TaskType: "detect"})
+			w := performRequest(testRouter, "POST", "/v1/models", bytes.NewBuffer(body))
+			assert.Equal(t, http.StatusCreated, w.Code)
+			var resp entity2.Model
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			return resp
+		}
+		helmetTag := fmt.Sprintf("helmet-%d", suffix)
+		nightTag := fmt.Sprintf("night-%d", suffix)
+		a := register(fmt.Sprintf("TagModelA_%d", suffix))
+		b := register(fmt.Sprintf("TagModelB_%d", suffix))
+
+		w := performRequest(testRouter, "POST", fmt.Sprintf("/v1/models/%d/tags", a.ID),
+			bytes.NewBufferString(fmt.Sprintf(`{"tags":["%s","%s"],"labels":{"env":"dev"}}`, helmetTag, nightTag)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(testRouter, "POST", fmt.Sprintf("/v1/models/%d/tags", a.ID), bytes.NewBufferString(`{"labels":{"env":"prod"}}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var tags service.ArtifactTags
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &tags))
+		assert.ElementsMatch(t, []string{helmetTag, nightTag}, tags.Tags)
+		assert.Equal(t, map[string]string{"env": "prod"}, tags.Labels)
+
+		w = performRequest(testRouter, "POST", fmt.Sprintf("/v1/models/%d/tags", b.ID), bytes.NewBufferString(fmt.Sprintf(`{"tags":["%s"]}`, helmetTag)))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		count := func(query string) int64 {
+			w := performRequest(testRouter, "GET", "/v1/models?"+query, nil)
+			assert.Equal(t, http.StatusOK, w.Code)
+			var result entity2.PageResult
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			return result.Total
+		}
+		assert.Equal(t, int64(2), count("tag="+helmetTag))
+		assert.Equal(t, int64(1), count(fmt.Sprintf("tag=%s&tag=%s", helmetTag, nightTag)))
+		assert.Equal(t, int64(2), count(fmt.Sprintf("tag=%s,%s&tag_match=any", helmetTag, nightTag)))
+		assert.Equal(t, int64(1), count(fmt.Sprintf("tag=%s&label=env=prod", helmetTag)))
+		assert.Equal(t, int64(0), count(fmt.Sprintf("tag=%s&label=env=dev", helmetTag)))
+
+		w = performRequest(testRouter, "GET", "/v1/models?tag_match=some", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(testRouter, "DELETE", fmt.Sprintf("/v1/models/%d/tags?tag=%s&label=env", a.ID, nightTag), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &tags))
+		assert.Equal(t, []string{helmetTag}, tags.Tags)
+		assert.Empty(t, tags.Labels)
+
+		w = performRequest(testRouter, "POST", "/v1/models/999999999/tags", bytes.NewBufferString(`{"tags":["x"]}`))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
 	// 4. 测试排序
 	t.Run("Sort Models", func(t *testing.T) {
 		w := performRequest(testRouter, "GET", "/v1/models?size_sort=desc", nil)
@@ -441,6 +597,45 @@ func TestModelAPI(t *testing.T) {
 		})
 	})
 
+	t.Run("Get And Delete Model By ID", func(t *testing.T) {
+		weightName := fmt.Sprintf("delete_by_id_%d.pt", time.Now().UnixNano())
+		body, _ := json.Marshal(entity2.Model{Name: fmt.Sprintf("DeleteByID_%d", time.Now().UnixNano()), Version: 1.00, WeightName: weightName, WeightSizeMB: 1, TaskType: "detect"})
+		w := performRequest(testRouter, "POST", "/v1/models", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created entity2.Model
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		path := fmt.Sprintf("/v1/models/%d", created.ID)
+
+		w = performRequest(testRouter, "GET", path, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		resultBody, _ := json.Marshal(entity2.ModelTrainingResult{ModelID: created.ID, DatasetID: 1, TrainingStatus: 2})
+		w = performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(resultBody))
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		localPath := filepath.Join(service.DefaultBackendWeightsRoot, weightName)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0o755))
+		assert.NoError(t, os.WriteFile(localPath, []byte("to-delete"), 0o644))
+
+		w = performRequest(testRouter, "DELETE", path, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		w = performRequest(testRouter, "DELETE", path+"?cascade=maybe", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(testRouter, "DELETE", path+"?cascade=true", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var result service.ArtifactDeleteResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, int64(1), result.DeletedTrainingResults)
+		assert.True(t, result.LocalFileDeleted)
+		assert.NoFileExists(t, localPath)
+
+		w = performRequest(testRouter, "GET", path, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		w = performRequest(testRouter, "DELETE", path, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
 	t.Run("Delete Model By FileName", func(t *testing.T) {
 		algorithmID := "delete_file_algo"
 		weightName := fmt.Sprintf("delete_by_name_%d.pt", time.Now().UnixNano())
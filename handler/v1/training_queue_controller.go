@@ -0,0 +1,127 @@
+package v1
+
+import (
+	"errors"
+	"lucky_project/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrainingQueueController struct {
+	queueService *service.TrainingQueueService
+}
+
+func NewTrainingQueueController() *TrainingQueueController {
+	return &TrainingQueueController{
+		queueService: service.DefaultTrainingQueueService(),
+	}
+}
+
+// EnqueueTrainingJob handles POST /v1/training-queue
+// 请求体为 launch 参数加 priority/gpu_count/gpu_memory_mb/max_attempts，由调度器派发到有空闲资源的核心服务器。
+func (c *TrainingQueueController) EnqueueTrainingJob(ctx *gin.Context) {
+	var req service.EnqueueTrainingJobRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := c.queueService.Enqueue(ctx.Request.Context(), req)
+	if err != nil {
+		writeTrainingQueueError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, job)
+}
+
+// ListTrainingJobs handles GET /v1/training-queue
+// 可选 query: status(queued/dispatching/running/finished/failed/cancelled)
+func (c *TrainingQueueController) ListTrainingJobs(ctx *gin.Context) {
+	jobs, err := c.queueService.List(ctx.Request.Context(), ctx.Query("status"))
+	if err != nil {
+		writeTrainingQueueError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, jobs)
+}
+
+// GetTrainingJob handles GET /v1/training-queue/:id
+func (c *TrainingQueueController) GetTrainingJob(ctx *gin.Context) {
+	id, err := parseTrainingQueueJobID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := c.queueService.Get(ctx.Request.Context(), id)
+	if err != nil {
+		writeTrainingQueueError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+// CancelTrainingJob handles POST /v1/training-queue/:id/cancel
+// 排队中的任务直接出队；运行中的任务会结束远程训练进程并将训练结果置为中断。
+func (c *TrainingQueueController) CancelTrainingJob(ctx *gin.Context) {
+	id, err := parseTrainingQueueJobID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := c.queueService.Cancel(ctx.Request.Context(), id)
+	if err != nil {
+		writeTrainingQueueError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+// DispatchTrainingJobs handles POST /v1/training-queue/dispatch
+// 立即执行一轮调度并返回本轮报告；其他实例正在调度时返回 409。
+func (c *TrainingQueueController) DispatchTrainingJobs(ctx *gin.Context) {
+	report, err := c.queueService.RunOnce(ctx.Request.Context())
+	if err != nil {
+		writeTrainingQueueError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+func parseTrainingQueueJobID(ctx *gin.Context) (int64, error) {
+	value, err := strconv.ParseInt(strings.TrimSpace(ctx.Param("id")), 10, 64)
+	if err != nil || value <= 0 {
+		return 0, errors.New("id must be a positive integer")
+	}
+	return value, nil
+}
+
+func writeTrainingQueueError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTrainingQueueJob),
+		errors.Is(err, service.ErrInvalidTrainingLaunch),
+		errors.Is(err, service.ErrCoreServerKeyRequired),
+		errors.Is(err, service.ErrCoreServerNotFound),
+		errors.Is(err, service.ErrSSHCredentialOverrideForbidden):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTrainingQueueJobNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTrainingQueueJobNotCancellable),
+		errors.Is(err, service.ErrTrainingQueueDispatching):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRedisNotInitialized),
+		errors.Is(err, service.ErrTrainingQueueNotReady):
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		writeHTTPError(ctx, err)
+	}
+}
@@ -0,0 +1,29 @@
+package v1_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrainingQueueAPIValidation(t *testing.T) {
+	w := performRequest(testRouter, http.MethodPost, "/v1/training-queue", bytes.NewBufferString(`{"model_id":"x"}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(testRouter, http.MethodPost, "/v1/training-queue", bytes.NewBufferString(`{"model_id":1}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(testRouter, http.MethodPost, "/v1/training-queue", bytes.NewBufferString(`{"model_id":1,"dataset_id":1,"priority":101}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(testRouter, http.MethodPost, "/v1/training-queue", bytes.NewBufferString(`{"model_id":1,"dataset_id":1,"gpu_count":-1}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(testRouter, http.MethodGet, "/v1/training-queue/abc", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(testRouter, http.MethodPost, "/v1/training-queue/0/cancel", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
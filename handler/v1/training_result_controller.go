@@ -1,20 +1,43 @@
 package v1
 
 import (
+	"context"
+	"errors"
+	"io"
 	entity2 "lucky_project/entity"
 	"lucky_project/service"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	defaultTrainingLogStreamLines = 50
+	trainingLogKeepAliveInterval  = 15 * time.Second
+)
+
 type TrainingResultController struct {
 	trainingService *service.TrainingResultService
+	metricService   *service.TrainingMetricService
+	ingestService   *service.UltralyticsIngestService
+	registerService *service.TrainedModelRegistrationService
+	launchService   *service.TrainingLaunchService
+	logService      *service.TrainingLogService
 }
 
 func NewTrainingResultController() *TrainingResultController {
+	sshSvc := service.NewSSHArtifactTransferService()
 	return &TrainingResultController{
 		trainingService: service.NewTrainingResultService(),
+		metricService:   service.NewTrainingMetricService(),
+		ingestService:   service.NewUltralyticsIngestService(),
+		registerService: service.NewTrainedModelRegistrationService(sshSvc),
+		launchService:   service.NewTrainingLaunchService(sshSvc),
+		logService:      service.NewTrainingLogService(sshSvc),
 	}
 }
 
@@ -27,7 +50,7 @@ func (c *TrainingResultController) CreateTrainingResult(ctx *gin.Context) {
 	}
 
 	if err := c.trainingService.CreateTrainingResult(ctx.Request.Context(), &result); err != nil {
-		writeHTTPError(ctx, err)
+		writeTrainingResultError(ctx, err)
 		return
 	}
 
@@ -50,3 +73,532 @@ func (c *TrainingResultController) GetAllResults(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, result)
 }
+
+// GetTrainingResult handles GET /v1/training-results/:id
+func (c *TrainingResultController) GetTrainingResult(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.trainingService.GetByID(ctx.Request.Context(), id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// UpdateTrainingResult handles PATCH /v1/training-results/:id
+// 变更 training_status 时校验状态机，并补记开始/结束时间；终态记录不能再更新 metric_detail。
+func (c *TrainingResultController) UpdateTrainingResult(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var payload map[string]interface{}
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updates, err := parseTrainingResultUpdates(payload)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.trainingService.UpdateTrainingResult(ctx.Request.Context(), id, updates)
+	if err != nil {
+		writeTrainingResultError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeleteTrainingResult handles DELETE /v1/training-results/:id
+func (c *TrainingResultController) DeleteTrainingResult(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.trainingService.DeleteByID(ctx.Request.Context(), id); err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "delete success", "id": id})
+}
+
+// StartTrainingResult handles POST /v1/training-results/:id/start
+func (c *TrainingResultController) StartTrainingResult(ctx *gin.Context) {
+	c.applyTrainingAction(ctx, service.TrainingActionStart)
+}
+
+// FinishTrainingResult handles POST /v1/training-results/:id/finish
+func (c *TrainingResultController) FinishTrainingResult(ctx *gin.Context) {
+	c.applyTrainingAction(ctx, service.TrainingActionFinish)
+}
+
+// FailTrainingResult handles POST /v1/training-results/:id/fail
+func (c *TrainingResultController) FailTrainingResult(ctx *gin.Context) {
+	c.applyTrainingAction(ctx, service.TrainingActionFail)
+}
+
+// InterruptTrainingResult handles POST /v1/training-results/:id/interrupt
+func (c *TrainingResultController) InterruptTrainingResult(ctx *gin.Context) {
+	c.applyTrainingAction(ctx, service.TrainingActionInterrupt)
+}
+
+// applyTrainingAction 请求体可选，可同时写入 metric_detail / weight_path / comet_log_url。
+func (c *TrainingResultController) applyTrainingAction(ctx *gin.Context, action string) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req service.TrainingActionRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := c.trainingService.ApplyTrainingAction(ctx.Request.Context(), id, action, req)
+	if err != nil {
+		writeTrainingResultError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// IngestTrainingMetrics handles POST /v1/training-results/:id/metrics
+func (c *TrainingResultController) IngestTrainingMetrics(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var batch service.TrainingMetricBatch
+	if err := ctx.ShouldBindJSON(&batch); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.metricService.Ingest(ctx.Request.Context(), id, batch)
+	if err != nil {
+		writeTrainingResultError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetTrainingMetrics handles GET /v1/training-results/:id/metrics?names=a,b&max_points=500
+func (c *TrainingResultController) GetTrainingMetrics(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	maxPoints := 0
+	if raw := strings.TrimSpace(ctx.Query("max_points")); raw != "" {
+		maxPoints, err = strconv.Atoi(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidMaxPoints.Error()})
+			return
+		}
+	}
+
+	result, err := c.metricService.GetSeries(ctx.Request.Context(), id, ctx.QueryArray("names"), maxPoints)
+	if err != nil {
+		writeTrainingResultError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CompareTrainingResults handles GET /v1/training-results/compare
+// query: ids（2~20 个，逗号分隔或重复传参）、only_changed（默认 false，仅返回取值不同的行）
+func (c *TrainingResultController) CompareTrainingResults(ctx *gin.Context) {
+	ids, err := service.ParseTrainingCompareIDs(ctx.QueryArray("ids"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	onlyChanged, err := parseOptionalBoolQuery(ctx, "only_changed", false)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comparison, err := c.trainingService.CompareResults(ctx.Request.Context(), ids, onlyChanged)
+	if err != nil {
+		writeTrainingResultError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, comparison)
+}
+
+// GetTrainingHyperparameters handles GET /v1/training-results/:id/hyperparameters
+func (c *TrainingResultController) GetTrainingHyperparameters(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.trainingService.GetHyperparameters(ctx.Request.Context(), id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// IngestUltralyticsFiles handles POST /v1/training-results/:id/ultralytics
+// multipart 字段 results（results.csv）与 args（args.yaml）至少传一个。
+func (c *TrainingResultController) IngestUltralyticsFiles(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resultsFile, err := openOptionalFormFile(ctx, "results")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if resultsFile != nil {
+		defer resultsFile.Close()
+	}
+	argsFile, err := openOptionalFormFile(ctx, "args")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if argsFile != nil {
+		defer argsFile.Close()
+	}
+
+	result, err := c.ingestService.Ingest(ctx.Request.Context(), id, resultsFile, argsFile)
+	if err != nil {
+		writeTrainingResultError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// RegisterTrainedModel handles POST /v1/training-results/:id/register-model
+// 将成功训练（training_status=2）产出的权重注册为训练所用模型的新版本；core_server_key 为空时从后端读取 weight_path。
+func (c *TrainingResultController) RegisterTrainedModel(ctx *gin.Context) {
+	logger := handlerLogger().With("controller", "TrainingResultController", "method", "RegisterTrainedModel")
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req service.RegisterTrainedModelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var coreServer *service.CoreServer
+	if strings.TrimSpace(req.CoreServerKey) != "" {
+		server, err := lookupAvailableCoreServer(ctx, req.CoreServerKey)
+		if err != nil {
+			logger.Error("resolve core server failed", "core_server_key", req.CoreServerKey, "error", err)
+			writeRemoteTrainingError(ctx, err)
+			return
+		}
+		coreServer = &server
+	}
+
+	result, err := c.registerService.Register(ctx.Request.Context(), id, req, coreServer)
+	if err != nil {
+		logger.Error("register trained model failed", "training_result_id", id, "error", err)
+		writeRemoteTrainingError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// GetRegisteredModel handles GET /v1/training-results/:id/registered-model
+func (c *TrainingResultController) GetRegisteredModel(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.registerService.GetRegisteredModel(ctx.Request.Context(), id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// LaunchTraining handles POST /v1/training-results/launch
+// 在核心服务器上启动训练：确认权重与数据集已在 other_local 目录（缺失时从后端传输），按配置的命令模板后台启动，
+// 并登记一条 running 状态的训练结果。
+func (c *TrainingResultController) LaunchTraining(ctx *gin.Context) {
+	logger := handlerLogger().With("controller", "TrainingResultController", "method", "LaunchTraining")
+	var req service.LaunchTrainingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.CoreServerKey) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": service.ErrCoreServerKeyRequired.Error()})
+		return
+	}
+
+	server, err := lookupAvailableCoreServer(ctx, req.CoreServerKey)
+	if err != nil {
+		logger.Error("resolve core server failed", "core_server_key", req.CoreServerKey, "error", err)
+		writeRemoteTrainingError(ctx, err)
+		return
+	}
+
+	result, err := c.launchService.Launch(ctx.Request.Context(), req, server)
+	if err != nil {
+		logger.Error("launch training failed", "core_server_key", server.Key, "model_id", req.ModelID, "dataset_id", req.DatasetID, "error", err)
+		writeRemoteTrainingError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// GetTrainingJob handles GET /v1/training-results/:id/job
+func (c *TrainingResultController) GetTrainingJob(ctx *gin.Context) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := c.launchService.GetJob(ctx.Request.Context(), id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+// GetTrainingLogs handles GET /v1/training-results/:id/logs?lines=100
+// 通过 SSH 读取训练日志最后 N 行，使用核心服务器已登记的登录凭据。
+func (c *TrainingResultController) GetTrainingLogs(ctx *gin.Context) {
+	logger := handlerLogger().With("controller", "TrainingResultController", "method", "GetTrainingLogs")
+	source, server, lines, ok := c.resolveTrainingLogSource(ctx, service.DefaultTrainingLogTailLines)
+	if !ok {
+		return
+	}
+
+	result, err := c.logService.Tail(source, lines, server.Port)
+	if err != nil {
+		logger.Error("tail training log failed", "training_result_id", source.TrainingResultID, "core_server_key", server.Key, "error", err)
+		writeRemoteTrainingError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// StreamTrainingLogs handles GET /v1/training-results/:id/logs/stream?lines=50
+// 以 SSE 转发训练日志：先发送 source 事件，再逐行发送 log 事件；训练进程退出后发送 end 事件，
+// 读取失败时发送 error 事件。连接建立前的错误仍以 JSON 返回。
+func (c *TrainingResultController) StreamTrainingLogs(ctx *gin.Context) {
+	logger := handlerLogger().With("controller", "TrainingResultController", "method", "StreamTrainingLogs")
+	source, server, lines, ok := c.resolveTrainingLogSource(ctx, defaultTrainingLogStreamLines)
+	if !ok {
+		return
+	}
+
+	requestCtx := ctx.Request.Context()
+	streamCtx, cancel := context.WithCancel(requestCtx)
+	defer cancel()
+	lineCh := make(chan string, 256)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.logService.Follow(streamCtx, source, lines, server.Port, func(line string) error {
+			select {
+			case lineCh <- line:
+				return nil
+			case <-streamCtx.Done():
+				return streamCtx.Err()
+			}
+		})
+	}()
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.SSEvent("source", source)
+	ctx.Writer.Flush()
+
+	keepAlive := time.NewTicker(trainingLogKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case line := <-lineCh:
+			ctx.SSEvent("log", line)
+			ctx.Writer.Flush()
+		case err := <-errCh:
+			// Follow 返回后不会再有新行，先把缓冲中的行发完
+			for len(lineCh) > 0 {
+				ctx.SSEvent("log", <-lineCh)
+			}
+			if err != nil {
+				if requestCtx.Err() != nil {
+					return
+				}
+				logger.Error("stream training log failed", "training_result_id", source.TrainingResultID, "core_server_key", server.Key, "error", err)
+				ctx.SSEvent("error", gin.H{"error": err.Error()})
+			} else {
+				ctx.SSEvent("end", gin.H{"reason": "training process exited"})
+			}
+			ctx.Writer.Flush()
+			return
+		case <-keepAlive.C:
+			_, _ = io.WriteString(ctx.Writer, ": keep-alive\n\n")
+			ctx.Writer.Flush()
+		case <-requestCtx.Done():
+			return
+		}
+	}
+}
+
+// resolveTrainingLogSource 解析 id/lines 参数并定位日志所在的核心服务器，失败时已写入响应。
+// 读日志是只读的 GET 请求，只使用注册表登记的登录凭据（credential_id 或默认凭据），不接受请求指定凭据。
+func (c *TrainingResultController) resolveTrainingLogSource(ctx *gin.Context, defaultLines int) (service.TrainingLogSource, service.CoreServer, int, bool) {
+	id, err := parseUintPathParam(ctx, "id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return service.TrainingLogSource{}, service.CoreServer{}, 0, false
+	}
+	lines := defaultLines
+	if raw := strings.TrimSpace(ctx.Query("lines")); raw != "" {
+		if lines, err = strconv.Atoi(raw); err != nil {
+			lines = -1
+		}
+	}
+	if err := service.ValidateTrainingLogLines(lines); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return service.TrainingLogSource{}, service.CoreServer{}, 0, false
+	}
+
+	source, err := c.logService.GetSource(ctx.Request.Context(), id)
+	if err != nil {
+		writeHTTPError(ctx, err)
+		return service.TrainingLogSource{}, service.CoreServer{}, 0, false
+	}
+	server, err := lookupAvailableCoreServer(ctx, source.CoreServerKey)
+	if err != nil {
+		writeRemoteTrainingError(ctx, err)
+		return service.TrainingLogSource{}, service.CoreServer{}, 0, false
+	}
+	return source, server, lines, true
+}
+
+// lookupAvailableCoreServer 解析核心服务器，离线服务器返回 ErrCoreServerOffline。
+func lookupAvailableCoreServer(ctx *gin.Context, key string) (service.CoreServer, error) {
+	server, err := service.GetCoreServerByKey(ctx.Request.Context(), strings.TrimSpace(key))
+	if err != nil {
+		return service.CoreServer{}, err
+	}
+	if err := service.EnsureCoreServerAvailable(server); err != nil {
+		return service.CoreServer{}, err
+	}
+	return server, nil
+}
+
+// writeRemoteTrainingError 映射注册训练产出、远程启动训练、读取训练日志中与核心服务器交互相关的错误。
+func writeRemoteTrainingError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTrainingResultNotRegistrable),
+		errors.Is(err, service.ErrInvalidTrainedWeightPath),
+		errors.Is(err, service.ErrInvalidTrainingLaunch),
+		errors.Is(err, service.ErrLocalSourceFileNotFound),
+		errors.Is(err, service.ErrLocalSourcePathNotRegularFile),
+		errors.Is(err, service.ErrInvalidVersionBump),
+		errors.Is(err, service.ErrModelVersionOverflow),
+		errors.Is(err, service.ErrInvalidStorageTarget),
+		errors.Is(err, service.ErrInvalidUploadFile),
+		errors.Is(err, service.ErrCoreServerKeyRequired),
+		errors.Is(err, service.ErrCoreServerNotFound),
+		errors.Is(err, service.ErrSSHServerNotRegistered),
+		errors.Is(err, service.ErrSSHServerPortInvalid),
+		errors.Is(err, service.ErrSSHFilePathRequired),
+		errors.Is(err, service.ErrSSHCredentialOverrideForbidden),
+		errors.Is(err, service.ErrCredentialNotFound),
+		errors.Is(err, service.ErrCredentialInvalid),
+		errors.Is(err, service.ErrInvalidTrainingLogLines):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRemoteArtifactNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRemoteCommandFailed):
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCoreServerOffline),
+		errors.Is(err, service.ErrVaultNotInitialized):
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		writeHTTPError(ctx, err)
+	}
+}
+
+// openOptionalFormFile 打开 multipart 文件字段，字段缺失时返回 nil。
+func openOptionalFormFile(ctx *gin.Context, field string) (multipart.File, error) {
+	header, err := ctx.FormFile(field)
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return header.Open()
+}
+
+func writeTrainingResultError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTrainingStatus),
+		errors.Is(err, service.ErrIllegalTrainingTransition),
+		errors.Is(err, service.ErrTrainingResultFinalized),
+		errors.Is(err, service.ErrInvalidMetricPoint),
+		errors.Is(err, service.ErrInvalidMaxPoints),
+		errors.Is(err, service.ErrInvalidUltralyticsFile),
+		errors.Is(err, service.ErrInvalidHyperparameters),
+		errors.Is(err, service.ErrInvalidTrainingCompare):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUltralyticsFileTooLarge):
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	default:
+		writeHTTPError(ctx, err)
+	}
+}
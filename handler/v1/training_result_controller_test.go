@@ -2,10 +2,18 @@ package v1_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"lucky_project/dao"
 	entity2 "lucky_project/entity"
+	"lucky_project/service"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -43,4 +51,374 @@ func TestTrainingResultAPI(t *testing.T) {
 		json.Unmarshal(w.Body.Bytes(), &result)
 		assert.True(t, result.Total >= 1)
 	})
+
+	t.Run("Filter And Sort Training Results By Metric", func(t *testing.T) {
+		datasetID := uint(time.Now().UnixNano() % 1000000000)
+		start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+		for i, detail := range []string{`{"mAP50":0.55}`, `{"mAP50":0.72}`, `{"mAP50":0.64}`, `{"note":"no metric"}`} {
+			end := start.Add(time.Duration(i+1) * time.Hour)
+			body, _ := json.Marshal(entity2.ModelTrainingResult{
+				ModelID:        1,
+				DatasetID:      datasetID,
+				TrainingStatus: entity2.TrainingStatusSuccess,
+				MetricDetail:   json.RawMessage(detail),
+				TrainStartTime: &start,
+				TrainEndTime:   &end,
+			})
+			w := performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+			assert.Equal(t, http.StatusCreated, w.Code)
+		}
+
+		base := fmt.Sprintf("/v1/training-results?training_dataset_id=%d", datasetID)
+		w := performRequest(testRouter, "GET", base+"&metric="+url.QueryEscape("mAP50>=0.6")+"&sort_by=metric.mAP50&sort_order=desc", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var page struct {
+			Total int64                         `json:"total"`
+			List  []entity2.ModelTrainingResult `json:"list"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, int64(2), page.Total)
+		if assert.Len(t, page.List, 2) {
+			assert.JSONEq(t, `{"mAP50":0.72}`, string(page.List[0].MetricDetail))
+			if assert.NotNil(t, page.List[0].DurationSeconds) {
+				assert.Equal(t, int64(7200), *page.List[0].DurationSeconds)
+			}
+		}
+
+		w = performRequest(testRouter, "GET", base+"&sort_by=duration&sort_order=asc&min_duration_seconds=7200", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, int64(3), page.Total)
+		if assert.NotEmpty(t, page.List) {
+			assert.Equal(t, int64(7200), *page.List[0].DurationSeconds)
+		}
+
+		w = performRequest(testRouter, "GET", base+"&train_end_from=2026-10-01T10:30:00Z", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, int64(2), page.Total)
+
+		for _, query := range []string{"&metric=" + url.QueryEscape(`mAP50"))>0`), "&sort_by=name", "&sort_order=up", "&train_start_from=yesterday"} {
+			w = performRequest(testRouter, "GET", base+query, nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("Get Update Delete Training Result By ID", func(t *testing.T) {
+		body, _ := json.Marshal(entity2.ModelTrainingResult{ModelID: 1, DatasetID: 1, TrainingStatus: 1})
+		w := performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created entity2.ModelTrainingResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		path := fmt.Sprintf("/v1/training-results/%d", created.ID)
+
+		w = performRequest(testRouter, "GET", path, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = performRequest(testRouter, "PATCH", path, bytes.NewBufferString(`{"training_status":2,"metric_detail":{"mAP50":0.9},"train_end_time":"2026-01-02T03:04:05Z"}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var updated entity2.ModelTrainingResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+		assert.Equal(t, int8(2), updated.TrainingStatus)
+		assert.JSONEq(t, `{"mAP50":0.9}`, string(updated.MetricDetail))
+		assert.NotNil(t, updated.TrainEndTime)
+
+		w = performRequest(testRouter, "PATCH", path, bytes.NewBufferString(`{"id":5}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(testRouter, "PATCH", path, bytes.NewBufferString(`{"training_status":9}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(testRouter, "DELETE", path, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(testRouter, "GET", path, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		w = performRequest(testRouter, "DELETE", path, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Delete Running Training Result With Job", func(t *testing.T) {
+		body, _ := json.Marshal(entity2.ModelTrainingResult{ModelID: 1, DatasetID: 1, TrainingStatus: entity2.TrainingStatusRunning})
+		w := performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created entity2.ModelTrainingResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		path := fmt.Sprintf("/v1/training-results/%d", created.ID)
+		assert.NoError(t, dao.NewTrainingJobDAO().Create(context.Background(), &entity2.TrainingJob{
+			TrainingResultID: created.ID,
+			CoreServerKey:    "gpu-delete-test",
+			PID:              4242,
+			RunDir:           "/runs/delete-test",
+		}))
+
+		w = performRequest(testRouter, "DELETE", path, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		w = performRequest(testRouter, "GET", path+"/job", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = performRequest(testRouter, "POST", path+"/interrupt", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(testRouter, "DELETE", path, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(testRouter, "GET", path+"/job", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Training Result Lifecycle Actions", func(t *testing.T) {
+		body, _ := json.Marshal(entity2.ModelTrainingResult{ModelID: 1, DatasetID: 1})
+		w := performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created entity2.ModelTrainingResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.Equal(t, entity2.TrainingStatusPending, created.TrainingStatus)
+		path := fmt.Sprintf("/v1/training-results/%d", created.ID)
+
+		w = performRequest(testRouter, "POST", path+"/finish", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(testRouter, "POST", path+"/start", bytes.NewBufferString(`{"comet_log_url":"https://comet.ml/exp/1"}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var started entity2.ModelTrainingResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+		assert.Equal(t, entity2.TrainingStatusRunning, started.TrainingStatus)
+		assert.NotNil(t, started.TrainStartTime)
+		assert.Equal(t, "https://comet.ml/exp/1", started.CometLogURL)
+
+		w = performRequest(testRouter, "POST", path+"/finish", bytes.NewBufferString(`{"metric_detail":{"mAP50":0.91},"weight_path":"/data/best.pt"}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var finished entity2.ModelTrainingResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &finished))
+		assert.Equal(t, entity2.TrainingStatusSuccess, finished.TrainingStatus)
+		assert.NotNil(t, finished.TrainEndTime)
+
+		w = performRequest(testRouter, "PATCH", path, bytes.NewBufferString(`{"metric_detail":{"mAP50":0.99}}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(testRouter, "POST", path+"/interrupt", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("Ingest And Query Training Metrics", func(t *testing.T) {
+		body, _ := json.Marshal(entity2.ModelTrainingResult{ModelID: 1, DatasetID: 1, TrainingStatus: entity2.TrainingStatusRunning})
+		w := performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created entity2.ModelTrainingResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		path := fmt.Sprintf("/v1/training-results/%d", created.ID)
+
+		points := make([]map[string]interface{}, 0, 20)
+		for epoch := 0; epoch < 10; epoch++ {
+			points = append(points,
+				map[string]interface{}{"name": "train/box_loss", "epoch": epoch, "value": 1.0 / float64(epoch+1)},
+				map[string]interface{}{"name": "metrics/mAP50(B)", "epoch": epoch, "value": float64(epoch) / 10},
+			)
+		}
+		body, _ = json.Marshal(map[string]interface{}{"points": points})
+		w = performRequest(testRouter, "POST", path+"/metrics", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = performRequest(testRouter, "POST", path+"/metrics", bytes.NewBufferString(`{"points":[{"name":"train/box_loss","value":1}]}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(testRouter, "GET", path+"/metrics?names=train/box_loss&max_points=4", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var series service.TrainingMetricSeries
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &series))
+		if assert.Len(t, series.Series, 1) {
+			assert.Equal(t, 10, series.Series[0].TotalPoints)
+			assert.True(t, series.Series[0].Downsampled)
+			assert.Len(t, series.Series[0].Points, 4)
+		}
+
+		w = performRequest(testRouter, "GET", path+"/metrics", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &series))
+		assert.Len(t, series.Series, 2)
+
+		w = performRequest(testRouter, "POST", path+"/finish", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(testRouter, "POST", path+"/metrics", bytes.NewBufferString(`{"points":[{"name":"train/box_loss","step":11,"value":0.1}]}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("Ingest Ultralytics Results And Args", func(t *testing.T) {
+		body, _ := json.Marshal(entity2.ModelTrainingResult{ModelID: 1, DatasetID: 1, MetricDetail: json.RawMessage(`{"note":"manual"}`)})
+		w := performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created entity2.ModelTrainingResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		path := fmt.Sprintf("/v1/training-results/%d/ultralytics", created.ID)
+
+		dir := t.TempDir()
+		resultsPath := filepath.Join(dir, "results.csv")
+		argsPath := filepath.Join(dir, "args.yaml")
+		assert.NoError(t, os.WriteFile(resultsPath, []byte("epoch,train/box_loss,metrics/precision(B),metrics/recall(B),metrics/mAP50(B),metrics/mAP50-95(B)\n1,1.5,0.5,0.4,0.45,0.2\n2,1.2,0.7,0.6,0.66,0.41\n"), 0o644))
+		assert.NoError(t, os.WriteFile(argsPath, []byte("task: detect\nepochs: 2\nlr0: 0.01\n"), 0o644))
+
+		w = performMultipartRequest(t, testRouter, "POST", path, "results", resultsPath, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var ingested service.UltralyticsIngestResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &ingested))
+		assert.Equal(t, 2, ingested.Epochs)
+		assert.Equal(t, 10, ingested.MetricPoints)
+		if assert.NotNil(t, ingested.Result) {
+			var detail map[string]interface{}
+			assert.NoError(t, json.Unmarshal(ingested.Result.MetricDetail, &detail))
+			assert.Equal(t, 0.41, detail["mAP50-95"])
+			assert.Equal(t, "manual", detail["note"])
+		}
+
+		w = performMultipartRequest(t, testRouter, "POST", path, "args", argsPath, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &ingested))
+		assert.Equal(t, 3, ingested.Hyperparameters)
+
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/training-results/%d/hyperparameters", created.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var hyperparameters entity2.TrainingHyperparameters
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &hyperparameters))
+		assert.Equal(t, entity2.HyperparameterSourceUltralyticsArgs, hyperparameters.Source)
+		assert.JSONEq(t, `{"task":"detect","epochs":2,"lr0":0.01}`, string(hyperparameters.Hyperparameters))
+
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/training-results/%d/metrics?names=metrics/mAP50(B)", created.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = performMultipartRequest(t, testRouter, "POST", path, "results", argsPath, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("Register Trained Weight As Model Version", func(t *testing.T) {
+		suffix := time.Now().UnixNano()
+		baseName := fmt.Sprintf("register_base_%d", suffix)
+		body, _ := json.Marshal(entity2.Model{Name: baseName, Version: 1.0, TaskType: "detect", WeightName: baseName + ".pt"})
+		w := performRequest(testRouter, "POST", "/v1/models", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var base entity2.Model
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &base))
+
+		weightFile := fmt.Sprintf("register_best_%d.pt", suffix)
+		localPath := filepath.Join(service.DefaultBackendWeightsRoot, weightFile)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0o755))
+		assert.NoError(t, os.WriteFile(localPath, []byte("trained weights"), 0o644))
+		t.Cleanup(func() { _ = os.Remove(localPath) })
+
+		body, _ = json.Marshal(entity2.ModelTrainingResult{ModelID: base.ID, DatasetID: 1, TrainingStatus: 2, WeightPath: weightFile})
+		w = performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created entity2.ModelTrainingResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		path := fmt.Sprintf("/v1/training-results/%d/register-model", created.ID)
+
+		w = performRequest(testRouter, "POST", path, nil)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var registration service.TrainedModelRegistration
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &registration))
+		assert.Equal(t, service.TrainedWeightSourceBackend, registration.Source)
+		if assert.NotNil(t, registration.Model) {
+			assert.Equal(t, baseName, registration.Model.Name)
+			assert.Equal(t, base.ID, registration.Model.BaseModelID)
+			assert.Equal(t, 1.01, registration.Model.Version)
+			assert.Equal(t, fmt.Sprintf("%s_train%d.pt", baseName, created.ID), registration.Model.WeightName)
+		}
+		t.Cleanup(func() { _ = os.Remove(registration.Upload.ResolvedPath) })
+
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/training-results/%d/registered-model", created.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var link service.TrainedModelLink
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+		if registration.Model != nil {
+			assert.Equal(t, registration.Model.ID, link.ModelID)
+		}
+
+		w = performRequest(testRouter, "POST", path, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		body, _ = json.Marshal(entity2.ModelTrainingResult{ModelID: base.ID, DatasetID: 1, TrainingStatus: 1, WeightPath: weightFile})
+		w = performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		w = performRequest(testRouter, "POST", fmt.Sprintf("/v1/training-results/%d/register-model", created.ID), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/training-results/%d/registered-model", created.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("Launch Training Validation And Job Lookup", func(t *testing.T) {
+		w := performRequest(testRouter, "POST", "/v1/training-results/launch", bytes.NewBufferString(`{"model_id":1,"dataset_id":1}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(testRouter, "POST", "/v1/training-results/launch", bytes.NewBufferString(`{"model_id":"x"}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		body, _ := json.Marshal(entity2.ModelTrainingResult{ModelID: 1, DatasetID: 1})
+		w = performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created entity2.ModelTrainingResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/training-results/%d/job", created.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Training Logs Validation", func(t *testing.T) {
+		body, _ := json.Marshal(entity2.ModelTrainingResult{ModelID: 1, DatasetID: 1})
+		w := performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var created entity2.ModelTrainingResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		path := fmt.Sprintf("/v1/training-results/%d", created.ID)
+
+		w = performRequest(testRouter, "GET", path+"/logs?lines=abc", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(testRouter, "GET", path+"/logs/stream?lines=-1", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// 不是通过远程启动的训练没有日志位置
+		w = performRequest(testRouter, "GET", path+"/logs", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = performRequest(testRouter, "GET", path+"/logs/stream", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Hyperparameters And Compare", func(t *testing.T) {
+		createRun := func(hyperparameters string, metrics string) entity2.ModelTrainingResult {
+			body, _ := json.Marshal(entity2.ModelTrainingResult{
+				ModelID:         1,
+				DatasetID:       1,
+				MetricDetail:    json.RawMessage(metrics),
+				Hyperparameters: json.RawMessage(hyperparameters),
+			})
+			w := performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBuffer(body))
+			assert.Equal(t, http.StatusCreated, w.Code)
+			var created entity2.ModelTrainingResult
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+			return created
+		}
+		first := createRun(`{"epochs":100,"lr0":0.01}`, `{"mAP50":0.61}`)
+		second := createRun(`{"epochs":100,"lr0":0.02}`, `{"mAP50":0.65}`)
+
+		w := performRequest(testRouter, "POST", "/v1/training-results", bytes.NewBufferString(`{"model_id":1,"dataset_id":1,"hyperparameters":[1,2]}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/training-results/%d", first.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"hyperparameters":{"epochs":100,"lr0":0.01}`)
+
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/training-results/compare?ids=%d,%d&only_changed=true", first.ID, second.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var comparison service.TrainingResultComparison
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &comparison))
+		assert.Equal(t, []string{"lr0"}, comparison.ChangedHyperparameters)
+		assert.Equal(t, []string{"mAP50"}, comparison.ChangedMetrics)
+		assert.Len(t, comparison.Hyperparameters, 1)
+
+		patch := fmt.Sprintf("/v1/training-results/%d", second.ID)
+		w = performRequest(testRouter, "PATCH", patch, bytes.NewBufferString(`{"hyperparameters":"lr0=0.02"}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(testRouter, "PATCH", patch, bytes.NewBufferString(`{"hyperparameters":null}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(testRouter, "GET", patch+"/hyperparameters", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/training-results/compare?ids=%d", first.ID), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(testRouter, "GET", fmt.Sprintf("/v1/training-results/compare?ids=%d&ids=999999999", first.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
 }
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func parseTrainingResultUpdates(payload map[string]interface{}) (map[string]interface{}, error) {
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("request body is empty")
+	}
+
+	updates := make(map[string]interface{}, len(payload))
+	for key, value := range payload {
+		switch key {
+		case "id", "create_time":
+			return nil, fmt.Errorf("%s is immutable", key)
+		case "model_id", "dataset_id":
+			id, err := parseUintField(value, key)
+			if err != nil {
+				return nil, err
+			}
+			updates[key] = id
+		case "dataset_version":
+			version, err := parseNonNegativeFloatField(value, key)
+			if err != nil {
+				return nil, err
+			}
+			updates[key] = version
+		case "training_status":
+			status, err := parseUintField(value, key)
+			if err != nil {
+				return nil, err
+			}
+			if status > 4 {
+				return nil, fmt.Errorf("training_status must be one of 0/1/2/3/4")
+			}
+			updates[key] = int8(status)
+		case "metric_detail":
+			detail, err := parseJSONRawField(value, key)
+			if err != nil {
+				return nil, err
+			}
+			updates[key] = detail
+		case "hyperparameters":
+			// 对象整体覆盖已记录的超参数，null 删除
+			if value == nil {
+				updates[key] = json.RawMessage(nil)
+				continue
+			}
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("hyperparameters must be object or null")
+			}
+			raw, err := json.Marshal(object)
+			if err != nil {
+				return nil, fmt.Errorf("hyperparameters must be valid json")
+			}
+			updates[key] = json.RawMessage(raw)
+		case "weight_path", "comet_log_url":
+			text, ok := value.(string)
+			if value != nil && !ok {
+				return nil, fmt.Errorf("%s must be string or null", key)
+			}
+			updates[key] = strings.TrimSpace(text)
+		case "train_start_time", "train_end_time":
+			parsed, err := parseNullableTimeField(value, key)
+			if err != nil {
+				return nil, err
+			}
+			updates[key] = parsed
+		default:
+			return nil, fmt.Errorf("unsupported field: %s", key)
+		}
+	}
+
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("no updatable fields provided")
+	}
+
+	return updates, nil
+}
+
+// parseNullableTimeField 接受 RFC3339 字符串或 null
+func parseNullableTimeField(value interface{}, field string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s must be RFC3339 string or null", field)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be RFC3339 string or null", field)
+	}
+	return &parsed, nil
+}
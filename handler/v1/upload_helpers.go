@@ -2,6 +2,7 @@ package v1
 
 import (
 	"fmt"
+	"lucky_project/service"
 	"strconv"
 	"strings"
 
@@ -20,3 +21,29 @@ func parseOptionalBoolForm(ctx *gin.Context, key string, defaultValue bool) (boo
 	}
 	return value, nil
 }
+
+func parseOptionalBoolQuery(ctx *gin.Context, key string, defaultValue bool) (bool, error) {
+	raw := strings.TrimSpace(ctx.Query(key))
+	if raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean", key)
+	}
+	return value, nil
+}
+
+// parseArtifactDeleteOptions 解析 DELETE /v1/models/:id、/v1/datasets/:id 的 cascade、keep_file 参数。
+func parseArtifactDeleteOptions(ctx *gin.Context) (service.ArtifactDeleteOptions, error) {
+	cascade, err := parseOptionalBoolQuery(ctx, "cascade", false)
+	if err != nil {
+		return service.ArtifactDeleteOptions{}, err
+	}
+	keepFile, err := parseOptionalBoolQuery(ctx, "keep_file", false)
+	if err != nil {
+		return service.ArtifactDeleteOptions{}, err
+	}
+	return service.ArtifactDeleteOptions{Cascade: cascade, KeepFile: keepFile}, nil
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"lucky_project/config"
 	"lucky_project/router"
+	"lucky_project/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,20 +22,34 @@ func main() {
 		log.Fatalf("Init config failed: %v", err)
 	}
 
-	// 2. Initialize database
+	// 2. Initialize credential vault (DB/Redis passwords may reference it)
+	if err := config.InitVault(); err != nil {
+		log.Fatalf("Init credential vault failed: %v", err)
+	}
+
+	// 3. Baidu token auto-refresh needs the vault (refresh tokens are single-use)
+	if err := service.CheckBaiduTokenRefreshStore(); err != nil {
+		log.Fatalf("Check baidu token refresh failed: %v", err)
+	}
+
+	// 4. Initialize database
 	if err := config.InitDB(); err != nil {
 		log.Fatalf("Init database failed: %v", err)
 	}
 
-	// 3. Initialize redis
+	// 5. Initialize redis
 	if err := config.InitRedis(); err != nil {
 		log.Fatalf("Init redis failed: %v", err)
 	}
 
-	// 4. Setup router
+	// 6. Setup router
 	r := router.SetupRouter()
 
-	// 5. Start server
+	// 7. Start background jobs (no-op when disabled in config)
+	service.DefaultBaiduMirrorService().Start(context.Background())
+	service.DefaultTrainingQueueService().Start(context.Background())
+
+	// 8. Start server
 	port := config.AppConfig.Server.Port
 	if port == 0 {
 		port = 8080
@@ -14,8 +14,10 @@ func SetupRouter() *gin.Engine {
 	modelController := v2.NewModelController()
 	datasetController := v2.NewDatasetController()
 	trainingController := v2.NewTrainingResultController()
+	trainingQueueController := v2.NewTrainingQueueController()
 	baiduController := v2.NewBaiduController()
 	coreServerController := v2.NewCoreServerController()
+	credentialController := v2.NewCredentialController()
 
 	r := gin.New()
 	r.MaxMultipartMemory = 256 << 20 // 256MB
@@ -50,10 +52,22 @@ func SetupRouter() *gin.Engine {
 		{
 			models.POST("", modelController.CreateModel)
 			models.GET("", modelController.GetAllModels)
+			models.GET("/by-name/:name/latest", modelController.GetLatestModelByName)
+			models.GET("/by-name/:name/versions", modelController.ListModelVersionsByName)
+			models.GET("/by-name/:name/production", modelController.GetProductionModelByName)
+			models.GET("/:id", modelController.GetModel)
+			models.DELETE("/:id", modelController.DeleteModel)
 			models.GET("/:id/download", modelController.DownloadModelFile)
 			models.PATCH("/:id", modelController.UpdateModelMetadata)
 			models.GET("/:id/storage-server", modelController.GetModelStorageServers)
 			models.PATCH("/:id/storage-server", modelController.UpdateModelStorageServers)
+			models.GET("/:id/replicas", modelController.GetModelReplicas)
+			models.GET("/:id/lineage", modelController.GetModelLineage)
+			models.GET("/:id/tags", modelController.GetModelTags)
+			models.POST("/:id/tags", modelController.AddModelTags)
+			models.DELETE("/:id/tags", modelController.RemoveModelTags)
+			models.POST("/:id/transition", modelController.TransitionModelStage)
+			models.GET("/:id/stage-history", modelController.GetModelStageHistory)
 			models.POST("/upload", modelController.UploadModelFile)
 			models.DELETE("/by-filename", modelController.DeleteModelByFileName)
 		}
@@ -63,10 +77,17 @@ func SetupRouter() *gin.Engine {
 		{
 			datasets.POST("", datasetController.CreateDataset)
 			datasets.GET("", datasetController.GetAllDatasets)
+			datasets.GET("/:id", datasetController.GetDataset)
+			datasets.DELETE("/:id", datasetController.DeleteDataset)
 			datasets.GET("/:id/download", datasetController.DownloadDatasetFile)
 			datasets.PATCH("/:id", datasetController.UpdateDatasetMetadata)
 			datasets.GET("/:id/storage-server", datasetController.GetDatasetStorageServers)
 			datasets.PATCH("/:id/storage-server", datasetController.UpdateDatasetStorageServers)
+			datasets.GET("/:id/replicas", datasetController.GetDatasetReplicas)
+			datasets.GET("/:id/leaderboard", datasetController.GetDatasetLeaderboard)
+			datasets.GET("/:id/tags", datasetController.GetDatasetTags)
+			datasets.POST("/:id/tags", datasetController.AddDatasetTags)
+			datasets.DELETE("/:id/tags", datasetController.RemoveDatasetTags)
 			datasets.POST("/upload", datasetController.UploadDatasetFile)
 			datasets.DELETE("/by-filename", datasetController.DeleteDatasetByFileName)
 		}
@@ -76,18 +97,65 @@ func SetupRouter() *gin.Engine {
 		{
 			trainings.POST("", trainingController.CreateTrainingResult)
 			trainings.GET("", trainingController.GetAllResults)
+			trainings.POST("/launch", trainingController.LaunchTraining)
+			trainings.GET("/compare", trainingController.CompareTrainingResults)
+			trainings.GET("/:id", trainingController.GetTrainingResult)
+			trainings.PATCH("/:id", trainingController.UpdateTrainingResult)
+			trainings.DELETE("/:id", trainingController.DeleteTrainingResult)
+			trainings.POST("/:id/start", trainingController.StartTrainingResult)
+			trainings.POST("/:id/finish", trainingController.FinishTrainingResult)
+			trainings.POST("/:id/fail", trainingController.FailTrainingResult)
+			trainings.POST("/:id/interrupt", trainingController.InterruptTrainingResult)
+			trainings.POST("/:id/metrics", trainingController.IngestTrainingMetrics)
+			trainings.GET("/:id/metrics", trainingController.GetTrainingMetrics)
+			trainings.POST("/:id/ultralytics", trainingController.IngestUltralyticsFiles)
+			trainings.GET("/:id/hyperparameters", trainingController.GetTrainingHyperparameters)
+			trainings.POST("/:id/register-model", trainingController.RegisterTrainedModel)
+			trainings.GET("/:id/registered-model", trainingController.GetRegisteredModel)
+			trainings.GET("/:id/job", trainingController.GetTrainingJob)
+			trainings.GET("/:id/logs", trainingController.GetTrainingLogs)
+			trainings.GET("/:id/logs/stream", trainingController.StreamTrainingLogs)
+		}
+
+		// Training queue routes
+		trainingQueue := v1Group.Group("/training-queue")
+		{
+			trainingQueue.POST("", trainingQueueController.EnqueueTrainingJob)
+			trainingQueue.GET("", trainingQueueController.ListTrainingJobs)
+			trainingQueue.POST("/dispatch", trainingQueueController.DispatchTrainingJobs)
+			trainingQueue.GET("/:id", trainingQueueController.GetTrainingJob)
+			trainingQueue.POST("/:id/cancel", trainingQueueController.CancelTrainingJob)
 		}
 
 		// Baidu Pan routes
 		baidu := v1Group.Group("/baidu")
 		{
 			baidu.POST("/download", baiduController.DownloadFileToLocal)
+			baidu.GET("/files", baiduController.ListFiles)
+			baidu.POST("/import", baiduController.ImportDirectory)
+			baidu.GET("/import/:id", baiduController.GetImportJob)
+			baidu.GET("/token/status", baiduController.GetTokenStatus)
+			baidu.POST("/token/refresh", baiduController.RefreshToken)
+			baidu.GET("/mirror/status", baiduController.GetMirrorStatus)
+			baidu.POST("/mirror/run", baiduController.RunMirror)
 		}
 
 		// Core server routes
 		coreServers := v1Group.Group("/core-servers")
 		{
 			coreServers.GET("", coreServerController.ListCoreServers)
+			coreServers.POST("/:key/heartbeat", coreServerController.ReportHeartbeat)
+			coreServers.GET("/:key/artifacts", coreServerController.ListArtifacts)
+		}
+
+		// Credential vault routes (never return secret material)
+		credentials := v1Group.Group("/credentials")
+		{
+			credentials.POST("", credentialController.CreateCredential)
+			credentials.GET("", credentialController.ListCredentials)
+			credentials.GET("/:id", credentialController.GetCredential)
+			credentials.POST("/:id/rotate", credentialController.RotateCredential)
+			credentials.DELETE("/:id", credentialController.DeleteCredential)
 		}
 	}
 
@@ -0,0 +1,94 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+log_info() {
+  printf '[%s] [INFO] %s\n' "$(date '+%Y-%m-%d %H:%M:%S')" "$*"
+}
+
+log_error() {
+  printf '[%s] [ERROR] %s\n' "$(date '+%Y-%m-%d %H:%M:%S')" "$*" >&2
+}
+
+usage() {
+  cat <<'USAGE'
+Usage:
+  scripts/core_server_heartbeat.sh --key <core_server_key> [--api <base_url>] [--disk <path>] [--interval <seconds>]
+
+Options:
+  --key       Core server key registered in Redis hash core-servers (required)
+  --api       Backend base URL (default: http://localhost:8080/v1)
+  --disk      Path used to measure free disk space (default: /project/luckyProject)
+  --interval  Report repeatedly every N seconds; omit to report once
+  --help      Show this help
+
+Examples:
+  scripts/core_server_heartbeat.sh --key rtx3090 --api http://10.0.0.2:8080/v1
+  scripts/core_server_heartbeat.sh --key rtx3090 --interval 20
+USAGE
+}
+
+KEY=""
+API="http://localhost:8080/v1"
+DISK="/project/luckyProject"
+INTERVAL=""
+
+while [[ $# -gt 0 ]]; do
+  case "$1" in
+    --key) KEY="$2"; shift 2 ;;
+    --api) API="$2"; shift 2 ;;
+    --disk) DISK="$2"; shift 2 ;;
+    --interval) INTERVAL="$2"; shift 2 ;;
+    --help) usage; exit 0 ;;
+    *) log_error "unknown option: $1"; usage; exit 1 ;;
+  esac
+done
+
+if [[ -z "${KEY}" ]]; then
+  log_error "--key is required"
+  usage
+  exit 1
+fi
+
+collect_payload() {
+  local gpu_count=0 gpu_used=0 gpu_total=0
+  if command -v nvidia-smi >/dev/null 2>&1; then
+    read -r gpu_count gpu_used gpu_total < <(
+      nvidia-smi --query-gpu=memory.used,memory.total --format=csv,noheader,nounits |
+        awk -F', *' '{n++; u+=$1; t+=$2} END {printf "%d %d %d\n", n, u, t}'
+    )
+  fi
+
+  local disk_path="${DISK}"
+  [[ -e "${disk_path}" ]] || disk_path="/"
+  local disk_free_gb
+  disk_free_gb="$(df -Pk "${disk_path}" | awk 'NR==2 {printf "%.2f", $4/1024/1024}')"
+
+  local load1 load5 load15
+  read -r load1 load5 load15 _ < /proc/loadavg
+
+  printf '{"gpu_count":%s,"gpu_memory_used_mb":%s,"gpu_memory_total_mb":%s,"disk_free_gb":%s,"load1":%s,"load5":%s,"load15":%s}' \
+    "${gpu_count}" "${gpu_used}" "${gpu_total}" "${disk_free_gb}" "${load1}" "${load5}" "${load15}"
+}
+
+report_once() {
+  local payload
+  payload="$(collect_payload)"
+  if curl -fsS -X POST "${API}/core-servers/${KEY}/heartbeat" \
+    -H "Content-Type: application/json" \
+    -d "${payload}" >/dev/null; then
+    log_info "heartbeat reported: key=${KEY} payload=${payload}"
+  else
+    log_error "heartbeat report failed: key=${KEY}"
+    return 1
+  fi
+}
+
+if [[ -z "${INTERVAL}" ]]; then
+  report_once
+  exit $?
+fi
+
+while true; do
+  report_once || true
+  sleep "${INTERVAL}"
+done
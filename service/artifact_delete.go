@@ -0,0 +1,57 @@
+package service
+
+import (
+	"fmt"
+	"os"
+)
+
+// ArtifactDeleteOptions DELETE /v1/models/:id、/v1/datasets/:id 的删除选项
+type ArtifactDeleteOptions struct {
+	// Cascade 为 true 时一并删除引用该记录的训练结果（模型另将派生模型改挂到其基础模型），否则存在引用时拒绝删除。
+	Cascade bool
+	// KeepFile 为 true 时保留后端本地文件；否则仅在没有其他记录引用同名文件时删除。
+	KeepFile bool
+}
+
+// ArtifactDeleteResult 按 ID 删除模型/数据集的结果
+type ArtifactDeleteResult struct {
+	ID                     uint   `json:"id"`
+	FileName               string `json:"file_name"`
+	DeletedTrainingResults int64  `json:"deleted_training_results"`
+	// ReparentedModels 删除模型时改挂 base_model_id 的派生模型数，数据集恒为 0。
+	ReparentedModels int64 `json:"reparented_models,omitempty"`
+	LocalFileDeleted bool  `json:"local_file_deleted"`
+	// FileSharedBy 仍引用同名文件的其他记录数，大于 0 时文件被保留。
+	FileSharedBy int64 `json:"file_shared_by"`
+}
+
+// removeBackendArtifactFile 删除后端本地制品文件；文件不存在或为目录时返回 false。
+func removeBackendArtifactFile(pathService *ArtifactPathService, category, fileName string) (bool, error) {
+	if pathService == nil {
+		return false, nil
+	}
+
+	localPath, err := pathService.BuildPath(category, StorageTargetBackend, fileName)
+	if err != nil {
+		return false, err
+	}
+	if localPath == "" {
+		return false, nil
+	}
+
+	info, statErr := os.Stat(localPath)
+	if statErr != nil {
+		if os.IsNotExist(statErr) {
+			return false, nil
+		}
+		return false, fmt.Errorf("stat local %s file failed: %w", category, statErr)
+	}
+	if info.IsDir() {
+		return false, nil
+	}
+
+	if removeErr := os.Remove(localPath); removeErr != nil {
+		return false, fmt.Errorf("remove local %s file failed: %w", category, removeErr)
+	}
+	return true, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveBackendArtifactFile(t *testing.T) {
+	root := t.TempDir()
+	pathService := &ArtifactPathService{BackendWeightsRoot: root, BackendDatasetsRoot: root}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "best.pt"), []byte("weights"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "coco"), 0o755))
+
+	deleted, err := removeBackendArtifactFile(pathService, ArtifactCategoryWeights, "best.pt")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.NoFileExists(t, filepath.Join(root, "best.pt"))
+
+	deleted, err = removeBackendArtifactFile(pathService, ArtifactCategoryWeights, "best.pt")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	deleted, err = removeBackendArtifactFile(pathService, ArtifactCategoryDatasets, "coco")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	assert.DirExists(t, filepath.Join(root, "coco"))
+
+	deleted, err = removeBackendArtifactFile(nil, ArtifactCategoryWeights, "best.pt")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
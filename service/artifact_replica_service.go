@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+)
+
+// ArtifactReplicaService 维护制品在各存储上的副本校验状态（当前用于 baidu_netdisk）。
+type ArtifactReplicaService struct {
+	replicaDAO *dao.ArtifactReplicaDAO
+	modelDAO   *dao.ModelDAO
+	datasetDAO *dao.DatasetDAO
+}
+
+func NewArtifactReplicaService() *ArtifactReplicaService {
+	return &ArtifactReplicaService{
+		replicaDAO: dao.NewArtifactReplicaDAO(),
+		modelDAO:   dao.NewModelDAO(),
+		datasetDAO: dao.NewDatasetDAO(),
+	}
+}
+
+// RecordBaiduUpload 将一次网盘上传的校验结果写入副本表（按文件名关联记录，记录可晚于上传创建）。
+func (s *ArtifactReplicaService) RecordBaiduUpload(ctx context.Context, artifactType, fileName string, receipt BaiduUploadReceipt) (*entity2.ArtifactReplica, error) {
+	replica := buildBaiduReplica(artifactType, fileName, receipt)
+	if err := s.replicaDAO.Upsert(ctx, replica); err != nil {
+		return nil, err
+	}
+	return replica, nil
+}
+
+// BaiduReplicasByName 按文件名返回 baidu_netdisk 上的副本记录，用于镜像时判断远端是否已校验过同一内容。
+func (s *ArtifactReplicaService) BaiduReplicasByName(ctx context.Context, artifactType string, fileNames []string) (map[string]entity2.ArtifactReplica, error) {
+	replicas, err := s.replicaDAO.FindByFileNames(ctx, artifactType, StorageTargetBaiduNetdisk, fileNames)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]entity2.ArtifactReplica, len(replicas))
+	for _, replica := range replicas {
+		byName[replica.FileName] = replica
+	}
+	return byName, nil
+}
+
+// ListByModelID 返回模型权重文件的副本状态
+func (s *ArtifactReplicaService) ListByModelID(ctx context.Context, id uint) ([]entity2.ArtifactReplica, error) {
+	fileName, err := s.modelDAO.FindWeightNameByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.replicaDAO.FindByFileName(ctx, entity2.ArtifactTypeModel, fileName)
+}
+
+// ListByDatasetID 返回数据集文件的副本状态
+func (s *ArtifactReplicaService) ListByDatasetID(ctx context.Context, id uint) ([]entity2.ArtifactReplica, error) {
+	fileName, err := s.datasetDAO.FindFileNameByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.replicaDAO.FindByFileName(ctx, entity2.ArtifactTypeDataset, fileName)
+}
+
+func buildBaiduReplica(artifactType, fileName string, receipt BaiduUploadReceipt) *entity2.ArtifactReplica {
+	verifiedAt := receipt.VerifiedAt
+	return &entity2.ArtifactReplica{
+		ArtifactType:  artifactType,
+		FileName:      fileName,
+		StorageTarget: StorageTargetBaiduNetdisk,
+		RemotePath:    receipt.RemotePath,
+		LocalSize:     receipt.LocalSize,
+		LocalMD5:      receipt.LocalMD5,
+		RemoteSize:    receipt.RemoteSize,
+		RemoteMD5:     receipt.RemoteMD5,
+		Status:        receipt.Status,
+		StatusDetail:  receipt.StatusDetail,
+		RapidUpload:   receipt.RapidUpload,
+		VerifiedAt:    &verifiedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"sort"
+)
+
+// ArtifactTagsRequest POST /v1/models/:id/tags、POST /v1/datasets/:id/tags 请求体
+type ArtifactTagsRequest struct {
+	Tags   []string          `json:"tags"`
+	Labels map[string]string `json:"labels"`
+}
+
+// ArtifactTags 制品当前的普通标签与键值标签
+type ArtifactTags struct {
+	ArtifactType string            `json:"artifact_type"`
+	ArtifactID   uint              `json:"artifact_id"`
+	Tags         []string          `json:"tags"`
+	Labels       map[string]string `json:"labels"`
+}
+
+// ArtifactTagService 维护模型/数据集的标签
+type ArtifactTagService struct {
+	tagDAO     *dao.TagDAO
+	modelDAO   *dao.ModelDAO
+	datasetDAO *dao.DatasetDAO
+}
+
+func NewArtifactTagService() *ArtifactTagService {
+	return &ArtifactTagService{
+		tagDAO:     dao.NewTagDAO(),
+		modelDAO:   dao.NewModelDAO(),
+		datasetDAO: dao.NewDatasetDAO(),
+	}
+}
+
+// Get 返回制品当前标签，制品不存在时返回 gorm.ErrRecordNotFound。
+func (s *ArtifactTagService) Get(ctx context.Context, artifactType string, id uint) (ArtifactTags, error) {
+	if err := s.ensureArtifact(ctx, artifactType, id); err != nil {
+		return ArtifactTags{}, err
+	}
+	return s.current(ctx, artifactType, id)
+}
+
+// Add 添加标签；同一键已有取值时覆盖。
+func (s *ArtifactTagService) Add(ctx context.Context, artifactType string, id uint, req ArtifactTagsRequest) (ArtifactTags, error) {
+	tags, err := parseArtifactTagsRequest(req)
+	if err != nil {
+		return ArtifactTags{}, err
+	}
+	if err := s.ensureArtifact(ctx, artifactType, id); err != nil {
+		return ArtifactTags{}, err
+	}
+	if err := s.tagDAO.Attach(ctx, artifactType, id, tags); err != nil {
+		return ArtifactTags{}, err
+	}
+	return s.current(ctx, artifactType, id)
+}
+
+// Remove 按 tag/label 参数移除标签（label=env 移除 env 的任意取值），不存在的标签忽略。
+func (s *ArtifactTagService) Remove(ctx context.Context, artifactType string, id uint, tags, labels []string) (ArtifactTags, error) {
+	selectors, err := dao.ParseTagSelectors(tags, labels)
+	if err != nil {
+		return ArtifactTags{}, err
+	}
+	if len(selectors) == 0 {
+		return ArtifactTags{}, fmt.Errorf("%w: tag or label is required", dao.ErrInvalidTag)
+	}
+	if err := s.ensureArtifact(ctx, artifactType, id); err != nil {
+		return ArtifactTags{}, err
+	}
+	if _, err := s.tagDAO.Detach(ctx, artifactType, id, selectors); err != nil {
+		return ArtifactTags{}, err
+	}
+	return s.current(ctx, artifactType, id)
+}
+
+func (s *ArtifactTagService) ensureArtifact(ctx context.Context, artifactType string, id uint) error {
+	switch artifactType {
+	case entity2.ArtifactTypeModel:
+		_, err := s.modelDAO.FindByID(ctx, id)
+		return err
+	case entity2.ArtifactTypeDataset:
+		_, err := s.datasetDAO.FindByID(ctx, id)
+		return err
+	default:
+		return fmt.Errorf("unsupported artifact type: %s", artifactType)
+	}
+}
+
+func (s *ArtifactTagService) current(ctx context.Context, artifactType string, id uint) (ArtifactTags, error) {
+	tags, err := s.tagDAO.ListByArtifact(ctx, artifactType, id)
+	if err != nil {
+		return ArtifactTags{}, err
+	}
+	return buildArtifactTags(artifactType, id, tags), nil
+}
+
+// parseArtifactTagsRequest 校验请求并去重，普通标签与键值标签不能使用同一个键。
+func parseArtifactTagsRequest(req ArtifactTagsRequest) ([]entity2.Tag, error) {
+	tags := make([]entity2.Tag, 0, len(req.Tags)+len(req.Labels))
+	seen := make(map[string]struct{}, cap(tags))
+	for _, raw := range req.Tags {
+		tag, err := dao.ParseTag(raw)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[tag.TagKey]; ok {
+			continue
+		}
+		seen[tag.TagKey] = struct{}{}
+		tags = append(tags, tag)
+	}
+
+	keys := make([]string, 0, len(req.Labels))
+	for key := range req.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		tag, err := dao.ParseLabel(key, req.Labels[key])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[tag.TagKey]; ok {
+			return nil, fmt.Errorf("%w: key %s is used more than once", dao.ErrInvalidTag, tag.TagKey)
+		}
+		seen[tag.TagKey] = struct{}{}
+		tags = append(tags, tag)
+	}
+
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("%w: tags or labels is required", dao.ErrInvalidTag)
+	}
+	return tags, nil
+}
+
+func buildArtifactTags(artifactType string, id uint, tags []entity2.Tag) ArtifactTags {
+	result := ArtifactTags{
+		ArtifactType: artifactType,
+		ArtifactID:   id,
+		Tags:         make([]string, 0),
+		Labels:       make(map[string]string),
+	}
+	for _, tag := range tags {
+		if tag.TagValue == "" {
+			result.Tags = append(result.Tags, tag.TagKey)
+			continue
+		}
+		result.Labels[tag.TagKey] = tag.TagValue
+	}
+	return result
+}
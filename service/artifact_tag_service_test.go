@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArtifactTagsRequest(t *testing.T) {
+	tags, err := parseArtifactTagsRequest(ArtifactTagsRequest{
+		Tags:   []string{" helmet ", "helmet", "night"},
+		Labels: map[string]string{"owner": "cv-team", "env": " prod "},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []entity2.Tag{
+		{TagKey: "helmet"},
+		{TagKey: "night"},
+		{TagKey: "env", TagValue: "prod"},
+		{TagKey: "owner", TagValue: "cv-team"},
+	}, tags)
+
+	invalid := []ArtifactTagsRequest{
+		{},
+		{Tags: []string{"has space"}},
+		{Tags: []string{"env=prod"}},
+		{Labels: map[string]string{"env": ""}},
+		{Labels: map[string]string{"env": "a,b"}},
+		{Tags: []string{"env"}, Labels: map[string]string{"env": "prod"}},
+	}
+	for _, req := range invalid {
+		_, err := parseArtifactTagsRequest(req)
+		assert.ErrorIs(t, err, dao.ErrInvalidTag, "%+v", req)
+	}
+}
+
+func TestParseTagSelectors(t *testing.T) {
+	selectors, err := dao.ParseTagSelectors([]string{"helmet,night", " "}, []string{"env=prod", "owner"})
+	require.NoError(t, err)
+	assert.Equal(t, []dao.TagSelector{
+		{Key: "helmet"},
+		{Key: "night"},
+		{Key: "env", Value: "prod"},
+		{Key: "owner", AnyValue: true},
+	}, selectors)
+
+	_, err = dao.ParseTagSelectors(nil, []string{"env="})
+	assert.ErrorIs(t, err, dao.ErrInvalidTag)
+
+	match, err := dao.NormalizeTagMatch("")
+	require.NoError(t, err)
+	assert.Equal(t, dao.TagMatchAll, match)
+	_, err = dao.NormalizeTagMatch("some")
+	assert.ErrorIs(t, err, dao.ErrInvalidTagMatch)
+}
+
+func TestBuildArtifactTags(t *testing.T) {
+	result := buildArtifactTags(entity2.ArtifactTypeDataset, 7, []entity2.Tag{
+		{TagKey: "env", TagValue: "prod"},
+		{TagKey: "helmet"},
+	})
+	assert.Equal(t, ArtifactTags{
+		ArtifactType: entity2.ArtifactTypeDataset,
+		ArtifactID:   7,
+		Tags:         []string{"helmet"},
+		Labels:       map[string]string{"env": "prod"},
+	}, result)
+}
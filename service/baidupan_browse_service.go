@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/dao"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	openapi "github.com/S-zhi/baidupansdk/openxpanapi"
+)
+
+const (
+	// baiduListDirLimit 百度 xpanfilelist 单页最多返回 1000 条，更多条目需翻页
+	baiduListDirLimit = 1000
+
+	defaultBaiduFilePageSize = 10
+	maxBaiduFilePageSize     = 1000
+)
+
+var ErrBaiduDirOutsideRoots = errors.New("baidu dir must be under the weights or datasets root")
+
+// BaiduFileEntry 百度网盘目录中的单个条目
+type BaiduFileEntry struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	MD5     string    `json:"md5"`
+	ModTime time.Time `json:"mod_time"`
+	IsDir   bool      `json:"is_dir"`
+	FsID    int64     `json:"fs_id"`
+}
+
+// BaiduDirLister 列出百度网盘目录
+type BaiduDirLister interface {
+	ListDir(dir string) ([]BaiduFileEntry, error)
+}
+
+// BaiduPanLister 列出百度网盘目录，请求经由共享的 BaiduPanClient 限流。
+type BaiduPanLister struct {
+	Client *BaiduPanClient
+}
+
+func NewBaiduPanListerFromConfig() *BaiduPanLister {
+	return &BaiduPanLister{Client: DefaultBaiduPanClient()}
+}
+
+func (l *BaiduPanLister) ListDir(dir string) ([]BaiduFileEntry, error) {
+	if l.Client == nil {
+		return nil, ErrBaiduPanClientNil
+	}
+	normalizedDir, err := normalizeBaiduRemoteDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := l.Client.ListDirAll(context.Background(), normalizedDir)
+	if err != nil {
+		if errors.Is(err, ErrBaiduPanAccessTokenRequired) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("list baidu pan dir failed: %w", err)
+	}
+
+	return baiduFileEntriesFromList(normalizedDir, list), nil
+}
+
+func baiduFileEntriesFromList(dir string, list []openapi.Filecreateresponse) []BaiduFileEntry {
+	entries := make([]BaiduFileEntry, 0, len(list))
+	for _, item := range list {
+		entry := BaiduFileEntry{
+			Name:  item.GetServerFilename(),
+			Path:  item.GetPath(),
+			Size:  int64(item.GetSize()),
+			MD5:   item.GetMd5(),
+			IsDir: item.GetIsdir() == 1,
+			FsID:  item.GetFsId(),
+		}
+		if entry.Name == "" {
+			entry.Name = path.Base(entry.Path)
+		}
+		if entry.Path == "" {
+			entry.Path = path.Join(dir, entry.Name)
+		}
+		if mtime := item.GetMtime(); mtime > 0 {
+			entry.ModTime = time.Unix(int64(mtime), 0)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// BaiduFileListQuery GET /v1/baidu/files 查询参数
+type BaiduFileListQuery struct {
+	Dir      string `form:"dir"`
+	Keyword  string `form:"keyword"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+}
+
+// BaiduFileListItem 目录条目及其匹配到的记录
+type BaiduFileListItem struct {
+	BaiduFileEntry
+	Registered bool                      `json:"registered"`
+	Records    []RemoteArtifactRecordRef `json:"records"`
+}
+
+// BaiduFileListResult 目录浏览结果
+type BaiduFileListResult struct {
+	Dir      string              `json:"dir"`
+	Category string              `json:"category"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+	Total    int64               `json:"total"`
+	List     []BaiduFileListItem `json:"list"`
+}
+
+// BaiduFileBrowseService 浏览百度网盘 weights/datasets 根目录并标注已登记的记录。
+type BaiduFileBrowseService struct {
+	PathService *ArtifactPathService
+	Lister      BaiduDirLister
+	modelDAO    *dao.ModelDAO
+	datasetDAO  *dao.DatasetDAO
+}
+
+func NewBaiduFileBrowseService() *BaiduFileBrowseService {
+	return &BaiduFileBrowseService{
+		PathService: NewArtifactPathService(),
+		Lister:      NewBaiduPanListerFromConfig(),
+		modelDAO:    dao.NewModelDAO(),
+		datasetDAO:  dao.NewDatasetDAO(),
+	}
+}
+
+// ListFiles 列出目录（仅限 weights/datasets 根目录及其子目录），支持关键字过滤与分页。
+// 文件按 weight_name/file_name 匹配 models/datasets，records[].claimed 表示 storage_server 已包含 baidu_netdisk。
+func (s *BaiduFileBrowseService) ListFiles(ctx context.Context, query BaiduFileListQuery) (BaiduFileListResult, error) {
+	logger := serviceLogger().With("service", "BaiduFileBrowseService", "method", "ListFiles")
+	start := time.Now()
+	if s.PathService == nil {
+		return BaiduFileListResult{}, ErrArtifactPathServiceNil
+	}
+	if s.Lister == nil {
+		return BaiduFileListResult{}, ErrBaiduPanAccessTokenRequired
+	}
+
+	dir, category, err := s.resolveBrowseDir(query.Dir)
+	if err != nil {
+		logger.Warn("list baidu files failed: invalid dir", "dir", query.Dir, "error", err)
+		return BaiduFileListResult{}, err
+	}
+
+	entries, err := s.Lister.ListDir(dir)
+	if err != nil {
+		logger.Error("list baidu files failed: list dir failed", "dir", dir, "error", err)
+		return BaiduFileListResult{}, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir {
+			names = append(names, entry.Name)
+		}
+	}
+	var records []artifactRecord
+	if len(names) > 0 {
+		records, err = s.findRecords(ctx, category, names)
+		if err != nil {
+			logger.Error("list baidu files failed: query records failed", "dir", dir, "error", err)
+			return BaiduFileListResult{}, err
+		}
+	}
+
+	result := buildBaiduFileListResult(dir, category, entries, records, query)
+	logger.Info(
+		"list baidu files success",
+		"dir", dir,
+		"category", category,
+		"entries", len(entries),
+		"total", result.Total,
+		"returned", len(result.List),
+		"cost_ms", time.Since(start).Milliseconds(),
+	)
+	return result, nil
+}
+
+// resolveBrowseDir 校验目录位于百度 weights/datasets 根目录下，并返回对应分类。
+func (s *BaiduFileBrowseService) resolveBrowseDir(rawDir string) (string, string, error) {
+	dir, err := normalizeBaiduRemoteDir(rawDir)
+	if err != nil {
+		return "", "", err
+	}
+	for _, category := range []string{ArtifactCategoryWeights, ArtifactCategoryDatasets} {
+		root, err := s.PathService.ResolveRoot(category, StorageTargetBaiduNetdisk)
+		if err != nil {
+			return "", "", err
+		}
+		root = path.Clean("/" + strings.TrimPrefix(root, "/"))
+		if dir == root || strings.HasPrefix(dir, root+"/") {
+			return dir, category, nil
+		}
+	}
+	return "", "", fmt.Errorf("%w: %s", ErrBaiduDirOutsideRoots, dir)
+}
+
+func (s *BaiduFileBrowseService) findRecords(ctx context.Context, category string, names []string) ([]artifactRecord, error) {
+	if category == ArtifactCategoryWeights {
+		models, err := s.modelDAO.FindByWeightNamesOrStorageServer(ctx, names, "")
+		if err != nil {
+			return nil, err
+		}
+		return modelArtifactRecords(models), nil
+	}
+	datasets, err := s.datasetDAO.FindByFileNamesOrStorageServer(ctx, names, "")
+	if err != nil {
+		return nil, err
+	}
+	return datasetArtifactRecords(datasets), nil
+}
+
+func buildBaiduFileListResult(dir, category string, entries []BaiduFileEntry, records []artifactRecord, query BaiduFileListQuery) BaiduFileListResult {
+	byFileName := make(map[string][]artifactRecord, len(records))
+	for _, record := range records {
+		fileName := strings.TrimSpace(record.fileName)
+		if fileName != "" {
+			byFileName[fileName] = append(byFileName[fileName], record)
+		}
+	}
+
+	keyword := strings.ToLower(strings.TrimSpace(query.Keyword))
+	items := make([]BaiduFileListItem, 0, len(entries))
+	for _, entry := range entries {
+		if keyword != "" && !strings.Contains(strings.ToLower(entry.Name), keyword) {
+			continue
+		}
+		refs := make([]RemoteArtifactRecordRef, 0)
+		if !entry.IsDir {
+			for _, record := range byFileName[entry.Name] {
+				refs = append(refs, RemoteArtifactRecordRef{
+					ID:      record.id,
+					Name:    record.name,
+					Claimed: storageServerFieldContains(record.storageServer, StorageTargetBaiduNetdisk),
+				})
+			}
+		}
+		items = append(items, BaiduFileListItem{
+			BaiduFileEntry: entry,
+			Registered:     len(refs) > 0,
+			Records:        refs,
+		})
+	}
+	// 目录在前，其余按名称排序
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].IsDir != items[j].IsDir {
+			return items[i].IsDir
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	page, pageSize := query.Page, query.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultBaiduFilePageSize
+	}
+	if pageSize > maxBaiduFilePageSize {
+		pageSize = maxBaiduFilePageSize
+	}
+	total := len(items)
+	// 先比较页码再相乘，超大的 page 不会溢出成负数下标
+	from := total
+	if page-1 <= total/pageSize {
+		from = (page - 1) * pageSize
+	}
+	to := from + pageSize
+	if to > total {
+		to = total
+	}
+
+	return BaiduFileListResult{
+		Dir:      dir,
+		Category: category,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    int64(total),
+		List:     items[from:to],
+	}
+}
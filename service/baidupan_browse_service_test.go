@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBaiduDirLister struct {
+	entries map[string][]BaiduFileEntry
+	calls   []string
+}
+
+func (l *fakeBaiduDirLister) ListDir(dir string) ([]BaiduFileEntry, error) {
+	l.calls = append(l.calls, dir)
+	return l.entries[dir], nil
+}
+
+func TestBaiduFileBrowseServiceRejectsDirOutsideRoots(t *testing.T) {
+	lister := &fakeBaiduDirLister{}
+	svc := &BaiduFileBrowseService{
+		PathService: NewArtifactPathService(),
+		Lister:      lister,
+	}
+
+	_, err := svc.ListFiles(context.Background(), BaiduFileListQuery{Dir: "/project/other"})
+	assert.ErrorIs(t, err, ErrBaiduDirOutsideRoots)
+
+	_, err = svc.ListFiles(context.Background(), BaiduFileListQuery{Dir: "/project/luckyProject/weights/../../etc"})
+	assert.ErrorIs(t, err, ErrBaiduDirOutsideRoots)
+
+	_, err = svc.ListFiles(context.Background(), BaiduFileListQuery{Dir: "/project/luckyProject/weightsX"})
+	assert.ErrorIs(t, err, ErrBaiduDirOutsideRoots)
+
+	_, err = svc.ListFiles(context.Background(), BaiduFileListQuery{})
+	assert.ErrorIs(t, err, ErrInvalidBaiduRemoteDir)
+	assert.Empty(t, lister.calls)
+}
+
+func TestBaiduFileBrowseServiceResolveBrowseDir(t *testing.T) {
+	svc := &BaiduFileBrowseService{PathService: NewArtifactPathService()}
+
+	dir, category, err := svc.resolveBrowseDir("project/luckyProject/weights/")
+	require.NoError(t, err)
+	assert.Equal(t, "/project/luckyProject/weights", dir)
+	assert.Equal(t, ArtifactCategoryWeights, category)
+
+	dir, category, err = svc.resolveBrowseDir("/project/luckyProject/datasets/coco")
+	require.NoError(t, err)
+	assert.Equal(t, "/project/luckyProject/datasets/coco", dir)
+	assert.Equal(t, ArtifactCategoryDatasets, category)
+}
+
+func TestBuildBaiduFileListResult(t *testing.T) {
+	entries := []BaiduFileEntry{
+		{Name: "yolo-b.pt", Path: "/w/yolo-b.pt", Size: 20},
+		{Name: "archive", Path: "/w/archive", IsDir: true},
+		{Name: "yolo-a.pt", Path: "/w/yolo-a.pt", Size: 10, MD5: "abc"},
+		{Name: "notes.txt", Path: "/w/notes.txt"},
+	}
+	records := []artifactRecord{
+		{id: 1, name: "yolo-a", fileName: "yolo-a.pt", storageServer: `["backend","baidu_netdisk"]`},
+		{id: 2, name: "yolo-b", fileName: "yolo-b.pt", storageServer: `["backend"]`},
+	}
+
+	result := buildBaiduFileListResult("/w", ArtifactCategoryWeights, entries, records, BaiduFileListQuery{})
+	assert.Equal(t, "/w", result.Dir)
+	assert.Equal(t, ArtifactCategoryWeights, result.Category)
+	assert.Equal(t, 1, result.Page)
+	assert.Equal(t, defaultBaiduFilePageSize, result.PageSize)
+	assert.EqualValues(t, 4, result.Total)
+	require.Len(t, result.List, 4)
+	assert.Equal(t, "archive", result.List[0].Name)
+	assert.False(t, result.List[0].Registered)
+	assert.Equal(t, "notes.txt", result.List[1].Name)
+	assert.False(t, result.List[1].Registered)
+	assert.NotNil(t, result.List[1].Records)
+
+	yoloA := result.List[2]
+	assert.Equal(t, "yolo-a.pt", yoloA.Name)
+	assert.Equal(t, "abc", yoloA.MD5)
+	assert.True(t, yoloA.Registered)
+	require.Len(t, yoloA.Records, 1)
+	assert.Equal(t, RemoteArtifactRecordRef{ID: 1, Name: "yolo-a", Claimed: true}, yoloA.Records[0])
+
+	yoloB := result.List[3]
+	assert.True(t, yoloB.Registered)
+	require.Len(t, yoloB.Records, 1)
+	assert.False(t, yoloB.Records[0].Claimed)
+}
+
+func TestBuildBaiduFileListResultKeywordAndPagination(t *testing.T) {
+	entries := []BaiduFileEntry{
+		{Name: "YOLO-c.pt"},
+		{Name: "yolo-a.pt"},
+		{Name: "resnet.pt"},
+		{Name: "yolo-b.pt"},
+	}
+
+	result := buildBaiduFileListResult("/w", ArtifactCategoryWeights, entries, nil, BaiduFileListQuery{Keyword: " yolo ", Page: 2, PageSize: 2})
+	assert.EqualValues(t, 3, result.Total)
+	require.Len(t, result.List, 1)
+	assert.Equal(t, "yolo-b.pt", result.List[0].Name)
+
+	result = buildBaiduFileListResult("/w", ArtifactCategoryWeights, entries, nil, BaiduFileListQuery{Page: 5, PageSize: 2})
+	assert.EqualValues(t, 4, result.Total)
+	assert.Empty(t, result.List)
+
+	for _, page := range []int{math.MaxInt, math.MaxInt/2 + 1, math.MaxInt / maxBaiduFilePageSize} {
+		result = buildBaiduFileListResult("/w", ArtifactCategoryWeights, entries, nil, BaiduFileListQuery{Page: page, PageSize: maxBaiduFilePageSize})
+		assert.Equal(t, page, result.Page)
+		assert.Empty(t, result.List)
+	}
+
+	result = buildBaiduFileListResult("/w", ArtifactCategoryWeights, entries, nil, BaiduFileListQuery{PageSize: 5000})
+	assert.Equal(t, maxBaiduFilePageSize, result.PageSize)
+	assert.Len(t, result.List, 4)
+}
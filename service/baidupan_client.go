@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	baidupanplus "github.com/S-zhi/baidupansdk/baidupanplus"
+	openapi "github.com/S-zhi/baidupansdk/openxpanapi"
+
+	"lucky_project/config"
+)
+
+const (
+	// DefaultBaiduPanMaxConcurrent 未配置 baidu_pan.max_concurrent_transfers 时的并发上限
+	DefaultBaiduPanMaxConcurrent = 4
+
+	// maxBaiduListDirPages 单个目录最多翻页次数（1000 页 × 1000 条），防止接口异常时无限翻页
+	maxBaiduListDirPages = 1000
+)
+
+var ErrBaiduPanClientNil = errors.New("baidu pan client is nil")
+
+// baiduPanSDK 抽象 baidupanplus 的 *WithConfig 调用，便于测试替换。
+type baiduPanSDK interface {
+	Upload(cfg baidupanplus.UploadFileConfig) error
+	Download(cfg baidupanplus.DownloadFileConfig) error
+	QueryDir(cfg *baidupanplus.QueryDirConfig, start int) (*baidupanplus.FileListResponse, error)
+	UploadPart(accessToken, remotePath, uploadID string, partSeq int, data []byte) error
+}
+
+type defaultBaiduPanSDK struct{}
+
+func (defaultBaiduPanSDK) Upload(cfg baidupanplus.UploadFileConfig) error {
+	return baidupanplus.UploadFileWithConfig(cfg)
+}
+
+func (defaultBaiduPanSDK) Download(cfg baidupanplus.DownloadFileConfig) error {
+	return baidupanplus.DownloadFileWithConfig(cfg)
+}
+
+// baiduOpenAPI SDK 未覆盖的调用直接走 openxpanapi：
+// QueryDirWithConfig 固定 start=0，分页列目录调用 xpanfilelist；
+// UploadPart 在响应为空时会空指针，分片上传调用 pcssuperfile2。
+var baiduOpenAPI = openapi.NewAPIClient(openapi.NewConfiguration())
+
+func (defaultBaiduPanSDK) QueryDir(cfg *baidupanplus.QueryDirConfig, start int) (*baidupanplus.FileListResponse, error) {
+	request := baiduOpenAPI.FileinfoApi.Xpanfilelist(context.Background()).
+		AccessToken(cfg.AccessToken).
+		Dir(cfg.Dir).
+		Start(strconv.Itoa(start)).
+		Limit(cfg.Limit)
+	raw, _, err := baiduOpenAPI.FileinfoApi.XpanfilelistExecute(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp baidupanplus.FileListResponse
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		return nil, fmt.Errorf("decode file list response failed: %w", err)
+	}
+	if resp.Errno != 0 {
+		return nil, fmt.Errorf("get file list failed with errno: %d", resp.Errno)
+	}
+	return &resp, nil
+}
+
+func (defaultBaiduPanSDK) UploadPart(accessToken, remotePath, uploadID string, partSeq int, data []byte) error {
+	tmpFile, err := os.CreateTemp("", "baidu-part-*")
+	if err != nil {
+		return fmt.Errorf("create part temp file failed: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+	if _, err := tmpFile.Write(data); err != nil {
+		return fmt.Errorf("write part temp file failed: %w", err)
+	}
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind part temp file failed: %w", err)
+	}
+
+	request := baiduOpenAPI.FileuploadApi.Pcssuperfile2(context.Background()).
+		AccessToken(accessToken).
+		Path(remotePath).
+		Uploadid(uploadID).
+		Type_("tmpfile").
+		Partseq(strconv.Itoa(partSeq)).
+		File(tmpFile)
+	if _, _, err := baiduOpenAPI.FileuploadApi.Pcssuperfile2Execute(request); err != nil {
+		return fmt.Errorf("upload part %d failed: %w", partSeq, err)
+	}
+	return nil
+}
+
+// BaiduPanClient 百度网盘 SDK 的共享访问层。
+// SDK 的 NewBasicConfig/NewXxxConfig 读写包级全局变量，并发调用会互相覆盖 token；
+// 这里每次请求直接构造独立的 *FileConfig 值（*WithConfig 只读取入参），
+// 全局状态仅在首次使用时初始化一次 SDK 日志，并用 slots 限制同时进行的请求数。
+type BaiduPanClient struct {
+	AccessToken string
+	IsSVIP      bool
+	LogPath     string
+	Tokens      BaiduTokenProvider
+	// APIBaseURL / HTTPClient 仅用于 SDK 未封装的接口（precreate / create）；为空时使用默认值。
+	APIBaseURL string
+	HTTPClient *http.Client
+
+	slots   chan struct{}
+	logOnce sync.Once
+	sdk     baiduPanSDK
+}
+
+var (
+	defaultBaiduPanClient     *BaiduPanClient
+	defaultBaiduPanClientOnce sync.Once
+)
+
+// DefaultBaiduPanClient 进程内共享的客户端，上传/下载/列目录共用同一并发上限。
+func DefaultBaiduPanClient() *BaiduPanClient {
+	defaultBaiduPanClientOnce.Do(func() {
+		defaultBaiduPanClient = NewBaiduPanClientFromConfig()
+	})
+	return defaultBaiduPanClient
+}
+
+func NewBaiduPanClientFromConfig() *BaiduPanClient {
+	var cfg config.BaiduPanConfig
+	if config.AppConfig != nil {
+		cfg = config.AppConfig.BaiduPan
+	}
+
+	logPath := strings.TrimSpace(cfg.LogPath)
+	if logPath == "" {
+		logPath = DefaultBaiduPanLogPath
+	}
+
+	// access_token_credential_id 优先；解析失败时留空，调用时返回 ErrBaiduPanAccessTokenRequired。
+	accessToken, err := config.ResolveSecret(cfg.AccessTokenCredentialID, cfg.AccessToken)
+	if err != nil {
+		serviceLogger().With("service", "BaiduPanClient", "method", "NewBaiduPanClientFromConfig").
+			Error("resolve baidu access token failed", "credential_id", cfg.AccessTokenCredentialID, "error", err)
+	}
+
+	return NewBaiduPanClient(strings.TrimSpace(accessToken), cfg.IsSVIP, logPath, DefaultBaiduTokenManager(), cfg.MaxConcurrentTransfers)
+}
+
+// NewBaiduPanClient maxConcurrent <= 0 时使用 DefaultBaiduPanMaxConcurrent。
+func NewBaiduPanClient(accessToken string, isSVIP bool, logPath string, tokens BaiduTokenProvider, maxConcurrent int) *BaiduPanClient {
+	if maxConcurrent <= 0 {
+		maxConcurrent = DefaultBaiduPanMaxConcurrent
+	}
+	return &BaiduPanClient{
+		AccessToken: accessToken,
+		IsSVIP:      isSVIP,
+		LogPath:     logPath,
+		Tokens:      tokens,
+		slots:       make(chan struct{}, maxConcurrent),
+		sdk:         defaultBaiduPanSDK{},
+	}
+}
+
+// MaxConcurrent 返回并发上限
+func (c *BaiduPanClient) MaxConcurrent() int {
+	return cap(c.slots)
+}
+
+// Upload 上传本地文件到 remotePath（完整路径）
+func (c *BaiduPanClient) Upload(ctx context.Context, localPath, remotePath string) error {
+	return c.do(ctx, func(base baidupanplus.Config) error {
+		base.Operate = baidupanplus.UploadFileOperate
+		return c.sdk.Upload(baidupanplus.UploadFileConfig{
+			Config:     base,
+			LocalPath:  localPath,
+			RemotePath: remotePath,
+		})
+	})
+}
+
+// Download 下载 remotePath 到本地 localPath
+func (c *BaiduPanClient) Download(ctx context.Context, remotePath, localPath string) error {
+	return c.do(ctx, func(base baidupanplus.Config) error {
+		base.Operate = baidupanplus.DownloadFileOperate
+		return c.sdk.Download(baidupanplus.DownloadFileConfig{
+			Config:     base,
+			LocalPath:  localPath,
+			RemotePath: remotePath,
+		})
+	})
+}
+
+// QueryDir 列出目录的一页：从第 start 条开始，最多 limit 条
+func (c *BaiduPanClient) QueryDir(ctx context.Context, dir string, start, limit int) (*baidupanplus.FileListResponse, error) {
+	var resp *baidupanplus.FileListResponse
+	err := c.do(ctx, func(base baidupanplus.Config) error {
+		base.Operate = baidupanplus.QueryDirOperate
+		var queryErr error
+		resp, queryErr = c.sdk.QueryDir(&baidupanplus.QueryDirConfig{
+			Config: base,
+			Dir:    dir,
+			Limit:  int32(limit),
+		}, start)
+		return queryErr
+	})
+	return resp, err
+}
+
+// ListDirAll 按 baiduListDirLimit 翻页列出目录全部条目，直到某页不足一页为止；每页单独占用并发槽位。
+func (c *BaiduPanClient) ListDirAll(ctx context.Context, dir string) ([]openapi.Filecreateresponse, error) {
+	all := make([]openapi.Filecreateresponse, 0)
+	for page := 0; page < maxBaiduListDirPages; page++ {
+		resp, err := c.QueryDir(ctx, dir, page*baiduListDirLimit, baiduListDirLimit)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, resp.List...)
+		if len(resp.List) < baiduListDirLimit {
+			return all, nil
+		}
+	}
+	return nil, fmt.Errorf("list baidu pan dir %s: more than %d entries", dir, maxBaiduListDirPages*baiduListDirLimit)
+}
+
+// do 占用一个并发槽位后执行请求；鉴权失败时由 withBaiduTokenRetry 刷新 token 重试一次。
+func (c *BaiduPanClient) do(ctx context.Context, fn func(base baidupanplus.Config) error) error {
+	if c == nil {
+		return ErrBaiduPanClientNil
+	}
+	if c.Tokens == nil && strings.TrimSpace(c.AccessToken) == "" {
+		return ErrBaiduPanAccessTokenRequired
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	select {
+	case c.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-c.slots }()
+
+	c.initSDKLog()
+	return withBaiduTokenRetry(ctx, c.Tokens, c.AccessToken, func(token string) error {
+		return fn(baidupanplus.Config{
+			AccessToken: token,
+			IsSVIP:      c.IsSVIP,
+			LogPath:     c.LogPath,
+		})
+	})
+}
+
+// initSDKLog SDK 日志只能通过 NewBasicConfig 初始化，且每次调用都会重建日志输出，故只调用一次。
+func (c *BaiduPanClient) initSDKLog() {
+	if _, ok := c.sdk.(defaultBaiduPanSDK); !ok {
+		return
+	}
+	c.logOnce.Do(func() {
+		baidupanplus.NewBasicConfig("", c.IsSVIP, c.LogPath)
+	})
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	baidupanplus "github.com/S-zhi/baidupansdk/baidupanplus"
+	openapi "github.com/S-zhi/baidupansdk/openxpanapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBaiduPanSDK struct {
+	mu        sync.Mutex
+	inFlight  int32
+	maxSeen   int32
+	hold      time.Duration
+	uploads   []baidupanplus.UploadFileConfig
+	downloads []baidupanplus.DownloadFileConfig
+	queries   []baidupanplus.QueryDirConfig
+	starts    []int
+	parts     []int
+	list      []openapi.Filecreateresponse
+	err       error
+}
+
+func (f *fakeBaiduPanSDK) enter() {
+	current := atomic.AddInt32(&f.inFlight, 1)
+	for {
+		seen := atomic.LoadInt32(&f.maxSeen)
+		if current <= seen || atomic.CompareAndSwapInt32(&f.maxSeen, seen, current) {
+			break
+		}
+	}
+	time.Sleep(f.hold)
+	atomic.AddInt32(&f.inFlight, -1)
+}
+
+func (f *fakeBaiduPanSDK) Upload(cfg baidupanplus.UploadFileConfig) error {
+	f.enter()
+	f.mu.Lock()
+	f.uploads = append(f.uploads, cfg)
+	f.mu.Unlock()
+	return f.err
+}
+
+func (f *fakeBaiduPanSDK) Download(cfg baidupanplus.DownloadFileConfig) error {
+	f.enter()
+	f.mu.Lock()
+	f.downloads = append(f.downloads, cfg)
+	f.mu.Unlock()
+	return f.err
+}
+
+func (f *fakeBaiduPanSDK) UploadPart(accessToken, remotePath, uploadID string, partSeq int, data []byte) error {
+	f.mu.Lock()
+	f.parts = append(f.parts, partSeq)
+	f.mu.Unlock()
+	return f.err
+}
+
+// QueryDir 按 start/limit 返回 list 的一页
+func (f *fakeBaiduPanSDK) QueryDir(cfg *baidupanplus.QueryDirConfig, start int) (*baidupanplus.FileListResponse, error) {
+	f.enter()
+	f.mu.Lock()
+	f.queries = append(f.queries, *cfg)
+	f.starts = append(f.starts, start)
+	f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	if start >= len(f.list) {
+		return &baidupanplus.FileListResponse{List: []openapi.Filecreateresponse{}}, nil
+	}
+	end := start + int(cfg.Limit)
+	if end > len(f.list) {
+		end = len(f.list)
+	}
+	return &baidupanplus.FileListResponse{List: f.list[start:end]}, nil
+}
+
+func newTestBaiduPanClient(sdk baiduPanSDK, maxConcurrent int) *BaiduPanClient {
+	client := NewBaiduPanClient("static-token", true, "logs/test.log", nil, maxConcurrent)
+	client.sdk = sdk
+	return client
+}
+
+func TestBaiduPanClientBuildsPerRequestConfig(t *testing.T) {
+	sdk := &fakeBaiduPanSDK{}
+	client := newTestBaiduPanClient(sdk, 2)
+
+	require.NoError(t, client.Upload(context.Background(), "/tmp/a.pt", "/w/a.pt"))
+	require.NoError(t, client.Download(context.Background(), "/w/b.pt", "/tmp/b.pt"))
+	_, err := client.QueryDir(context.Background(), "/w", 0, 1000)
+	require.NoError(t, err)
+
+	require.Len(t, sdk.uploads, 1)
+	assert.Equal(t, "static-token", sdk.uploads[0].AccessToken)
+	assert.Equal(t, baidupanplus.UploadFileOperate, sdk.uploads[0].Operate)
+	assert.True(t, sdk.uploads[0].IsSVIP)
+	assert.Equal(t, "/tmp/a.pt", sdk.uploads[0].LocalPath)
+	assert.Equal(t, "/w/a.pt", sdk.uploads[0].RemotePath)
+
+	require.Len(t, sdk.downloads, 1)
+	assert.Equal(t, baidupanplus.DownloadFileOperate, sdk.downloads[0].Operate)
+	assert.Equal(t, "/w/b.pt", sdk.downloads[0].RemotePath)
+
+	require.Len(t, sdk.queries, 1)
+	assert.Equal(t, baidupanplus.QueryDirOperate, sdk.queries[0].Operate)
+	assert.EqualValues(t, 1000, sdk.queries[0].Limit)
+}
+
+func TestBaiduPanClientBoundsConcurrency(t *testing.T) {
+	sdk := &fakeBaiduPanSDK{hold: 20 * time.Millisecond}
+	client := newTestBaiduPanClient(sdk, 2)
+	assert.Equal(t, 2, client.MaxConcurrent())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				assert.NoError(t, client.Upload(context.Background(), "/tmp/a.pt", "/w/a.pt"))
+			} else {
+				assert.NoError(t, client.Download(context.Background(), "/w/a.pt", "/tmp/a.pt"))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 2, atomic.LoadInt32(&sdk.maxSeen))
+	assert.Len(t, sdk.uploads, 4)
+	assert.Len(t, sdk.downloads, 4)
+}
+
+func TestBaiduPanClientWaitHonoursContext(t *testing.T) {
+	sdk := &fakeBaiduPanSDK{hold: 200 * time.Millisecond}
+	client := newTestBaiduPanClient(sdk, 1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = client.Download(context.Background(), "/w/a.pt", "/tmp/a.pt")
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := client.Upload(ctx, "/tmp/b.pt", "/w/b.pt")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	<-done
+	assert.Empty(t, sdk.uploads)
+}
+
+func TestBaiduPanClientRequiresToken(t *testing.T) {
+	client := NewBaiduPanClient("", false, "", nil, 0)
+	client.sdk = &fakeBaiduPanSDK{}
+	assert.Equal(t, DefaultBaiduPanMaxConcurrent, client.MaxConcurrent())
+	assert.ErrorIs(t, client.Upload(context.Background(), "/tmp/a.pt", "/w/a.pt"), ErrBaiduPanAccessTokenRequired)
+
+	var nilClient *BaiduPanClient
+	assert.ErrorIs(t, nilClient.Download(context.Background(), "/w/a.pt", "/tmp/a.pt"), ErrBaiduPanClientNil)
+}
+
+func TestBaiduPanDownloaderWrapsClientError(t *testing.T) {
+	sdk := &fakeBaiduPanSDK{err: errors.New("boom")}
+	downloader := &BaiduPanDownloader{Client: newTestBaiduPanClient(sdk, 1)}
+
+	err := downloader.Download("/w/a.pt", "/tmp/a.pt")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "download file from baidu pan failed: boom")
+
+	assert.ErrorIs(t, (&BaiduPanDownloader{}).Download("/w/a.pt", "/tmp/a.pt"), ErrBaiduPanClientNil)
+}
+
+func TestBaiduPanClientListDirAllPages(t *testing.T) {
+	sdk := &fakeBaiduPanSDK{}
+	for i := 0; i < 2*baiduListDirLimit+5; i++ {
+		sdk.list = append(sdk.list, fakeBaiduListEntry(fmt.Sprintf("f%04d.pt", i), 1, ""))
+	}
+	client := newTestBaiduPanClient(sdk, 2)
+
+	list, err := client.ListDirAll(context.Background(), "/w")
+	require.NoError(t, err)
+	assert.Len(t, list, 2*baiduListDirLimit+5)
+	assert.Equal(t, []int{0, baiduListDirLimit, 2 * baiduListDirLimit}, sdk.starts)
+
+	// 恰好整页时多请求一页空结果确认结束
+	sdk.list = sdk.list[:baiduListDirLimit]
+	sdk.starts = nil
+	list, err = client.ListDirAll(context.Background(), "/w")
+	require.NoError(t, err)
+	assert.Len(t, list, baiduListDirLimit)
+	assert.Equal(t, []int{0, baiduListDirLimit}, sdk.starts)
+}
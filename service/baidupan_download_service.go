@@ -1,14 +1,12 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"sync"
-
-	baidupanplus "github.com/S-zhi/baidupansdk/baidupanplus"
 )
 
 var (
@@ -42,25 +40,18 @@ type BaiduDownloadService struct {
 	Downloader  BaiduDownloader
 }
 
+// BaiduPanDownloader 从百度网盘下载，请求经由共享的 BaiduPanClient 限流。
 type BaiduPanDownloader struct {
-	AccessToken string
-	IsSVIP      bool
-	LogPath     string
-	mu          sync.Mutex
+	Client *BaiduPanClient
 }
 
 func NewBaiduPanDownloaderFromConfig() *BaiduPanDownloader {
-	uploader := NewBaiduPanUploaderFromConfig()
-	return &BaiduPanDownloader{
-		AccessToken: uploader.AccessToken,
-		IsSVIP:      uploader.IsSVIP,
-		LogPath:     uploader.LogPath,
-	}
+	return &BaiduPanDownloader{Client: DefaultBaiduPanClient()}
 }
 
 func (d *BaiduPanDownloader) Download(remotePath, localPath string) error {
-	if strings.TrimSpace(d.AccessToken) == "" {
-		return ErrBaiduPanAccessTokenRequired
+	if d.Client == nil {
+		return ErrBaiduPanClientNil
 	}
 
 	normalizedRemote, err := normalizeBaiduRemotePath(remotePath)
@@ -73,12 +64,10 @@ func (d *BaiduPanDownloader) Download(remotePath, localPath string) error {
 		return ErrInvalidLocalDownloadFile
 	}
 
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
-	baidupanplus.NewBasicConfig(d.AccessToken, d.IsSVIP, d.LogPath)
-	downloadConfig := baidupanplus.NewDownloadFileConfig(normalizedLocal, normalizedRemote)
-	if err := baidupanplus.DownloadFileWithConfig(downloadConfig); err != nil {
+	if err := d.Client.Download(context.Background(), normalizedRemote, normalizedLocal); err != nil {
+		if errors.Is(err, ErrBaiduPanAccessTokenRequired) {
+			return err
+		}
 		return fmt.Errorf("download file from baidu pan failed: %w", err)
 	}
 	return nil
@@ -0,0 +1,645 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// maxBaiduSidecarBytes sidecar 元数据文件大小上限
+	maxBaiduSidecarBytes = 1 << 20
+	// maxBaiduImportDirs 单次导入最多遍历的目录数（含起始目录），防止误选根目录时无限遍历
+	maxBaiduImportDirs = 500
+	// maxBaiduImportJobs 内存中保留的导入任务数，超出时淘汰最早结束的任务
+	maxBaiduImportJobs = 100
+
+	BaiduImportJobStatusRunning   = "running"
+	BaiduImportJobStatusSucceeded = "succeeded"
+	BaiduImportJobStatusFailed    = "failed"
+)
+
+var (
+	ErrBaiduImportServiceNotReady = errors.New("baidu import service is not ready")
+	ErrBaiduSidecarTooLarge       = errors.New("baidu sidecar metadata file is too large")
+	ErrBaiduImportRunning         = errors.New("baidu import of this dir is already running")
+	ErrBaiduImportJobNotFound     = errors.New("baidu import job not found")
+	ErrBaiduImportTooManyDirs     = errors.New("baidu import dir has too many subdirectories")
+)
+
+var baiduSidecarExts = []string{".json", ".yaml", ".yml"}
+
+// BaiduImportRequest POST /v1/baidu/import 请求体
+type BaiduImportRequest struct {
+	Dir      string `json:"dir"`
+	Download bool   `json:"download"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// BaiduImportItem 导入报告中的单个文件
+type BaiduImportItem struct {
+	FileName      string `json:"file_name"`
+	RemotePath    string `json:"remote_path"`
+	Sidecar       string `json:"sidecar,omitempty"`
+	RecordID      uint   `json:"record_id,omitempty"`
+	RecordName    string `json:"record_name,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	Downloaded    bool   `json:"downloaded,omitempty"`
+	LocalPath     string `json:"local_path,omitempty"`
+	DownloadError string `json:"download_error,omitempty"`
+}
+
+// BaiduImportResult 导入报告；Total 为遍历到的文件数，DirCount 为遍历的目录数（含起始目录）。
+type BaiduImportResult struct {
+	Dir          string            `json:"dir"`
+	Category     string            `json:"category"`
+	DryRun       bool              `json:"dry_run"`
+	Download     bool              `json:"download"`
+	DirCount     int               `json:"dir_count"`
+	Total        int               `json:"total"`
+	CreatedCount int               `json:"created_count"`
+	SkippedCount int               `json:"skipped_count"`
+	FailedCount  int               `json:"failed_count"`
+	Created      []BaiduImportItem `json:"created"`
+	Skipped      []BaiduImportItem `json:"skipped"`
+	Failed       []BaiduImportItem `json:"failed"`
+}
+
+// BaiduImportJob 后台导入任务；任务只保存在内存中，服务重启后丢失。
+type BaiduImportJob struct {
+	ID         uint64             `json:"id"`
+	Status     string             `json:"status"`
+	Dir        string             `json:"dir"`
+	Category   string             `json:"category"`
+	DryRun     bool               `json:"dry_run"`
+	Download   bool               `json:"download"`
+	CreatedAt  time.Time          `json:"created_at"`
+	FinishedAt *time.Time         `json:"finished_at"`
+	Error      string             `json:"error,omitempty"`
+	Result     *BaiduImportResult `json:"result"`
+}
+
+type baiduImportCandidate struct {
+	entry   BaiduFileEntry
+	sidecar *BaiduFileEntry
+}
+
+// baiduImportDir 遍历到的一个目录及其条目
+type baiduImportDir struct {
+	dir     string
+	entries []BaiduFileEntry
+}
+
+// BaiduImportService 将百度网盘目录（含子目录）批量登记为模型/数据集记录。
+// 导入以后台任务执行，同一目录同时只允许一个任务。
+type BaiduImportService struct {
+	browseService   *BaiduFileBrowseService
+	downloadService *BaiduDownloadService
+	modelService    *ModelService
+	datasetService  *DatasetService
+
+	mu     sync.Mutex
+	nextID uint64
+	jobs   map[uint64]*BaiduImportJob
+}
+
+func NewBaiduImportService() *BaiduImportService {
+	return &BaiduImportService{
+		browseService:   NewBaiduFileBrowseService(),
+		downloadService: NewBaiduDownloadService(),
+		modelService:    NewModelService(),
+		datasetService:  NewDatasetService(),
+	}
+}
+
+// StartImport 校验目录后创建后台导入任务并立即返回；目录非法时直接返回错误，同一目录已有任务在执行时返回 ErrBaiduImportRunning。
+func (s *BaiduImportService) StartImport(req BaiduImportRequest) (BaiduImportJob, error) {
+	logger := serviceLogger().With("service", "BaiduImportService", "method", "StartImport")
+	if err := s.ready(); err != nil {
+		return BaiduImportJob{}, err
+	}
+	dir, category, err := s.browseService.resolveBrowseDir(req.Dir)
+	if err != nil {
+		logger.Warn("start import failed: invalid dir", "dir", req.Dir, "error", err)
+		return BaiduImportJob{}, err
+	}
+
+	s.mu.Lock()
+	for _, job := range s.jobs {
+		if job.Status == BaiduImportJobStatusRunning && job.Dir == dir {
+			s.mu.Unlock()
+			return BaiduImportJob{}, fmt.Errorf("%w: job %d", ErrBaiduImportRunning, job.ID)
+		}
+	}
+	if s.jobs == nil {
+		s.jobs = make(map[uint64]*BaiduImportJob)
+	}
+	s.nextID++
+	job := &BaiduImportJob{
+		ID:        s.nextID,
+		Status:    BaiduImportJobStatusRunning,
+		Dir:       dir,
+		Category:  category,
+		DryRun:    req.DryRun,
+		Download:  req.Download,
+		CreatedAt: time.Now(),
+	}
+	s.jobs[job.ID] = job
+	s.pruneJobsLocked()
+	snapshot := *job
+	s.mu.Unlock()
+
+	logger.Info("import job started", "job_id", job.ID, "dir", dir, "dry_run", req.DryRun, "download", req.Download)
+	req.Dir = dir
+	go s.runJob(job.ID, req)
+	return snapshot, nil
+}
+
+// GetImportJob 返回导入任务的当前状态，任务结束后 result 为导入报告。
+func (s *BaiduImportService) GetImportJob(id uint64) (BaiduImportJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return BaiduImportJob{}, ErrBaiduImportJobNotFound
+	}
+	return *job, nil
+}
+
+// runJob 任务与发起请求解耦，使用独立的 context，请求结束不会中断导入。
+func (s *BaiduImportService) runJob(id uint64, req BaiduImportRequest) {
+	logger := serviceLogger().With("service", "BaiduImportService", "method", "runJob")
+	result, err := s.Import(context.Background(), req)
+	finishedAt := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	job.FinishedAt = &finishedAt
+	if err != nil {
+		job.Status = BaiduImportJobStatusFailed
+		job.Error = err.Error()
+		logger.Error("import job failed", "job_id", id, "dir", req.Dir, "error", err)
+		return
+	}
+	job.Status = BaiduImportJobStatusSucceeded
+	job.Result = &result
+}
+
+// pruneJobsLocked 超出 maxBaiduImportJobs 时按结束时间淘汰已结束的任务；调用方需持有 s.mu。
+func (s *BaiduImportService) pruneJobsLocked() {
+	if len(s.jobs) <= maxBaiduImportJobs {
+		return
+	}
+	finished := make([]*BaiduImportJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		if job.FinishedAt != nil {
+			finished = append(finished, job)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].FinishedAt.Before(*finished[j].FinishedAt) })
+	for _, job := range finished {
+		if len(s.jobs) <= maxBaiduImportJobs {
+			return
+		}
+		delete(s.jobs, job.ID)
+	}
+}
+
+func (s *BaiduImportService) ready() error {
+	if s.browseService == nil || s.browseService.Lister == nil || s.downloadService == nil ||
+		s.modelService == nil || s.datasetService == nil {
+		return ErrBaiduImportServiceNotReady
+	}
+	if s.browseService.PathService == nil {
+		return ErrArtifactPathServiceNil
+	}
+	return nil
+}
+
+// Import 同步遍历目录及其子目录并导入：
+// 1. 已按 weight_name/file_name 登记的文件跳过；记录以文件名关联，不同子目录中的同名文件只导入第一个；
+// 2. 同目录下与制品配对的 sidecar（<file>.json|yaml|yml 或 <stem>.json|yaml|yml）中的字段作为记录元数据；
+// 3. storage_server 设置为 baidu_netdisk，download=true 时再下载到后端并追加 backend。
+func (s *BaiduImportService) Import(ctx context.Context, req BaiduImportRequest) (BaiduImportResult, error) {
+	logger := serviceLogger().With("service", "BaiduImportService", "method", "Import")
+	start := time.Now()
+	if err := s.ready(); err != nil {
+		return BaiduImportResult{}, err
+	}
+
+	dir, category, err := s.browseService.resolveBrowseDir(req.Dir)
+	if err != nil {
+		logger.Warn("import failed: invalid dir", "dir", req.Dir, "error", err)
+		return BaiduImportResult{}, err
+	}
+
+	dirs, err := s.walkDir(dir)
+	if err != nil {
+		logger.Error("import failed: list dir failed", "dir", dir, "error", err)
+		return BaiduImportResult{}, err
+	}
+
+	names := make([]string, 0)
+	for _, listed := range dirs {
+		for _, entry := range listed.entries {
+			if !entry.IsDir {
+				names = append(names, entry.Name)
+			}
+		}
+	}
+	existing := make(map[string]bool)
+	if len(names) > 0 {
+		records, err := s.browseService.findRecords(ctx, category, names)
+		if err != nil {
+			logger.Error("import failed: query records failed", "dir", dir, "error", err)
+			return BaiduImportResult{}, err
+		}
+		for _, record := range records {
+			existing[strings.TrimSpace(record.fileName)] = true
+		}
+	}
+
+	candidates := make([]baiduImportCandidate, 0)
+	skipped := make([]BaiduImportItem, 0)
+	seen := make(map[string]string)
+	for _, listed := range dirs {
+		dirCandidates, dirSkipped := planBaiduImport(listed.entries, existing)
+		skipped = append(skipped, dirSkipped...)
+		for _, candidate := range dirCandidates {
+			if first, ok := seen[candidate.entry.Name]; ok {
+				skipped = append(skipped, BaiduImportItem{
+					FileName:   candidate.entry.Name,
+					RemotePath: candidate.entry.Path,
+					Reason:     "duplicate file name of " + first,
+				})
+				continue
+			}
+			seen[candidate.entry.Name] = candidate.entry.Path
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	result := BaiduImportResult{
+		Dir:      dir,
+		Category: category,
+		DryRun:   req.DryRun,
+		Download: req.Download,
+		DirCount: len(dirs),
+		Total:    len(names),
+		Created:  make([]BaiduImportItem, 0, len(candidates)),
+		Skipped:  skipped,
+		Failed:   make([]BaiduImportItem, 0),
+	}
+
+	for _, candidate := range candidates {
+		item, err := s.importOne(ctx, category, candidate, req)
+		if err != nil {
+			item.Reason = err.Error()
+			logger.Warn("import item failed", "dir", dir, "file_name", candidate.entry.Name, "error", err)
+			result.Failed = append(result.Failed, item)
+			continue
+		}
+		result.Created = append(result.Created, item)
+	}
+
+	result.CreatedCount = len(result.Created)
+	result.SkippedCount = len(result.Skipped)
+	result.FailedCount = len(result.Failed)
+	logger.Info(
+		"import success",
+		"dir", dir,
+		"category", category,
+		"dry_run", req.DryRun,
+		"download", req.Download,
+		"dirs", result.DirCount,
+		"created", result.CreatedCount,
+		"skipped", result.SkippedCount,
+		"failed", result.FailedCount,
+		"cost_ms", time.Since(start).Milliseconds(),
+	)
+	return result, nil
+}
+
+// walkDir 广度优先列出 root 及其全部子目录，子目录按路径排序；目录数超过 maxBaiduImportDirs 时返回错误。
+func (s *BaiduImportService) walkDir(root string) ([]baiduImportDir, error) {
+	dirs := make([]baiduImportDir, 0)
+	queue := []string{root}
+	for len(queue) > 0 {
+		if len(dirs) >= maxBaiduImportDirs {
+			return nil, fmt.Errorf("%w: more than %d dirs under %s", ErrBaiduImportTooManyDirs, maxBaiduImportDirs, root)
+		}
+		dir := queue[0]
+		queue = queue[1:]
+		entries, err := s.browseService.Lister.ListDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, baiduImportDir{dir: dir, entries: entries})
+
+		children := make([]string, 0)
+		for _, entry := range entries {
+			if !entry.IsDir {
+				continue
+			}
+			child := entry.Path
+			if strings.TrimSpace(child) == "" {
+				child = path.Join(dir, entry.Name)
+			}
+			children = append(children, child)
+		}
+		sort.Strings(children)
+		queue = append(queue, children...)
+	}
+	return dirs, nil
+}
+
+func (s *BaiduImportService) importOne(ctx context.Context, category string, candidate baiduImportCandidate, req BaiduImportRequest) (BaiduImportItem, error) {
+	item := BaiduImportItem{
+		FileName:   candidate.entry.Name,
+		RemotePath: candidate.entry.Path,
+	}
+
+	var metadata map[string]interface{}
+	if candidate.sidecar != nil {
+		item.Sidecar = candidate.sidecar.Path
+		data, err := s.fetchSidecar(*candidate.sidecar)
+		if err != nil {
+			return item, err
+		}
+		metadata, err = parseBaiduSidecar(data)
+		if err != nil {
+			return item, fmt.Errorf("parse sidecar %s failed: %w", candidate.sidecar.Name, err)
+		}
+	}
+
+	var recordID uint
+	switch category {
+	case ArtifactCategoryWeights:
+		model, err := buildImportModel(candidate.entry, metadata)
+		if err != nil {
+			return item, err
+		}
+		item.RecordName = model.Name
+		if req.DryRun {
+			return item, nil
+		}
+		if err := s.modelService.CreateModel(ctx, model); err != nil {
+			return item, fmt.Errorf("create model failed: %w", err)
+		}
+		recordID = model.ID
+	default:
+		dataset, err := buildImportDataset(candidate.entry, metadata)
+		if err != nil {
+			return item, err
+		}
+		item.RecordName = dataset.Name
+		if req.DryRun {
+			return item, nil
+		}
+		if err := s.datasetService.CreateDataset(ctx, dataset); err != nil {
+			return item, fmt.Errorf("create dataset failed: %w", err)
+		}
+		recordID = dataset.ID
+	}
+	item.RecordID = recordID
+
+	if !req.Download {
+		return item, nil
+	}
+	// 下载失败不回滚记录：记录仍有效（baidu_netdisk 副本存在），仅在报告中标注。
+	downloaded, err := s.downloadService.DownloadToLocal(candidate.entry.Path, category, candidate.entry.Name)
+	if err != nil {
+		item.DownloadError = err.Error()
+		return item, nil
+	}
+	item.LocalPath = downloaded.LocalPath
+	if category == ArtifactCategoryWeights {
+		_, err = s.modelService.UpdateStorageServersByID(ctx, recordID, dao.StorageActionAdd, []string{StorageTargetBackend})
+	} else {
+		_, err = s.datasetService.UpdateStorageServersByID(ctx, recordID, dao.StorageActionAdd, []string{StorageTargetBackend})
+	}
+	if err != nil {
+		item.DownloadError = fmt.Sprintf("update storage_server failed: %v", err)
+		return item, nil
+	}
+	item.Downloaded = true
+	return item, nil
+}
+
+// fetchSidecar 将 sidecar 下载到临时目录后读取内容。
+func (s *BaiduImportService) fetchSidecar(entry BaiduFileEntry) ([]byte, error) {
+	if entry.Size > maxBaiduSidecarBytes {
+		return nil, fmt.Errorf("%w: %s", ErrBaiduSidecarTooLarge, entry.Name)
+	}
+	if s.downloadService.Downloader == nil {
+		return nil, ErrBaiduDownloaderNil
+	}
+	tmpDir, err := os.MkdirTemp("", "baidu-sidecar-*")
+	if err != nil {
+		return nil, fmt.Errorf("create sidecar temp dir failed: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	localPath := filepath.Join(tmpDir, filepath.Base(entry.Name))
+	if err := s.downloadService.Downloader.Download(entry.Path, localPath); err != nil {
+		return nil, fmt.Errorf("download sidecar %s failed: %w", entry.Name, err)
+	}
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("read sidecar %s failed: %w", entry.Name, err)
+	}
+	if len(data) > maxBaiduSidecarBytes {
+		return nil, fmt.Errorf("%w: %s", ErrBaiduSidecarTooLarge, entry.Name)
+	}
+	return data, nil
+}
+
+// planBaiduImport 处理单个目录的条目（子目录由 walkDir 展开，这里忽略），区分待导入文件、sidecar 与跳过项。
+// 只有能与同目录制品配对的 .json/.yaml/.yml 才视为 sidecar，其余同扩展名文件按普通制品导入。
+func planBaiduImport(entries []BaiduFileEntry, existing map[string]bool) ([]baiduImportCandidate, []BaiduImportItem) {
+	files := make([]BaiduFileEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir {
+			files = append(files, entry)
+		}
+	}
+
+	sidecarNames := baiduSidecarCandidates(files)
+	sidecars := make(map[string]BaiduFileEntry)
+	artifacts := make([]BaiduFileEntry, 0, len(files))
+	skipped := make([]BaiduImportItem, 0)
+	for _, entry := range files {
+		if sidecarNames[entry.Name] {
+			sidecars[entry.Name] = entry
+			continue
+		}
+		artifacts = append(artifacts, entry)
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Name < artifacts[j].Name })
+
+	used := make(map[string]bool, len(sidecars))
+	candidates := make([]baiduImportCandidate, 0, len(artifacts))
+	for _, entry := range artifacts {
+		var sidecar *BaiduFileEntry
+		for _, name := range baiduSidecarNames(entry.Name) {
+			if found, ok := sidecars[name]; ok {
+				found := found
+				sidecar = &found
+				used[name] = true
+				break
+			}
+		}
+		if existing[entry.Name] {
+			skipped = append(skipped, BaiduImportItem{FileName: entry.Name, RemotePath: entry.Path, Reason: "already registered"})
+			continue
+		}
+		candidates = append(candidates, baiduImportCandidate{entry: entry, sidecar: sidecar})
+	}
+
+	unusedNames := make([]string, 0)
+	for name := range sidecars {
+		if !used[name] {
+			unusedNames = append(unusedNames, name)
+		}
+	}
+	sort.Strings(unusedNames)
+	for _, name := range unusedNames {
+		skipped = append(skipped, BaiduImportItem{FileName: name, RemotePath: sidecars[name].Path, Reason: "metadata file superseded by another sidecar"})
+	}
+	return candidates, skipped
+}
+
+// baiduSidecarCandidates 返回同目录中作为 sidecar 的文件名：
+// <file>.ext 需存在名为 <file> 的文件；<stem>.ext 需存在去扩展名后为 <stem> 且自身不是 .json/.yaml/.yml 的文件，
+// 避免 data.json 与 data.yaml 这类普通文件互相配对。
+func baiduSidecarCandidates(files []BaiduFileEntry) map[string]bool {
+	byName := make(map[string]bool, len(files))
+	stems := make(map[string]bool, len(files))
+	for _, entry := range files {
+		byName[entry.Name] = true
+		if !hasBaiduSidecarExt(entry.Name) {
+			if stem := strings.TrimSuffix(entry.Name, path.Ext(entry.Name)); stem != "" && stem != entry.Name {
+				stems[stem] = true
+			}
+		}
+	}
+
+	sidecars := make(map[string]bool)
+	for _, entry := range files {
+		if !hasBaiduSidecarExt(entry.Name) {
+			continue
+		}
+		base := strings.TrimSuffix(entry.Name, path.Ext(entry.Name))
+		if base == "" {
+			continue
+		}
+		if (byName[base] && base != entry.Name) || stems[base] {
+			sidecars[entry.Name] = true
+		}
+	}
+	return sidecars
+}
+
+func hasBaiduSidecarExt(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, candidate := range baiduSidecarExts {
+		if ext == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// baiduSidecarNames 按优先级返回制品对应的 sidecar 文件名：先 <file>.ext，后 <stem>.ext。
+func baiduSidecarNames(fileName string) []string {
+	stem := strings.TrimSuffix(fileName, path.Ext(fileName))
+	names := make([]string, 0, 2*len(baiduSidecarExts))
+	for _, ext := range baiduSidecarExts {
+		names = append(names, fileName+ext)
+	}
+	if stem != "" && stem != fileName {
+		for _, ext := range baiduSidecarExts {
+			names = append(names, stem+ext)
+		}
+	}
+	return names
+}
+
+// parseBaiduSidecar 解析 JSON/YAML sidecar（JSON 是 YAML 的子集，统一按 YAML 解析）。
+func parseBaiduSidecar(data []byte) (map[string]interface{}, error) {
+	metadata := make(map[string]interface{})
+	if strings.TrimSpace(string(data)) == "" {
+		return metadata, nil
+	}
+	if err := yaml.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// decodeImportMetadata 按记录的 JSON 字段名（与 POST /v1/models、/v1/datasets 一致）填充实体。
+func decodeImportMetadata(metadata map[string]interface{}, target interface{}) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("encode sidecar metadata failed: %w", err)
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return fmt.Errorf("decode sidecar metadata failed: %w", err)
+	}
+	return nil
+}
+
+func buildImportModel(entry BaiduFileEntry, metadata map[string]interface{}) (*entity2.Model, error) {
+	model := &entity2.Model{}
+	if err := decodeImportMetadata(metadata, model); err != nil {
+		return nil, err
+	}
+	model.ID = 0
+	model.WeightName = entry.Name
+	model.LegacyFileName = ""
+	model.LegacyModelPath = ""
+	model.StorageServer = StorageTargetBaiduNetdisk
+	if strings.TrimSpace(model.Name) == "" {
+		model.Name = strings.TrimSuffix(entry.Name, path.Ext(entry.Name))
+	}
+	if model.WeightSizeMB <= 0 {
+		model.WeightSizeMB = bytesToMB(entry.Size)
+	}
+	return model, nil
+}
+
+func buildImportDataset(entry BaiduFileEntry, metadata map[string]interface{}) (*entity2.Dataset, error) {
+	dataset := &entity2.Dataset{}
+	if err := decodeImportMetadata(metadata, dataset); err != nil {
+		return nil, err
+	}
+	dataset.ID = 0
+	dataset.FileName = entry.Name
+	dataset.StorageServer = StorageTargetBaiduNetdisk
+	if strings.TrimSpace(dataset.Name) == "" {
+		dataset.Name = strings.TrimSuffix(entry.Name, path.Ext(entry.Name))
+	}
+	if dataset.SizeMB <= 0 {
+		dataset.SizeMB = bytesToMB(entry.Size)
+	}
+	return dataset, nil
+}
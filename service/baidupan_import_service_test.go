@@ -0,0 +1,204 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanBaiduImport(t *testing.T) {
+	entries := []BaiduFileEntry{
+		{Name: "yolo-b.pt", Path: "/w/yolo-b.pt"},
+		{Name: "yolo-b.yaml", Path: "/w/yolo-b.yaml"},
+		{Name: "yolo-a.pt", Path: "/w/yolo-a.pt"},
+		{Name: "yolo-a.pt.json", Path: "/w/yolo-a.pt.json"},
+		{Name: "yolo-a.json", Path: "/w/yolo-a.json"},
+		{Name: "known.pt", Path: "/w/known.pt"},
+		{Name: "known.pt.yml", Path: "/w/known.pt.yml"},
+		{Name: "orphan.yml", Path: "/w/orphan.yml"},
+		{Name: "archive", Path: "/w/archive", IsDir: true},
+	}
+
+	candidates, skipped := planBaiduImport(entries, map[string]bool{"known.pt": true})
+	require.Len(t, candidates, 3)
+	assert.Equal(t, "orphan.yml", candidates[0].entry.Name)
+	assert.Nil(t, candidates[0].sidecar)
+	assert.Equal(t, "yolo-a.pt", candidates[1].entry.Name)
+	require.NotNil(t, candidates[1].sidecar)
+	assert.Equal(t, "yolo-a.pt.json", candidates[1].sidecar.Name)
+	assert.Equal(t, "yolo-b.pt", candidates[2].entry.Name)
+	require.NotNil(t, candidates[2].sidecar)
+	assert.Equal(t, "/w/yolo-b.yaml", candidates[2].sidecar.Path)
+
+	reasons := make(map[string]string, len(skipped))
+	for _, item := range skipped {
+		reasons[item.FileName] = item.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"known.pt":    "already registered",
+		"yolo-a.json": "metadata file superseded by another sidecar",
+	}, reasons)
+}
+
+func TestBaiduSidecarCandidatesOnlyPairsWithArtifacts(t *testing.T) {
+	files := []BaiduFileEntry{
+		{Name: "coco.json"},
+		{Name: "coco.yaml"},
+		{Name: "coco.json.yaml"},
+		{Name: "best.pt"},
+		{Name: "best.yml"},
+		{Name: ".json"},
+	}
+	assert.Equal(t, map[string]bool{"coco.json.yaml": true, "best.yml": true}, baiduSidecarCandidates(files))
+}
+
+func TestBaiduImportServiceWalkDirRecurses(t *testing.T) {
+	lister := &fakeBaiduDirLister{entries: map[string][]BaiduFileEntry{
+		"/w": {
+			{Name: "b", Path: "/w/b", IsDir: true},
+			{Name: "a", Path: "/w/a", IsDir: true},
+			{Name: "root.pt", Path: "/w/root.pt"},
+		},
+		"/w/a":      {{Name: "deep", Path: "/w/a/deep", IsDir: true}},
+		"/w/a/deep": {{Name: "x.pt", Path: "/w/a/deep/x.pt"}},
+	}}
+	svc := &BaiduImportService{browseService: &BaiduFileBrowseService{Lister: lister}}
+
+	dirs, err := svc.walkDir("/w")
+	require.NoError(t, err)
+	paths := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		paths = append(paths, dir.dir)
+	}
+	assert.Equal(t, []string{"/w", "/w/a", "/w/b", "/w/a/deep"}, paths)
+}
+
+func TestBaiduImportServiceRunsJobInBackground(t *testing.T) {
+	lister := &fakeBaiduDirLister{entries: map[string][]BaiduFileEntry{
+		"/project/luckyProject/weights": {{Name: "empty", Path: "/project/luckyProject/weights/empty", IsDir: true}},
+	}}
+	svc := &BaiduImportService{
+		browseService:   &BaiduFileBrowseService{PathService: NewArtifactPathService(), Lister: lister},
+		downloadService: &BaiduDownloadService{},
+		modelService:    &ModelService{},
+		datasetService:  &DatasetService{},
+	}
+
+	_, err := svc.StartImport(BaiduImportRequest{Dir: "/project/other"})
+	assert.ErrorIs(t, err, ErrBaiduDirOutsideRoots)
+
+	job, err := svc.StartImport(BaiduImportRequest{Dir: "project/luckyProject/weights/", DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), job.ID)
+	assert.Equal(t, "/project/luckyProject/weights", job.Dir)
+	assert.Equal(t, ArtifactCategoryWeights, job.Category)
+
+	require.Eventually(t, func() bool {
+		current, err := svc.GetImportJob(job.ID)
+		return err == nil && current.Status != BaiduImportJobStatusRunning
+	}, time.Second, 10*time.Millisecond)
+
+	finished, err := svc.GetImportJob(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, BaiduImportJobStatusSucceeded, finished.Status)
+	require.NotNil(t, finished.Result)
+	assert.Equal(t, 2, finished.Result.DirCount)
+	assert.Equal(t, 0, finished.Result.Total)
+	assert.NotNil(t, finished.FinishedAt)
+
+	_, err = svc.GetImportJob(99)
+	assert.ErrorIs(t, err, ErrBaiduImportJobNotFound)
+}
+
+func TestBaiduImportServiceRejectsConcurrentImportOfSameDir(t *testing.T) {
+	svc := &BaiduImportService{
+		browseService:   &BaiduFileBrowseService{PathService: NewArtifactPathService(), Lister: &fakeBaiduDirLister{}},
+		downloadService: &BaiduDownloadService{},
+		modelService:    &ModelService{},
+		datasetService:  &DatasetService{},
+		jobs: map[uint64]*BaiduImportJob{
+			7: {ID: 7, Status: BaiduImportJobStatusRunning, Dir: "/project/luckyProject/datasets"},
+		},
+	}
+
+	_, err := svc.StartImport(BaiduImportRequest{Dir: "/project/luckyProject/datasets"})
+	assert.ErrorIs(t, err, ErrBaiduImportRunning)
+}
+
+func TestBaiduImportServicePrunesFinishedJobs(t *testing.T) {
+	svc := &BaiduImportService{jobs: make(map[uint64]*BaiduImportJob)}
+	base := time.Now()
+	for i := 1; i <= maxBaiduImportJobs+2; i++ {
+		finishedAt := base.Add(time.Duration(i) * time.Second)
+		svc.jobs[uint64(i)] = &BaiduImportJob{ID: uint64(i), FinishedAt: &finishedAt}
+	}
+	svc.jobs[1000] = &BaiduImportJob{ID: 1000, Status: BaiduImportJobStatusRunning}
+
+	svc.pruneJobsLocked()
+	assert.Len(t, svc.jobs, maxBaiduImportJobs)
+	assert.NotContains(t, svc.jobs, uint64(1))
+	assert.Contains(t, svc.jobs, uint64(1000))
+}
+
+func TestBuildImportModelFromYAMLSidecar(t *testing.T) {
+	metadata, err := parseBaiduSidecar([]byte("name: yolo11n-coco\nversion: 1.1\ntask_type: detect\nframework: pytorch\nstorage_server: backend\nweight_name: other.pt\n"))
+	require.NoError(t, err)
+
+	model, err := buildImportModel(BaiduFileEntry{Name: "yolo11n.pt", Size: 2 * 1024 * 1024}, metadata)
+	require.NoError(t, err)
+	assert.Equal(t, "yolo11n-coco", model.Name)
+	assert.Equal(t, 1.1, model.Version)
+	assert.Equal(t, "detect", model.TaskType)
+	require.NotNil(t, model.Framework)
+	assert.Equal(t, "pytorch", *model.Framework)
+	assert.Equal(t, "yolo11n.pt", model.WeightName)
+	assert.Equal(t, StorageTargetBaiduNetdisk, model.StorageServer)
+	assert.Equal(t, 2.0, model.WeightSizeMB)
+}
+
+func TestBuildImportDatasetFromJSONSidecar(t *testing.T) {
+	metadata, err := parseBaiduSidecar([]byte(`{"description":"coco subset","num_classes":2,"class_names":["cat","dog"],"size_mb":12.5}`))
+	require.NoError(t, err)
+
+	dataset, err := buildImportDataset(BaiduFileEntry{Name: "coco-mini.zip", Size: 1024}, metadata)
+	require.NoError(t, err)
+	assert.Equal(t, "coco-mini", dataset.Name)
+	assert.Equal(t, "coco-mini.zip", dataset.FileName)
+	require.NotNil(t, dataset.NumClasses)
+	assert.EqualValues(t, 2, *dataset.NumClasses)
+	assert.JSONEq(t, `["cat","dog"]`, string(dataset.ClassNames))
+	assert.Equal(t, 12.5, dataset.SizeMB)
+	assert.Equal(t, StorageTargetBaiduNetdisk, dataset.StorageServer)
+}
+
+func TestBuildImportModelWithoutSidecar(t *testing.T) {
+	model, err := buildImportModel(BaiduFileEntry{Name: "best.pt"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "best", model.Name)
+	assert.Equal(t, "best.pt", model.WeightName)
+	assert.Zero(t, model.WeightSizeMB)
+}
+
+func TestParseBaiduSidecarInvalid(t *testing.T) {
+	_, err := parseBaiduSidecar([]byte("name: [unterminated"))
+	assert.Error(t, err)
+
+	_, err = buildImportModel(BaiduFileEntry{Name: "a.pt"}, map[string]interface{}{"version": "not-a-number"})
+	assert.Error(t, err)
+}
+
+func TestBaiduImportServiceFetchSidecar(t *testing.T) {
+	downloader := &fakeBaiduDownloader{content: []byte("name: demo\n")}
+	svc := &BaiduImportService{downloadService: &BaiduDownloadService{Downloader: downloader}}
+
+	data, err := svc.fetchSidecar(BaiduFileEntry{Name: "demo.pt.yaml", Path: "/w/demo.pt.yaml", Size: 11})
+	require.NoError(t, err)
+	assert.Equal(t, "name: demo\n", string(data))
+	assert.Equal(t, "/w/demo.pt.yaml", downloader.remotePath)
+	assert.NoFileExists(t, downloader.localPath)
+
+	_, err = svc.fetchSidecar(BaiduFileEntry{Name: "big.json", Path: "/w/big.json", Size: maxBaiduSidecarBytes + 1})
+	assert.ErrorIs(t, err, ErrBaiduSidecarTooLarge)
+}
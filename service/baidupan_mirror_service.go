@@ -0,0 +1,601 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBaiduMirrorInterval   = time.Hour
+	defaultBaiduMirrorMinFileAge = time.Minute
+
+	baiduMirrorReasonMissing      = "missing"
+	baiduMirrorReasonSizeMismatch = "size_mismatch"
+	baiduMirrorReasonMD5Mismatch  = "md5_mismatch"
+	// baiduMirrorReasonUnverified 多分片文件远端 md5 不是内容 MD5，且副本表中没有同内容的校验记录。
+	baiduMirrorReasonUnverified = "unverified"
+)
+
+var (
+	ErrBaiduMirrorRunning  = errors.New("baidu mirror is already running")
+	ErrBaiduMirrorNotReady = errors.New("baidu mirror service is not ready")
+)
+
+// baiduMirrorRecordStore 读写镜像结果：查询/写入副本表，并为匹配记录的 storage_server 追加 baidu_netdisk。
+type baiduMirrorRecordStore interface {
+	BaiduReplicas(ctx context.Context, artifactType string, fileNames []string) (map[string]entity2.ArtifactReplica, error)
+	RecordReplica(ctx context.Context, artifactType, fileName string, receipt BaiduUploadReceipt) error
+	MarkMirrored(ctx context.Context, category string, fileNames []string) (int, error)
+}
+
+// BaiduMirrorFileResult 单个文件的镜像结果
+type BaiduMirrorFileResult struct {
+	FileName     string `json:"file_name"`
+	Size         int64  `json:"size"`
+	Reason       string `json:"reason"`
+	RapidUpload  bool   `json:"rapid_upload,omitempty"`
+	Status       string `json:"status,omitempty"`
+	StatusDetail string `json:"status_detail,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// BaiduMirrorCategoryReport 单个目录（weights/datasets）的镜像结果
+type BaiduMirrorCategoryReport struct {
+	Category       string                  `json:"category"`
+	LocalRoot      string                  `json:"local_root"`
+	RemoteRoot     string                  `json:"remote_root"`
+	Scanned        int                     `json:"scanned"`
+	InSync         int                     `json:"in_sync"`
+	Deferred       []string                `json:"deferred"`
+	Uploaded       []BaiduMirrorFileResult `json:"uploaded"`
+	Failed         []BaiduMirrorFileResult `json:"failed"`
+	RecordsUpdated int                     `json:"records_updated"`
+	Error          string                  `json:"error,omitempty"`
+}
+
+// BaiduMirrorRunReport 一次镜像的汇总
+type BaiduMirrorRunReport struct {
+	StartedAt     time.Time                   `json:"started_at"`
+	FinishedAt    time.Time                   `json:"finished_at"`
+	DurationMs    int64                       `json:"duration_ms"`
+	ScannedCount  int                         `json:"scanned_count"`
+	InSyncCount   int                         `json:"in_sync_count"`
+	UploadedCount int                         `json:"uploaded_count"`
+	UploadedBytes int64                       `json:"uploaded_bytes"`
+	FailedCount   int                         `json:"failed_count"`
+	PendingCount  int                         `json:"pending_count"`
+	PendingBytes  int64                       `json:"pending_bytes"`
+	Categories    []BaiduMirrorCategoryReport `json:"categories"`
+}
+
+// BaiduMirrorStatus 镜像状态与同步滞后。
+// LastSyncedAt 为最近一次“无待同步文件”的运行开始时间，lag_seconds 即距该时间的秒数。
+type BaiduMirrorStatus struct {
+	Enabled           bool                  `json:"enabled"`
+	IntervalSeconds   int64                 `json:"interval_seconds"`
+	Running           bool                  `json:"running"`
+	LastRunStartedAt  *time.Time            `json:"last_run_started_at"`
+	LastRunFinishedAt *time.Time            `json:"last_run_finished_at"`
+	LastSyncedAt      *time.Time            `json:"last_synced_at"`
+	LagSeconds        *int64                `json:"lag_seconds"`
+	PendingCount      int                   `json:"pending_count"`
+	PendingBytes      int64                 `json:"pending_bytes"`
+	LastError         string                `json:"last_error,omitempty"`
+	LastRun           *BaiduMirrorRunReport `json:"last_run"`
+}
+
+type baiduMirrorLocalFile struct {
+	name    string
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+type cachedFileDigest struct {
+	size    int64
+	modTime time.Time
+	md5     string
+}
+
+// BaiduMirrorService 将后端 weights/datasets 目录单向增量镜像到百度网盘。
+// 以 size + md5 比对本地与网盘，只上传缺失或不一致的文件；本地 md5 按 (size, mtime) 缓存，避免每轮重算。
+// 网盘列表的 md5 仅对单分片文件等于内容 MD5，多分片文件以副本表中上传时记录的本地 size/md5 为准。
+type BaiduMirrorService struct {
+	PathService *ArtifactPathService
+	Lister      BaiduDirLister
+	Uploader    BaiduUploader
+	Enabled     bool
+	Interval    time.Duration
+	MinFileAge  time.Duration
+
+	records baiduMirrorRecordStore
+	now     func() time.Time
+
+	mu        sync.Mutex
+	running   bool
+	startOnce sync.Once
+	digests   map[string]cachedFileDigest
+	status    BaiduMirrorStatus
+}
+
+var (
+	defaultBaiduMirrorService     *BaiduMirrorService
+	defaultBaiduMirrorServiceOnce sync.Once
+)
+
+// DefaultBaiduMirrorService 进程内共享的镜像服务，定时任务与管理接口共用同一份状态。
+func DefaultBaiduMirrorService() *BaiduMirrorService {
+	defaultBaiduMirrorServiceOnce.Do(func() {
+		defaultBaiduMirrorService = NewBaiduMirrorServiceFromConfig()
+	})
+	return defaultBaiduMirrorService
+}
+
+func NewBaiduMirrorServiceFromConfig() *BaiduMirrorService {
+	var cfg config.BaiduMirrorConfig
+	if config.AppConfig != nil {
+		cfg = config.AppConfig.BaiduPan.Mirror
+	}
+
+	return &BaiduMirrorService{
+		PathService: NewArtifactPathService(),
+		Lister:      NewBaiduPanListerFromConfig(),
+		Uploader:    NewBaiduPanUploaderFromConfig(),
+		Enabled:     cfg.Enabled,
+		Interval:    time.Duration(cfg.IntervalSeconds) * time.Second,
+		MinFileAge:  time.Duration(cfg.MinFileAgeSeconds) * time.Second,
+		records:     newDBBaiduMirrorRecordStore(),
+	}
+}
+
+// Start 启用时在后台按间隔执行镜像（启动后立即执行一次），ctx 取消后停止；重复调用无效。
+func (s *BaiduMirrorService) Start(ctx context.Context) {
+	if s == nil || !s.Enabled {
+		return
+	}
+	s.startOnce.Do(func() {
+		go s.loop(ctx)
+	})
+}
+
+func (s *BaiduMirrorService) loop(ctx context.Context) {
+	logger := serviceLogger().With("service", "BaiduMirrorService", "method", "loop")
+	interval := s.interval()
+	logger.Info("baidu mirror scheduler started", "interval", interval.String())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && !errors.Is(err, ErrBaiduMirrorRunning) {
+			logger.Error("baidu mirror run failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("baidu mirror scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 立即执行一次镜像；已有镜像在执行时返回 ErrBaiduMirrorRunning。
+// 单个目录或文件失败不会中断其余文件，失败记录在报告中并计入待同步数。
+func (s *BaiduMirrorService) RunOnce(ctx context.Context) (BaiduMirrorRunReport, error) {
+	logger := serviceLogger().With("service", "BaiduMirrorService", "method", "RunOnce")
+	if s == nil || s.PathService == nil || s.Lister == nil || s.Uploader == nil {
+		return BaiduMirrorRunReport{}, ErrBaiduMirrorNotReady
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	startedAt := s.clock()
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return BaiduMirrorRunReport{}, ErrBaiduMirrorRunning
+	}
+	s.running = true
+	s.status.LastRunStartedAt = &startedAt
+	s.mu.Unlock()
+
+	report := BaiduMirrorRunReport{StartedAt: startedAt}
+	var runErrs []string
+	for _, category := range []string{ArtifactCategoryWeights, ArtifactCategoryDatasets} {
+		categoryReport, pendingBytes := s.mirrorCategory(ctx, category, startedAt)
+		if categoryReport.Error != "" {
+			runErrs = append(runErrs, category+": "+categoryReport.Error)
+		}
+		report.Categories = append(report.Categories, categoryReport)
+		report.ScannedCount += categoryReport.Scanned
+		report.InSyncCount += categoryReport.InSync
+		report.UploadedCount += len(categoryReport.Uploaded)
+		for _, item := range categoryReport.Uploaded {
+			report.UploadedBytes += item.Size
+		}
+		report.FailedCount += len(categoryReport.Failed)
+		report.PendingCount += len(categoryReport.Deferred) + len(categoryReport.Failed)
+		report.PendingBytes += pendingBytes
+	}
+	report.FinishedAt = s.clock()
+	report.DurationMs = report.FinishedAt.Sub(startedAt).Milliseconds()
+
+	var runErr error
+	if len(runErrs) > 0 {
+		runErr = fmt.Errorf("baidu mirror failed: %s", strings.Join(runErrs, "; "))
+	}
+
+	s.mu.Lock()
+	s.running = false
+	finishedAt := report.FinishedAt
+	s.status.LastRunFinishedAt = &finishedAt
+	s.status.PendingCount = report.PendingCount
+	s.status.PendingBytes = report.PendingBytes
+	s.status.LastRun = &report
+	s.status.LastError = ""
+	if runErr != nil {
+		s.status.LastError = runErr.Error()
+	} else if report.PendingCount == 0 {
+		s.status.LastSyncedAt = &startedAt
+	}
+	s.mu.Unlock()
+
+	logger.Info(
+		"baidu mirror run finished",
+		"scanned", report.ScannedCount,
+		"in_sync", report.InSyncCount,
+		"uploaded", report.UploadedCount,
+		"failed", report.FailedCount,
+		"pending", report.PendingCount,
+		"duration_ms", report.DurationMs,
+	)
+	return report, runErr
+}
+
+// Status 返回镜像状态；lag_seconds 在从未完整同步过时为 null。
+func (s *BaiduMirrorService) Status() BaiduMirrorStatus {
+	if s == nil {
+		return BaiduMirrorStatus{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.status
+	status.Enabled = s.Enabled
+	status.IntervalSeconds = int64(s.interval() / time.Second)
+	status.Running = s.running
+	if status.LastSyncedAt != nil {
+		lag := int64(s.clock().Sub(*status.LastSyncedAt) / time.Second)
+		if lag < 0 {
+			lag = 0
+		}
+		status.LagSeconds = &lag
+	}
+	return status
+}
+
+func (s *BaiduMirrorService) mirrorCategory(ctx context.Context, category string, startedAt time.Time) (BaiduMirrorCategoryReport, int64) {
+	logger := serviceLogger().With("service", "BaiduMirrorService", "method", "mirrorCategory")
+	report := BaiduMirrorCategoryReport{
+		Category: category,
+		Deferred: make([]string, 0),
+		Uploaded: make([]BaiduMirrorFileResult, 0),
+		Failed:   make([]BaiduMirrorFileResult, 0),
+	}
+
+	localRoot, err := s.PathService.ResolveRoot(category, StorageTargetBackend)
+	if err != nil {
+		report.Error = err.Error()
+		return report, 0
+	}
+	remoteRoot, err := s.PathService.ResolveRoot(category, StorageTargetBaiduNetdisk)
+	if err != nil {
+		report.Error = err.Error()
+		return report, 0
+	}
+	report.LocalRoot = filepath.ToSlash(localRoot)
+	report.RemoteRoot = remoteRoot
+
+	localFiles, err := listBaiduMirrorLocalFiles(localRoot)
+	if err != nil {
+		report.Error = err.Error()
+		return report, 0
+	}
+	report.Scanned = len(localFiles)
+	if len(localFiles) == 0 {
+		return report, 0
+	}
+
+	remoteEntries, err := s.Lister.ListDir(remoteRoot)
+	if err != nil {
+		report.Error = err.Error()
+		var pendingBytes int64
+		for _, file := range localFiles {
+			pendingBytes += file.size
+		}
+		return report, pendingBytes
+	}
+	remoteByName := make(map[string]BaiduFileEntry, len(remoteEntries))
+	for _, entry := range remoteEntries {
+		if !entry.IsDir {
+			remoteByName[entry.Name] = entry
+		}
+	}
+
+	artifactType := artifactTypeForCategory(category)
+	replicas := map[string]entity2.ArtifactReplica{}
+	if s.records != nil {
+		names := make([]string, 0, len(localFiles))
+		for _, file := range localFiles {
+			names = append(names, file.name)
+		}
+		found, err := s.records.BaiduReplicas(ctx, artifactType, names)
+		if err != nil {
+			// 查不到副本记录时多分片文件会被重新上传（通常命中秒传），不影响正确性。
+			logger.Warn("load baidu replicas failed", "category", category, "error", err)
+		} else {
+			replicas = found
+		}
+	}
+	minAge := s.minFileAge()
+	var pendingBytes int64
+	mirrored := make([]string, 0, len(localFiles))
+	for _, file := range localFiles {
+		if err := ctx.Err(); err != nil {
+			report.Error = err.Error()
+			report.Deferred = append(report.Deferred, file.name)
+			pendingBytes += file.size
+			continue
+		}
+
+		var remote *BaiduFileEntry
+		if entry, ok := remoteByName[file.name]; ok {
+			remote = &entry
+		}
+
+		var replica *entity2.ArtifactReplica
+		if record, ok := replicas[file.name]; ok {
+			replica = &record
+		}
+
+		localMD5 := ""
+		if remote != nil && remote.Size == file.size {
+			localMD5, err = s.localMD5(file)
+			if err != nil {
+				report.Failed = append(report.Failed, BaiduMirrorFileResult{FileName: file.name, Size: file.size, Error: err.Error()})
+				pendingBytes += file.size
+				continue
+			}
+		}
+
+		reason := baiduMirrorDiffReason(file, remote, localMD5, replica)
+		if reason == "" {
+			report.InSync++
+			mirrored = append(mirrored, file.name)
+			continue
+		}
+		// 仍在写入的文件等下一轮再传，避免上传半截文件。
+		if startedAt.Sub(file.modTime) < minAge {
+			report.Deferred = append(report.Deferred, file.name)
+			pendingBytes += file.size
+			continue
+		}
+
+		result := BaiduMirrorFileResult{FileName: file.name, Size: file.size, Reason: reason}
+		receipt, err := s.Uploader.Upload(file.path, remoteRoot)
+		if err != nil {
+			result.Error = err.Error()
+			report.Failed = append(report.Failed, result)
+			pendingBytes += file.size
+			logger.Error("mirror file to baidu failed", "file_name", file.name, "reason", reason, "error", err)
+			continue
+		}
+		result.RapidUpload = receipt.RapidUpload
+		result.Status = receipt.Status
+		result.StatusDetail = receipt.StatusDetail
+
+		if s.records != nil {
+			if err := s.records.RecordReplica(ctx, artifactType, file.name, receipt); err != nil {
+				logger.Error("record baidu replica failed", "file_name", file.name, "error", err)
+			}
+		}
+		if receipt.Status == entity2.ReplicaStatusCorrupt {
+			result.Error = "remote copy failed verification"
+			report.Failed = append(report.Failed, result)
+			pendingBytes += file.size
+			continue
+		}
+		report.Uploaded = append(report.Uploaded, result)
+		mirrored = append(mirrored, file.name)
+	}
+
+	if s.records != nil && len(mirrored) > 0 {
+		updated, err := s.records.MarkMirrored(ctx, category, mirrored)
+		if err != nil {
+			report.Error = err.Error()
+		}
+		report.RecordsUpdated = updated
+	}
+	return report, pendingBytes
+}
+
+// localMD5 仅在 size 或 mtime 变化时重新计算。
+func (s *BaiduMirrorService) localMD5(file baiduMirrorLocalFile) (string, error) {
+	s.mu.Lock()
+	cached, ok := s.digests[file.path]
+	s.mu.Unlock()
+	if ok && cached.size == file.size && cached.modTime.Equal(file.modTime) {
+		return cached.md5, nil
+	}
+
+	digest, err := ComputeFileDigest(file.path)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	if s.digests == nil {
+		s.digests = make(map[string]cachedFileDigest)
+	}
+	s.digests[file.path] = cachedFileDigest{size: file.size, modTime: file.modTime, md5: digest.MD5}
+	s.mu.Unlock()
+	return digest.MD5, nil
+}
+
+func (s *BaiduMirrorService) interval() time.Duration {
+	if s.Interval <= 0 {
+		return defaultBaiduMirrorInterval
+	}
+	return s.Interval
+}
+
+func (s *BaiduMirrorService) minFileAge() time.Duration {
+	if s.MinFileAge <= 0 {
+		return defaultBaiduMirrorMinFileAge
+	}
+	return s.MinFileAge
+}
+
+func (s *BaiduMirrorService) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// baiduMirrorDiffReason 返回需要上传的原因，空字符串表示已同步；localMD5 在远端存在且大小一致时提供。
+// 副本表中已校验、且本地 size/md5 与远端 md5 均未变化的记录直接视为已同步；
+// 否则远端未返回 md5 时只比较大小，单分片文件比较远端 md5，多分片文件无法证明一致，返回 unverified。
+func baiduMirrorDiffReason(file baiduMirrorLocalFile, remote *BaiduFileEntry, localMD5 string, replica *entity2.ArtifactReplica) string {
+	if remote == nil {
+		return baiduMirrorReasonMissing
+	}
+	if remote.Size != file.size {
+		return baiduMirrorReasonSizeMismatch
+	}
+	remoteMD5 := strings.TrimSpace(remote.MD5)
+	if replica != nil && replica.Status == entity2.ReplicaStatusVerified &&
+		replica.LocalSize == file.size && strings.EqualFold(replica.LocalMD5, localMD5) &&
+		(replica.RemoteMD5 == "" || strings.EqualFold(replica.RemoteMD5, remoteMD5)) {
+		return ""
+	}
+	if remoteMD5 == "" {
+		return ""
+	}
+	if file.size <= baiduBlockSize {
+		if !strings.EqualFold(remoteMD5, localMD5) {
+			return baiduMirrorReasonMD5Mismatch
+		}
+		return ""
+	}
+	return baiduMirrorReasonUnverified
+}
+
+// listBaiduMirrorLocalFiles 列出根目录下的普通文件（不递归，忽略隐藏文件）；目录不存在视为空。
+func listBaiduMirrorLocalFiles(root string) ([]baiduMirrorLocalFile, error) {
+	if strings.TrimSpace(root) == "" {
+		return nil, ErrInvalidStorageTarget
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read local dir failed: %w", err)
+	}
+
+	files := make([]baiduMirrorLocalFile, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, baiduMirrorLocalFile{
+			name:    entry.Name(),
+			path:    filepath.Join(root, entry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files, nil
+}
+
+func artifactTypeForCategory(category string) string {
+	if category == ArtifactCategoryDatasets {
+		return entity2.ArtifactTypeDataset
+	}
+	return entity2.ArtifactTypeModel
+}
+
+type dbBaiduMirrorRecordStore struct {
+	replicaService *ArtifactReplicaService
+	modelDAO       *dao.ModelDAO
+	datasetDAO     *dao.DatasetDAO
+}
+
+func newDBBaiduMirrorRecordStore() *dbBaiduMirrorRecordStore {
+	return &dbBaiduMirrorRecordStore{
+		replicaService: NewArtifactReplicaService(),
+		modelDAO:       dao.NewModelDAO(),
+		datasetDAO:     dao.NewDatasetDAO(),
+	}
+}
+
+func (r *dbBaiduMirrorRecordStore) BaiduReplicas(ctx context.Context, artifactType string, fileNames []string) (map[string]entity2.ArtifactReplica, error) {
+	return r.replicaService.BaiduReplicasByName(ctx, artifactType, fileNames)
+}
+
+func (r *dbBaiduMirrorRecordStore) RecordReplica(ctx context.Context, artifactType, fileName string, receipt BaiduUploadReceipt) error {
+	_, err := r.replicaService.RecordBaiduUpload(ctx, artifactType, fileName, receipt)
+	return err
+}
+
+// MarkMirrored 为文件名匹配且尚未声明 baidu_netdisk 的记录追加该存储，返回更新的记录数。
+func (r *dbBaiduMirrorRecordStore) MarkMirrored(ctx context.Context, category string, fileNames []string) (int, error) {
+	var records []artifactRecord
+	if category == ArtifactCategoryDatasets {
+		datasets, err := r.datasetDAO.FindByFileNamesOrStorageServer(ctx, fileNames, "")
+		if err != nil {
+			return 0, err
+		}
+		records = datasetArtifactRecords(datasets)
+	} else {
+		models, err := r.modelDAO.FindByWeightNamesOrStorageServer(ctx, fileNames, "")
+		if err != nil {
+			return 0, err
+		}
+		records = modelArtifactRecords(models)
+	}
+
+	updated := 0
+	for _, record := range records {
+		if storageServerFieldContains(record.storageServer, StorageTargetBaiduNetdisk) {
+			continue
+		}
+		var err error
+		if category == ArtifactCategoryDatasets {
+			_, err = r.datasetDAO.UpdateStorageServersByID(ctx, record.id, dao.StorageActionAdd, []string{StorageTargetBaiduNetdisk})
+		} else {
+			_, err = r.modelDAO.UpdateStorageServersByID(ctx, record.id, dao.StorageActionAdd, []string{StorageTargetBaiduNetdisk})
+		}
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
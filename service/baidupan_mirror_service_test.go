@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	entity2 "lucky_project/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMirrorUploader struct {
+	uploads []string
+	fail    map[string]error
+	status  map[string]string
+}
+
+func (f *fakeMirrorUploader) Upload(localPath, remoteDir string) (BaiduUploadReceipt, error) {
+	name := filepath.Base(localPath)
+	f.uploads = append(f.uploads, name)
+	if err := f.fail[name]; err != nil {
+		return BaiduUploadReceipt{}, err
+	}
+	status := entity2.ReplicaStatusVerified
+	if value, ok := f.status[name]; ok {
+		status = value
+	}
+	return BaiduUploadReceipt{RemotePath: remoteDir + "/" + name, Status: status}, nil
+}
+
+type fakeMirrorRecordStore struct {
+	known    map[string]entity2.ArtifactReplica
+	replicas map[string]string
+	marked   map[string][]string
+}
+
+func (f *fakeMirrorRecordStore) BaiduReplicas(ctx context.Context, artifactType string, fileNames []string) (map[string]entity2.ArtifactReplica, error) {
+	found := make(map[string]entity2.ArtifactReplica)
+	for _, name := range fileNames {
+		if replica, ok := f.known[name]; ok {
+			found[name] = replica
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeMirrorRecordStore) RecordReplica(ctx context.Context, artifactType, fileName string, receipt BaiduUploadReceipt) error {
+	if f.replicas == nil {
+		f.replicas = make(map[string]string)
+	}
+	f.replicas[artifactType+"/"+fileName] = receipt.Status
+	return nil
+}
+
+func (f *fakeMirrorRecordStore) MarkMirrored(ctx context.Context, category string, fileNames []string) (int, error) {
+	if f.marked == nil {
+		f.marked = make(map[string][]string)
+	}
+	f.marked[category] = append(f.marked[category], fileNames...)
+	return len(fileNames), nil
+}
+
+func writeMirrorFile(t *testing.T, dir, name, content string, modTime time.Time) string {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	filePath := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0o644))
+	require.NoError(t, os.Chtimes(filePath, modTime, modTime))
+	return filePath
+}
+
+func newTestBaiduMirrorService(t *testing.T, lister BaiduDirLister, uploader BaiduUploader, records baiduMirrorRecordStore, now *time.Time) (*BaiduMirrorService, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	weightsRoot := filepath.Join(tmpDir, "backend", "weights")
+	svc := &BaiduMirrorService{
+		PathService: &ArtifactPathService{
+			BackendWeightsRoot:  weightsRoot,
+			BackendDatasetsRoot: filepath.Join(tmpDir, "backend", "datasets"),
+			BaiduWeightsRoot:    "/project/luckyProject/weights",
+			BaiduDatasetsRoot:   "/project/luckyProject/datasets",
+		},
+		Lister:     lister,
+		Uploader:   uploader,
+		Enabled:    true,
+		MinFileAge: time.Minute,
+		records:    records,
+		now:        func() time.Time { return *now },
+	}
+	return svc, weightsRoot
+}
+
+func TestBaiduMirrorServiceUploadsDelta(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	old := now.Add(-2 * time.Hour)
+	lister := &fakeBaiduDirLister{entries: map[string][]BaiduFileEntry{}}
+	uploader := &fakeMirrorUploader{}
+	records := &fakeMirrorRecordStore{}
+	svc, weightsRoot := newTestBaiduMirrorService(t, lister, uploader, records, &now)
+
+	writeMirrorFile(t, weightsRoot, "a.pt", "aaaa", old)
+	writeMirrorFile(t, weightsRoot, "b.pt", "bbbb", old)
+	writeMirrorFile(t, weightsRoot, "c.pt", "cccc", old)
+	writeMirrorFile(t, weightsRoot, "d.pt", "dddd", old)
+	writeMirrorFile(t, weightsRoot, "e.pt", "eeee", now.Add(-10*time.Second))
+	writeMirrorFile(t, weightsRoot, ".hidden", "x", old)
+	lister.entries["/project/luckyProject/weights"] = []BaiduFileEntry{
+		{Name: "b.pt", Size: 4, MD5: md5Hex([]byte("bbbb"))},
+		{Name: "c.pt", Size: 4, MD5: md5Hex([]byte("other"))},
+		{Name: "d.pt", Size: 3},
+		{Name: "sub", IsDir: true},
+	}
+
+	report, err := svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.pt", "c.pt", "d.pt"}, uploader.uploads)
+	assert.Equal(t, 5, report.ScannedCount)
+	assert.Equal(t, 1, report.InSyncCount)
+	assert.Equal(t, 3, report.UploadedCount)
+	assert.EqualValues(t, 12, report.UploadedBytes)
+	assert.Equal(t, 1, report.PendingCount)
+	assert.EqualValues(t, 4, report.PendingBytes)
+
+	require.Len(t, report.Categories, 2)
+	weights := report.Categories[0]
+	assert.Equal(t, ArtifactCategoryWeights, weights.Category)
+	assert.Equal(t, []string{"e.pt"}, weights.Deferred)
+	reasons := map[string]string{}
+	for _, item := range weights.Uploaded {
+		reasons[item.FileName] = item.Reason
+	}
+	assert.Equal(t, map[string]string{"a.pt": "missing", "c.pt": "md5_mismatch", "d.pt": "size_mismatch"}, reasons)
+	assert.Equal(t, 4, weights.RecordsUpdated)
+	assert.ElementsMatch(t, []string{"a.pt", "b.pt", "c.pt", "d.pt"}, records.marked[ArtifactCategoryWeights])
+	assert.Equal(t, entity2.ReplicaStatusVerified, records.replicas["model/a.pt"])
+	assert.Equal(t, 0, report.Categories[1].Scanned)
+
+	status := svc.Status()
+	assert.True(t, status.Enabled)
+	assert.Equal(t, 1, status.PendingCount)
+	assert.Nil(t, status.LastSyncedAt)
+	assert.Nil(t, status.LagSeconds)
+}
+
+func TestBaiduMirrorServiceReportsLagAfterFullSync(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	lister := &fakeBaiduDirLister{entries: map[string][]BaiduFileEntry{
+		"/project/luckyProject/weights": {{Name: "a.pt", Size: 4}},
+	}}
+	uploader := &fakeMirrorUploader{}
+	svc, weightsRoot := newTestBaiduMirrorService(t, lister, uploader, &fakeMirrorRecordStore{}, &now)
+	writeMirrorFile(t, weightsRoot, "a.pt", "aaaa", now.Add(-time.Hour))
+
+	_, err := svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, uploader.uploads)
+
+	now = now.Add(90 * time.Second)
+	status := svc.Status()
+	require.NotNil(t, status.LastSyncedAt)
+	require.NotNil(t, status.LagSeconds)
+	assert.EqualValues(t, 90, *status.LagSeconds)
+	assert.Equal(t, 0, status.PendingCount)
+	assert.EqualValues(t, 3600, status.IntervalSeconds)
+}
+
+func TestBaiduMirrorServiceKeepsFailuresPending(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	lister := &fakeBaiduDirLister{entries: map[string][]BaiduFileEntry{}}
+	uploader := &fakeMirrorUploader{
+		fail:   map[string]error{"a.pt": errors.New("network down")},
+		status: map[string]string{"b.pt": entity2.ReplicaStatusCorrupt},
+	}
+	records := &fakeMirrorRecordStore{}
+	svc, weightsRoot := newTestBaiduMirrorService(t, lister, uploader, records, &now)
+	writeMirrorFile(t, weightsRoot, "a.pt", "aaaa", now.Add(-time.Hour))
+	writeMirrorFile(t, weightsRoot, "b.pt", "bbbb", now.Add(-time.Hour))
+
+	report, err := svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.FailedCount)
+	assert.Equal(t, 2, report.PendingCount)
+	assert.Empty(t, records.marked[ArtifactCategoryWeights])
+	assert.Equal(t, entity2.ReplicaStatusCorrupt, records.replicas["model/b.pt"])
+	assert.Nil(t, svc.Status().LastSyncedAt)
+}
+
+func TestBaiduMirrorServiceRejectsConcurrentRun(t *testing.T) {
+	now := time.Now()
+	svc, _ := newTestBaiduMirrorService(t, &fakeBaiduDirLister{}, &fakeMirrorUploader{}, nil, &now)
+	svc.running = true
+
+	_, err := svc.RunOnce(context.Background())
+	assert.ErrorIs(t, err, ErrBaiduMirrorRunning)
+
+	_, err = (&BaiduMirrorService{}).RunOnce(context.Background())
+	assert.ErrorIs(t, err, ErrBaiduMirrorNotReady)
+}
+
+func TestBaiduMirrorServiceCachesLocalMD5(t *testing.T) {
+	now := time.Now()
+	svc, weightsRoot := newTestBaiduMirrorService(t, &fakeBaiduDirLister{}, &fakeMirrorUploader{}, nil, &now)
+	modTime := now.Add(-time.Hour)
+	filePath := writeMirrorFile(t, weightsRoot, "a.pt", "aaaa", modTime)
+	file := baiduMirrorLocalFile{name: "a.pt", path: filePath, size: 4, modTime: modTime}
+
+	first, err := svc.localMD5(file)
+	require.NoError(t, err)
+	assert.Equal(t, md5Hex([]byte("aaaa")), first)
+
+	writeMirrorFile(t, weightsRoot, "a.pt", "zzzz", modTime)
+	cached, err := svc.localMD5(file)
+	require.NoError(t, err)
+	assert.Equal(t, first, cached)
+
+	file.modTime = now
+	fresh, err := svc.localMD5(file)
+	require.NoError(t, err)
+	assert.Equal(t, md5Hex([]byte("zzzz")), fresh)
+}
+
+func TestBaiduMirrorDiffReasonMultiBlock(t *testing.T) {
+	size := int64(baiduBlockSize + 1)
+	file := baiduMirrorLocalFile{name: "big.pt", size: size}
+	remote := &BaiduFileEntry{Name: "big.pt", Size: size, MD5: "server-md5"}
+
+	// 多分片文件的远端 md5 与内容 MD5 不可比，没有副本记录时无法确认一致
+	assert.Equal(t, baiduMirrorReasonUnverified, baiduMirrorDiffReason(file, remote, "local-md5", nil))
+
+	replica := &entity2.ArtifactReplica{Status: entity2.ReplicaStatusVerified, LocalSize: size, LocalMD5: "local-md5", RemoteMD5: "server-md5"}
+	assert.Empty(t, baiduMirrorDiffReason(file, remote, "local-md5", replica))
+
+	// 本地内容变化、远端被替换或上次校验失败都需要重新上传
+	assert.Equal(t, baiduMirrorReasonUnverified, baiduMirrorDiffReason(file, remote, "changed", replica))
+	assert.Equal(t, baiduMirrorReasonUnverified, baiduMirrorDiffReason(file, &BaiduFileEntry{Size: size, MD5: "other"}, "local-md5", replica))
+	corrupt := *replica
+	corrupt.Status = entity2.ReplicaStatusCorrupt
+	assert.Equal(t, baiduMirrorReasonUnverified, baiduMirrorDiffReason(file, remote, "local-md5", &corrupt))
+
+	// 单分片文件仍直接比较远端 md5
+	small := baiduMirrorLocalFile{name: "a.pt", size: 4}
+	assert.Equal(t, baiduMirrorReasonMD5Mismatch, baiduMirrorDiffReason(small, &BaiduFileEntry{Size: 4, MD5: "x"}, "y", nil))
+	assert.Empty(t, baiduMirrorDiffReason(small, &BaiduFileEntry{Size: 4, MD5: "X"}, "x", nil))
+}
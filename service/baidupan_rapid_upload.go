@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	baidupanplus "github.com/S-zhi/baidupansdk/baidupanplus"
+
+	entity2 "lucky_project/entity"
+)
+
+const (
+	DefaultBaiduPanAPIBaseURL = "https://pan.baidu.com"
+
+	// 百度秒传要求：block_list 按 4MB 分块计算，slice-md5 为前 256KB 的 MD5。
+	baiduBlockSize      = 4 * 1024 * 1024
+	baiduSliceSize      = 256 * 1024
+	baiduRtypeOverwrite = 3
+	// baiduReturnTypeExists precreate 返回 return_type=2 表示云端已有相同内容，秒传成功。
+	baiduReturnTypeExists = 2
+	// filemanager 部分失败时返回 errno=12，逐个文件的结果在 info 中，-9 表示文件不存在。
+	baiduErrnoPartialFailure = 12
+	baiduErrnoNotFound       = -9
+)
+
+var ErrBaiduRapidUploadFailed = errors.New("baidu rapid upload failed")
+
+// FileDigest 本地文件的校验信息
+type FileDigest struct {
+	Size      int64
+	MD5       string
+	SliceMD5  string
+	BlockMD5s []string
+}
+
+// ComputeFileDigest 单次读取文件，同时计算整体 MD5、前 256KB 的 slice MD5 与 4MB 分块 MD5。
+func ComputeFileDigest(localPath string) (FileDigest, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return FileDigest{}, fmt.Errorf("open file for digest failed: %w", err)
+	}
+	defer file.Close()
+
+	whole := md5.New()
+	slice := md5.New()
+	digest := FileDigest{}
+	buf := make([]byte, baiduBlockSize)
+	for {
+		n, readErr := io.ReadFull(file, buf)
+		if n > 0 {
+			block := buf[:n]
+			whole.Write(block)
+			if digest.Size < baiduSliceSize {
+				remaining := baiduSliceSize - digest.Size
+				if int64(n) < remaining {
+					remaining = int64(n)
+				}
+				slice.Write(block[:remaining])
+			}
+			blockSum := md5.Sum(block)
+			digest.BlockMD5s = append(digest.BlockMD5s, hex.EncodeToString(blockSum[:]))
+			digest.Size += int64(n)
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return FileDigest{}, fmt.Errorf("read file for digest failed: %w", readErr)
+		}
+	}
+
+	digest.MD5 = hex.EncodeToString(whole.Sum(nil))
+	digest.SliceMD5 = hex.EncodeToString(slice.Sum(nil))
+	return digest, nil
+}
+
+// BaiduRemoteFileStat 远端文件查询结果；Entry 为空表示父目录中没有该文件。
+type BaiduRemoteFileStat struct {
+	Entry *BaiduFileEntry
+}
+
+type baiduPrecreateResponse struct {
+	Errno      int    `json:"errno"`
+	ReturnType int    `json:"return_type"`
+	UploadID   string `json:"uploadid"`
+}
+
+type baiduCreateResponse struct {
+	Errno int `json:"errno"`
+}
+
+type baiduFileManagerResponse struct {
+	Errno int `json:"errno"`
+	Info  []struct {
+		Errno int    `json:"errno"`
+		Path  string `json:"path"`
+	} `json:"info"`
+}
+
+// RapidUpload 用 content-md5/slice-md5 调用 precreate 尝试秒传；hit 为 true 表示云端已按内容创建文件，无需再传输。
+// 未命中时返回 precreate 打开的 uploadID，调用方应通过 UploadPrecreated 在同一会话内完成上传，而不是另开会话。
+// 小于 256KB 的文件不满足秒传条件，直接返回未命中且不打开会话。
+func (c *BaiduPanClient) RapidUpload(ctx context.Context, remotePath string, digest FileDigest) (bool, string, error) {
+	if digest.Size <= baiduSliceSize || len(digest.BlockMD5s) == 0 {
+		return false, "", nil
+	}
+	blockList, err := json.Marshal(digest.BlockMD5s)
+	if err != nil {
+		return false, "", fmt.Errorf("%w: encode block_list: %v", ErrBaiduRapidUploadFailed, err)
+	}
+
+	var payload baiduPrecreateResponse
+	err = c.do(ctx, func(base baidupanplus.Config) error {
+		form := url.Values{}
+		form.Set("path", remotePath)
+		form.Set("size", strconv.FormatInt(digest.Size, 10))
+		form.Set("isdir", "0")
+		form.Set("autoinit", "1")
+		form.Set("rtype", strconv.Itoa(baiduRtypeOverwrite))
+		form.Set("block_list", string(blockList))
+		form.Set("content-md5", digest.MD5)
+		form.Set("slice-md5", digest.SliceMD5)
+
+		payload = baiduPrecreateResponse{}
+		if err := c.postXpanFile(ctx, "precreate", base.AccessToken, form, &payload); err != nil {
+			return fmt.Errorf("%w: %v", ErrBaiduRapidUploadFailed, err)
+		}
+		if payload.Errno != 0 {
+			return fmt.Errorf("%w: precreate failed with errno: %d", ErrBaiduRapidUploadFailed, payload.Errno)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, "", err
+	}
+	if payload.ReturnType == baiduReturnTypeExists {
+		return true, "", nil
+	}
+	return false, payload.UploadID, nil
+}
+
+// UploadPrecreated 在 RapidUpload 打开的会话内按 4MB 分片上传并 create，整个过程占用一个并发槽位。
+// 分片内容须与 precreate 时的 block_list 一致，文件在此期间被修改时返回错误。
+// xpan 没有取消会话的接口，中途失败的会话由服务端过期回收。
+func (c *BaiduPanClient) UploadPrecreated(ctx context.Context, localPath, remotePath, uploadID string, digest FileDigest) error {
+	if strings.TrimSpace(uploadID) == "" {
+		return errors.New("upload id is required")
+	}
+	blockList, err := json.Marshal(digest.BlockMD5s)
+	if err != nil {
+		return fmt.Errorf("encode block_list failed: %w", err)
+	}
+
+	return c.do(ctx, func(base baidupanplus.Config) error {
+		file, err := os.Open(localPath)
+		if err != nil {
+			return fmt.Errorf("open file for upload failed: %w", err)
+		}
+		defer file.Close()
+
+		buf := make([]byte, baiduBlockSize)
+		for seq, expected := range digest.BlockMD5s {
+			n, readErr := io.ReadFull(file, buf)
+			if readErr != nil && readErr != io.ErrUnexpectedEOF {
+				return fmt.Errorf("read part %d failed: %w", seq, readErr)
+			}
+			sum := md5.Sum(buf[:n])
+			if hex.EncodeToString(sum[:]) != expected {
+				return fmt.Errorf("local file changed during upload: part %d md5 mismatch", seq)
+			}
+			if err := c.sdk.UploadPart(base.AccessToken, remotePath, uploadID, seq, buf[:n]); err != nil {
+				return err
+			}
+		}
+
+		form := url.Values{}
+		form.Set("path", remotePath)
+		form.Set("size", strconv.FormatInt(digest.Size, 10))
+		form.Set("isdir", "0")
+		form.Set("rtype", strconv.Itoa(baiduRtypeOverwrite))
+		form.Set("uploadid", uploadID)
+		form.Set("block_list", string(blockList))
+
+		var payload baiduCreateResponse
+		if err := c.postXpanFile(ctx, "create", base.AccessToken, form, &payload); err != nil {
+			return fmt.Errorf("create file failed: %w", err)
+		}
+		if payload.Errno != 0 {
+			return fmt.Errorf("create file failed with errno: %d", payload.Errno)
+		}
+		return nil
+	})
+}
+
+// DeleteFile 通过 filemanager 同步删除网盘文件；文件不存在（errno -9）时视为成功。
+func (c *BaiduPanClient) DeleteFile(ctx context.Context, remotePath string) error {
+	fileList, err := json.Marshal([]string{remotePath})
+	if err != nil {
+		return fmt.Errorf("encode filelist failed: %w", err)
+	}
+	return c.do(ctx, func(base baidupanplus.Config) error {
+		form := url.Values{}
+		form.Set("async", "0")
+		form.Set("filelist", string(fileList))
+
+		var payload baiduFileManagerResponse
+		if err := c.postXpanFile(ctx, "filemanager&opera=delete", base.AccessToken, form, &payload); err != nil {
+			return fmt.Errorf("delete file failed: %w", err)
+		}
+		switch payload.Errno {
+		case 0:
+			return nil
+		case baiduErrnoPartialFailure:
+			for _, item := range payload.Info {
+				if item.Errno != 0 && item.Errno != baiduErrnoNotFound {
+					return fmt.Errorf("delete file failed with errno: %d", item.Errno)
+				}
+			}
+			return nil
+		default:
+			return fmt.Errorf("delete file failed with errno: %d", payload.Errno)
+		}
+	})
+}
+
+// postXpanFile 以表单方式调用 /rest/2.0/xpan/file 的 method 并解析 JSON 响应。
+func (c *BaiduPanClient) postXpanFile(ctx context.Context, method, accessToken string, form url.Values, out interface{}) error {
+	endpoint := strings.TrimRight(c.apiBaseURL(), "/") + "/rest/2.0/xpan/file?method=" + method + "&access_token=" + url.QueryEscape(accessToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.New("401 unauthorized")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %v", err)
+	}
+	return nil
+}
+
+// StatFile 通过列举父目录查找远端文件元数据。
+func (c *BaiduPanClient) StatFile(ctx context.Context, remotePath string) (BaiduRemoteFileStat, error) {
+	dir := path.Dir(remotePath)
+	name := path.Base(remotePath)
+	list, err := c.ListDirAll(ctx, dir)
+	if err != nil {
+		return BaiduRemoteFileStat{}, err
+	}
+
+	stat := BaiduRemoteFileStat{}
+	for _, item := range baiduFileEntriesFromList(dir, list) {
+		if item.IsDir || item.Name != name {
+			continue
+		}
+		entry := item
+		stat.Entry = &entry
+		break
+	}
+	return stat, nil
+}
+
+func (c *BaiduPanClient) apiBaseURL() string {
+	if strings.TrimSpace(c.APIBaseURL) == "" {
+		return DefaultBaiduPanAPIBaseURL
+	}
+	return c.APIBaseURL
+}
+
+func (c *BaiduPanClient) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+// verifyBaiduCopy 比较本地校验值与远端元数据：远端缺失或大小不一致视为 corrupt。
+// 列表接口的 md5 只有单分片文件才等于内容 MD5（分片上传的文件返回的是服务端重算值），
+// 因此仅在单分片时把 MD5 不一致判为 corrupt；多分片文件大小一致即视为 verified，内容一致性由 create 时的 block_list 保证。
+func verifyBaiduCopy(digest FileDigest, stat BaiduRemoteFileStat) (string, string) {
+	if stat.Entry == nil {
+		return entity2.ReplicaStatusCorrupt, "remote file not found after upload"
+	}
+	if stat.Entry.Size != digest.Size {
+		return entity2.ReplicaStatusCorrupt, fmt.Sprintf("size mismatch: local=%d remote=%d", digest.Size, stat.Entry.Size)
+	}
+	remoteMD5 := strings.TrimSpace(stat.Entry.MD5)
+	if remoteMD5 == "" {
+		return entity2.ReplicaStatusVerified, "size matched; remote md5 unavailable"
+	}
+	if strings.EqualFold(remoteMD5, digest.MD5) {
+		return entity2.ReplicaStatusVerified, ""
+	}
+	if len(digest.BlockMD5s) > 1 {
+		return entity2.ReplicaStatusVerified, "size matched; remote md5 is not a content md5 for sliced uploads"
+	}
+	return entity2.ReplicaStatusCorrupt, fmt.Sprintf("md5 mismatch: local=%s remote=%s", digest.MD5, remoteMD5)
+}
+
+// BaiduUploadReceipt 一次网盘上传的结果及校验信息
+type BaiduUploadReceipt struct {
+	RemotePath   string    `json:"remote_path"`
+	RapidUpload  bool      `json:"rapid_upload"`
+	LocalSize    int64     `json:"local_size"`
+	LocalMD5     string    `json:"local_md5"`
+	RemoteSize   int64     `json:"remote_size"`
+	RemoteMD5    string    `json:"remote_md5"`
+	Status       string    `json:"status"`
+	StatusDetail string    `json:"status_detail,omitempty"`
+	VerifiedAt   time.Time `json:"verified_at"`
+}
+
+func newBaiduUploadReceipt(remotePath string, rapid bool, digest FileDigest, stat BaiduRemoteFileStat, statErr error) BaiduUploadReceipt {
+	receipt := BaiduUploadReceipt{
+		RemotePath:  remotePath,
+		RapidUpload: rapid,
+		LocalSize:   digest.Size,
+		LocalMD5:    digest.MD5,
+		VerifiedAt:  time.Now(),
+	}
+	if statErr != nil {
+		receipt.Status = entity2.ReplicaStatusUnverified
+		receipt.StatusDetail = fmt.Sprintf("query remote metadata failed: %v", statErr)
+		return receipt
+	}
+	if stat.Entry != nil {
+		receipt.RemoteSize = stat.Entry.Size
+		receipt.RemoteMD5 = stat.Entry.MD5
+	}
+	receipt.Status, receipt.StatusDetail = verifyBaiduCopy(digest, stat)
+	return receipt
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	entity2 "lucky_project/entity"
+
+	openapi "github.com/S-zhi/baidupansdk/openxpanapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDigestTestFile(t *testing.T, size int) (string, []byte) {
+	t.Helper()
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	localPath := filepath.Join(t.TempDir(), "weights.pt")
+	require.NoError(t, os.WriteFile(localPath, content, 0o644))
+	return localPath, content
+}
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestComputeFileDigest(t *testing.T) {
+	size := baiduBlockSize + 1024
+	localPath, content := writeDigestTestFile(t, size)
+
+	digest, err := ComputeFileDigest(localPath)
+	require.NoError(t, err)
+	assert.EqualValues(t, size, digest.Size)
+	assert.Equal(t, md5Hex(content), digest.MD5)
+	assert.Equal(t, md5Hex(content[:baiduSliceSize]), digest.SliceMD5)
+	assert.Equal(t, []string{md5Hex(content[:baiduBlockSize]), md5Hex(content[baiduBlockSize:])}, digest.BlockMD5s)
+}
+
+func TestBaiduPanClientRapidUpload(t *testing.T) {
+	var form url.Values
+	var query url.Values
+	returnType := baiduReturnTypeExists
+	errno := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		query = r.URL.Query()
+		form = r.PostForm
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errno": errno, "return_type": returnType, "uploadid": "u1"})
+	}))
+	defer server.Close()
+
+	client := newTestBaiduPanClient(&fakeBaiduPanSDK{}, 1)
+	client.APIBaseURL = server.URL
+	digest := FileDigest{Size: baiduSliceSize + 1, MD5: "content-md5", SliceMD5: "slice-md5", BlockMD5s: []string{"b1"}}
+
+	hit, uploadID, err := client.RapidUpload(context.Background(), "/w/a.pt", digest)
+	require.NoError(t, err)
+	assert.True(t, hit)
+	assert.Empty(t, uploadID)
+	assert.Equal(t, "precreate", query.Get("method"))
+	assert.Equal(t, "static-token", query.Get("access_token"))
+	assert.Equal(t, "/w/a.pt", form.Get("path"))
+	assert.Equal(t, "content-md5", form.Get("content-md5"))
+	assert.Equal(t, "slice-md5", form.Get("slice-md5"))
+	assert.Equal(t, `["b1"]`, form.Get("block_list"))
+	assert.Equal(t, "3", form.Get("rtype"))
+
+	returnType = 1
+	hit, uploadID, err = client.RapidUpload(context.Background(), "/w/a.pt", digest)
+	require.NoError(t, err)
+	assert.False(t, hit)
+	assert.Equal(t, "u1", uploadID)
+
+	errno = 31061
+	_, _, err = client.RapidUpload(context.Background(), "/w/a.pt", digest)
+	assert.ErrorIs(t, err, ErrBaiduRapidUploadFailed)
+
+	form = nil
+	hit, uploadID, err = client.RapidUpload(context.Background(), "/w/a.pt", FileDigest{Size: 10, BlockMD5s: []string{"b1"}})
+	require.NoError(t, err)
+	assert.False(t, hit)
+	assert.Empty(t, uploadID)
+	assert.Nil(t, form)
+}
+
+func TestBaiduPanClientUploadPrecreated(t *testing.T) {
+	localPath, _ := writeDigestTestFile(t, baiduBlockSize+100)
+	digest, err := ComputeFileDigest(localPath)
+	require.NoError(t, err)
+
+	var form url.Values
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		method = r.URL.Query().Get("method")
+		form = r.PostForm
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errno": 0})
+	}))
+	defer server.Close()
+
+	sdk := &fakeBaiduPanSDK{}
+	client := newTestBaiduPanClient(sdk, 1)
+	client.APIBaseURL = server.URL
+
+	require.NoError(t, client.UploadPrecreated(context.Background(), localPath, "/w/weights.pt", "u1", digest))
+	assert.Equal(t, []int{0, 1}, sdk.parts)
+	assert.Equal(t, "create", method)
+	assert.Equal(t, "u1", form.Get("uploadid"))
+	assert.Equal(t, strconv.FormatInt(digest.Size, 10), form.Get("size"))
+	blockList, _ := json.Marshal(digest.BlockMD5s)
+	assert.Equal(t, string(blockList), form.Get("block_list"))
+
+	// 上传期间文件被修改：不再 create
+	method = ""
+	sdk.parts = nil
+	changed := digest
+	changed.BlockMD5s = []string{"stale", digest.BlockMD5s[1]}
+	err = client.UploadPrecreated(context.Background(), localPath, "/w/weights.pt", "u1", changed)
+	assert.ErrorContains(t, err, "local file changed")
+	assert.Empty(t, sdk.parts)
+	assert.Empty(t, method)
+}
+
+func TestBaiduPanClientDeleteFile(t *testing.T) {
+	var query, form url.Values
+	response := map[string]interface{}{"errno": 0}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		query = r.URL.Query()
+		form = r.PostForm
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := newTestBaiduPanClient(&fakeBaiduPanSDK{}, 1)
+	client.APIBaseURL = server.URL
+
+	require.NoError(t, client.DeleteFile(context.Background(), "/w/weights.pt"))
+	assert.Equal(t, "filemanager", query.Get("method"))
+	assert.Equal(t, "delete", query.Get("opera"))
+	assert.Equal(t, "0", form.Get("async"))
+	assert.Equal(t, `["/w/weights.pt"]`, form.Get("filelist"))
+
+	// 远端已不存在视为成功，其他逐文件错误返回失败
+	response = map[string]interface{}{"errno": 12, "info": []map[string]interface{}{{"errno": -9, "path": "/w/weights.pt"}}}
+	assert.NoError(t, client.DeleteFile(context.Background(), "/w/weights.pt"))
+	response = map[string]interface{}{"errno": 12, "info": []map[string]interface{}{{"errno": 31061, "path": "/w/weights.pt"}}}
+	assert.ErrorContains(t, client.DeleteFile(context.Background(), "/w/weights.pt"), "errno: 31061")
+}
+
+func TestVerifyBaiduCopy(t *testing.T) {
+	digest := FileDigest{Size: 10, MD5: "abc"}
+
+	status, _ := verifyBaiduCopy(digest, BaiduRemoteFileStat{Entry: &BaiduFileEntry{Size: 10, MD5: "ABC"}})
+	assert.Equal(t, entity2.ReplicaStatusVerified, status)
+
+	status, detail := verifyBaiduCopy(digest, BaiduRemoteFileStat{Entry: &BaiduFileEntry{Size: 10}})
+	assert.Equal(t, entity2.ReplicaStatusVerified, status)
+	assert.Contains(t, detail, "md5 unavailable")
+
+	status, detail = verifyBaiduCopy(digest, BaiduRemoteFileStat{Entry: &BaiduFileEntry{Size: 9, MD5: "abc"}})
+	assert.Equal(t, entity2.ReplicaStatusCorrupt, status)
+	assert.Contains(t, detail, "size mismatch")
+
+	status, detail = verifyBaiduCopy(digest, BaiduRemoteFileStat{Entry: &BaiduFileEntry{Size: 10, MD5: "def"}})
+	assert.Equal(t, entity2.ReplicaStatusCorrupt, status)
+	assert.Contains(t, detail, "md5 mismatch")
+
+	status, _ = verifyBaiduCopy(digest, BaiduRemoteFileStat{})
+	assert.Equal(t, entity2.ReplicaStatusCorrupt, status)
+
+	// 多分片文件的列表 md5 不是内容 MD5，不据此判定损坏
+	sliced := FileDigest{Size: 10, MD5: "abc", BlockMD5s: []string{"b1", "b2"}}
+	status, detail = verifyBaiduCopy(sliced, BaiduRemoteFileStat{Entry: &BaiduFileEntry{Size: 10, MD5: "def"}})
+	assert.Equal(t, entity2.ReplicaStatusVerified, status)
+	assert.Contains(t, detail, "not a content md5")
+}
+
+func fakeBaiduListEntry(name string, size int32, md5 string) openapi.Filecreateresponse {
+	isdir := int32(0)
+	return openapi.Filecreateresponse{ServerFilename: &name, Size: &size, Md5: &md5, Isdir: &isdir}
+}
+
+func TestBaiduPanUploaderRapidUploadSkipsTransfer(t *testing.T) {
+	localPath, content := writeDigestTestFile(t, baiduSliceSize+100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errno": 0, "return_type": baiduReturnTypeExists})
+	}))
+	defer server.Close()
+
+	sdk := &fakeBaiduPanSDK{list: []openapi.Filecreateresponse{
+		fakeBaiduListEntry("other.pt", 1, ""),
+		fakeBaiduListEntry("weights.pt", int32(len(content)), md5Hex(content)),
+	}}
+	client := newTestBaiduPanClient(sdk, 1)
+	client.APIBaseURL = server.URL
+	uploader := &BaiduPanUploader{Client: client}
+
+	receipt, err := uploader.Upload(localPath, "/project/luckyProject/weights")
+	require.NoError(t, err)
+	assert.Equal(t, "/project/luckyProject/weights/weights.pt", receipt.RemotePath)
+	assert.True(t, receipt.RapidUpload)
+	assert.Equal(t, entity2.ReplicaStatusVerified, receipt.Status)
+	assert.Equal(t, md5Hex(content), receipt.LocalMD5)
+	assert.EqualValues(t, len(content), receipt.RemoteSize)
+	assert.Empty(t, sdk.uploads)
+	require.Len(t, sdk.queries, 1)
+	assert.Equal(t, "/project/luckyProject/weights", sdk.queries[0].Dir)
+}
+
+func TestBaiduPanUploaderReusesPrecreateSession(t *testing.T) {
+	localPath, content := writeDigestTestFile(t, baiduSliceSize+100)
+	var methods []string
+	var createUploadID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		method := r.URL.Query().Get("method")
+		methods = append(methods, method)
+		if method == "create" {
+			createUploadID = r.PostForm.Get("uploadid")
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errno": 0, "return_type": 1, "uploadid": "session-1"})
+	}))
+	defer server.Close()
+
+	sdk := &fakeBaiduPanSDK{list: []openapi.Filecreateresponse{
+		fakeBaiduListEntry("weights.pt", int32(len(content)), md5Hex(content)),
+	}}
+	client := newTestBaiduPanClient(sdk, 1)
+	client.APIBaseURL = server.URL
+	uploader := &BaiduPanUploader{Client: client}
+
+	receipt, err := uploader.Upload(localPath, "/project/luckyProject/weights")
+	require.NoError(t, err)
+	assert.False(t, receipt.RapidUpload)
+	assert.Equal(t, entity2.ReplicaStatusVerified, receipt.Status)
+	assert.Equal(t, []string{"precreate", "create"}, methods)
+	assert.Equal(t, "session-1", createUploadID)
+	assert.Equal(t, []int{0}, sdk.parts)
+	assert.Empty(t, sdk.uploads)
+}
+
+func TestBaiduPanUploaderFallsBackAndMarksCorrupt(t *testing.T) {
+	localPath, content := writeDigestTestFile(t, baiduSliceSize+100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sdk := &fakeBaiduPanSDK{list: []openapi.Filecreateresponse{
+		fakeBaiduListEntry("weights.pt", int32(len(content)-1), ""),
+	}}
+	client := newTestBaiduPanClient(sdk, 1)
+	client.APIBaseURL = server.URL
+	uploader := &BaiduPanUploader{Client: client}
+
+	receipt, err := uploader.Upload(localPath, "/project/luckyProject/weights")
+	require.NoError(t, err)
+	assert.False(t, receipt.RapidUpload)
+	require.Len(t, sdk.uploads, 1)
+	assert.Equal(t, "/project/luckyProject/weights/weights.pt", sdk.uploads[0].RemotePath)
+	assert.Equal(t, entity2.ReplicaStatusCorrupt, receipt.Status)
+	assert.Contains(t, receipt.StatusDetail, "size mismatch")
+}
+
+func TestBuildBaiduReplica(t *testing.T) {
+	receipt := BaiduUploadReceipt{
+		RemotePath:  "/w/a.pt",
+		RapidUpload: true,
+		LocalSize:   3,
+		LocalMD5:    "abc",
+		RemoteSize:  3,
+		RemoteMD5:   "abc",
+		Status:      entity2.ReplicaStatusVerified,
+	}
+	replica := buildBaiduReplica(entity2.ArtifactTypeModel, "a.pt", receipt)
+	assert.Equal(t, entity2.ArtifactTypeModel, replica.ArtifactType)
+	assert.Equal(t, "a.pt", replica.FileName)
+	assert.Equal(t, StorageTargetBaiduNetdisk, replica.StorageTarget)
+	assert.True(t, replica.RapidUpload)
+	assert.Equal(t, entity2.ReplicaStatusVerified, replica.Status)
+	assert.NotNil(t, replica.VerifiedAt)
+}
@@ -0,0 +1,559 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBaiduTokenURL       = "https://openapi.baidu.com/oauth/2.0/token"
+	DefaultBaiduTokenStatePath = "data/baidu_token.json"
+
+	defaultBaiduRefreshBefore = time.Hour
+
+	baiduTokenStoreMemory = "memory"
+	baiduTokenStoreVault  = "vault"
+
+	baiduAccessTokenCredentialName  = "baidu-pan-access-token"
+	baiduRefreshTokenCredentialName = "baidu-pan-refresh-token"
+)
+
+// baiduAuthErrnos 百度网盘鉴权失败的 errno：-6 身份校验失败 / 111 access token 失效 / 31045 access_token 验证未通过
+var baiduAuthErrnos = map[int]struct{}{-6: {}, 111: {}, 31045: {}}
+
+// baiduErrnoPattern 匹配 SDK 与本项目错误信息中的 "errno: <code>"
+var baiduErrnoPattern = regexp.MustCompile(`errno:\s*(-?\d+)`)
+
+var (
+	ErrBaiduTokenRefreshNotConfigured = errors.New("baidu pan token refresh is not configured (refresh_token/app_key/app_secret required)")
+	ErrBaiduTokenRefreshFailed        = errors.New("baidu pan token refresh failed")
+	// ErrBaiduTokenRefreshNeedsVault 百度 refresh_token 只能使用一次，刷新结果无法写入凭据库时重启后将无法续期
+	ErrBaiduTokenRefreshNeedsVault = errors.New("baidu pan token refresh requires the credential vault")
+)
+
+// BaiduTokenProvider 为百度网盘调用提供 access_token，鉴权失败时可强制刷新。
+type BaiduTokenProvider interface {
+	AccessToken(ctx context.Context) (string, error)
+	ForceRefresh(ctx context.Context) (string, error)
+}
+
+// BaiduTokenStatus 管理接口返回的 token 状态，不包含任何 token 内容。
+type BaiduTokenStatus struct {
+	HasAccessToken      bool       `json:"has_access_token"`
+	HasRefreshToken     bool       `json:"has_refresh_token"`
+	RefreshConfigured   bool       `json:"refresh_configured"`
+	ExpiresAt           *time.Time `json:"expires_at"`
+	ExpiresInSeconds    *int64     `json:"expires_in_seconds"`
+	Expired             bool       `json:"expired"`
+	RefreshBeforeSecond int64      `json:"refresh_before_seconds"`
+	LastRefreshAt       *time.Time `json:"last_refresh_at"`
+	LastRefreshError    string     `json:"last_refresh_error,omitempty"`
+	LastRefreshErrorAt  *time.Time `json:"last_refresh_error_at,omitempty"`
+	Store               string     `json:"store"`
+}
+
+// baiduTokenState 持久化结构：token 只存凭据库，文件记录凭据 ID 与过期时间。
+// AccessToken/RefreshToken 仅用于读取旧版本写入的明文状态文件，不再写出。
+type baiduTokenState struct {
+	AccessToken         string    `json:"access_token,omitempty"`
+	RefreshToken        string    `json:"refresh_token,omitempty"`
+	AccessCredentialID  string    `json:"access_token_credential_id,omitempty"`
+	RefreshCredentialID string    `json:"refresh_token_credential_id,omitempty"`
+	ExpiresAt           time.Time `json:"expires_at"`
+	RefreshedAt         time.Time `json:"refreshed_at"`
+}
+
+// baiduOAuthResponse 百度 OAuth token 接口响应
+type baiduOAuthResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// BaiduTokenManager 维护百度网盘 access_token：过期前自动刷新、鉴权失败时强制刷新一次，并把新 token 轮换到凭据库。
+// 刷新依赖凭据库：未启用凭据库时不发起刷新，只使用配置中的 access_token。
+type BaiduTokenManager struct {
+	appKey        string
+	appSecret     string
+	tokenURL      string
+	statePath     string
+	refreshBefore time.Duration
+	httpClient    *http.Client
+
+	vault               *config.CredentialVault
+	accessCredentialID  string
+	refreshCredentialID string
+
+	mu                 sync.Mutex
+	state              baiduTokenState
+	lastRefreshError   string
+	lastRefreshErrorAt time.Time
+	now                func() time.Time
+}
+
+var (
+	defaultBaiduTokenManager     *BaiduTokenManager
+	defaultBaiduTokenManagerOnce sync.Once
+)
+
+// DefaultBaiduTokenManager 返回进程内共享的 token 管理器，上传与下载共用同一份 token 状态。
+func DefaultBaiduTokenManager() *BaiduTokenManager {
+	defaultBaiduTokenManagerOnce.Do(func() {
+		defaultBaiduTokenManager = NewBaiduTokenManagerFromConfig()
+	})
+	return defaultBaiduTokenManager
+}
+
+// NewBaiduTokenManagerFromConfig 从 baidu_pan 配置构建 token 管理器，并加载已持久化的刷新结果。
+func NewBaiduTokenManagerFromConfig() *BaiduTokenManager {
+	logger := serviceLogger().With("service", "BaiduTokenManager", "method", "NewBaiduTokenManagerFromConfig")
+	var cfg config.BaiduPanConfig
+	if config.AppConfig != nil {
+		cfg = config.AppConfig.BaiduPan
+	}
+
+	resolve := func(field, credentialID, plain string) string {
+		value, err := config.ResolveSecret(credentialID, plain)
+		if err != nil {
+			logger.Error("resolve baidu secret failed", "field", field, "credential_id", credentialID, "error", err)
+			return ""
+		}
+		return strings.TrimSpace(value)
+	}
+
+	manager := &BaiduTokenManager{
+		appKey:        strings.TrimSpace(cfg.AppKey),
+		appSecret:     resolve("app_secret", cfg.AppSecretCredentialID, cfg.AppSecret),
+		tokenURL:      strings.TrimSpace(cfg.TokenURL),
+		statePath:     strings.TrimSpace(cfg.TokenStatePath),
+		refreshBefore: time.Duration(cfg.RefreshBeforeSeconds) * time.Second,
+		state: baiduTokenState{
+			AccessToken:  resolve("access_token", cfg.AccessTokenCredentialID, cfg.AccessToken),
+			RefreshToken: resolve("refresh_token", cfg.RefreshTokenCredentialID, cfg.RefreshToken),
+		},
+	}
+	if expiresAt := strings.TrimSpace(cfg.TokenExpiresAt); expiresAt != "" {
+		if parsed, err := time.Parse(time.RFC3339, expiresAt); err == nil {
+			manager.state.ExpiresAt = parsed
+		} else {
+			logger.Warn("invalid baidu_pan.token_expires_at ignored", "value", expiresAt, "error", err)
+		}
+	}
+	// 启用凭据库时刷新结果轮换到配置引用的凭据；未引用凭据的 token 在首次刷新时自动创建凭据。
+	if config.Vault != nil {
+		manager.vault = config.Vault
+		manager.accessCredentialID = strings.TrimSpace(cfg.AccessTokenCredentialID)
+		manager.refreshCredentialID = strings.TrimSpace(cfg.RefreshTokenCredentialID)
+	}
+
+	if err := manager.loadState(); err != nil {
+		logger.Error("load baidu token state failed", "path", manager.resolvedStatePath(), "error", err)
+	}
+	return manager
+}
+
+// CheckBaiduTokenRefreshStore 启动时校验：配置了 token 自动刷新却未启用凭据库时返回 ErrBaiduTokenRefreshNeedsVault。
+// 百度刷新后旧 refresh_token 立即失效，新 token 只留在内存中的话进程重启后就再也无法续期。
+func CheckBaiduTokenRefreshStore() error {
+	return DefaultBaiduTokenManager().checkRefreshStore()
+}
+
+func (m *BaiduTokenManager) checkRefreshStore() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.refreshConfiguredLocked() && m.vault == nil {
+		return fmt.Errorf("%w: set %s or remove baidu_pan.refresh_token", ErrBaiduTokenRefreshNeedsVault, config.VaultMasterKeyEnv)
+	}
+	return nil
+}
+
+// AccessToken 返回可用的 access_token；临近过期且可刷新时先刷新。
+// 刷新失败但旧 token 尚未过期时继续使用旧 token。
+func (m *BaiduTokenManager) AccessToken(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.needsRefreshLocked() && m.refreshConfiguredLocked() {
+		if err := m.refreshLocked(ctx); err != nil {
+			if m.state.AccessToken == "" || m.expiredLocked() {
+				return "", err
+			}
+			serviceLogger().With("service", "BaiduTokenManager", "method", "AccessToken").
+				Warn("proactive token refresh failed, keep current token", "expires_at", m.state.ExpiresAt, "error", err)
+		}
+	}
+	if m.state.AccessToken == "" {
+		return "", ErrBaiduPanAccessTokenRequired
+	}
+	return m.state.AccessToken, nil
+}
+
+// ForceRefresh 立即刷新 token（用于鉴权失败后的单次重试或管理接口）。
+func (m *BaiduTokenManager) ForceRefresh(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.refreshConfiguredLocked() {
+		return "", ErrBaiduTokenRefreshNotConfigured
+	}
+	if err := m.refreshLocked(ctx); err != nil {
+		return "", err
+	}
+	return m.state.AccessToken, nil
+}
+
+// Status 返回 token 状态快照。
+func (m *BaiduTokenManager) Status() BaiduTokenStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := BaiduTokenStatus{
+		HasAccessToken:      m.state.AccessToken != "",
+		HasRefreshToken:     m.state.RefreshToken != "",
+		RefreshConfigured:   m.refreshConfiguredLocked(),
+		Expired:             m.expiredLocked(),
+		RefreshBeforeSecond: int64(m.refreshBeforeDuration().Seconds()),
+		LastRefreshError:    m.lastRefreshError,
+		Store:               m.storeName(),
+	}
+	if !m.state.ExpiresAt.IsZero() {
+		expiresAt := m.state.ExpiresAt
+		expiresIn := int64(expiresAt.Sub(m.clock()).Seconds())
+		status.ExpiresAt = &expiresAt
+		status.ExpiresInSeconds = &expiresIn
+	}
+	if !m.state.RefreshedAt.IsZero() {
+		refreshedAt := m.state.RefreshedAt
+		status.LastRefreshAt = &refreshedAt
+	}
+	if !m.lastRefreshErrorAt.IsZero() {
+		errorAt := m.lastRefreshErrorAt
+		status.LastRefreshErrorAt = &errorAt
+	}
+	return status
+}
+
+// CredentialIDs 返回当前用于保存 token 的凭据 ID（含刷新时自动创建的），供删除凭据前的引用检查。
+func (m *BaiduTokenManager) CredentialIDs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, 2)
+	for _, id := range []string{m.accessCredentialID, m.refreshCredentialID} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (m *BaiduTokenManager) refreshLocked(ctx context.Context) error {
+	logger := serviceLogger().With("service", "BaiduTokenManager", "method", "refresh")
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// 请求前确认刷新结果可以落盘：换取新 token 后旧 refresh_token 即失效，不能只留在内存中
+	if m.vault == nil {
+		err := fmt.Errorf("%w (env %s is not set)", ErrBaiduTokenRefreshNeedsVault, config.VaultMasterKeyEnv)
+		m.lastRefreshError = err.Error()
+		m.lastRefreshErrorAt = m.clock()
+		logger.Error("refresh baidu token skipped", "error", err)
+		return err
+	}
+
+	resp, err := m.requestToken(ctx)
+	if err != nil {
+		m.lastRefreshError = err.Error()
+		m.lastRefreshErrorAt = m.clock()
+		logger.Error("refresh baidu token failed", "error", err)
+		return err
+	}
+
+	now := m.clock()
+	next := baiduTokenState{
+		AccessToken:  strings.TrimSpace(resp.AccessToken),
+		RefreshToken: m.state.RefreshToken,
+		RefreshedAt:  now,
+	}
+	// 百度刷新后旧 refresh_token 失效，必须保存新的。
+	if refreshToken := strings.TrimSpace(resp.RefreshToken); refreshToken != "" {
+		next.RefreshToken = refreshToken
+	}
+	if resp.ExpiresIn > 0 {
+		next.ExpiresAt = now.Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+
+	if err := m.persist(next); err != nil {
+		m.lastRefreshError = err.Error()
+		m.lastRefreshErrorAt = now
+		logger.Error("persist refreshed baidu token failed", "error", err)
+		return err
+	}
+	m.state = next
+	m.lastRefreshError = ""
+	m.lastRefreshErrorAt = time.Time{}
+	logger.Info("refresh baidu token success", "expires_at", next.ExpiresAt, "store", m.storeName())
+	return nil
+}
+
+func (m *BaiduTokenManager) requestToken(ctx context.Context) (baiduOAuthResponse, error) {
+	query := url.Values{}
+	query.Set("grant_type", "refresh_token")
+	query.Set("refresh_token", m.state.RefreshToken)
+	query.Set("client_id", m.appKey)
+	query.Set("client_secret", m.appSecret)
+
+	tokenURL := m.tokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultBaiduTokenURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return baiduOAuthResponse{}, fmt.Errorf("%w: build request: %v", ErrBaiduTokenRefreshFailed, err)
+	}
+
+	client := m.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return baiduOAuthResponse{}, fmt.Errorf("%w: %v", ErrBaiduTokenRefreshFailed, err)
+	}
+	defer httpResp.Body.Close()
+
+	var payload baiduOAuthResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&payload); err != nil {
+		return baiduOAuthResponse{}, fmt.Errorf("%w: decode response (status=%d): %v", ErrBaiduTokenRefreshFailed, httpResp.StatusCode, err)
+	}
+	if payload.Error != "" {
+		return baiduOAuthResponse{}, fmt.Errorf("%w: %s: %s", ErrBaiduTokenRefreshFailed, payload.Error, payload.ErrorDescription)
+	}
+	if httpResp.StatusCode != http.StatusOK || strings.TrimSpace(payload.AccessToken) == "" {
+		return baiduOAuthResponse{}, fmt.Errorf("%w: unexpected response (status=%d)", ErrBaiduTokenRefreshFailed, httpResp.StatusCode)
+	}
+	return payload, nil
+}
+
+// persist 保存刷新结果：token 轮换到凭据库（未引用凭据时新建），0600 文件只写凭据 ID 与过期时间。
+func (m *BaiduTokenManager) persist(state baiduTokenState) error {
+	if m.vault == nil {
+		return ErrBaiduTokenRefreshNeedsVault
+	}
+
+	accessID, err := m.storeTokenCredential(m.accessCredentialID, baiduAccessTokenCredentialName, state.AccessToken)
+	if err != nil {
+		return fmt.Errorf("store baidu access token credential failed: %w", err)
+	}
+	m.accessCredentialID = accessID
+	if state.RefreshToken != "" && (m.refreshCredentialID == "" || state.RefreshToken != m.state.RefreshToken) {
+		refreshID, err := m.storeTokenCredential(m.refreshCredentialID, baiduRefreshTokenCredentialName, state.RefreshToken)
+		if err != nil {
+			return fmt.Errorf("store baidu refresh token credential failed: %w", err)
+		}
+		m.refreshCredentialID = refreshID
+	}
+
+	fileState := baiduTokenState{
+		AccessCredentialID:  m.accessCredentialID,
+		RefreshCredentialID: m.refreshCredentialID,
+		ExpiresAt:           state.ExpiresAt,
+		RefreshedAt:         state.RefreshedAt,
+	}
+	data, err := json.MarshalIndent(fileState, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode baidu token state failed: %w", err)
+	}
+	statePath := m.resolvedStatePath()
+	if err := os.MkdirAll(filepath.Dir(statePath), 0o700); err != nil {
+		return fmt.Errorf("create baidu token state dir failed: %w", err)
+	}
+	tmp := statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write baidu token state failed: %w", err)
+	}
+	if err := os.Rename(tmp, statePath); err != nil {
+		return fmt.Errorf("replace baidu token state failed: %w", err)
+	}
+	return nil
+}
+
+// storeTokenCredential 轮换已有凭据，id 为空时新建一条 baidu_token 凭据，返回凭据 ID。
+func (m *BaiduTokenManager) storeTokenCredential(id, name, token string) (string, error) {
+	if id != "" {
+		if _, err := m.vault.Rotate(id, []byte(token)); err != nil {
+			return "", err
+		}
+		return id, nil
+	}
+	meta, err := m.vault.Create(config.CredentialKindBaiduToken, name, "created by baidu token refresh", []byte(token))
+	if err != nil {
+		return "", err
+	}
+	return meta.ID, nil
+}
+
+// loadState 读取上次刷新的结果，覆盖配置中的初始 token：
+// 凭据库模式按文件记录的凭据 ID 读取 token；旧版本写入的明文 token 仍可读取，下次刷新后从文件中移除。
+func (m *BaiduTokenManager) loadState() error {
+	data, err := os.ReadFile(m.resolvedStatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var stored baiduTokenState
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+
+	if stored.AccessToken != "" {
+		m.state.AccessToken = stored.AccessToken
+	}
+	if stored.RefreshToken != "" {
+		m.state.RefreshToken = stored.RefreshToken
+	}
+	if m.vault != nil {
+		if m.accessCredentialID == "" {
+			m.accessCredentialID = stored.AccessCredentialID
+		}
+		if m.refreshCredentialID == "" {
+			m.refreshCredentialID = stored.RefreshCredentialID
+		}
+		// 配置直接引用的凭据已在构建时解析，这里只补充读取刷新时自动创建的凭据
+		for _, item := range []struct {
+			id     string
+			target *string
+		}{
+			{stored.AccessCredentialID, &m.state.AccessToken},
+			{stored.RefreshCredentialID, &m.state.RefreshToken},
+		} {
+			if item.id == "" {
+				continue
+			}
+			secret, _, err := m.vault.Secret(item.id)
+			if err != nil {
+				return fmt.Errorf("load baidu token credential %s failed: %w", item.id, err)
+			}
+			*item.target = strings.TrimSpace(string(secret))
+		}
+	}
+	if !stored.ExpiresAt.IsZero() {
+		m.state.ExpiresAt = stored.ExpiresAt
+	}
+	m.state.RefreshedAt = stored.RefreshedAt
+	return nil
+}
+
+func (m *BaiduTokenManager) needsRefreshLocked() bool {
+	if m.state.AccessToken == "" {
+		return true
+	}
+	if m.state.ExpiresAt.IsZero() {
+		return false
+	}
+	return !m.clock().Add(m.refreshBeforeDuration()).Before(m.state.ExpiresAt)
+}
+
+func (m *BaiduTokenManager) expiredLocked() bool {
+	return !m.state.ExpiresAt.IsZero() && !m.clock().Before(m.state.ExpiresAt)
+}
+
+func (m *BaiduTokenManager) refreshConfiguredLocked() bool {
+	return m.state.RefreshToken != "" && m.appKey != "" && m.appSecret != ""
+}
+
+func (m *BaiduTokenManager) refreshBeforeDuration() time.Duration {
+	if m.refreshBefore <= 0 {
+		return defaultBaiduRefreshBefore
+	}
+	return m.refreshBefore
+}
+
+func (m *BaiduTokenManager) resolvedStatePath() string {
+	if m.statePath == "" {
+		return DefaultBaiduTokenStatePath
+	}
+	return m.statePath
+}
+
+func (m *BaiduTokenManager) storeName() string {
+	if m.vault != nil {
+		return baiduTokenStoreVault
+	}
+	return baiduTokenStoreMemory
+}
+
+func (m *BaiduTokenManager) clock() time.Time {
+	if m.now != nil {
+		return m.now()
+	}
+	return time.Now()
+}
+
+// isBaiduAuthError 识别 SDK 返回的鉴权失败：只按 errno（-6 / 111 / 31045）与 HTTP 401 判断，
+// 其他含 "expired" 等字样的错误（如上传 uploadid 过期）不触发刷新。
+func isBaiduAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	if strings.Contains(message, "401 unauthorized") {
+		return true
+	}
+	for _, match := range baiduErrnoPattern.FindAllStringSubmatch(message, -1) {
+		code, convErr := strconv.Atoi(match[1])
+		if convErr != nil {
+			continue
+		}
+		if _, ok := baiduAuthErrnos[code]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// withBaiduTokenRetry 取 token 执行 fn；鉴权失败时强制刷新一次并重试。
+// provider 为空时退化为使用静态 token。
+func withBaiduTokenRetry(ctx context.Context, provider BaiduTokenProvider, staticToken string, fn func(token string) error) error {
+	if provider == nil {
+		if strings.TrimSpace(staticToken) == "" {
+			return ErrBaiduPanAccessTokenRequired
+		}
+		return fn(strings.TrimSpace(staticToken))
+	}
+
+	token, err := provider.AccessToken(ctx)
+	if err != nil {
+		return err
+	}
+	err = fn(token)
+	if !isBaiduAuthError(err) {
+		return err
+	}
+
+	serviceLogger().With("service", "BaiduTokenManager", "method", "withBaiduTokenRetry").
+		Warn("baidu auth failure, force refresh and retry once", "error", err)
+	refreshed, refreshErr := provider.ForceRefresh(ctx)
+	if refreshErr != nil {
+		return fmt.Errorf("%w (token refresh failed: %v)", err, refreshErr)
+	}
+	return fn(refreshed)
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeBaiduOAuthServer 模拟百度 OAuth token 接口，每次刷新返回递增的 token。
+func newFakeBaiduOAuthServer(t *testing.T, fail bool) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		query := r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		if fail || query.Get("grant_type") != "refresh_token" || query.Get("client_id") != "app-key" || query.Get("client_secret") != "app-secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "refresh token has been used"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("access-%d", n),
+			"refresh_token": fmt.Sprintf("refresh-%d", n),
+			"expires_in":    2592000,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// newTestBaiduTokenVault 在临时目录中打开一个凭据库，供刷新结果落盘。
+func newTestBaiduTokenVault(t *testing.T) *config.CredentialVault {
+	t.Helper()
+	vault, err := config.OpenCredentialVault(filepath.Join(t.TempDir(), "vault.json"), bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	return vault
+}
+
+// newTestBaiduTokenManager 构建带独立凭据库的 token 管理器。
+func newTestBaiduTokenManager(t *testing.T, tokenURL, statePath string, expiresAt time.Time) *BaiduTokenManager {
+	t.Helper()
+	return &BaiduTokenManager{
+		vault:         newTestBaiduTokenVault(t),
+		appKey:        "app-key",
+		appSecret:     "app-secret",
+		tokenURL:      tokenURL,
+		statePath:     statePath,
+		refreshBefore: time.Hour,
+		state: baiduTokenState{
+			AccessToken:  "access-0",
+			RefreshToken: "refresh-0",
+			ExpiresAt:    expiresAt,
+		},
+	}
+}
+
+func TestBaiduTokenManagerRefreshesBeforeExpiry(t *testing.T) {
+	server, calls := newFakeBaiduOAuthServer(t, false)
+	statePath := filepath.Join(t.TempDir(), "baidu_token.json")
+
+	fresh := newTestBaiduTokenManager(t, server.URL, statePath, time.Now().Add(48*time.Hour))
+	token, err := fresh.AccessToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "access-0", token)
+	assert.EqualValues(t, 0, atomic.LoadInt32(calls))
+
+	expiring := newTestBaiduTokenManager(t, server.URL, statePath, time.Now().Add(10*time.Minute))
+	token, err = expiring.AccessToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+	status := expiring.Status()
+	assert.True(t, status.HasAccessToken)
+	assert.False(t, status.Expired)
+	require.NotNil(t, status.LastRefreshAt)
+	require.NotNil(t, status.ExpiresInSeconds)
+	assert.Greater(t, *status.ExpiresInSeconds, int64(2500000))
+	assert.Equal(t, baiduTokenStoreVault, status.Store)
+
+	body, err := json.Marshal(status)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "access-1")
+	assert.NotContains(t, string(body), "refresh-1")
+}
+
+func TestBaiduTokenManagerRefreshRequiresVault(t *testing.T) {
+	server, calls := newFakeBaiduOAuthServer(t, false)
+	statePath := filepath.Join(t.TempDir(), "baidu_token.json")
+
+	manager := newTestBaiduTokenManager(t, server.URL, statePath, time.Now().Add(10*time.Minute))
+	manager.vault = nil
+	assert.True(t, errors.Is(manager.checkRefreshStore(), ErrBaiduTokenRefreshNeedsVault))
+
+	// 临近过期：不发起刷新（否则旧 refresh_token 失效而新 token 无处保存），继续使用未过期的旧 token
+	token, err := manager.AccessToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "access-0", token)
+	assert.Equal(t, baiduTokenStoreMemory, manager.Status().Store)
+	assert.Contains(t, manager.Status().LastRefreshError, ErrBaiduTokenRefreshNeedsVault.Error())
+
+	_, err = manager.ForceRefresh(context.Background())
+	assert.True(t, errors.Is(err, ErrBaiduTokenRefreshNeedsVault))
+	assert.EqualValues(t, 0, atomic.LoadInt32(calls))
+	assert.Equal(t, "refresh-0", manager.state.RefreshToken)
+	_, err = os.Stat(statePath)
+	assert.True(t, os.IsNotExist(err))
+
+	manager.vault = newTestBaiduTokenVault(t)
+	assert.NoError(t, manager.checkRefreshStore())
+	static := &BaiduTokenManager{state: baiduTokenState{AccessToken: "static"}}
+	assert.NoError(t, static.checkRefreshStore())
+}
+
+func TestBaiduTokenManagerPersistsToVault(t *testing.T) {
+	server, _ := newFakeBaiduOAuthServer(t, false)
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "baidu_token.json")
+	vault := newTestBaiduTokenVault(t)
+
+	manager := newTestBaiduTokenManager(t, server.URL, statePath, time.Now().Add(10*time.Minute))
+	manager.vault = vault
+	token, err := manager.AccessToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token)
+	assert.Equal(t, baiduTokenStoreVault, manager.Status().Store)
+	require.Len(t, vault.List(), 2)
+
+	data, err := os.ReadFile(statePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "access-1")
+	assert.NotContains(t, string(data), "refresh-1")
+	info, err := os.Stat(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// 再次刷新轮换同一组凭据，不新建
+	_, err = manager.ForceRefresh(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, vault.List(), 2)
+
+	// 重新加载时按状态文件中的凭据 ID 从凭据库读取新 token
+	reloaded := newTestBaiduTokenManager(t, server.URL, statePath, time.Time{})
+	reloaded.vault = vault
+	require.NoError(t, reloaded.loadState())
+	assert.Equal(t, "access-2", reloaded.state.AccessToken)
+	assert.Equal(t, "refresh-2", reloaded.state.RefreshToken)
+	assert.Equal(t, manager.accessCredentialID, reloaded.accessCredentialID)
+
+	// 旧版本写入的明文状态文件仍可读取
+	require.NoError(t, os.WriteFile(statePath, []byte(`{"access_token":"legacy-access","refresh_token":"legacy-refresh"}`), 0o600))
+	legacy := newTestBaiduTokenManager(t, server.URL, statePath, time.Time{})
+	require.NoError(t, legacy.loadState())
+	assert.Equal(t, "legacy-access", legacy.state.AccessToken)
+	assert.Equal(t, "legacy-refresh", legacy.state.RefreshToken)
+}
+
+func TestBaiduTokenManagerRefreshFailure(t *testing.T) {
+	server, _ := newFakeBaiduOAuthServer(t, true)
+	statePath := filepath.Join(t.TempDir(), "baidu_token.json")
+
+	// 临近过期但未过期：刷新失败时继续使用旧 token
+	expiring := newTestBaiduTokenManager(t, server.URL, statePath, time.Now().Add(10*time.Minute))
+	token, err := expiring.AccessToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "access-0", token)
+	assert.Contains(t, expiring.Status().LastRefreshError, "invalid_grant")
+
+	expired := newTestBaiduTokenManager(t, server.URL, statePath, time.Now().Add(-time.Minute))
+	_, err = expired.AccessToken(context.Background())
+	assert.True(t, errors.Is(err, ErrBaiduTokenRefreshFailed))
+
+	unconfigured := &BaiduTokenManager{state: baiduTokenState{AccessToken: "static"}}
+	_, err = unconfigured.ForceRefresh(context.Background())
+	assert.True(t, errors.Is(err, ErrBaiduTokenRefreshNotConfigured))
+}
+
+func TestWithBaiduTokenRetryRefreshesOnceOnAuthFailure(t *testing.T) {
+	server, calls := newFakeBaiduOAuthServer(t, false)
+	manager := newTestBaiduTokenManager(t, server.URL, filepath.Join(t.TempDir(), "baidu_token.json"), time.Now().Add(48*time.Hour))
+
+	var used []string
+	err := withBaiduTokenRetry(context.Background(), manager, "", func(token string) error {
+		used = append(used, token)
+		if token == "access-0" {
+			return errors.New("precreate failed with errno: -6")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"access-0", "access-1"}, used)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+	used = nil
+	err = withBaiduTokenRetry(context.Background(), manager, "", func(token string) error {
+		used = append(used, token)
+		return errors.New("create file failed with errno: 111")
+	})
+	assert.Error(t, err)
+	assert.Len(t, used, 2)
+
+	err = withBaiduTokenRetry(context.Background(), nil, "", func(string) error { return nil })
+	assert.True(t, errors.Is(err, ErrBaiduPanAccessTokenRequired))
+}
+
+func TestIsBaiduAuthError(t *testing.T) {
+	assert.True(t, isBaiduAuthError(errors.New("get file list failed with errno: -6")))
+	assert.True(t, isBaiduAuthError(errors.New("401 Unauthorized")))
+	assert.True(t, isBaiduAuthError(errors.New("create file failed with errno: 111")))
+	assert.True(t, isBaiduAuthError(fmt.Errorf("%w: precreate failed with errno: 31045", ErrBaiduRapidUploadFailed)))
+	assert.False(t, isBaiduAuthError(errors.New("precreate failed with errno: 31061")))
+	assert.False(t, isBaiduAuthError(errors.New("upload failed: uploadid expired")))
+	assert.False(t, isBaiduAuthError(errors.New("superfile2 failed with errno: 1116")))
+	assert.False(t, isBaiduAuthError(errors.New("invalid access token format")))
+	assert.False(t, isBaiduAuthError(nil))
+}
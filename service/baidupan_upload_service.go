@@ -1,16 +1,12 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path"
 	"path/filepath"
 	"strings"
-	"sync"
-
-	baidupanplus "github.com/S-zhi/baidupansdk/baidupanplus"
-
-	"lucky_project/config"
 )
 
 const (
@@ -24,59 +20,80 @@ var (
 	ErrInvalidBaiduRemoteDir       = errors.New("invalid baidu remote dir")
 )
 
-// BaiduPanUploader wraps baidupansdk upload flow and serializes calls for the SDK's global config state.
+// BaiduPanUploader 上传到百度网盘，请求经由共享的 BaiduPanClient 限流。
+// 先按内容 MD5 尝试秒传，未命中时在同一 precreate 会话内分片上传；完成后比对远端元数据并给出校验结果。
 type BaiduPanUploader struct {
-	AccessToken string
-	IsSVIP      bool
-	LogPath     string
-	mu          sync.Mutex
+	Client *BaiduPanClient
 }
 
 func NewBaiduPanUploaderFromConfig() *BaiduPanUploader {
-	var cfg config.BaiduPanConfig
-	if config.AppConfig != nil {
-		cfg = config.AppConfig.BaiduPan
-	}
-
-	logPath := strings.TrimSpace(cfg.LogPath)
-	if logPath == "" {
-		logPath = DefaultBaiduPanLogPath
-	}
-
-	return &BaiduPanUploader{
-		AccessToken: strings.TrimSpace(cfg.AccessToken),
-		IsSVIP:      cfg.IsSVIP,
-		LogPath:     logPath,
-	}
+	return &BaiduPanUploader{Client: DefaultBaiduPanClient()}
 }
 
-func (u *BaiduPanUploader) Upload(localPath, remoteDir string) (string, error) {
-	if strings.TrimSpace(u.AccessToken) == "" {
-		return "", ErrBaiduPanAccessTokenRequired
+func (u *BaiduPanUploader) Upload(localPath, remoteDir string) (BaiduUploadReceipt, error) {
+	logger := serviceLogger().With("service", "BaiduPanUploader", "method", "Upload")
+	if u.Client == nil {
+		return BaiduUploadReceipt{}, ErrBaiduPanClientNil
 	}
 
 	normalizedDir, err := normalizeBaiduRemoteDir(remoteDir)
 	if err != nil {
-		return "", err
+		return BaiduUploadReceipt{}, err
 	}
 
 	baseName := filepath.Base(localPath)
 	if strings.TrimSpace(baseName) == "" || baseName == "." || baseName == string(filepath.Separator) {
-		return "", ErrInvalidUploadFile
+		return BaiduUploadReceipt{}, ErrInvalidUploadFile
 	}
 
 	remotePath := path.Join(normalizedDir, baseName)
+	digest, err := ComputeFileDigest(localPath)
+	if err != nil {
+		return BaiduUploadReceipt{}, fmt.Errorf("upload file to baidu pan failed: %w", err)
+	}
 
-	u.mu.Lock()
-	defer u.mu.Unlock()
-
-	baidupanplus.NewBasicConfig(u.AccessToken, u.IsSVIP, u.LogPath)
-	uploadConfig := baidupanplus.NewUploadFileConfig(localPath, remotePath)
-	if err := baidupanplus.UploadFileWithConfig(uploadConfig); err != nil {
-		return "", fmt.Errorf("upload file to baidu pan failed: %w", err)
+	ctx := context.Background()
+	rapid, uploadID, err := u.Client.RapidUpload(ctx, remotePath, digest)
+	if err != nil {
+		// 秒传只是优化，precreate 失败时回退到普通上传。
+		logger.Warn("rapid upload failed, fallback to regular upload", "remote_path", remotePath, "error", err)
+		rapid, uploadID = false, ""
+	}
+	if !rapid {
+		if uploadID != "" {
+			// 复用 precreate 打开的会话，避免遗留未完成的上传会话。
+			err = u.Client.UploadPrecreated(ctx, localPath, remotePath, uploadID, digest)
+		} else {
+			err = u.Client.Upload(ctx, localPath, remotePath)
+		}
+		if err != nil {
+			if errors.Is(err, ErrBaiduPanAccessTokenRequired) {
+				return BaiduUploadReceipt{}, err
+			}
+			return BaiduUploadReceipt{}, fmt.Errorf("upload file to baidu pan failed: %w", err)
+		}
 	}
 
-	return remotePath, nil
+	stat, statErr := u.Client.StatFile(ctx, remotePath)
+	receipt := newBaiduUploadReceipt(remotePath, rapid, digest, stat, statErr)
+	logger.Info(
+		"upload to baidu pan finished",
+		"remote_path", remotePath,
+		"rapid_upload", rapid,
+		"size", digest.Size,
+		"md5", digest.MD5,
+		"status", receipt.Status,
+		"status_detail", receipt.StatusDetail,
+	)
+	return receipt, nil
+}
+
+// Remove 删除网盘上的文件，远端不存在时视为成功。
+func (u *BaiduPanUploader) Remove(remotePath string) error {
+	if u.Client == nil {
+		return ErrBaiduPanClientNil
+	}
+	return u.Client.DeleteFile(context.Background(), remotePath)
 }
 
 func normalizeBaiduRemoteDir(remoteDir string) (string, error) {
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	coreServerHeartbeatKeyPrefix = "core-servers:heartbeat:"
+	coreServerLastSeenHashKey    = "core-servers:last-seen"
+
+	defaultCoreServerHeartbeatTTL = 60 * time.Second
+
+	CoreServerStatusOnline  = "online"
+	CoreServerStatusOffline = "offline"
+	CoreServerStatusUnknown = "unknown"
+)
+
+var (
+	ErrCoreServerOffline          = errors.New("core server is offline")
+	ErrCoreServerHeartbeatInvalid = errors.New("core server heartbeat is invalid")
+)
+
+// CoreServerHeartbeat 核心服务器心跳上报内容，写入 Redis 后按 TTL 自动过期。
+type CoreServerHeartbeat struct {
+	GPUCount         int       `json:"gpu_count"`
+	GPUMemoryUsedMB  float64   `json:"gpu_memory_used_mb"`
+	GPUMemoryTotalMB float64   `json:"gpu_memory_total_mb"`
+	DiskFreeGB       float64   `json:"disk_free_gb"`
+	Load1            float64   `json:"load1"`
+	Load5            float64   `json:"load5"`
+	Load15           float64   `json:"load15"`
+	ReportedAt       time.Time `json:"reported_at"`
+}
+
+// CoreServerHeartbeatTTL 返回配置中的心跳过期时间，未配置时使用默认 60 秒。
+func CoreServerHeartbeatTTL() time.Duration {
+	if config.AppConfig == nil || config.AppConfig.CoreServer.HeartbeatTTLSeconds <= 0 {
+		return defaultCoreServerHeartbeatTTL
+	}
+	return time.Duration(config.AppConfig.CoreServer.HeartbeatTTLSeconds) * time.Second
+}
+
+// ReportCoreServerHeartbeat 写入一次心跳（带 TTL），并刷新不过期的 last-seen 时间。
+// 只接受已在 core-servers 注册表中的服务器。
+func ReportCoreServerHeartbeat(ctx context.Context, key string, heartbeat CoreServerHeartbeat) (CoreServer, error) {
+	logger := serviceLogger().With("service", "CoreServerHeartbeat", "method", "ReportCoreServerHeartbeat")
+	if config.RedisClient == nil {
+		return CoreServer{}, ErrRedisNotInitialized
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	server, err := getRegisteredCoreServer(ctx, key)
+	if err != nil {
+		return CoreServer{}, err
+	}
+	if err := validateCoreServerHeartbeat(heartbeat); err != nil {
+		logger.Warn("report heartbeat failed: invalid payload", "core_server_key", server.Key, "error", err)
+		return CoreServer{}, err
+	}
+
+	heartbeat.ReportedAt = time.Now()
+	payload, err := json.Marshal(heartbeat)
+	if err != nil {
+		return CoreServer{}, fmt.Errorf("encode core server heartbeat failed: %w", err)
+	}
+
+	ttl := CoreServerHeartbeatTTL()
+	pipe := config.RedisClient.TxPipeline()
+	pipe.Set(ctx, coreServerHeartbeatKeyPrefix+server.Key, payload, ttl)
+	pipe.HSet(ctx, coreServerLastSeenHashKey, server.Key, heartbeat.ReportedAt.Format(time.RFC3339))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return CoreServer{}, fmt.Errorf("write core server heartbeat failed (key=%s): %w", server.Key, err)
+	}
+
+	lastSeen := heartbeat.ReportedAt
+	server.Status = CoreServerStatusOnline
+	server.LastSeen = &lastSeen
+	server.Heartbeat = &heartbeat
+	logger.Info(
+		"report heartbeat success",
+		"core_server_key", server.Key,
+		"gpu_count", heartbeat.GPUCount,
+		"disk_free_gb", heartbeat.DiskFreeGB,
+		"ttl_seconds", int(ttl.Seconds()),
+	)
+	return server, nil
+}
+
+// EnsureCoreServerAvailable 校验核心服务器可作为上传目标：离线服务器一律拒绝，
+// 从未上报心跳的服务器在 require_heartbeat=true 时同样拒绝。
+func EnsureCoreServerAvailable(server CoreServer) error {
+	switch server.Status {
+	case CoreServerStatusOffline:
+		return fmt.Errorf("%w (key=%s)", ErrCoreServerOffline, server.Key)
+	case CoreServerStatusUnknown, "":
+		if config.AppConfig != nil && config.AppConfig.CoreServer.RequireHeartbeat {
+			return fmt.Errorf("%w (key=%s, no heartbeat reported)", ErrCoreServerOffline, server.Key)
+		}
+	}
+	return nil
+}
+
+// attachCoreServerStatus 批量读取心跳与 last-seen，回填到服务器列表。
+func attachCoreServerStatus(ctx context.Context, servers []CoreServer) error {
+	if len(servers) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(servers))
+	for _, server := range servers {
+		keys = append(keys, coreServerHeartbeatKeyPrefix+server.Key)
+	}
+	heartbeats, err := config.RedisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return fmt.Errorf("mget core server heartbeats failed: %w", err)
+	}
+
+	fields := make([]string, 0, len(servers))
+	for _, server := range servers {
+		fields = append(fields, server.Key)
+	}
+	lastSeenValues, err := config.RedisClient.HMGet(ctx, coreServerLastSeenHashKey, fields...).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("hmget %s failed: %w", coreServerLastSeenHashKey, err)
+	}
+
+	for i := range servers {
+		var heartbeat *CoreServerHeartbeat
+		if raw, ok := heartbeats[i].(string); ok && strings.TrimSpace(raw) != "" {
+			var value CoreServerHeartbeat
+			if err := json.Unmarshal([]byte(raw), &value); err == nil {
+				heartbeat = &value
+			}
+		}
+
+		var lastSeen *time.Time
+		if i < len(lastSeenValues) {
+			if raw, ok := lastSeenValues[i].(string); ok {
+				if parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(raw)); err == nil {
+					lastSeen = &parsed
+				}
+			}
+		}
+
+		servers[i].Heartbeat = heartbeat
+		servers[i].LastSeen = lastSeen
+		servers[i].Status = resolveCoreServerStatus(heartbeat, lastSeen)
+	}
+	return nil
+}
+
+func resolveCoreServerStatus(heartbeat *CoreServerHeartbeat, lastSeen *time.Time) string {
+	switch {
+	case heartbeat != nil:
+		return CoreServerStatusOnline
+	case lastSeen != nil:
+		return CoreServerStatusOffline
+	default:
+		return CoreServerStatusUnknown
+	}
+}
+
+func validateCoreServerHeartbeat(heartbeat CoreServerHeartbeat) error {
+	switch {
+	case heartbeat.GPUCount < 0:
+		return fmt.Errorf("%w: gpu_count must be >= 0", ErrCoreServerHeartbeatInvalid)
+	case heartbeat.GPUMemoryUsedMB < 0, heartbeat.GPUMemoryTotalMB < 0:
+		return fmt.Errorf("%w: gpu memory must be >= 0", ErrCoreServerHeartbeatInvalid)
+	case heartbeat.GPUMemoryTotalMB > 0 && heartbeat.GPUMemoryUsedMB > heartbeat.GPUMemoryTotalMB:
+		return fmt.Errorf("%w: gpu_memory_used_mb exceeds gpu_memory_total_mb", ErrCoreServerHeartbeatInvalid)
+	case heartbeat.DiskFreeGB < 0:
+		return fmt.Errorf("%w: disk_free_gb must be >= 0", ErrCoreServerHeartbeatInvalid)
+	case heartbeat.Load1 < 0, heartbeat.Load5 < 0, heartbeat.Load15 < 0:
+		return fmt.Errorf("%w: load must be >= 0", ErrCoreServerHeartbeatInvalid)
+	}
+	return nil
+}
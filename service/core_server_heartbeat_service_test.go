@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"lucky_project/config"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCoreServerStatus(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, CoreServerStatusOnline, resolveCoreServerStatus(&CoreServerHeartbeat{}, &now))
+	assert.Equal(t, CoreServerStatusOffline, resolveCoreServerStatus(nil, &now))
+	assert.Equal(t, CoreServerStatusUnknown, resolveCoreServerStatus(nil, nil))
+}
+
+func TestValidateCoreServerHeartbeat(t *testing.T) {
+	assert.NoError(t, validateCoreServerHeartbeat(CoreServerHeartbeat{
+		GPUCount:         2,
+		GPUMemoryUsedMB:  1024,
+		GPUMemoryTotalMB: 49152,
+		DiskFreeGB:       512.5,
+		Load1:            1.2,
+	}))
+	assert.ErrorIs(t, validateCoreServerHeartbeat(CoreServerHeartbeat{GPUCount: -1}), ErrCoreServerHeartbeatInvalid)
+	assert.ErrorIs(t, validateCoreServerHeartbeat(CoreServerHeartbeat{GPUMemoryUsedMB: 10, GPUMemoryTotalMB: 5}), ErrCoreServerHeartbeatInvalid)
+	assert.ErrorIs(t, validateCoreServerHeartbeat(CoreServerHeartbeat{DiskFreeGB: -1}), ErrCoreServerHeartbeatInvalid)
+}
+
+func TestEnsureCoreServerAvailable(t *testing.T) {
+	assert.NoError(t, EnsureCoreServerAvailable(CoreServer{Key: "a", Status: CoreServerStatusOnline}))
+	assert.NoError(t, EnsureCoreServerAvailable(CoreServer{Key: "a", Status: CoreServerStatusUnknown}))
+	assert.ErrorIs(t, EnsureCoreServerAvailable(CoreServer{Key: "a", Status: CoreServerStatusOffline}), ErrCoreServerOffline)
+}
+
+// initMiniredisForTest 将 config.RedisClient 指向进程内的 miniredis，测试结束后恢复，
+// 避免向共享的核心服务器注册表写入假服务器与心跳。
+func initMiniredisForTest(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	previous := config.RedisClient
+	config.RedisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = config.RedisClient.Close()
+		config.RedisClient = previous
+	})
+	return mr
+}
+
+func TestReportCoreServerHeartbeat(t *testing.T) {
+	mr := initMiniredisForTest(t)
+	mr.HSet(coreServersHashKey, "rtx-heartbeat", `{"ip":"10.10.10.11","port":22}`)
+
+	ctx := context.Background()
+	_, err := ReportCoreServerHeartbeat(ctx, "rtx-heartbeat", CoreServerHeartbeat{GPUCount: 1, DiskFreeGB: 100})
+	require.NoError(t, err)
+
+	server, err := GetCoreServerByKey(ctx, "rtx-heartbeat")
+	require.NoError(t, err)
+	assert.Equal(t, CoreServerStatusOnline, server.Status)
+	require.NotNil(t, server.LastSeen)
+	require.NotNil(t, server.Heartbeat)
+	assert.Equal(t, 1, server.Heartbeat.GPUCount)
+	assert.Greater(t, mr.TTL(coreServerHeartbeatKeyPrefix+"rtx-heartbeat"), time.Duration(0))
+
+	mr.Del(coreServerHeartbeatKeyPrefix + "rtx-heartbeat")
+	server, err = GetCoreServerByKey(ctx, "rtx-heartbeat")
+	require.NoError(t, err)
+	assert.Equal(t, CoreServerStatusOffline, server.Status)
+	assert.NotNil(t, server.LastSeen)
+}
+
+func TestReportCoreServerHeartbeatUnknownServer(t *testing.T) {
+	initMiniredisForTest(t)
+
+	_, err := ReportCoreServerHeartbeat(context.Background(), "not-exists-core-server", CoreServerHeartbeat{})
+	assert.ErrorIs(t, err, ErrCoreServerNotFound)
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CoreServerInventoryService 核对核心服务器上已缓存的构件文件与数据库记录。
+type CoreServerInventoryService struct {
+	modelDAO        *dao.ModelDAO
+	datasetDAO      *dao.DatasetDAO
+	transferService *SSHArtifactTransferService
+}
+
+// RemoteArtifactRecordRef 与远程文件同名的记录摘要
+type RemoteArtifactRecordRef struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Claimed bool   `json:"claimed"` // storage_server 是否已包含该服务器
+}
+
+// RemoteArtifactInventoryEntry 远程目录中的文件及其匹配到的记录
+type RemoteArtifactInventoryEntry struct {
+	RemoteFileEntry
+	Known   bool                      `json:"known"`
+	Records []RemoteArtifactRecordRef `json:"records"`
+}
+
+// MissingRemoteArtifact storage_server 声明在该服务器但远程目录中不存在文件的记录
+type MissingRemoteArtifact struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	FileName     string `json:"file_name"`
+	ExpectedPath string `json:"expected_path"`
+}
+
+// CoreServerArtifactSummary 清单统计
+type CoreServerArtifactSummary struct {
+	Weights         int `json:"weights"`
+	Datasets        int `json:"datasets"`
+	UnknownWeights  int `json:"unknown_weights"`
+	UnknownDatasets int `json:"unknown_datasets"`
+	MissingModels   int `json:"missing_models"`
+	MissingDatasets int `json:"missing_datasets"`
+}
+
+// CoreServerArtifactInventory GET /v1/core-servers/:key/artifacts 的返回结构
+type CoreServerArtifactInventory struct {
+	ServerKey       string                         `json:"server_key"`
+	ServerIP        string                         `json:"server_ip"`
+	WeightsRoot     string                         `json:"weights_root"`
+	DatasetsRoot    string                         `json:"datasets_root"`
+	Weights         []RemoteArtifactInventoryEntry `json:"weights"`
+	Datasets        []RemoteArtifactInventoryEntry `json:"datasets"`
+	MissingModels   []MissingRemoteArtifact        `json:"missing_models"`
+	MissingDatasets []MissingRemoteArtifact        `json:"missing_datasets"`
+	Summary         CoreServerArtifactSummary      `json:"summary"`
+}
+
+func NewCoreServerInventoryService(transferService *SSHArtifactTransferService) *CoreServerInventoryService {
+	return &CoreServerInventoryService{
+		modelDAO:        dao.NewModelDAO(),
+		datasetDAO:      dao.NewDatasetDAO(),
+		transferService: transferService,
+	}
+}
+
+// ListArtifacts 通过 SFTP 列出核心服务器 weights/datasets 根目录，并与 models/datasets 记录按文件名匹配。
+// 服务器地址由 core-servers 注册表解析，credential 为本次调用的登录凭据，零值时使用注册表或默认凭据。
+func (s *CoreServerInventoryService) ListArtifacts(ctx context.Context, server CoreServer, credential SSHCredential) (CoreServerArtifactInventory, error) {
+	logger := serviceLogger().With("service", "CoreServerInventoryService", "method", "ListArtifacts")
+	start := time.Now()
+	if s.transferService == nil {
+		return CoreServerArtifactInventory{}, ErrSSHClientFactoryNil
+	}
+
+	listing, err := s.transferService.ListRemoteArtifactsInDefaultOtherRoots(server.Key, server.Port, credential)
+	if err != nil {
+		logger.Error("list artifacts failed: list remote roots failed", "core_server_key", server.Key, "error", err)
+		return CoreServerArtifactInventory{}, err
+	}
+
+	models, err := s.modelDAO.FindByWeightNamesOrStorageServer(ctx, remoteEntryNames(listing.Weights), server.Key)
+	if err != nil {
+		logger.Error("list artifacts failed: query models failed", "core_server_key", server.Key, "error", err)
+		return CoreServerArtifactInventory{}, err
+	}
+	datasets, err := s.datasetDAO.FindByFileNamesOrStorageServer(ctx, remoteEntryNames(listing.Datasets), server.Key)
+	if err != nil {
+		logger.Error("list artifacts failed: query datasets failed", "core_server_key", server.Key, "error", err)
+		return CoreServerArtifactInventory{}, err
+	}
+
+	inventory := buildCoreServerArtifactInventory(server.Key, listing, models, datasets)
+	logger.Info(
+		"list artifacts success",
+		"core_server_key", server.Key,
+		"weights", inventory.Summary.Weights,
+		"datasets", inventory.Summary.Datasets,
+		"unknown_weights", inventory.Summary.UnknownWeights,
+		"unknown_datasets", inventory.Summary.UnknownDatasets,
+		"missing_models", inventory.Summary.MissingModels,
+		"missing_datasets", inventory.Summary.MissingDatasets,
+		"cost_ms", time.Since(start).Milliseconds(),
+	)
+	return inventory, nil
+}
+
+// artifactRecord 模型/数据集在清单核对中的公共视图
+type artifactRecord struct {
+	id            uint
+	name          string
+	fileName      string
+	storageServer string
+}
+
+func modelArtifactRecords(models []entity2.Model) []artifactRecord {
+	records := make([]artifactRecord, 0, len(models))
+	for _, model := range models {
+		records = append(records, artifactRecord{
+			id:            model.ID,
+			name:          model.Name,
+			fileName:      model.WeightName,
+			storageServer: model.StorageServer,
+		})
+	}
+	return records
+}
+
+func datasetArtifactRecords(datasets []entity2.Dataset) []artifactRecord {
+	records := make([]artifactRecord, 0, len(datasets))
+	for _, dataset := range datasets {
+		records = append(records, artifactRecord{
+			id:            dataset.ID,
+			name:          dataset.Name,
+			fileName:      dataset.FileName,
+			storageServer: dataset.StorageServer,
+		})
+	}
+	return records
+}
+
+func buildCoreServerArtifactInventory(serverKey string, listing RemoteArtifactListing, models []entity2.Model, datasets []entity2.Dataset) CoreServerArtifactInventory {
+	modelRecords := modelArtifactRecords(models)
+	datasetRecords := datasetArtifactRecords(datasets)
+
+	weights, missingModels := matchRemoteArtifacts(serverKey, listing.WeightsRoot, listing.Weights, modelRecords)
+	datasetEntries, missingDatasets := matchRemoteArtifacts(serverKey, listing.DatasetsRoot, listing.Datasets, datasetRecords)
+
+	inventory := CoreServerArtifactInventory{
+		ServerKey:       serverKey,
+		ServerIP:        listing.ServerIP,
+		WeightsRoot:     listing.WeightsRoot,
+		DatasetsRoot:    listing.DatasetsRoot,
+		Weights:         weights,
+		Datasets:        datasetEntries,
+		MissingModels:   missingModels,
+		MissingDatasets: missingDatasets,
+	}
+	inventory.Summary = CoreServerArtifactSummary{
+		Weights:         len(weights),
+		Datasets:        len(datasetEntries),
+		UnknownWeights:  countUnknownEntries(weights),
+		UnknownDatasets: countUnknownEntries(datasetEntries),
+		MissingModels:   len(missingModels),
+		MissingDatasets: len(missingDatasets),
+	}
+	return inventory
+}
+
+func matchRemoteArtifacts(serverKey, root string, files []RemoteFileEntry, records []artifactRecord) ([]RemoteArtifactInventoryEntry, []MissingRemoteArtifact) {
+	byFileName := make(map[string][]artifactRecord, len(records))
+	for _, record := range records {
+		fileName := strings.TrimSpace(record.fileName)
+		if fileName == "" {
+			continue
+		}
+		byFileName[fileName] = append(byFileName[fileName], record)
+	}
+
+	present := make(map[string]struct{}, len(files))
+	entries := make([]RemoteArtifactInventoryEntry, 0, len(files))
+	for _, file := range files {
+		present[file.Name] = struct{}{}
+		matched := byFileName[file.Name]
+		refs := make([]RemoteArtifactRecordRef, 0, len(matched))
+		for _, record := range matched {
+			refs = append(refs, RemoteArtifactRecordRef{
+				ID:      record.id,
+				Name:    record.name,
+				Claimed: storageServerFieldContains(record.storageServer, serverKey),
+			})
+		}
+		entries = append(entries, RemoteArtifactInventoryEntry{
+			RemoteFileEntry: file,
+			Known:           len(refs) > 0,
+			Records:         refs,
+		})
+	}
+
+	missing := make([]MissingRemoteArtifact, 0)
+	for _, record := range records {
+		if !storageServerFieldContains(record.storageServer, serverKey) {
+			continue
+		}
+		fileName := strings.TrimSpace(record.fileName)
+		if _, ok := present[fileName]; ok && fileName != "" {
+			continue
+		}
+		expectedPath := ""
+		if fileName != "" {
+			expectedPath = filepath.ToSlash(filepath.Join(root, fileName))
+		}
+		missing = append(missing, MissingRemoteArtifact{
+			ID:           record.id,
+			Name:         record.name,
+			FileName:     fileName,
+			ExpectedPath: expectedPath,
+		})
+	}
+	return entries, missing
+}
+
+func remoteEntryNames(entries []RemoteFileEntry) []string {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+func countUnknownEntries(entries []RemoteArtifactInventoryEntry) int {
+	count := 0
+	for _, entry := range entries {
+		if !entry.Known {
+			count++
+		}
+	}
+	return count
+}
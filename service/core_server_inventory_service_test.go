@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+
+	entity2 "lucky_project/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSHArtifactTransferServiceListRemoteArtifactsInDefaultOtherRoots(t *testing.T) {
+	pathService := &ArtifactPathService{
+		BackendWeightsRoot:  "/tmp/backend/weights",
+		BackendDatasetsRoot: "/tmp/backend/datasets",
+		BaiduWeightsRoot:    "/tmp/baidu/weights",
+		BaiduDatasetsRoot:   "/tmp/baidu/datasets",
+		OtherWeightsRoot:    "/project/luckyProject/weights",
+		OtherDatasetsRoot:   "/project/luckyProject/datasets",
+	}
+	client := &fakeRemoteFileClient{
+		remoteFiles: map[string][]byte{
+			"/project/luckyProject/weights/b.pt":       []byte("bb"),
+			"/project/luckyProject/weights/a.pt":       []byte("a"),
+			"/project/luckyProject/datasets/train.zip": []byte("train"),
+			"/project/luckyProject/other/ignored.txt":  []byte("x"),
+		},
+	}
+	factory := &fakeRemoteFileClientFactory{client: client}
+	svc := &SSHArtifactTransferService{
+		PathService:       pathService,
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "gpu-1", IP: "10.0.0.9", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     factory,
+	}
+
+	listing, err := svc.ListRemoteArtifactsInDefaultOtherRoots("gpu-1", 0, SSHCredential{})
+	require.NoError(t, err)
+	assert.Equal(t, "gpu-1", listing.ServerName)
+	assert.Equal(t, "10.0.0.9", listing.ServerIP)
+	assert.Equal(t, "/project/luckyProject/weights", listing.WeightsRoot)
+	require.Len(t, listing.Weights, 2)
+	assert.Equal(t, "a.pt", listing.Weights[0].Name)
+	assert.Equal(t, "b.pt", listing.Weights[1].Name)
+	assert.EqualValues(t, 2, listing.Weights[1].Size)
+	require.Len(t, listing.Datasets, 1)
+	assert.Equal(t, "/project/luckyProject/datasets/train.zip", listing.Datasets[0].Path)
+	assert.Len(t, factory.serverCalls, 1)
+}
+
+func TestBuildCoreServerArtifactInventory(t *testing.T) {
+	listing := RemoteArtifactListing{
+		ServerName:   "gpu-1",
+		ServerIP:     "10.0.0.9",
+		WeightsRoot:  "/project/luckyProject/weights",
+		DatasetsRoot: "/project/luckyProject/datasets",
+		Weights: []RemoteFileEntry{
+			{Name: "known.pt", Path: "/project/luckyProject/weights/known.pt"},
+			{Name: "stray.pt", Path: "/project/luckyProject/weights/stray.pt"},
+		},
+		Datasets: []RemoteFileEntry{
+			{Name: "train.zip", Path: "/project/luckyProject/datasets/train.zip"},
+		},
+	}
+	models := []entity2.Model{
+		{ID: 1, Name: "yolo", WeightName: "known.pt", StorageServer: `["backend","gpu-1"]`},
+		{ID: 2, Name: "yolo-lost", WeightName: "lost.pt", StorageServer: `["gpu-1"]`},
+		{ID: 3, Name: "yolo-other", WeightName: "known.pt", StorageServer: `["backend"]`},
+	}
+	datasets := []entity2.Dataset{
+		{ID: 7, Name: "coco", FileName: "train.zip", StorageServer: `["backend"]`},
+		{ID: 8, Name: "voc", FileName: "voc.zip", StorageServer: "gpu-1"},
+	}
+
+	inventory := buildCoreServerArtifactInventory("gpu-1", listing, models, datasets)
+
+	assert.Equal(t, "gpu-1", inventory.ServerKey)
+	require.Len(t, inventory.Weights, 2)
+	assert.True(t, inventory.Weights[0].Known)
+	require.Len(t, inventory.Weights[0].Records, 2)
+	assert.Equal(t, RemoteArtifactRecordRef{ID: 1, Name: "yolo", Claimed: true}, inventory.Weights[0].Records[0])
+	assert.Equal(t, RemoteArtifactRecordRef{ID: 3, Name: "yolo-other", Claimed: false}, inventory.Weights[0].Records[1])
+	assert.False(t, inventory.Weights[1].Known)
+	assert.Empty(t, inventory.Weights[1].Records)
+
+	require.Len(t, inventory.Datasets, 1)
+	assert.True(t, inventory.Datasets[0].Known)
+
+	require.Len(t, inventory.MissingModels, 1)
+	assert.Equal(t, MissingRemoteArtifact{
+		ID:           2,
+		Name:         "yolo-lost",
+		FileName:     "lost.pt",
+		ExpectedPath: "/project/luckyProject/weights/lost.pt",
+	}, inventory.MissingModels[0])
+	require.Len(t, inventory.MissingDatasets, 1)
+	assert.Equal(t, uint(8), inventory.MissingDatasets[0].ID)
+
+	assert.Equal(t, CoreServerArtifactSummary{
+		Weights:         2,
+		Datasets:        1,
+		UnknownWeights:  1,
+		UnknownDatasets: 0,
+		MissingModels:   1,
+		MissingDatasets: 1,
+	}, inventory.Summary)
+}
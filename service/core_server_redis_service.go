@@ -8,6 +8,7 @@ import (
 	"lucky_project/config"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -22,11 +23,29 @@ type CoreServer struct {
 	Key  string `json:"key"`
 	IP   string `json:"ip"`
 	Port int    `json:"port"`
+	// SSHUser/CredentialID 为可选的登录信息，CredentialID 引用凭据库中的 ssh_key 或 password。
+	SSHUser      string               `json:"ssh_user,omitempty"`
+	CredentialID string               `json:"credential_id,omitempty"`
+	Status       string               `json:"status"`
+	LastSeen     *time.Time           `json:"last_seen"`
+	Heartbeat    *CoreServerHeartbeat `json:"heartbeat,omitempty"`
 }
 
 type coreServerValue struct {
-	IP   string `json:"ip"`
-	Port int    `json:"port"`
+	IP           string `json:"ip"`
+	Port         int    `json:"port"`
+	User         string `json:"user,omitempty"`
+	CredentialID string `json:"credential_id,omitempty"`
+}
+
+func (v coreServerValue) toCoreServer(key string) CoreServer {
+	return CoreServer{
+		Key:          key,
+		IP:           v.IP,
+		Port:         v.Port,
+		SSHUser:      strings.TrimSpace(v.User),
+		CredentialID: strings.TrimSpace(v.CredentialID),
+	}
 }
 
 func ListCoreServers(ctx context.Context) ([]CoreServer, error) {
@@ -60,16 +79,16 @@ func ListCoreServers(ctx context.Context) ([]CoreServer, error) {
 			return nil, fmt.Errorf("parse core server failed (key=%s): %w", key, err)
 		}
 
-		result = append(result, CoreServer{
-			Key:  key,
-			IP:   value.IP,
-			Port: value.Port,
-		})
+		result = append(result, value.toCoreServer(key))
 	}
 
+	if err := attachCoreServerStatus(ctx, result); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
+// GetCoreServerByKey 读取单个核心服务器注册信息，并附带心跳在线状态。
 func GetCoreServerByKey(ctx context.Context, key string) (CoreServer, error) {
 	if config.RedisClient == nil {
 		return CoreServer{}, ErrRedisNotInitialized
@@ -78,6 +97,20 @@ func GetCoreServerByKey(ctx context.Context, key string) (CoreServer, error) {
 		ctx = context.Background()
 	}
 
+	server, err := getRegisteredCoreServer(ctx, key)
+	if err != nil {
+		return CoreServer{}, err
+	}
+
+	servers := []CoreServer{server}
+	if err := attachCoreServerStatus(ctx, servers); err != nil {
+		return CoreServer{}, err
+	}
+	return servers[0], nil
+}
+
+// getRegisteredCoreServer 仅读取 core-servers 注册表中的 ip/port，不查询心跳。
+func getRegisteredCoreServer(ctx context.Context, key string) (CoreServer, error) {
 	trimmedKey := strings.TrimSpace(key)
 	if trimmedKey == "" {
 		return CoreServer{}, ErrCoreServerKeyRequired
@@ -101,9 +134,5 @@ func GetCoreServerByKey(ctx context.Context, key string) (CoreServer, error) {
 		return CoreServer{}, fmt.Errorf("parse core server failed (key=%s): %w", trimmedKey, err)
 	}
 
-	return CoreServer{
-		Key:  trimmedKey,
-		IP:   value.IP,
-		Port: value.Port,
-	}, nil
+	return value.toCoreServer(trimmedKey), nil
 }
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	"strings"
+)
+
+var (
+	ErrVaultNotInitialized = config.ErrVaultNotInitialized
+	ErrCredentialNotFound  = config.ErrCredentialNotFound
+	ErrCredentialInvalid   = config.ErrCredentialInvalid
+	ErrCredentialInUse     = errors.New("credential is still referenced")
+)
+
+// CredentialCreateRequest 新建凭据请求，secret 只写入凭据库，不会出现在任何响应中。
+type CredentialCreateRequest struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Secret      string `json:"secret"`
+}
+
+// CredentialRotateRequest 轮换凭据请求。
+type CredentialRotateRequest struct {
+	Secret string `json:"secret"`
+}
+
+// CredentialService 凭据库管理（创建/轮换/删除），返回值只包含元信息。
+type CredentialService struct {
+	vault func() *config.CredentialVault
+}
+
+func NewCredentialService() *CredentialService {
+	return &CredentialService{
+		vault: func() *config.CredentialVault { return config.Vault },
+	}
+}
+
+func (s *CredentialService) CreateCredential(ctx context.Context, req CredentialCreateRequest) (config.CredentialMeta, error) {
+	logger := serviceLogger().With("service", "CredentialService", "method", "CreateCredential")
+	vault, err := s.currentVault()
+	if err != nil {
+		return config.CredentialMeta{}, err
+	}
+
+	meta, err := vault.Create(req.Kind, req.Name, req.Description, []byte(req.Secret))
+	if err != nil {
+		logger.Warn("create credential failed", "kind", req.Kind, "name", req.Name, "error", err)
+		return config.CredentialMeta{}, err
+	}
+	logger.Info("create credential success", "credential_id", meta.ID, "kind", meta.Kind, "name", meta.Name)
+	return meta, nil
+}
+
+func (s *CredentialService) RotateCredential(ctx context.Context, id string, req CredentialRotateRequest) (config.CredentialMeta, error) {
+	logger := serviceLogger().With("service", "CredentialService", "method", "RotateCredential")
+	vault, err := s.currentVault()
+	if err != nil {
+		return config.CredentialMeta{}, err
+	}
+
+	meta, err := vault.Rotate(id, []byte(req.Secret))
+	if err != nil {
+		logger.Warn("rotate credential failed", "credential_id", id, "error", err)
+		return config.CredentialMeta{}, err
+	}
+	logger.Info("rotate credential success", "credential_id", meta.ID, "version", meta.Version, "fingerprint", meta.Fingerprint)
+	return meta, nil
+}
+
+func (s *CredentialService) GetCredential(ctx context.Context, id string) (config.CredentialMeta, error) {
+	vault, err := s.currentVault()
+	if err != nil {
+		return config.CredentialMeta{}, err
+	}
+	return vault.Get(id)
+}
+
+func (s *CredentialService) ListCredentials(ctx context.Context) ([]config.CredentialMeta, error) {
+	vault, err := s.currentVault()
+	if err != nil {
+		return nil, err
+	}
+	return vault.List(), nil
+}
+
+// DeleteCredential 删除凭据；仍被配置或核心服务器引用时拒绝删除。
+func (s *CredentialService) DeleteCredential(ctx context.Context, id string) error {
+	logger := serviceLogger().With("service", "CredentialService", "method", "DeleteCredential")
+	vault, err := s.currentVault()
+	if err != nil {
+		return err
+	}
+	id = strings.TrimSpace(id)
+	if _, err := vault.Get(id); err != nil {
+		return err
+	}
+
+	refs, err := credentialReferences(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(refs) > 0 {
+		logger.Warn("delete credential rejected: still referenced", "credential_id", id, "references", refs)
+		return fmt.Errorf("%w: %s used by %s", ErrCredentialInUse, id, strings.Join(refs, ", "))
+	}
+
+	if err := vault.Delete(id); err != nil {
+		return err
+	}
+	logger.Info("delete credential success", "credential_id", id)
+	return nil
+}
+
+func (s *CredentialService) currentVault() (*config.CredentialVault, error) {
+	if s.vault == nil {
+		return nil, ErrVaultNotInitialized
+	}
+	vault := s.vault()
+	if vault == nil {
+		return nil, ErrVaultNotInitialized
+	}
+	return vault, nil
+}
+
+// credentialReferences 列出引用该凭据的配置项与核心服务器。
+func credentialReferences(ctx context.Context, id string) ([]string, error) {
+	refs := make([]string, 0)
+	if cfg := config.AppConfig; cfg != nil {
+		if strings.TrimSpace(cfg.DB.PasswordCredentialID) == id {
+			refs = append(refs, "config:db.password_credential_id")
+		}
+		if strings.TrimSpace(cfg.Redis.PasswordCredentialID) == id {
+			refs = append(refs, "config:redis.password_credential_id")
+		}
+		if strings.TrimSpace(cfg.BaiduPan.AccessTokenCredentialID) == id {
+			refs = append(refs, "config:baidu_pan.access_token_credential_id")
+		}
+		if strings.TrimSpace(cfg.BaiduPan.RefreshTokenCredentialID) == id {
+			refs = append(refs, "config:baidu_pan.refresh_token_credential_id")
+		}
+		if strings.TrimSpace(cfg.BaiduPan.AppSecretCredentialID) == id {
+			refs = append(refs, "config:baidu_pan.app_secret_credential_id")
+		}
+	}
+	// token 刷新时自动创建的凭据只记录在 token 状态文件中
+	for _, tokenID := range DefaultBaiduTokenManager().CredentialIDs() {
+		if tokenID == id {
+			refs = append(refs, "baidu-token-state")
+			break
+		}
+	}
+
+	if config.RedisClient != nil {
+		servers, err := ListCoreServers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, server := range servers {
+			if server.CredentialID == id {
+				refs = append(refs, "core-server:"+server.Key)
+			}
+		}
+	}
+	return refs, nil
+}
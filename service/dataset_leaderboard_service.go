@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultLeaderboardMetric = "map50_95"
+	DefaultLeaderboardLimit  = 20
+	MaxLeaderboardLimit      = 200
+
+	LeaderboardOrderDesc = "desc"
+	LeaderboardOrderAsc  = "asc"
+)
+
+var ErrInvalidLeaderboardQuery = errors.New("invalid leaderboard query")
+
+// LeaderboardQuery GET /v1/datasets/:id/leaderboard 查询参数
+type LeaderboardQuery struct {
+	Metric string `form:"metric"`
+	Order  string `form:"order"`
+	Limit  int    `form:"limit"`
+}
+
+// LeaderboardEntry 排行榜中的一条训练结果；并列时 Rank 相同（1, 2, 2, 4）。
+type LeaderboardEntry struct {
+	Rank             int        `json:"rank"`
+	TrainingResultID uint       `json:"training_result_id"`
+	ModelID          uint       `json:"model_id"`
+	ModelName        string     `json:"model_name"`
+	ModelVersion     float64    `json:"model_version"`
+	DatasetVersion   float64    `json:"dataset_version"`
+	MetricKey        string     `json:"metric_key"`
+	Value            float64    `json:"value"`
+	WeightPath       string     `json:"weight_path"`
+	TrainEndTime     *time.Time `json:"train_end_time"`
+}
+
+// LeaderboardMissing metric_detail 中缺少该指标（或不是数值）的成功训练
+type LeaderboardMissing struct {
+	TrainingResultID uint    `json:"training_result_id"`
+	ModelID          uint    `json:"model_id"`
+	ModelName        string  `json:"model_name"`
+	ModelVersion     float64 `json:"model_version"`
+}
+
+// DatasetLeaderboard 数据集排行榜
+type DatasetLeaderboard struct {
+	DatasetID      uint                 `json:"dataset_id"`
+	DatasetName    string               `json:"dataset_name"`
+	DatasetVersion string               `json:"dataset_version"`
+	Metric         string               `json:"metric"`
+	Order          string               `json:"order"`
+	Total          int                  `json:"total"`
+	Entries        []LeaderboardEntry   `json:"entries"`
+	Missing        []LeaderboardMissing `json:"missing"`
+}
+
+// DatasetLeaderboardService 按 metric_detail 中的指标对数据集上的成功训练排名
+type DatasetLeaderboardService struct {
+	datasetDAO  *dao.DatasetDAO
+	modelDAO    *dao.ModelDAO
+	trainingDAO *dao.TrainingResultDAO
+}
+
+func NewDatasetLeaderboardService() *DatasetLeaderboardService {
+	return &DatasetLeaderboardService{
+		datasetDAO:  dao.NewDatasetDAO(),
+		modelDAO:    dao.NewModelDAO(),
+		trainingDAO: dao.NewTrainingResultDAO(),
+	}
+}
+
+// GetLeaderboard 只统计 training_status=2 的训练结果；缺少指标的记录不参与排名，单独列在 missing 中。
+func (s *DatasetLeaderboardService) GetLeaderboard(ctx context.Context, datasetID uint, query LeaderboardQuery) (DatasetLeaderboard, error) {
+	query, err := normalizeLeaderboardQuery(query)
+	if err != nil {
+		return DatasetLeaderboard{}, err
+	}
+
+	dataset, err := s.datasetDAO.FindByID(ctx, datasetID)
+	if err != nil {
+		return DatasetLeaderboard{}, err
+	}
+	results, err := s.trainingDAO.FindByDatasetAndStatus(ctx, datasetID, entity2.TrainingStatusSuccess)
+	if err != nil {
+		return DatasetLeaderboard{}, err
+	}
+
+	modelIDs := make([]uint, 0, len(results))
+	for _, result := range results {
+		modelIDs = append(modelIDs, result.ModelID)
+	}
+	models, err := s.modelDAO.FindByIDs(ctx, modelIDs)
+	if err != nil {
+		return DatasetLeaderboard{}, err
+	}
+	modelsByID := make(map[uint]entity2.Model, len(models))
+	for _, model := range models {
+		modelsByID[model.ID] = model
+	}
+
+	entries, missing := rankLeaderboard(results, modelsByID, query.Metric, query.Order == LeaderboardOrderDesc)
+	total := len(entries)
+	if len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+	return DatasetLeaderboard{
+		DatasetID:      dataset.ID,
+		DatasetName:    dataset.Name,
+		DatasetVersion: dataset.Version,
+		Metric:         query.Metric,
+		Order:          query.Order,
+		Total:          total,
+		Entries:        entries,
+		Missing:        missing,
+	}, nil
+}
+
+func normalizeLeaderboardQuery(query LeaderboardQuery) (LeaderboardQuery, error) {
+	query.Metric = strings.TrimSpace(query.Metric)
+	if query.Metric == "" {
+		query.Metric = DefaultLeaderboardMetric
+	}
+	if _, err := dao.ParseMetricPath(query.Metric); err != nil {
+		return LeaderboardQuery{}, fmt.Errorf("%w: %v", ErrInvalidLeaderboardQuery, err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(query.Order)) {
+	case "", LeaderboardOrderDesc:
+		query.Order = LeaderboardOrderDesc
+	case LeaderboardOrderAsc:
+		query.Order = LeaderboardOrderAsc
+	default:
+		return LeaderboardQuery{}, fmt.Errorf("%w: order must be asc or desc", ErrInvalidLeaderboardQuery)
+	}
+
+	switch {
+	case query.Limit == 0:
+		query.Limit = DefaultLeaderboardLimit
+	case query.Limit < 0 || query.Limit > MaxLeaderboardLimit:
+		return LeaderboardQuery{}, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidLeaderboardQuery, MaxLeaderboardLimit)
+	}
+	return query, nil
+}
+
+// rankLeaderboard 按指标值排序并计算名次；值相同按 train_end_time 先后、再按 ID 排列，名次并列。
+func rankLeaderboard(results []entity2.ModelTrainingResult, models map[uint]entity2.Model, metric string, desc bool) ([]LeaderboardEntry, []LeaderboardMissing) {
+	path, _ := dao.ParseMetricPath(metric)
+	entries := make([]LeaderboardEntry, 0, len(results))
+	missing := make([]LeaderboardMissing, 0)
+	for _, result := range results {
+		model := models[result.ModelID]
+		key, value, ok := lookupMetricValue(result.MetricDetail, path)
+		if !ok {
+			missing = append(missing, LeaderboardMissing{
+				TrainingResultID: result.ID,
+				ModelID:          result.ModelID,
+				ModelName:        model.Name,
+				ModelVersion:     model.Version,
+			})
+			continue
+		}
+		entries = append(entries, LeaderboardEntry{
+			TrainingResultID: result.ID,
+			ModelID:          result.ModelID,
+			ModelName:        model.Name,
+			ModelVersion:     model.Version,
+			DatasetVersion:   result.DatasetVersion,
+			MetricKey:        key,
+			Value:            value,
+			WeightPath:       result.WeightPath,
+			TrainEndTime:     result.TrainEndTime,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Value != b.Value {
+			if desc {
+				return a.Value > b.Value
+			}
+			return a.Value < b.Value
+		}
+		if !timePtrEqual(a.TrainEndTime, b.TrainEndTime) {
+			return timePtrBefore(a.TrainEndTime, b.TrainEndTime)
+		}
+		return a.TrainingResultID < b.TrainingResultID
+	})
+	for i := range entries {
+		if i > 0 && entries[i].Value == entries[i-1].Value {
+			entries[i].Rank = entries[i-1].Rank
+			continue
+		}
+		entries[i].Rank = i + 1
+	}
+	return entries, missing
+}
+
+// lookupMetricValue 在 metric_detail 中逐级查找指标，返回实际命中的键路径。
+// 每一级先精确匹配，再忽略大小写及 -、_ 匹配（map50_95 可命中 mAP50-95）；数值或数值字符串均可。
+func lookupMetricValue(detail json.RawMessage, path []string) (string, float64, bool) {
+	if len(detail) == 0 || len(path) == 0 {
+		return "", 0, false
+	}
+	var current interface{}
+	if err := json.Unmarshal(detail, &current); err != nil {
+		return "", 0, false
+	}
+
+	keys := make([]string, 0, len(path))
+	for _, segment := range path {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return "", 0, false
+		}
+		key, ok := matchMetricKey(object, segment)
+		if !ok {
+			return "", 0, false
+		}
+		keys = append(keys, key)
+		current = object[key]
+	}
+
+	var value float64
+	switch v := current.(type) {
+	case float64:
+		value = v
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return "", 0, false
+		}
+		value = parsed
+	default:
+		return "", 0, false
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "", 0, false
+	}
+	return strings.Join(keys, "."), value, true
+}
+
+func matchMetricKey(object map[string]interface{}, segment string) (string, bool) {
+	if _, ok := object[segment]; ok {
+		return segment, true
+	}
+	normalized := normalizeMetricKey(segment)
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if normalizeMetricKey(key) == normalized {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func normalizeMetricKey(key string) string {
+	return dao.NormalizeMetricKey(key)
+}
+
+func timePtrEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// timePtrBefore nil 视为最晚
+func timePtrBefore(a, b *time.Time) bool {
+	if a == nil {
+		return false
+	}
+	if b == nil {
+		return true
+	}
+	return a.Before(*b)
+}
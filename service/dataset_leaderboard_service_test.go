@@ -0,0 +1,90 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	entity2 "lucky_project/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupMetricValue(t *testing.T) {
+	detail := json.RawMessage(`{"mAP50-95":0.71,"precision":"0.9","val":{"box_loss":1.2},"note":"manual","nan":null}`)
+
+	key, value, ok := lookupMetricValue(detail, []string{"map50_95"})
+	require.True(t, ok)
+	assert.Equal(t, "mAP50-95", key)
+	assert.Equal(t, 0.71, value)
+
+	_, value, ok = lookupMetricValue(detail, []string{"precision"})
+	require.True(t, ok)
+	assert.Equal(t, 0.9, value)
+
+	key, value, ok = lookupMetricValue(detail, []string{"val", "box-loss"})
+	require.True(t, ok)
+	assert.Equal(t, "val.box_loss", key)
+	assert.Equal(t, 1.2, value)
+
+	for _, path := range [][]string{{"note"}, {"nan"}, {"recall"}, {"val"}, {"note", "x"}} {
+		_, _, ok := lookupMetricValue(detail, path)
+		assert.False(t, ok, "path=%v", path)
+	}
+	_, _, ok = lookupMetricValue(json.RawMessage(`[1,2]`), []string{"map50"})
+	assert.False(t, ok)
+}
+
+func TestRankLeaderboardHandlesTiesAndMissing(t *testing.T) {
+	early := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+	results := []entity2.ModelTrainingResult{
+		{ID: 1, ModelID: 10, MetricDetail: json.RawMessage(`{"mAP50-95":0.5}`), TrainEndTime: &late},
+		{ID: 2, ModelID: 11, MetricDetail: json.RawMessage(`{"mAP50-95":0.7}`), TrainEndTime: &late},
+		{ID: 3, ModelID: 10, MetricDetail: json.RawMessage(`{"mAP50-95":0.7}`), TrainEndTime: &early},
+		{ID: 4, ModelID: 12, MetricDetail: json.RawMessage(`{"mAP50":0.9}`)},
+		{ID: 5, ModelID: 11},
+		{ID: 6, ModelID: 12, MetricDetail: json.RawMessage(`{"map50_95":0.6}`)},
+	}
+	models := map[uint]entity2.Model{
+		10: {ID: 10, Name: "yolov8n", Version: 1},
+		11: {ID: 11, Name: "yolov8s", Version: 2},
+	}
+
+	entries, missing := rankLeaderboard(results, models, "map50_95", true)
+	ids := make([]uint, 0, len(entries))
+	ranks := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.TrainingResultID)
+		ranks = append(ranks, entry.Rank)
+	}
+	assert.Equal(t, []uint{3, 2, 6, 1}, ids)
+	assert.Equal(t, []int{1, 1, 3, 4}, ranks)
+	assert.Equal(t, "yolov8n", entries[0].ModelName)
+	assert.Equal(t, "map50_95", entries[2].MetricKey)
+	require.Len(t, missing, 2)
+	assert.Equal(t, uint(4), missing[0].TrainingResultID)
+	assert.Equal(t, "", missing[0].ModelName)
+
+	entries, _ = rankLeaderboard(results, models, "map50_95", false)
+	assert.Equal(t, uint(1), entries[0].TrainingResultID)
+}
+
+func TestNormalizeLeaderboardQuery(t *testing.T) {
+	query, err := normalizeLeaderboardQuery(LeaderboardQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, LeaderboardQuery{Metric: DefaultLeaderboardMetric, Order: LeaderboardOrderDesc, Limit: DefaultLeaderboardLimit}, query)
+
+	for _, query := range []LeaderboardQuery{
+		{Metric: "map50'; DROP TABLE"},
+		{Metric: "a..b"},
+		{Order: "up"},
+		{Limit: -1},
+		{Limit: MaxLeaderboardLimit + 1},
+	} {
+		_, err := normalizeLeaderboardQuery(query)
+		assert.True(t, errors.Is(err, ErrInvalidLeaderboardQuery), "query=%+v", query)
+	}
+}
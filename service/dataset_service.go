@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"lucky_project/dao"
 	entity2 "lucky_project/entity"
 	"os"
@@ -149,34 +148,38 @@ func (s *DatasetService) DeleteByFileName(ctx context.Context, fileName string)
 		DeletedRecords: deletedRows,
 	}
 
-	if s.pathService == nil {
-		return result, nil
-	}
+	deleted, err := removeBackendArtifactFile(s.pathService, ArtifactCategoryDatasets, name)
+	result.LocalFileDeleted = deleted
+	return result, err
+}
 
-	localPath, err := s.pathService.BuildPath(ArtifactCategoryDatasets, StorageTargetBackend, name)
+// DeleteByID 删除数据集记录及其标签；训练结果按 opts.Cascade 处理。
+// 数据集文件仅在没有其他数据集引用且未指定 KeepFile 时从后端删除，网盘等远端副本不受影响。
+func (s *DatasetService) DeleteByID(ctx context.Context, id uint, opts ArtifactDeleteOptions) (ArtifactDeleteResult, error) {
+	dataset, err := s.datasetDAO.FindByID(ctx, id)
 	if err != nil {
-		return result, err
-	}
-	if localPath == "" {
-		return result, nil
+		return ArtifactDeleteResult{}, err
 	}
 
-	info, statErr := os.Stat(localPath)
-	if statErr != nil {
-		if os.IsNotExist(statErr) {
-			return result, nil
-		}
-		return result, fmt.Errorf("stat local dataset file failed: %w", statErr)
+	deletedResults, err := s.datasetDAO.DeleteByIDWithDependents(ctx, id, opts.Cascade)
+	if err != nil {
+		return ArtifactDeleteResult{}, err
 	}
-	if info.IsDir() {
+	result := ArtifactDeleteResult{
+		ID:                     id,
+		FileName:               dataset.FileName,
+		DeletedTrainingResults: deletedResults,
+	}
+	if opts.KeepFile || dataset.FileName == "" {
 		return result, nil
 	}
 
-	if removeErr := os.Remove(localPath); removeErr != nil {
-		return result, fmt.Errorf("remove local dataset file failed: %w", removeErr)
+	result.FileSharedBy, err = s.datasetDAO.CountByFileName(ctx, dataset.FileName)
+	if err != nil || result.FileSharedBy > 0 {
+		return result, err
 	}
-	result.LocalFileDeleted = true
-	return result, nil
+	result.LocalFileDeleted, err = removeBackendArtifactFile(s.pathService, ArtifactCategoryDatasets, dataset.FileName)
+	return result, err
 }
 
 func (s *DatasetService) resolveLocalDatasetSizeMB(fileName string) (float64, bool) {
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	entity2 "lucky_project/entity"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ModelLineageFormatJSON    = "json"
+	ModelLineageFormatMermaid = "mermaid"
+	ModelLineageFormatDOT     = "dot"
+
+	// maxModelLineageDepth 祖先链与子孙树各自的最大层数，防止异常数据导致无限遍历。
+	maxModelLineageDepth = 64
+)
+
+var ErrInvalidLineageFormat = errors.New("invalid lineage format, expected json|mermaid|dot")
+
+// modelLineageSource 血缘查询所需的数据访问，*dao.ModelDAO 满足该接口。
+type modelLineageSource interface {
+	FindByID(ctx context.Context, id uint) (*entity2.Model, error)
+	FindByBaseModelIDs(ctx context.Context, baseIDs []uint) ([]entity2.Model, error)
+}
+
+// ModelLineageNode 血缘中的单个模型
+type ModelLineageNode struct {
+	ID          uint                `json:"id"`
+	Name        string              `json:"name"`
+	Version     float64             `json:"version"`
+	TaskType    string              `json:"task_type"`
+	WeightName  string              `json:"weight_name"`
+	BaseModelID uint                `json:"base_model_id"`
+	Children    []*ModelLineageNode `json:"children,omitempty"`
+}
+
+// ModelLineageEdge 派生关系：ParentID 为基础模型，ChildID 为由其派生的模型。
+type ModelLineageEdge struct {
+	ParentID uint `json:"parent_id"`
+	ChildID  uint `json:"child_id"`
+}
+
+// ModelLineage GET /v1/models/:id/lineage 返回结构
+type ModelLineage struct {
+	Model ModelLineageNode `json:"model"`
+	// Ancestors 由近到远：第一个是直接基础模型，最后一个是根模型。
+	Ancestors []ModelLineageNode `json:"ancestors"`
+	// Descendants 直接派生模型，children 递归展开。
+	Descendants []*ModelLineageNode `json:"descendants"`
+	// MissingBaseModelID 祖先链引用了不存在的模型时，记录该 id。
+	MissingBaseModelID uint `json:"missing_base_model_id,omitempty"`
+	// Cycles 导致环的派生关系，遍历在这些边处停止。
+	Cycles    []ModelLineageEdge `json:"cycles"`
+	Truncated bool               `json:"truncated"`
+	Mermaid   string             `json:"mermaid"`
+	DOT       string             `json:"dot"`
+}
+
+// NormalizeLineageFormat 空值视为 json。
+func NormalizeLineageFormat(format string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(format))
+	switch value {
+	case "", ModelLineageFormatJSON:
+		return ModelLineageFormatJSON, nil
+	case ModelLineageFormatMermaid, ModelLineageFormatDOT:
+		return value, nil
+	default:
+		return "", ErrInvalidLineageFormat
+	}
+}
+
+// GetLineage 返回模型的完整祖先链与子孙树，并附带 Mermaid/DOT 渲染文本。
+func (s *ModelService) GetLineage(ctx context.Context, id uint) (ModelLineage, error) {
+	return buildModelLineage(ctx, s.modelDAO, id)
+}
+
+func buildModelLineage(ctx context.Context, source modelLineageSource, id uint) (ModelLineage, error) {
+	model, err := source.FindByID(ctx, id)
+	if err != nil {
+		return ModelLineage{}, err
+	}
+
+	lineage := ModelLineage{
+		Model:       newModelLineageNode(*model),
+		Ancestors:   make([]ModelLineageNode, 0),
+		Descendants: make([]*ModelLineageNode, 0),
+		Cycles:      make([]ModelLineageEdge, 0),
+	}
+	visited := map[uint]bool{model.ID: true}
+	cycleSeen := make(map[ModelLineageEdge]bool)
+	addCycle := func(edge ModelLineageEdge) {
+		if !cycleSeen[edge] {
+			cycleSeen[edge] = true
+			lineage.Cycles = append(lineage.Cycles, edge)
+		}
+	}
+
+	// 祖先链：逐级沿 base_model_id 向上。
+	current := *model
+	for current.BaseModelID != 0 {
+		if len(lineage.Ancestors) >= maxModelLineageDepth {
+			lineage.Truncated = true
+			break
+		}
+		if visited[current.BaseModelID] {
+			addCycle(ModelLineageEdge{ParentID: current.BaseModelID, ChildID: current.ID})
+			break
+		}
+		parent, err := source.FindByID(ctx, current.BaseModelID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				lineage.MissingBaseModelID = current.BaseModelID
+				break
+			}
+			return ModelLineage{}, err
+		}
+		visited[parent.ID] = true
+		lineage.Ancestors = append(lineage.Ancestors, newModelLineageNode(*parent))
+		current = *parent
+	}
+
+	// 子孙树：按层批量查询直接派生模型。
+	nodes := map[uint]*ModelLineageNode{model.ID: &lineage.Model}
+	level := []uint{model.ID}
+	for depth := 0; len(level) > 0; depth++ {
+		if depth >= maxModelLineageDepth {
+			lineage.Truncated = true
+			break
+		}
+		children, err := source.FindByBaseModelIDs(ctx, level)
+		if err != nil {
+			return ModelLineage{}, err
+		}
+		next := make([]uint, 0, len(children))
+		for _, child := range children {
+			if visited[child.ID] {
+				addCycle(ModelLineageEdge{ParentID: child.BaseModelID, ChildID: child.ID})
+				continue
+			}
+			visited[child.ID] = true
+			node := newModelLineageNode(child)
+			parent := nodes[child.BaseModelID]
+			parent.Children = append(parent.Children, &node)
+			nodes[child.ID] = &node
+			next = append(next, child.ID)
+		}
+		level = next
+	}
+	lineage.Descendants = lineage.Model.Children
+	if lineage.Descendants == nil {
+		lineage.Descendants = make([]*ModelLineageNode, 0)
+	}
+	lineage.Model.Children = nil
+
+	lineage.Mermaid = renderModelLineageMermaid(lineage)
+	lineage.DOT = renderModelLineageDOT(lineage)
+	return lineage, nil
+}
+
+func newModelLineageNode(model entity2.Model) ModelLineageNode {
+	return ModelLineageNode{
+		ID:          model.ID,
+		Name:        model.Name,
+		Version:     model.Version,
+		TaskType:    model.TaskType,
+		WeightName:  model.WeightName,
+		BaseModelID: model.BaseModelID,
+	}
+}
+
+// modelLineageGraph 按根 → 当前模型 → 子孙的顺序展开节点与边，供两种渲染共用。
+func modelLineageGraph(lineage ModelLineage) ([]ModelLineageNode, []ModelLineageEdge) {
+	nodes := make([]ModelLineageNode, 0, len(lineage.Ancestors)+1)
+	edges := make([]ModelLineageEdge, 0, len(lineage.Ancestors))
+	for i := len(lineage.Ancestors) - 1; i >= 0; i-- {
+		nodes = append(nodes, lineage.Ancestors[i])
+	}
+	nodes = append(nodes, lineage.Model)
+	for i := 0; i+1 < len(nodes); i++ {
+		edges = append(edges, ModelLineageEdge{ParentID: nodes[i].ID, ChildID: nodes[i+1].ID})
+	}
+
+	var walk func(parentID uint, children []*ModelLineageNode)
+	walk = func(parentID uint, children []*ModelLineageNode) {
+		for _, child := range children {
+			node := *child
+			node.Children = nil
+			nodes = append(nodes, node)
+			edges = append(edges, ModelLineageEdge{ParentID: parentID, ChildID: child.ID})
+			walk(child.ID, child.Children)
+		}
+	}
+	walk(lineage.Model.ID, lineage.Descendants)
+	return nodes, edges
+}
+
+func modelLineageLabel(node ModelLineageNode) string {
+	return fmt.Sprintf("%s v%.2f #%d", node.Name, node.Version, node.ID)
+}
+
+func renderModelLineageMermaid(lineage ModelLineage) string {
+	nodes, edges := modelLineageGraph(lineage)
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+	for _, node := range nodes {
+		label := strings.ReplaceAll(modelLineageLabel(node), `"`, "#quot;")
+		fmt.Fprintf(&b, "    m%d[\"%s\"]\n", node.ID, label)
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "    m%d --> m%d\n", edge.ParentID, edge.ChildID)
+	}
+	for _, edge := range lineage.Cycles {
+		fmt.Fprintf(&b, "    m%d -.->|cycle| m%d\n", edge.ParentID, edge.ChildID)
+	}
+	fmt.Fprintf(&b, "    style m%d stroke-width:3px\n", lineage.Model.ID)
+	return b.String()
+}
+
+func renderModelLineageDOT(lineage ModelLineage) string {
+	nodes, edges := modelLineageGraph(lineage)
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	var b strings.Builder
+	b.WriteString("digraph lineage {\n    rankdir=TB;\n    node [shape=box];\n")
+	for _, node := range nodes {
+		attrs := ""
+		if node.ID == lineage.Model.ID {
+			attrs = ", style=bold"
+		}
+		fmt.Fprintf(&b, "    m%d [label=\"%s\"%s];\n", node.ID, escaper.Replace(modelLineageLabel(node)), attrs)
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "    m%d -> m%d;\n", edge.ParentID, edge.ChildID)
+	}
+	for _, edge := range lineage.Cycles {
+		fmt.Fprintf(&b, "    m%d -> m%d [style=dashed, color=red, label=\"cycle\"];\n", edge.ParentID, edge.ChildID)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
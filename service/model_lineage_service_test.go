@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	entity2 "lucky_project/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeModelLineageSource struct {
+	models map[uint]entity2.Model
+}
+
+func newFakeModelLineageSource(models ...entity2.Model) *fakeModelLineageSource {
+	source := &fakeModelLineageSource{models: make(map[uint]entity2.Model)}
+	for _, model := range models {
+		source.models[model.ID] = model
+	}
+	return source
+}
+
+func (f *fakeModelLineageSource) FindByID(ctx context.Context, id uint) (*entity2.Model, error) {
+	model, ok := f.models[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &model, nil
+}
+
+func (f *fakeModelLineageSource) FindByBaseModelIDs(ctx context.Context, baseIDs []uint) ([]entity2.Model, error) {
+	records := make([]entity2.Model, 0)
+	for id := uint(1); id <= 100; id++ {
+		model, ok := f.models[id]
+		if !ok {
+			continue
+		}
+		for _, baseID := range baseIDs {
+			if model.BaseModelID == baseID {
+				records = append(records, model)
+				break
+			}
+		}
+	}
+	return records, nil
+}
+
+func lineageModel(id, baseID uint, name string) entity2.Model {
+	return entity2.Model{ID: id, BaseModelID: baseID, Name: name, Version: 1, TaskType: "detect"}
+}
+
+func TestBuildModelLineageAncestorsAndDescendants(t *testing.T) {
+	source := newFakeModelLineageSource(
+		lineageModel(1, 0, "yolov8n"),
+		lineageModel(2, 1, "yolov8n-coco"),
+		lineageModel(3, 2, "helmet"),
+		lineageModel(4, 3, "helmet-night"),
+		lineageModel(5, 3, "helmet-rain"),
+		lineageModel(6, 4, "helmet-night-v2"),
+		lineageModel(7, 1, "unrelated-branch"),
+	)
+
+	lineage, err := buildModelLineage(context.Background(), source, 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), lineage.Model.ID)
+	require.Len(t, lineage.Ancestors, 2)
+	assert.Equal(t, uint(2), lineage.Ancestors[0].ID)
+	assert.Equal(t, uint(1), lineage.Ancestors[1].ID)
+
+	require.Len(t, lineage.Descendants, 2)
+	assert.Equal(t, uint(4), lineage.Descendants[0].ID)
+	assert.Equal(t, uint(5), lineage.Descendants[1].ID)
+	require.Len(t, lineage.Descendants[0].Children, 1)
+	assert.Equal(t, uint(6), lineage.Descendants[0].Children[0].ID)
+	assert.Empty(t, lineage.Cycles)
+	assert.False(t, lineage.Truncated)
+
+	assert.Contains(t, lineage.Mermaid, "graph TD\n")
+	assert.Contains(t, lineage.Mermaid, `m3["helmet v1.00 #3"]`)
+	assert.Contains(t, lineage.Mermaid, "m1 --> m2")
+	assert.Contains(t, lineage.Mermaid, "m4 --> m6")
+	assert.Contains(t, lineage.Mermaid, "style m3 stroke-width:3px")
+	assert.NotContains(t, lineage.Mermaid, "m7")
+
+	assert.Contains(t, lineage.DOT, "digraph lineage {")
+	assert.Contains(t, lineage.DOT, `m3 [label="helmet v1.00 #3", style=bold];`)
+	assert.Contains(t, lineage.DOT, "m2 -> m3;")
+}
+
+func TestBuildModelLineageDetectsCycles(t *testing.T) {
+	source := newFakeModelLineageSource(
+		lineageModel(1, 3, "a"),
+		lineageModel(2, 1, "b"),
+		lineageModel(3, 2, "c"),
+	)
+
+	lineage, err := buildModelLineage(context.Background(), source, 1)
+	require.NoError(t, err)
+	require.Len(t, lineage.Ancestors, 2)
+	assert.Equal(t, uint(3), lineage.Ancestors[0].ID)
+	assert.Equal(t, uint(2), lineage.Ancestors[1].ID)
+	assert.Equal(t, []ModelLineageEdge{{ParentID: 1, ChildID: 2}}, lineage.Cycles)
+	assert.Empty(t, lineage.Descendants)
+	assert.Contains(t, lineage.Mermaid, "m1 -.->|cycle| m2")
+	assert.Contains(t, lineage.DOT, `m1 -> m2 [style=dashed, color=red, label="cycle"];`)
+
+	self := newFakeModelLineageSource(lineageModel(9, 9, "self"))
+	lineage, err = buildModelLineage(context.Background(), self, 9)
+	require.NoError(t, err)
+	assert.Empty(t, lineage.Ancestors)
+	assert.Empty(t, lineage.Descendants)
+	assert.Equal(t, []ModelLineageEdge{{ParentID: 9, ChildID: 9}}, lineage.Cycles)
+}
+
+func TestBuildModelLineageMissingBase(t *testing.T) {
+	source := newFakeModelLineageSource(lineageModel(2, 1, `quote"d`))
+
+	lineage, err := buildModelLineage(context.Background(), source, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), lineage.MissingBaseModelID)
+	assert.Empty(t, lineage.Ancestors)
+	assert.Contains(t, lineage.Mermaid, "quote#quot;d")
+	assert.Contains(t, lineage.DOT, `quote\"d`)
+
+	_, err = buildModelLineage(context.Background(), source, 42)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestNormalizeLineageFormat(t *testing.T) {
+	format, err := NormalizeLineageFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, ModelLineageFormatJSON, format)
+
+	format, err = NormalizeLineageFormat(" DOT ")
+	require.NoError(t, err)
+	assert.Equal(t, ModelLineageFormatDOT, format)
+
+	_, err = NormalizeLineageFormat("svg")
+	assert.ErrorIs(t, err, ErrInvalidLineageFormat)
+}
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"lucky_project/dao"
 	entity2 "lucky_project/entity"
 	"math"
@@ -32,6 +31,14 @@ func (s *ModelService) CreateModel(ctx context.Context, model *entity2.Model) er
 	if model == nil {
 		return dao.ErrNilEntity
 	}
+	if err := s.prepareModel(model); err != nil {
+		return err
+	}
+	return s.modelDAO.Save(ctx, model)
+}
+
+// prepareModel 规范 storage_server、推导 weight_name，并在未传大小时读取后端本地文件大小。
+func (s *ModelService) prepareModel(model *entity2.Model) error {
 	model.StorageServer = normalizeStorageServerField(model.StorageServer)
 	model.WeightName = deriveModelWeightName(model)
 	if model.WeightName == "" {
@@ -42,7 +49,7 @@ func (s *ModelService) CreateModel(ctx context.Context, model *entity2.Model) er
 			model.WeightSizeMB = sizeMB
 		}
 	}
-	return s.modelDAO.Save(ctx, model)
+	return nil
 }
 
 func (s *ModelService) GetAllModels(ctx context.Context, params entity2.QueryParams) (entity2.PageResult, error) {
@@ -152,34 +159,39 @@ func (s *ModelService) DeleteByFileName(ctx context.Context, fileName string) (M
 		DeletedRecords: deletedRows,
 	}
 
-	if s.pathService == nil {
-		return result, nil
-	}
+	deleted, err := removeBackendArtifactFile(s.pathService, ArtifactCategoryWeights, name)
+	result.LocalFileDeleted = deleted
+	return result, err
+}
 
-	localPath, err := s.pathService.BuildPath(ArtifactCategoryWeights, StorageTargetBackend, name)
+// DeleteByID 删除模型记录及其阶段、标签；训练结果与派生模型按 opts.Cascade 处理（派生模型改挂到被删模型的基础模型）。
+// 权重文件仅在没有其他模型引用且未指定 KeepFile 时从后端删除，网盘等远端副本不受影响。
+func (s *ModelService) DeleteByID(ctx context.Context, id uint, opts ArtifactDeleteOptions) (ArtifactDeleteResult, error) {
+	model, err := s.modelDAO.FindByID(ctx, id)
 	if err != nil {
-		return result, err
-	}
-	if localPath == "" {
-		return result, nil
+		return ArtifactDeleteResult{}, err
 	}
 
-	info, statErr := os.Stat(localPath)
-	if statErr != nil {
-		if os.IsNotExist(statErr) {
-			return result, nil
-		}
-		return result, fmt.Errorf("stat local model file failed: %w", statErr)
+	deletedResults, reparented, err := s.modelDAO.DeleteByIDWithDependents(ctx, id, opts.Cascade)
+	if err != nil {
+		return ArtifactDeleteResult{}, err
 	}
-	if info.IsDir() {
+	result := ArtifactDeleteResult{
+		ID:                     id,
+		FileName:               model.WeightName,
+		DeletedTrainingResults: deletedResults,
+		ReparentedModels:       reparented,
+	}
+	if opts.KeepFile || model.WeightName == "" {
 		return result, nil
 	}
 
-	if removeErr := os.Remove(localPath); removeErr != nil {
-		return result, fmt.Errorf("remove local model file failed: %w", removeErr)
+	result.FileSharedBy, err = s.modelDAO.CountByWeightName(ctx, model.WeightName)
+	if err != nil || result.FileSharedBy > 0 {
+		return result, err
 	}
-	result.LocalFileDeleted = true
-	return result, nil
+	result.LocalFileDeleted, err = removeBackendArtifactFile(s.pathService, ArtifactCategoryWeights, model.WeightName)
+	return result, err
 }
 
 func deriveModelWeightName(model *entity2.Model) string {
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidModelStage         = errors.New("invalid model stage, expected none|staging|production|archived")
+	ErrInvalidStageTransition    = errors.New("stage transition is not allowed")
+	ErrStageTransitionNoActor    = errors.New("actor is required for stage transition")
+	ErrModelStageServiceNotReady = errors.New("model stage service is not ready")
+)
+
+// allowedModelStageTransitions 允许的阶段变更：production 下线需先降级为 staging 或归档，归档版本需回到 staging/none 才能再次晋升。
+var allowedModelStageTransitions = map[string][]string{
+	entity2.ModelStageNone:       {entity2.ModelStageStaging, entity2.ModelStageProduction, entity2.ModelStageArchived},
+	entity2.ModelStageStaging:    {entity2.ModelStageProduction, entity2.ModelStageArchived, entity2.ModelStageNone},
+	entity2.ModelStageProduction: {entity2.ModelStageStaging, entity2.ModelStageArchived},
+	entity2.ModelStageArchived:   {entity2.ModelStageStaging, entity2.ModelStageNone},
+}
+
+// ModelStageTransitionRequest POST /v1/models/:id/transition 请求体
+type ModelStageTransitionRequest struct {
+	Stage           string `json:"stage"`
+	Actor           string `json:"actor"`
+	Reason          string `json:"reason"`
+	ArchiveExisting bool   `json:"archive_existing"`
+}
+
+// ModelStageTransitionResult 阶段变更结果；Archived 为因本次晋升被自动归档的同名版本。
+type ModelStageTransitionResult struct {
+	ModelID    uint                           `json:"model_id"`
+	Name       string                         `json:"name"`
+	Version    float64                        `json:"version"`
+	FromStage  string                         `json:"from_stage"`
+	Stage      string                         `json:"stage"`
+	Transition entity2.ModelStageTransition   `json:"transition"`
+	Archived   []entity2.ModelStageTransition `json:"archived"`
+}
+
+// ModelStageHistory 当前阶段与变更历史
+type ModelStageHistory struct {
+	ModelID uint                           `json:"model_id"`
+	Name    string                         `json:"name"`
+	Version float64                        `json:"version"`
+	Stage   string                         `json:"stage"`
+	History []entity2.ModelStageTransition `json:"history"`
+}
+
+// ModelStageService 管理模型生命周期阶段与晋升流程
+type ModelStageService struct {
+	modelDAO *dao.ModelDAO
+	stageDAO *dao.ModelStageDAO
+}
+
+func NewModelStageService() *ModelStageService {
+	return &ModelStageService{
+		modelDAO: dao.NewModelDAO(),
+		stageDAO: dao.NewModelStageDAO(),
+	}
+}
+
+// NormalizeModelStage 规范阶段名称，空值非法。
+func NormalizeModelStage(stage string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(stage))
+	switch value {
+	case entity2.ModelStageNone, entity2.ModelStageStaging, entity2.ModelStageProduction, entity2.ModelStageArchived:
+		return value, nil
+	default:
+		return "", ErrInvalidModelStage
+	}
+}
+
+func validateModelStageTransition(from, to string) error {
+	for _, allowed := range allowedModelStageTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidStageTransition, from, to)
+}
+
+// Transition 校验并执行阶段变更，记录操作人和原因。
+func (s *ModelStageService) Transition(ctx context.Context, id uint, req ModelStageTransitionRequest) (ModelStageTransitionResult, error) {
+	if s == nil || s.modelDAO == nil || s.stageDAO == nil {
+		return ModelStageTransitionResult{}, ErrModelStageServiceNotReady
+	}
+	toStage, err := NormalizeModelStage(req.Stage)
+	if err != nil {
+		return ModelStageTransitionResult{}, err
+	}
+	actor := strings.TrimSpace(req.Actor)
+	if actor == "" {
+		return ModelStageTransitionResult{}, ErrStageTransitionNoActor
+	}
+
+	model, err := s.modelDAO.FindByID(ctx, id)
+	if err != nil {
+		return ModelStageTransitionResult{}, err
+	}
+	fromStage, err := s.currentStage(ctx, id)
+	if err != nil {
+		return ModelStageTransitionResult{}, err
+	}
+	if err := validateModelStageTransition(fromStage, toStage); err != nil {
+		return ModelStageTransitionResult{}, err
+	}
+
+	written, err := s.stageDAO.Transition(ctx, dao.ModelStageChange{
+		Model:           model,
+		FromStage:       fromStage,
+		ToStage:         toStage,
+		Actor:           actor,
+		Reason:          strings.TrimSpace(req.Reason),
+		ArchiveExisting: req.ArchiveExisting,
+	})
+	if err != nil {
+		return ModelStageTransitionResult{}, err
+	}
+
+	serviceLogger().With("service", "ModelStageService", "method", "Transition").Info(
+		"model stage changed",
+		"model_id", model.ID,
+		"name", model.Name,
+		"from", fromStage,
+		"to", toStage,
+		"actor", actor,
+		"archived", len(written)-1,
+	)
+	return ModelStageTransitionResult{
+		ModelID:    model.ID,
+		Name:       model.Name,
+		Version:    model.Version,
+		FromStage:  fromStage,
+		Stage:      toStage,
+		Transition: written[0],
+		Archived:   append([]entity2.ModelStageTransition{}, written[1:]...),
+	}, nil
+}
+
+// GetHistory 返回模型当前阶段与全部变更历史
+func (s *ModelStageService) GetHistory(ctx context.Context, id uint) (ModelStageHistory, error) {
+	model, err := s.modelDAO.FindByID(ctx, id)
+	if err != nil {
+		return ModelStageHistory{}, err
+	}
+	stage, err := s.currentStage(ctx, id)
+	if err != nil {
+		return ModelStageHistory{}, err
+	}
+	history, err := s.stageDAO.ListTransitions(ctx, id)
+	if err != nil {
+		return ModelStageHistory{}, err
+	}
+	if history == nil {
+		history = make([]entity2.ModelStageTransition, 0)
+	}
+	return ModelStageHistory{
+		ModelID: model.ID,
+		Name:    model.Name,
+		Version: model.Version,
+		Stage:   stage,
+		History: history,
+	}, nil
+}
+
+// GetProductionByName 返回同名模型当前的 production 版本；没有或记录已改名时返回 gorm.ErrRecordNotFound。
+func (s *ModelStageService) GetProductionByName(ctx context.Context, name string) (*entity2.Model, error) {
+	stage, err := s.stageDAO.FindProductionByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	model, err := s.modelDAO.FindByID(ctx, stage.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	if model.Name != strings.TrimSpace(name) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return model, nil
+}
+
+func (s *ModelStageService) currentStage(ctx context.Context, id uint) (string, error) {
+	stage, err := s.stageDAO.FindByModelID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity2.ModelStageNone, nil
+		}
+		return "", err
+	}
+	return stage.Stage, nil
+}
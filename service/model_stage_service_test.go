@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	entity2 "lucky_project/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeModelStage(t *testing.T) {
+	stage, err := NormalizeModelStage(" Production ")
+	require.NoError(t, err)
+	assert.Equal(t, entity2.ModelStageProduction, stage)
+
+	_, err = NormalizeModelStage("")
+	assert.ErrorIs(t, err, ErrInvalidModelStage)
+	_, err = NormalizeModelStage("deployed")
+	assert.ErrorIs(t, err, ErrInvalidModelStage)
+}
+
+func TestValidateModelStageTransition(t *testing.T) {
+	allowed := [][2]string{
+		{entity2.ModelStageNone, entity2.ModelStageStaging},
+		{entity2.ModelStageNone, entity2.ModelStageProduction},
+		{entity2.ModelStageStaging, entity2.ModelStageProduction},
+		{entity2.ModelStageProduction, entity2.ModelStageStaging},
+		{entity2.ModelStageProduction, entity2.ModelStageArchived},
+		{entity2.ModelStageArchived, entity2.ModelStageStaging},
+	}
+	for _, pair := range allowed {
+		assert.NoError(t, validateModelStageTransition(pair[0], pair[1]), "%s -> %s", pair[0], pair[1])
+	}
+
+	rejected := [][2]string{
+		{entity2.ModelStageStaging, entity2.ModelStageStaging},
+		{entity2.ModelStageProduction, entity2.ModelStageNone},
+		{entity2.ModelStageArchived, entity2.ModelStageProduction},
+		{"unknown", entity2.ModelStageStaging},
+	}
+	for _, pair := range rejected {
+		err := validateModelStageTransition(pair[0], pair[1])
+		assert.ErrorIs(t, err, ErrInvalidStageTransition, "%s -> %s", pair[0], pair[1])
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"math"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ModelVersionBumpMajor = "major"
+	ModelVersionBumpMinor = "minor"
+
+	// 版本列为 decimal(5,2)：1.00 起，最大 999.99。
+	initialModelVersion = 1.0
+	maxModelVersion     = 999.99
+	// autoVersionMaxAttempts 并发注册同名模型时，唯一键冲突后重新计算版本的次数。
+	autoVersionMaxAttempts = 5
+)
+
+var (
+	ErrInvalidVersionBump   = errors.New("invalid version bump, expected major|minor")
+	ErrModelVersionOverflow = errors.New("model version exceeds 999.99")
+)
+
+// ModelVersionList GET /v1/models/by-name/:name/versions 返回结构
+type ModelVersionList struct {
+	Name          string          `json:"name"`
+	Total         int             `json:"total"`
+	LatestVersion float64         `json:"latest_version"`
+	Versions      []entity2.Model `json:"versions"`
+}
+
+// NormalizeVersionBump 空值表示不自动分配版本。
+func NormalizeVersionBump(bump string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(bump))
+	switch value {
+	case "", ModelVersionBumpMajor, ModelVersionBumpMinor:
+		return value, nil
+	default:
+		return "", ErrInvalidVersionBump
+	}
+}
+
+// GetLatestByName 返回同名模型中版本最高的一条
+func (s *ModelService) GetLatestByName(ctx context.Context, name string) (*entity2.Model, error) {
+	return s.modelDAO.FindByName(ctx, name)
+}
+
+// ListVersionsByName 返回同名模型的全部版本（从新到旧），不存在时返回 gorm.ErrRecordNotFound。
+func (s *ModelService) ListVersionsByName(ctx context.Context, name string) (ModelVersionList, error) {
+	models, err := s.modelDAO.FindVersionsByName(ctx, name)
+	if err != nil {
+		return ModelVersionList{}, err
+	}
+	if len(models) == 0 {
+		return ModelVersionList{}, gorm.ErrRecordNotFound
+	}
+	return ModelVersionList{
+		Name:          models[0].Name,
+		Total:         len(models),
+		LatestVersion: models[0].Version,
+		Versions:      models,
+	}, nil
+}
+
+// CreateModelWithNextVersion 忽略请求中的 version，按同名最新版本自动递增后插入。
+// 与 CreateModel 的 upsert 不同，这里只插入：并发注册撞上同一版本时重新读取最新版本再试，不会覆盖已有版本。
+func (s *ModelService) CreateModelWithNextVersion(ctx context.Context, model *entity2.Model, bump string) error {
+	logger := serviceLogger().With("service", "ModelService", "method", "CreateModelWithNextVersion")
+	if model == nil {
+		return dao.ErrNilEntity
+	}
+	normalizedBump, err := NormalizeVersionBump(bump)
+	if err != nil {
+		return err
+	}
+	if normalizedBump == "" {
+		normalizedBump = ModelVersionBumpMajor
+	}
+	model.Name = strings.TrimSpace(model.Name)
+	if model.Name == "" {
+		return dao.ErrNilEntity
+	}
+	if err := s.prepareModel(model); err != nil {
+		return err
+	}
+
+	for attempt := 1; attempt <= autoVersionMaxAttempts; attempt++ {
+		latest, err := s.modelDAO.FindByName(ctx, model.Name)
+		switch {
+		case err == nil:
+			model.Version, err = nextModelVersion(latest.Version, normalizedBump)
+			if err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			model.Version = initialModelVersion
+		default:
+			return err
+		}
+
+		model.ID = 0
+		err = s.modelDAO.Insert(ctx, model)
+		if err == nil {
+			logger.Info("model registered with auto version", "id", model.ID, "name", model.Name, "version", model.Version, "bump", normalizedBump)
+			return nil
+		}
+		if !errors.Is(err, dao.ErrAlreadyExists) {
+			return err
+		}
+		logger.Warn("auto version conflict, retry", "name", model.Name, "version", model.Version, "attempt", attempt)
+	}
+	return fmt.Errorf("%w: could not allocate a new version for %s after %d attempts", dao.ErrAlreadyExists, model.Name, autoVersionMaxAttempts)
+}
+
+// nextModelVersion major: 1.37 → 2.00；minor: 1.37 → 1.38。
+func nextModelVersion(current float64, bump string) (float64, error) {
+	cents := math.Round(current * 100)
+	var next float64
+	switch bump {
+	case ModelVersionBumpMajor:
+		next = (math.Floor(cents/100) + 1) * 100
+	case ModelVersionBumpMinor:
+		next = cents + 1
+	default:
+		return 0, ErrInvalidVersionBump
+	}
+	if next < initialModelVersion*100 {
+		next = initialModelVersion * 100
+	}
+	version := next / 100
+	if version > maxModelVersion {
+		return 0, ErrModelVersionOverflow
+	}
+	return version, nil
+}
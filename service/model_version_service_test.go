@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextModelVersion(t *testing.T) {
+	cases := []struct {
+		current float64
+		bump    string
+		want    float64
+	}{
+		{current: 1.00, bump: ModelVersionBumpMajor, want: 2.00},
+		{current: 1.37, bump: ModelVersionBumpMajor, want: 2.00},
+		{current: 1.37, bump: ModelVersionBumpMinor, want: 1.38},
+		{current: 1.09, bump: ModelVersionBumpMinor, want: 1.10},
+		{current: 2.29, bump: ModelVersionBumpMinor, want: 2.30},
+		{current: 0, bump: ModelVersionBumpMinor, want: 1.00},
+	}
+	for _, tc := range cases {
+		got, err := nextModelVersion(tc.current, tc.bump)
+		require.NoError(t, err)
+		assert.InDelta(t, tc.want, got, 1e-9, "current=%v bump=%s", tc.current, tc.bump)
+	}
+
+	_, err := nextModelVersion(999.50, ModelVersionBumpMajor)
+	assert.ErrorIs(t, err, ErrModelVersionOverflow)
+	_, err = nextModelVersion(999.99, ModelVersionBumpMinor)
+	assert.ErrorIs(t, err, ErrModelVersionOverflow)
+	_, err = nextModelVersion(1, "patch")
+	assert.ErrorIs(t, err, ErrInvalidVersionBump)
+}
+
+func TestNormalizeVersionBump(t *testing.T) {
+	bump, err := NormalizeVersionBump(" Minor ")
+	require.NoError(t, err)
+	assert.Equal(t, ModelVersionBumpMinor, bump)
+
+	bump, err = NormalizeVersionBump("")
+	require.NoError(t, err)
+	assert.Empty(t, bump)
+
+	_, err = NormalizeVersionBump("patch")
+	assert.ErrorIs(t, err, ErrInvalidVersionBump)
+}
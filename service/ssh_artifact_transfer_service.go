@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -8,17 +9,19 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"lucky_project/config"
+
 	"github.com/pkg/sftp"
 	"golang.org/x/crypto/ssh"
 )
 
 const (
-	// DefaultStaticServerIP 默认静态服务器IP地址
-	DefaultStaticServerIP = "192.168.1.100"
 	// DefaultSSHServerPort 默认SSH服务器端口
 	DefaultSSHServerPort = 22
 	// DefaultSSHServerUser 默认SSH服务器用户名
@@ -30,12 +33,16 @@ var (
 	ErrSSHClientFactoryNil = errors.New("ssh client factory is nil")
 	// ErrSSHServerNameRequired 服务器名称必填错误
 	ErrSSHServerNameRequired = errors.New("server name is required")
+	// ErrSSHServerNotRegistered 服务器未在core-servers注册表中登记错误
+	ErrSSHServerNotRegistered = errors.New("ssh server is not registered in core server registry")
 	// ErrSSHServerIPRequired 服务器IP必填错误
 	ErrSSHServerIPRequired = errors.New("server ip is required")
 	// ErrSSHServerPortInvalid SSH服务器端口非法错误
 	ErrSSHServerPortInvalid = errors.New("ssh server port is invalid")
 	// ErrSSHServerUserRequired SSH服务器用户必填错误
 	ErrSSHServerUserRequired = errors.New("ssh server user is required")
+	// ErrSSHCredentialOverrideForbidden 服务器已登记credential_id时请求又指定了其他私钥或凭据错误
+	ErrSSHCredentialOverrideForbidden = errors.New("server has a registered credential_id, request ssh key or credential override is not allowed")
 	// ErrSSHPrivateKeyPathRequired SSH私钥路径必填错误
 	ErrSSHPrivateKeyPathRequired = errors.New("ssh private key path is required")
 	// ErrSSHFilePathRequired 文件路径必填错误
@@ -61,6 +68,8 @@ var (
 var (
 	// defaultSSHTimeout 默认SSH连接超时时间
 	defaultSSHTimeout = 15 * time.Second
+	// defaultSSHServerCacheTTL 注册表解析结果的缓存时间
+	defaultSSHServerCacheTTL = 30 * time.Second
 )
 
 // SSHServerConfig SSH服务器配置信息
@@ -71,7 +80,11 @@ type SSHServerConfig struct {
 	Port           int
 	User           string
 	PrivateKeyPath string
-	Timeout        time.Duration
+	// CredentialID 引用凭据库中的 ssh_key/password，解析后写入 PrivateKey 或 Password
+	CredentialID string
+	PrivateKey   []byte
+	Password     string
+	Timeout      time.Duration
 }
 
 // SSHTransferResult SSH文件传输结果
@@ -102,12 +115,33 @@ type RemoteArtifactSearchResult struct {
 	MatchedRemotePath string `json:"matched_remote_path,omitempty"`
 }
 
+// RemoteFileEntry 远程目录中的单个常规文件
+type RemoteFileEntry struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// RemoteArtifactListing 远程服务器weights和datasets根目录的文件清单
+type RemoteArtifactListing struct {
+	ServerName   string            `json:"server_name"`
+	ServerIP     string            `json:"server_ip"`
+	WeightsRoot  string            `json:"weights_root"`
+	DatasetsRoot string            `json:"datasets_root"`
+	Weights      []RemoteFileEntry `json:"weights"`
+	Datasets     []RemoteFileEntry `json:"datasets"`
+}
+
 // remoteFileClient 远程文件客户端接口
 // 定义文件传输操作的标准接口
 type remoteFileClient interface {
 	UploadFile(localPath, remotePath string) (int64, error)
 	DownloadFile(remotePath, localPath string) (int64, error)
 	FileExists(remotePath string) (bool, error)
+	ListFiles(remoteDir string) ([]RemoteFileEntry, error)
+	RunCommand(command string) (RemoteCommandResult, error)
+	StreamCommand(ctx context.Context, command string, onLine func(line string) error) error
 	Close() error
 }
 
@@ -117,113 +151,94 @@ type remoteFileClientFactory interface {
 	New(server SSHServerConfig) (remoteFileClient, error)
 }
 
+// coreServerLookupFunc 按名称查询core-servers注册表，默认实现为GetCoreServerByKey
+type coreServerLookupFunc func(ctx context.Context, key string) (CoreServer, error)
+
+// credentialLookupFunc 按ID读取凭据内容，默认实现为config.Vault.Secret
+type credentialLookupFunc func(id string) ([]byte, config.CredentialMeta, error)
+
+// cachedSSHServer 注册表解析结果缓存项(不缓存凭据内容)
+type cachedSSHServer struct {
+	server    CoreServer
+	expiresAt time.Time
+}
+
+// SSHCredential 单次调用使用的SSH登录凭据，随调用传入，不保存在服务实例上
+// 服务器在注册表中登记了credential_id时只能使用该凭据，指定私钥路径或其他credential_id会被拒绝
+type SSHCredential struct {
+	// User SSH用户名，为空时使用注册表中的用户或默认用户
+	User string
+	// CredentialID 凭据库中的SSH凭据，注册表未登记credential_id时生效
+	CredentialID string
+	// PrivateKeyPath 后端私钥路径，注册表未登记credential_id时生效
+	PrivateKeyPath string
+}
+
 // SSHArtifactTransferService SSH构件传输服务
 // 提供基于SSH的文件传输功能，支持构件文件的上传、下载和搜索
+// 服务器名称一律通过core-servers注册表解析IP/端口，未登记的名称直接报错
 type SSHArtifactTransferService struct {
-	PathService   *ArtifactPathService
-	serverConfigs map[string]SSHServerConfig
-	defaultServer SSHServerConfig
-	clientFactory remoteFileClientFactory
+	PathService       *ArtifactPathService
+	defaultCredential SSHServerConfig
+	serverLookup      coreServerLookupFunc
+	credentialLookup  credentialLookupFunc
+	serverCache       map[string]cachedSSHServer
+	serverCacheTTL    time.Duration
+	mu                sync.Mutex
+	clientFactory     remoteFileClientFactory
 }
 
 // NewSSHArtifactTransferService 创建新的SSH文件传输服务实例
-// 初始化默认服务器配置和静态服务器映射
+// 初始化默认登录凭据，服务器地址通过Redis注册表解析
 // 返回SSHArtifactTransferService指针
 func NewSSHArtifactTransferService() *SSHArtifactTransferService {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		homeDir = ""
 	}
-	defaultKeyPath := filepath.Join(homeDir, ".ssh", "id_rsa")
 
-	defaultServer := SSHServerConfig{
-		Name:           "default",
-		IP:             DefaultStaticServerIP,
-		Port:           DefaultSSHServerPort,
-		User:           DefaultSSHServerUser,
-		PrivateKeyPath: defaultKeyPath,
-		Timeout:        defaultSSHTimeout,
-	}
-
-	// TODO: replace static mapping with Redis lookup.
-	serverConfigs := map[string]SSHServerConfig{
-		"other": {
-			Name:           "other",
-			IP:             DefaultStaticServerIP,
-			Port:           DefaultSSHServerPort,
-			User:           DefaultSSHServerUser,
-			PrivateKeyPath: defaultKeyPath,
-			Timeout:        defaultSSHTimeout,
-		},
-		"other_local": {
-			Name:           "other_local",
-			IP:             DefaultStaticServerIP,
-			Port:           DefaultSSHServerPort,
-			User:           DefaultSSHServerUser,
-			PrivateKeyPath: defaultKeyPath,
-			Timeout:        defaultSSHTimeout,
-		},
-		"backend": {
-			Name:           "backend",
-			IP:             DefaultStaticServerIP,
-			Port:           DefaultSSHServerPort,
-			User:           DefaultSSHServerUser,
-			PrivateKeyPath: defaultKeyPath,
-			Timeout:        defaultSSHTimeout,
-		},
-		"baidu_netdisk": {
-			Name:           "baidu_netdisk",
-			IP:             DefaultStaticServerIP,
-			Port:           DefaultSSHServerPort,
+	return &SSHArtifactTransferService{
+		PathService: NewArtifactPathService(),
+		defaultCredential: SSHServerConfig{
 			User:           DefaultSSHServerUser,
-			PrivateKeyPath: defaultKeyPath,
+			PrivateKeyPath: defaultSSHPrivateKeyPath(homeDir),
 			Timeout:        defaultSSHTimeout,
 		},
+		serverLookup:   GetCoreServerByKey,
+		serverCache:    make(map[string]cachedSSHServer),
+		serverCacheTTL: defaultSSHServerCacheTTL,
+		clientFactory:  &sshSFTPClientFactory{},
 	}
+}
 
-	return &SSHArtifactTransferService{
-		PathService:   NewArtifactPathService(),
-		serverConfigs: serverConfigs,
-		defaultServer: defaultServer,
-		clientFactory: &sshSFTPClientFactory{},
+// defaultSSHPrivateKeyPath 默认私钥路径：依次尝试~/.ssh下的id_rsa、id_ed25519，都不存在时返回id_rsa
+func defaultSSHPrivateKeyPath(homeDir string) string {
+	sshDir := filepath.Join(homeDir, ".ssh")
+	candidates := []string{
+		filepath.Join(sshDir, "id_rsa"),
+		filepath.Join(sshDir, "id_ed25519"),
+	}
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
 	}
+	return candidates[0]
 }
 
-// SetServerConfig 设置指定名称的SSH服务器配置
-// 参数:
-//   - serverName: 服务器名称
-//   - cfg: SSH服务器配置信息
-//
-// 返回错误信息，成功时返回nil
-func (s *SSHArtifactTransferService) SetServerConfig(serverName string, cfg SSHServerConfig) error {
-	logger := serviceLogger().With("service", "SSHArtifactTransferService", "method", "SetServerConfig")
-
+// InvalidateServer 清除指定服务器的注册表解析缓存，下次使用时重新查询Redis
+func (s *SSHArtifactTransferService) InvalidateServer(serverName string) {
 	name := strings.TrimSpace(serverName)
-	if name == "" {
-		logger.Warn("set server config failed: server name is empty")
-		return ErrSSHServerNameRequired
-	}
-
-	normalized, err := normalizeServerConfig(cfg)
-	if err != nil {
-		logger.Error("set server config failed: invalid config", "server_name", name, "error", err)
-		return err
-	}
-	normalized.Name = name
+	s.mu.Lock()
+	delete(s.serverCache, name)
+	s.mu.Unlock()
+}
 
-	if s.serverConfigs == nil {
-		s.serverConfigs = make(map[string]SSHServerConfig)
-	}
-	s.serverConfigs[name] = normalized
-	logger.Info(
-		"set server config success",
-		"server_name", name,
-		"server_ip", normalized.IP,
-		"port", normalized.Port,
-		"user", normalized.User,
-		"private_key_path", normalized.PrivateKeyPath,
-	)
-	return nil
+// InvalidateAllServers 清除全部注册表解析缓存
+func (s *SSHArtifactTransferService) InvalidateAllServers() {
+	s.mu.Lock()
+	s.serverCache = make(map[string]cachedSSHServer)
+	s.mu.Unlock()
 }
 
 // UploadFileByPath 通过指定路径上传文件到远程服务器
@@ -246,7 +261,20 @@ func (s *SSHArtifactTransferService) UploadFileByPath(localPath, remotePath, ser
 //
 // 返回传输结果和错误信息
 func (s *SSHArtifactTransferService) UploadFileByPathWithPort(localPath, remotePath, serverName string, port int) (SSHTransferResult, error) {
-	logger := serviceLogger().With("service", "SSHArtifactTransferService", "method", "UploadFileByPathWithPort")
+	return s.UploadFileByPathWithCredential(localPath, remotePath, serverName, port, SSHCredential{})
+}
+
+// UploadFileByPathWithCredential 通过指定路径上传文件到远程服务器，使用本次调用指定的登录凭据
+// 参数:
+//   - localPath: 本地文件路径
+//   - remotePath: 远程目标路径
+//   - serverName: 目标服务器名称
+//   - port: SSH端口(>0时覆盖服务器默认端口)
+//   - credential: 本次调用的登录凭据，零值时使用注册表或默认凭据
+//
+// 返回传输结果和错误信息
+func (s *SSHArtifactTransferService) UploadFileByPathWithCredential(localPath, remotePath, serverName string, port int, credential SSHCredential) (SSHTransferResult, error) {
+	logger := serviceLogger().With("service", "SSHArtifactTransferService", "method", "UploadFileByPathWithCredential")
 	start := time.Now()
 
 	logger.Info(
@@ -291,7 +319,7 @@ func (s *SSHArtifactTransferService) UploadFileByPathWithPort(localPath, remoteP
 		return SSHTransferResult{}, ErrLocalSourcePathNotRegularFile
 	}
 
-	server, err := s.resolveServerWithPort(serverName, port)
+	server, err := s.resolveServerWithCredential(serverName, port, credential)
 	if err != nil {
 		logger.Error("upload failed: resolve server failed", "server_name", serverName, "port", port, "error", err)
 		return SSHTransferResult{}, err
@@ -300,6 +328,7 @@ func (s *SSHArtifactTransferService) UploadFileByPathWithPort(localPath, remoteP
 	client, err := s.clientFactory.New(server)
 	if err != nil {
 		logger.Error("upload failed: create ssh client failed", "server_name", server.Name, "server_ip", server.IP, "error", err)
+		s.InvalidateServer(server.Name)
 		return SSHTransferResult{}, err
 	}
 	defer func() {
@@ -364,7 +393,20 @@ func (s *SSHArtifactTransferService) DownloadFileByPath(remotePath, localPath, s
 //
 // 返回传输结果和错误信息
 func (s *SSHArtifactTransferService) DownloadFileByPathWithPort(remotePath, localPath, serverName string, port int) (SSHTransferResult, error) {
-	logger := serviceLogger().With("service", "SSHArtifactTransferService", "method", "DownloadFileByPathWithPort")
+	return s.DownloadFileByPathWithCredential(remotePath, localPath, serverName, port, SSHCredential{})
+}
+
+// DownloadFileByPathWithCredential 通过指定路径从远程服务器下载文件，使用本次调用指定的登录凭据
+// 参数:
+//   - remotePath: 远程文件路径
+//   - localPath: 本地目标路径
+//   - serverName: 源服务器名称
+//   - port: SSH端口(>0时覆盖服务器默认端口)
+//   - credential: 本次调用的登录凭据，零值时使用注册表或默认凭据
+//
+// 返回传输结果和错误信息
+func (s *SSHArtifactTransferService) DownloadFileByPathWithCredential(remotePath, localPath, serverName string, port int, credential SSHCredential) (SSHTransferResult, error) {
+	logger := serviceLogger().With("service", "SSHArtifactTransferService", "method", "DownloadFileByPathWithCredential")
 	start := time.Now()
 
 	logger.Info(
@@ -395,7 +437,7 @@ func (s *SSHArtifactTransferService) DownloadFileByPathWithPort(remotePath, loca
 	}
 	normalizedLocal := filepath.Clean(strings.TrimSpace(localPath))
 
-	server, err := s.resolveServerWithPort(serverName, port)
+	server, err := s.resolveServerWithCredential(serverName, port, credential)
 	if err != nil {
 		logger.Error("download failed: resolve server failed", "server_name", serverName, "port", port, "error", err)
 		return SSHTransferResult{}, err
@@ -404,6 +446,7 @@ func (s *SSHArtifactTransferService) DownloadFileByPathWithPort(remotePath, loca
 	client, err := s.clientFactory.New(server)
 	if err != nil {
 		logger.Error("download failed: create ssh client failed", "server_name", server.Name, "server_ip", server.IP, "error", err)
+		s.InvalidateServer(server.Name)
 		return SSHTransferResult{}, err
 	}
 	defer func() {
@@ -524,6 +567,7 @@ func (s *SSHArtifactTransferService) SearchRemoteFileInDefaultOtherRootsWithPort
 	client, err := s.clientFactory.New(server)
 	if err != nil {
 		logger.Error("search remote file failed: create ssh client failed", "server_name", server.Name, "server_ip", server.IP, "error", err)
+		s.InvalidateServer(server.Name)
 		return RemoteArtifactSearchResult{}, err
 	}
 	defer func() {
@@ -576,6 +620,83 @@ func (s *SSHArtifactTransferService) SearchRemoteFileInDefaultOtherRootsWithPort
 	return result, nil
 }
 
+// ListRemoteArtifactsInDefaultOtherRoots 列出远程服务器默认other根目录下的全部构件文件
+// 参数:
+//   - serverName: 目标服务器名称
+//   - port: SSH端口(>0时覆盖服务器默认端口)
+//   - credential: 本次调用的登录凭据，零值时使用注册表或默认凭据
+//
+// 返回weights和datasets两个目录的文件清单
+func (s *SSHArtifactTransferService) ListRemoteArtifactsInDefaultOtherRoots(serverName string, port int, credential SSHCredential) (RemoteArtifactListing, error) {
+	logger := serviceLogger().With("service", "SSHArtifactTransferService", "method", "ListRemoteArtifactsInDefaultOtherRoots")
+	start := time.Now()
+
+	if s.PathService == nil {
+		logger.Warn("list remote artifacts failed: artifact path service is nil")
+		return RemoteArtifactListing{}, ErrArtifactPathServiceNil
+	}
+	if s.clientFactory == nil {
+		logger.Warn("list remote artifacts failed: ssh client factory is nil")
+		return RemoteArtifactListing{}, ErrSSHClientFactoryNil
+	}
+
+	weightsRoot, err := s.PathService.ResolveRoot(ArtifactCategoryWeights, StorageTargetOtherLocal)
+	if err != nil {
+		return RemoteArtifactListing{}, err
+	}
+	datasetsRoot, err := s.PathService.ResolveRoot(ArtifactCategoryDatasets, StorageTargetOtherLocal)
+	if err != nil {
+		return RemoteArtifactListing{}, err
+	}
+
+	server, err := s.resolveServerWithCredential(serverName, port, credential)
+	if err != nil {
+		logger.Error("list remote artifacts failed: resolve server failed", "server_name", serverName, "port", port, "error", err)
+		return RemoteArtifactListing{}, err
+	}
+
+	client, err := s.clientFactory.New(server)
+	if err != nil {
+		logger.Error("list remote artifacts failed: create ssh client failed", "server_name", server.Name, "server_ip", server.IP, "error", err)
+		s.InvalidateServer(server.Name)
+		return RemoteArtifactListing{}, err
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			logger.Error("list remote artifacts close client failed", "server_name", server.Name, "error", closeErr)
+		}
+	}()
+
+	weights, err := client.ListFiles(weightsRoot)
+	if err != nil {
+		logger.Error("list remote artifacts failed: list weights root failed", "remote_dir", weightsRoot, "error", err)
+		return RemoteArtifactListing{}, err
+	}
+	datasets, err := client.ListFiles(datasetsRoot)
+	if err != nil {
+		logger.Error("list remote artifacts failed: list datasets root failed", "remote_dir", datasetsRoot, "error", err)
+		return RemoteArtifactListing{}, err
+	}
+
+	logger.Info(
+		"list remote artifacts success",
+		"server_name", server.Name,
+		"server_ip", server.IP,
+		"port", server.Port,
+		"weights", len(weights),
+		"datasets", len(datasets),
+		"cost_ms", time.Since(start).Milliseconds(),
+	)
+	return RemoteArtifactListing{
+		ServerName:   server.Name,
+		ServerIP:     server.IP,
+		WeightsRoot:  filepath.ToSlash(weightsRoot),
+		DatasetsRoot: filepath.ToSlash(datasetsRoot),
+		Weights:      weights,
+		Datasets:     datasets,
+	}, nil
+}
+
 // UploadArtifactByName 根据文件名上传构件文件
 // 自动解析文件类别并在后端找到对应文件，然后上传到远程other目录
 // 参数:
@@ -775,8 +896,7 @@ func (s *SSHArtifactTransferService) DownloadArtifactByNameWithPort(fileName, se
 }
 
 // resolveServer 解析服务器配置
-// 根据服务器名称查找对应的SSH配置信息
-// 如果找不到则回退到默认配置
+// 根据服务器名称从core-servers注册表解析SSH配置信息
 // 参数:
 //   - serverName: 服务器名称
 //
@@ -786,13 +906,27 @@ func (s *SSHArtifactTransferService) resolveServer(serverName string) (SSHServer
 }
 
 // resolveServerWithPort 解析服务器配置并支持端口覆盖
+// IP/端口来自core-servers注册表(带缓存)，用户名/私钥来自注册表或默认凭据
+// 未登记的服务器名称返回ErrSSHServerNotRegistered，不再回退到默认地址
 // 参数:
 //   - serverName: 服务器名称
-//   - port: SSH端口(>0时覆盖服务器默认端口)
+//   - port: SSH端口(>0时覆盖注册表中的端口)
 //
 // 返回服务器配置和错误信息
 func (s *SSHArtifactTransferService) resolveServerWithPort(serverName string, port int) (SSHServerConfig, error) {
-	logger := serviceLogger().With("service", "SSHArtifactTransferService", "method", "resolveServerWithPort")
+	return s.resolveServerWithCredential(serverName, port, SSHCredential{})
+}
+
+// resolveServerWithCredential 解析服务器配置，登录凭据按 默认凭据 → 注册表 → 本次调用 的顺序合并
+// 注册表登记了credential_id时只能使用该凭据，本次调用指定私钥路径或其他credential_id返回ErrSSHCredentialOverrideForbidden
+// 参数:
+//   - serverName: 服务器名称
+//   - port: SSH端口(>0时覆盖注册表中的端口)
+//   - credential: 本次调用的登录凭据
+//
+// 返回服务器配置和错误信息
+func (s *SSHArtifactTransferService) resolveServerWithCredential(serverName string, port int, credential SSHCredential) (SSHServerConfig, error) {
+	logger := serviceLogger().With("service", "SSHArtifactTransferService", "method", "resolveServerWithCredential")
 
 	name := strings.TrimSpace(serverName)
 	if name == "" {
@@ -800,55 +934,124 @@ func (s *SSHArtifactTransferService) resolveServerWithPort(serverName string, po
 		return SSHServerConfig{}, ErrSSHServerNameRequired
 	}
 
-	if s.serverConfigs != nil {
-		if cfg, ok := s.serverConfigs[name]; ok {
-			normalized, err := normalizeServerConfig(cfg)
-			if err != nil {
-				logger.Error("resolve server failed: invalid mapped config", "server_name", name, "error", err)
-				return SSHServerConfig{}, err
-			}
-			normalized.Name = name
-			if port > 0 {
-				normalized.Port = port
-			}
-			if normalized.Port <= 0 || normalized.Port > 65535 {
-				logger.Error("resolve server failed: invalid port", "server_name", name, "port", normalized.Port)
-				return SSHServerConfig{}, ErrSSHServerPortInvalid
-			}
-			logger.Info(
-				"resolve server from static mapping",
-				"server_name", name,
-				"server_ip", normalized.IP,
-				"port", normalized.Port,
-				"user", normalized.User,
-				"private_key_path", normalized.PrivateKeyPath,
-			)
-			return normalized, nil
-		}
-	}
-
-	fallback, err := normalizeServerConfig(s.defaultServer)
+	registered, cached, err := s.lookupServerAddress(name)
 	if err != nil {
-		logger.Error("resolve server failed: invalid default server config", "error", err)
+		logger.Error("resolve server failed: registry lookup failed", "server_name", name, "error", err)
 		return SSHServerConfig{}, err
 	}
-	fallback.Name = name
+
+	cfg := s.defaultCredential
+	if registered.SSHUser != "" {
+		cfg.User = registered.SSHUser
+	}
+	if user := strings.TrimSpace(credential.User); user != "" {
+		cfg.User = user
+	}
+	credentialID := strings.TrimSpace(credential.CredentialID)
+	privateKeyPath := strings.TrimSpace(credential.PrivateKeyPath)
+	switch {
+	case registered.CredentialID != "":
+		if privateKeyPath != "" || (credentialID != "" && credentialID != registered.CredentialID) {
+			logger.Warn("resolve server failed: credential override on server with registered credential", "server_name", name, "credential_id", registered.CredentialID)
+			return SSHServerConfig{}, fmt.Errorf("%w: %s", ErrSSHCredentialOverrideForbidden, name)
+		}
+		cfg.CredentialID = registered.CredentialID
+		cfg.PrivateKeyPath = ""
+	case credentialID != "":
+		cfg.CredentialID = credentialID
+		cfg.PrivateKeyPath = ""
+	case privateKeyPath != "":
+		cfg.PrivateKeyPath = privateKeyPath
+	}
+	if cfg.CredentialID != "" {
+		if err := s.applyCredential(&cfg); err != nil {
+			logger.Error("resolve server failed: load credential failed", "server_name", name, "credential_id", cfg.CredentialID, "error", err)
+			return SSHServerConfig{}, err
+		}
+	}
+	cfg.IP = registered.IP
+	cfg.Port = registered.Port
 	if port > 0 {
-		fallback.Port = port
+		cfg.Port = port
 	}
-	if fallback.Port <= 0 || fallback.Port > 65535 {
-		logger.Error("resolve server failed: invalid port", "server_name", name, "port", fallback.Port)
-		return SSHServerConfig{}, ErrSSHServerPortInvalid
+
+	normalized, err := normalizeServerConfig(cfg)
+	if err != nil {
+		logger.Error("resolve server failed: invalid config", "server_name", name, "port", cfg.Port, "error", err)
+		return SSHServerConfig{}, err
 	}
-	logger.Warn(
-		"server not found in static mapping, use default static ip",
+	normalized.Name = name
+	logger.Info(
+		"resolve server from core server registry",
 		"server_name", name,
-		"server_ip", fallback.IP,
-		"port", fallback.Port,
-		"user", fallback.User,
-		"private_key_path", fallback.PrivateKeyPath,
+		"server_ip", normalized.IP,
+		"port", normalized.Port,
+		"user", normalized.User,
+		"private_key_path", normalized.PrivateKeyPath,
+		"credential_id", normalized.CredentialID,
+		"cached", cached,
 	)
-	return fallback, nil
+	return normalized, nil
+}
+
+// applyCredential 从凭据库读取credential_id对应的私钥或密码
+func (s *SSHArtifactTransferService) applyCredential(cfg *SSHServerConfig) error {
+	lookup := s.credentialLookup
+	if lookup == nil {
+		if config.Vault == nil {
+			return config.ErrVaultNotInitialized
+		}
+		lookup = config.Vault.Secret
+	}
+
+	secret, meta, err := lookup(cfg.CredentialID)
+	if err != nil {
+		return err
+	}
+	switch meta.Kind {
+	case config.CredentialKindSSHKey:
+		cfg.PrivateKey = secret
+	case config.CredentialKindPassword:
+		cfg.Password = string(secret)
+	default:
+		return fmt.Errorf("%w: credential %s kind %q cannot be used for ssh", config.ErrCredentialInvalid, cfg.CredentialID, meta.Kind)
+	}
+	return nil
+}
+
+// lookupServerAddress 查询服务器注册信息，命中未过期缓存时不访问Redis
+func (s *SSHArtifactTransferService) lookupServerAddress(name string) (CoreServer, bool, error) {
+	now := time.Now()
+	s.mu.Lock()
+	entry, ok := s.serverCache[name]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.server, true, nil
+	}
+
+	if s.serverLookup == nil {
+		return CoreServer{}, false, fmt.Errorf("%w: %s (registry lookup is not configured)", ErrSSHServerNotRegistered, name)
+	}
+	server, err := s.serverLookup(context.Background(), name)
+	if err != nil {
+		s.InvalidateServer(name)
+		if errors.Is(err, ErrCoreServerNotFound) {
+			return CoreServer{}, false, fmt.Errorf("%w: %s", ErrSSHServerNotRegistered, name)
+		}
+		return CoreServer{}, false, fmt.Errorf("lookup core server %s failed: %w", name, err)
+	}
+
+	ttl := s.serverCacheTTL
+	if ttl <= 0 {
+		ttl = defaultSSHServerCacheTTL
+	}
+	s.mu.Lock()
+	if s.serverCache == nil {
+		s.serverCache = make(map[string]cachedSSHServer)
+	}
+	s.serverCache[name] = cachedSSHServer{server: server, expiresAt: now.Add(ttl)}
+	s.mu.Unlock()
+	return server, false, nil
 }
 
 // normalizeServerConfig 标准化SSH服务器配置
@@ -877,7 +1080,7 @@ func normalizeServerConfig(cfg SSHServerConfig) (SSHServerConfig, error) {
 	if normalized.User == "" {
 		return SSHServerConfig{}, ErrSSHServerUserRequired
 	}
-	if normalized.PrivateKeyPath == "" {
+	if normalized.PrivateKeyPath == "" && len(normalized.PrivateKey) == 0 && normalized.Password == "" {
 		return SSHServerConfig{}, ErrSSHPrivateKeyPathRequired
 	}
 	return normalized, nil
@@ -1013,20 +1216,15 @@ func newSSHSFTPClient(server SSHServerConfig) (*sshSFTPClient, error) {
 		return nil, err
 	}
 
-	keyBytes, err := os.ReadFile(normalized.PrivateKeyPath)
-	if err != nil {
-		return nil, fmt.Errorf("read private key failed: %w", err)
-	}
-
-	signer, err := ssh.ParsePrivateKey(keyBytes)
+	authMethod, err := sshAuthMethod(normalized)
 	if err != nil {
-		return nil, fmt.Errorf("parse private key failed: %w", err)
+		return nil, err
 	}
 
 	clientConfig := &ssh.ClientConfig{
 		User: normalized.User,
 		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(signer),
+			authMethod,
 		},
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
 		Timeout:         normalized.Timeout,
@@ -1051,6 +1249,30 @@ func newSSHSFTPClient(server SSHServerConfig) (*sshSFTPClient, error) {
 	}, nil
 }
 
+// sshAuthMethod 按凭据库私钥、凭据库密码、本地私钥文件的顺序选择认证方式
+func sshAuthMethod(server SSHServerConfig) (ssh.AuthMethod, error) {
+	if len(server.PrivateKey) > 0 {
+		signer, err := ssh.ParsePrivateKey(server.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("parse private key failed (credential_id=%s): %w", server.CredentialID, err)
+		}
+		return ssh.PublicKeys(signer), nil
+	}
+	if server.Password != "" {
+		return ssh.Password(server.Password), nil
+	}
+
+	keyBytes, err := os.ReadFile(server.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("read private key failed: %w", err)
+	}
+	signer, err := ssh.ParsePrivateKey(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key failed: %w", err)
+	}
+	return ssh.PublicKeys(signer), nil
+}
+
 // UploadFile 上传本地文件到远程服务器
 // 实现remoteFileClient接口的文件上传功能
 // 参数:
@@ -1163,6 +1385,44 @@ func (c *sshSFTPClient) FileExists(remotePath string) (bool, error) {
 	return true, nil
 }
 
+// ListFiles 列出远程目录下的常规文件（不递归）
+// 实现remoteFileClient接口的目录列举功能，目录不存在时返回空列表
+// 参数:
+//   - remoteDir: 远程目录路径
+//
+// 返回按文件名排序的文件列表和错误信息
+func (c *sshSFTPClient) ListFiles(remoteDir string) ([]RemoteFileEntry, error) {
+	normalizedDir, err := normalizeRemoteFilePath(remoteDir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := c.sftpClient.ReadDir(normalizedDir)
+	if err != nil {
+		if isNotExistError(err) {
+			return []RemoteFileEntry{}, nil
+		}
+		return nil, fmt.Errorf("read remote directory failed: %w", err)
+	}
+
+	entries := make([]RemoteFileEntry, 0, len(infos))
+	for _, info := range infos {
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		entries = append(entries, RemoteFileEntry{
+			Name:    info.Name(),
+			Path:    path.Join(normalizedDir, info.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
 // Close 关闭SSH和SFTP客户端连接
 // 实现remoteFileClient接口的资源清理功能
 // 返回关闭过程中可能发生的第一个错误
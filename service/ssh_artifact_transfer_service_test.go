@@ -1,15 +1,50 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+type fakeCoreServerRegistry struct {
+	servers map[string]CoreServer
+	calls   int
+}
+
+func newFakeCoreServerRegistry(servers ...CoreServer) *fakeCoreServerRegistry {
+	registry := &fakeCoreServerRegistry{servers: make(map[string]CoreServer, len(servers))}
+	for _, server := range servers {
+		registry.servers[server.Key] = server
+	}
+	return registry
+}
+
+func (r *fakeCoreServerRegistry) Lookup(_ context.Context, key string) (CoreServer, error) {
+	r.calls++
+	server, ok := r.servers[key]
+	if !ok {
+		return CoreServer{}, ErrCoreServerNotFound
+	}
+	return server, nil
+}
+
+func testSSHCredential() SSHServerConfig {
+	return SSHServerConfig{
+		User:           "root",
+		PrivateKeyPath: "/tmp/id_rsa",
+		Timeout:        10 * time.Second,
+	}
+}
+
 type fakeRemoteFileClientFactory struct {
 	client      *fakeRemoteFileClient
 	serverCalls []SSHServerConfig
@@ -32,6 +67,11 @@ type fakeRemoteFileClient struct {
 	closeErr       error
 	uploadedPaths  []string
 	downloadedPath []string
+	commands       []string
+	commandResults []RemoteCommandResult
+	commandErr     error
+	streamLines    []string
+	streamErr      error
 }
 
 func (f *fakeRemoteFileClient) UploadFile(localPath, remotePath string) (int64, error) {
@@ -78,6 +118,52 @@ func (f *fakeRemoteFileClient) FileExists(remotePath string) (bool, error) {
 	return ok, nil
 }
 
+func (f *fakeRemoteFileClient) ListFiles(remoteDir string) ([]RemoteFileEntry, error) {
+	entries := make([]RemoteFileEntry, 0)
+	for remotePath, content := range f.remoteFiles {
+		if path.Dir(remotePath) != strings.TrimSuffix(remoteDir, "/") {
+			continue
+		}
+		entries = append(entries, RemoteFileEntry{
+			Name: path.Base(remotePath),
+			Path: remotePath,
+			Size: int64(len(content)),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// RunCommand 依次返回 commandResults，用尽后返回退出码 0 的空结果。
+func (f *fakeRemoteFileClient) RunCommand(command string) (RemoteCommandResult, error) {
+	f.commands = append(f.commands, command)
+	if f.commandErr != nil {
+		return RemoteCommandResult{}, f.commandErr
+	}
+	if len(f.commandResults) == 0 {
+		return RemoteCommandResult{Command: command}, nil
+	}
+	result := f.commandResults[0]
+	f.commandResults = f.commandResults[1:]
+	result.Command = command
+	return result, nil
+}
+
+func (f *fakeRemoteFileClient) StreamCommand(ctx context.Context, command string, onLine func(line string) error) error {
+	f.commands = append(f.commands, command)
+	for _, line := range f.streamLines {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := onLine(line); err != nil {
+			return err
+		}
+	}
+	return f.streamErr
+}
+
 func (f *fakeRemoteFileClient) Close() error {
 	return f.closeErr
 }
@@ -105,25 +191,10 @@ func TestSSHArtifactTransferServiceUploadArtifactByNameWeights(t *testing.T) {
 	factory := &fakeRemoteFileClientFactory{client: client}
 
 	svc := &SSHArtifactTransferService{
-		PathService: pathService,
-		serverConfigs: map[string]SSHServerConfig{
-			"dev-server": {
-				Name:           "dev-server",
-				IP:             "10.0.0.7",
-				Port:           22,
-				User:           "root",
-				PrivateKeyPath: "/tmp/id_rsa",
-				Timeout:        10 * time.Second,
-			},
-		},
-		defaultServer: SSHServerConfig{
-			IP:             "192.168.1.100",
-			Port:           22,
-			User:           "root",
-			PrivateKeyPath: "/tmp/id_rsa",
-			Timeout:        10 * time.Second,
-		},
-		clientFactory: factory,
+		PathService:       pathService,
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "dev-server", IP: "10.0.0.7", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     factory,
 	}
 
 	result, err := svc.UploadArtifactByName("demo.pt", "dev-server")
@@ -161,25 +232,10 @@ func TestSSHArtifactTransferServiceDownloadArtifactByNameDatasets(t *testing.T)
 	factory := &fakeRemoteFileClientFactory{client: client}
 
 	svc := &SSHArtifactTransferService{
-		PathService: pathService,
-		serverConfigs: map[string]SSHServerConfig{
-			"dev-server": {
-				Name:           "dev-server",
-				IP:             "10.0.0.8",
-				Port:           22,
-				User:           "root",
-				PrivateKeyPath: "/tmp/id_rsa",
-				Timeout:        10 * time.Second,
-			},
-		},
-		defaultServer: SSHServerConfig{
-			IP:             "192.168.1.100",
-			Port:           22,
-			User:           "root",
-			PrivateKeyPath: "/tmp/id_rsa",
-			Timeout:        10 * time.Second,
-		},
-		clientFactory: factory,
+		PathService:       pathService,
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "dev-server", IP: "10.0.0.8", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     factory,
 	}
 
 	result, err := svc.DownloadArtifactByName("train.zip", "dev-server")
@@ -215,25 +271,10 @@ func TestSSHArtifactTransferServiceSearchRemoteFileInDefaultOtherRoots(t *testin
 	factory := &fakeRemoteFileClientFactory{client: client}
 
 	svc := &SSHArtifactTransferService{
-		PathService: pathService,
-		serverConfigs: map[string]SSHServerConfig{
-			"dev-server": {
-				Name:           "dev-server",
-				IP:             "10.0.0.9",
-				Port:           22,
-				User:           "root",
-				PrivateKeyPath: "/tmp/id_rsa",
-				Timeout:        10 * time.Second,
-			},
-		},
-		defaultServer: SSHServerConfig{
-			IP:             "192.168.1.100",
-			Port:           22,
-			User:           "root",
-			PrivateKeyPath: "/tmp/id_rsa",
-			Timeout:        10 * time.Second,
-		},
-		clientFactory: factory,
+		PathService:       pathService,
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "dev-server", IP: "10.0.0.9", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     factory,
 	}
 
 	result, err := svc.SearchRemoteFileInDefaultOtherRoots("x.pt", "dev-server")
@@ -272,18 +313,13 @@ func TestSSHArtifactTransferServiceUploadArtifactByNameRemoteAlreadyExists(t *te
 	factory := &fakeRemoteFileClientFactory{client: client}
 
 	svc := &SSHArtifactTransferService{
-		PathService: pathService,
-		defaultServer: SSHServerConfig{
-			IP:             "192.168.1.100",
-			Port:           22,
-			User:           "root",
-			PrivateKeyPath: "/tmp/id_rsa",
-			Timeout:        10 * time.Second,
-		},
-		clientFactory: factory,
+		PathService:       pathService,
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "dev-server", IP: "10.0.0.11", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     factory,
 	}
 
-	_, err = svc.UploadArtifactByName("exists.pt", "any-server")
+	_, err = svc.UploadArtifactByName("exists.pt", "dev-server")
 	assert.True(t, errors.Is(err, ErrRemoteArtifactAlreadyExists))
 }
 
@@ -299,25 +335,10 @@ func TestSSHArtifactTransferServiceUploadFileByPathWithPortOverride(t *testing.T
 	factory := &fakeRemoteFileClientFactory{client: client}
 
 	svc := &SSHArtifactTransferService{
-		PathService: NewArtifactPathService(),
-		serverConfigs: map[string]SSHServerConfig{
-			"dev-server": {
-				Name:           "dev-server",
-				IP:             "10.0.0.10",
-				Port:           22,
-				User:           "root",
-				PrivateKeyPath: "/tmp/id_rsa",
-				Timeout:        10 * time.Second,
-			},
-		},
-		defaultServer: SSHServerConfig{
-			IP:             "192.168.1.100",
-			Port:           22,
-			User:           "root",
-			PrivateKeyPath: "/tmp/id_rsa",
-			Timeout:        10 * time.Second,
-		},
-		clientFactory: factory,
+		PathService:       NewArtifactPathService(),
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "dev-server", IP: "10.0.0.10", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     factory,
 	}
 
 	result, err := svc.UploadFileByPathWithPort(localFile, "/project/luckyProject/weights/demo.bin", "dev-server", 10022)
@@ -340,17 +361,102 @@ func TestSSHArtifactTransferServiceUploadFileByPathWithInvalidPort(t *testing.T)
 	factory := &fakeRemoteFileClientFactory{client: client}
 
 	svc := &SSHArtifactTransferService{
-		PathService: NewArtifactPathService(),
-		defaultServer: SSHServerConfig{
-			IP:             "192.168.1.100",
-			Port:           22,
-			User:           "root",
-			PrivateKeyPath: "/tmp/id_rsa",
-			Timeout:        10 * time.Second,
-		},
-		clientFactory: factory,
+		PathService:       NewArtifactPathService(),
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "dev-server", IP: "10.0.0.11", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     factory,
 	}
 
 	_, err = svc.UploadFileByPathWithPort(localFile, "/project/luckyProject/weights/demo.bin", "dev-server", 70000)
 	assert.True(t, errors.Is(err, ErrSSHServerPortInvalid))
 }
+
+func TestSSHArtifactTransferServiceResolveServerUnknownNameFails(t *testing.T) {
+	factory := &fakeRemoteFileClientFactory{client: &fakeRemoteFileClient{}}
+	svc := &SSHArtifactTransferService{
+		PathService:       NewArtifactPathService(),
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "dev-server", IP: "10.0.0.11", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     factory,
+	}
+
+	_, err := svc.SearchRemoteFileInDefaultOtherRoots("x.pt", "unknown-server")
+	assert.True(t, errors.Is(err, ErrSSHServerNotRegistered))
+	assert.Empty(t, factory.serverCalls)
+}
+
+func TestSSHArtifactTransferServiceResolveServerCacheAndInvalidate(t *testing.T) {
+	registry := newFakeCoreServerRegistry(CoreServer{Key: "gpu-1", IP: "10.0.0.20", Port: 2222})
+	svc := &SSHArtifactTransferService{
+		serverLookup:      registry.Lookup,
+		defaultCredential: testSSHCredential(),
+		serverCacheTTL:    time.Minute,
+	}
+
+	server, err := svc.resolveServer("gpu-1")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.20", server.IP)
+	assert.Equal(t, 2222, server.Port)
+	assert.Equal(t, "root", server.User)
+
+	_, err = svc.resolveServer("gpu-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, registry.calls)
+
+	registry.servers["gpu-1"] = CoreServer{Key: "gpu-1", IP: "10.0.0.21", Port: 22}
+	svc.InvalidateServer("gpu-1")
+	server, err = svc.resolveServer("gpu-1")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.21", server.IP)
+	assert.Equal(t, 2, registry.calls)
+
+	delete(registry.servers, "gpu-1")
+	svc.InvalidateAllServers()
+	_, err = svc.resolveServer("gpu-1")
+	assert.True(t, errors.Is(err, ErrSSHServerNotRegistered))
+}
+
+func TestSSHArtifactTransferServiceResolveServerWithCredential(t *testing.T) {
+	svc := &SSHArtifactTransferService{
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "gpu-1", IP: "10.0.0.20", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+	}
+
+	server, err := svc.resolveServerWithCredential("gpu-1", 10022, SSHCredential{User: "trainer", PrivateKeyPath: "/keys/gpu-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "trainer", server.User)
+	assert.Equal(t, "/keys/gpu-1", server.PrivateKeyPath)
+	assert.Equal(t, "10.0.0.20", server.IP)
+	assert.Equal(t, 10022, server.Port)
+
+	// 凭据只作用于本次调用，不影响之后的解析
+	server, err = svc.resolveServerWithPort("gpu-1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, testSSHCredential().User, server.User)
+	assert.Equal(t, testSSHCredential().PrivateKeyPath, server.PrivateKeyPath)
+
+	_, err = svc.resolveServerWithCredential(" ", 0, SSHCredential{User: "u", PrivateKeyPath: "k"})
+	assert.True(t, errors.Is(err, ErrSSHServerNameRequired))
+}
+
+func TestSSHArtifactTransferServiceInvalidatesCacheOnDialFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	localFile := filepath.Join(tmpDir, "demo.bin")
+	require.NoError(t, os.WriteFile(localFile, []byte("abc"), 0o644))
+
+	registry := newFakeCoreServerRegistry(CoreServer{Key: "gpu-1", IP: "10.0.0.20", Port: 22})
+	factory := &fakeRemoteFileClientFactory{newErr: errors.New("dial tcp: connection refused")}
+	svc := &SSHArtifactTransferService{
+		PathService:       NewArtifactPathService(),
+		serverLookup:      registry.Lookup,
+		defaultCredential: testSSHCredential(),
+		serverCacheTTL:    time.Minute,
+		clientFactory:     factory,
+	}
+
+	_, err := svc.UploadFileByPath(localFile, "/project/luckyProject/weights/demo.bin", "gpu-1")
+	require.Error(t, err)
+	_, err = svc.UploadFileByPath(localFile, "/project/luckyProject/weights/demo.bin", "gpu-1")
+	require.Error(t, err)
+	assert.Equal(t, 2, registry.calls)
+}
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// maxRemoteCommandOutput 单条命令保留的 stdout/stderr 上限，超出部分丢弃
+	maxRemoteCommandOutput = 1 << 20
+	// maxRemoteStreamLine 流式读取时单行的长度上限
+	maxRemoteStreamLine = 1 << 20
+)
+
+var (
+	// ErrRemoteCommandRequired 远程命令必填错误
+	ErrRemoteCommandRequired = errors.New("remote command is required")
+	// ErrRemoteCommandFailed 远程命令退出码非0错误
+	ErrRemoteCommandFailed = errors.New("remote command failed")
+)
+
+// RemoteCommandResult 远程命令执行结果
+type RemoteCommandResult struct {
+	ServerName string        `json:"server_name"`
+	ServerIP   string        `json:"server_ip"`
+	Command    string        `json:"command"`
+	Stdout     string        `json:"stdout"`
+	Stderr     string        `json:"stderr"`
+	ExitCode   int           `json:"exit_code"`
+	Cost       time.Duration `json:"cost"`
+}
+
+// RemoteArtifactPlacement 构件在核心服务器other根目录中的位置
+type RemoteArtifactPlacement struct {
+	Category    string `json:"category"`
+	FileName    string `json:"file_name"`
+	RemotePath  string `json:"remote_path"`
+	Transferred bool   `json:"transferred"`
+	Bytes       int64  `json:"bytes"`
+}
+
+// RunCommandWithPort 通过SSH exec通道在核心服务器上执行一条shell命令
+// 参数:
+//   - command: 交给远程登录shell执行的命令
+//   - serverName: 目标服务器名称
+//   - port: SSH端口(>0时覆盖服务器默认端口)
+//
+// 退出码非0时返回结果和ErrRemoteCommandFailed
+func (s *SSHArtifactTransferService) RunCommandWithPort(command, serverName string, port int) (RemoteCommandResult, error) {
+	return s.RunCommandWithCredential(command, serverName, port, SSHCredential{})
+}
+
+// RunCommandWithCredential 通过SSH exec通道在核心服务器上执行一条shell命令，使用本次调用指定的登录凭据
+// 参数:
+//   - command: 交给远程登录shell执行的命令
+//   - serverName: 目标服务器名称
+//   - port: SSH端口(>0时覆盖服务器默认端口)
+//   - credential: 本次调用的登录凭据，零值时使用注册表或默认凭据
+//
+// 退出码非0时返回结果和ErrRemoteCommandFailed
+func (s *SSHArtifactTransferService) RunCommandWithCredential(command, serverName string, port int, credential SSHCredential) (RemoteCommandResult, error) {
+	logger := serviceLogger().With("service", "SSHArtifactTransferService", "method", "RunCommandWithCredential")
+	start := time.Now()
+
+	if strings.TrimSpace(command) == "" {
+		return RemoteCommandResult{}, ErrRemoteCommandRequired
+	}
+	if s.clientFactory == nil {
+		logger.Warn("run command failed: ssh client factory is nil")
+		return RemoteCommandResult{}, ErrSSHClientFactoryNil
+	}
+
+	server, err := s.resolveServerWithCredential(serverName, port, credential)
+	if err != nil {
+		logger.Error("run command failed: resolve server failed", "server_name", serverName, "port", port, "error", err)
+		return RemoteCommandResult{}, err
+	}
+	client, err := s.clientFactory.New(server)
+	if err != nil {
+		logger.Error("run command failed: create ssh client failed", "server_name", server.Name, "server_ip", server.IP, "error", err)
+		s.InvalidateServer(server.Name)
+		return RemoteCommandResult{}, err
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			logger.Error("run command close client failed", "server_name", server.Name, "error", closeErr)
+		}
+	}()
+
+	result, err := client.RunCommand(command)
+	if err != nil {
+		logger.Error("run command failed", "server_name", server.Name, "server_ip", server.IP, "error", err)
+		return RemoteCommandResult{}, err
+	}
+	result.ServerName = server.Name
+	result.ServerIP = server.IP
+	result.Cost = time.Since(start)
+
+	if result.ExitCode != 0 {
+		logger.Warn("run command exited with non-zero code", "server_name", server.Name, "exit_code", result.ExitCode, "stderr", result.Stderr)
+		return result, fmt.Errorf("%w: exit code %d: %s", ErrRemoteCommandFailed, result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+	logger.Info("run command success", "server_name", server.Name, "server_ip", server.IP, "cost_ms", result.Cost.Milliseconds())
+	return result, nil
+}
+
+// StreamCommandWithPort 通过SSH exec通道执行长时间运行的命令（如 tail -F），按行回调stdout
+// 参数:
+//   - ctx: 取消时关闭会话并返回ctx.Err()
+//   - command: 交给远程登录shell执行的命令
+//   - serverName: 目标服务器名称
+//   - port: SSH端口(>0时覆盖服务器默认端口)
+//   - onLine: 每行回调，返回错误时停止读取；\n 与 \r 都视为换行，便于转发进度条刷新
+//
+// 命令正常退出返回nil，退出码非0返回ErrRemoteCommandFailed
+func (s *SSHArtifactTransferService) StreamCommandWithPort(ctx context.Context, command, serverName string, port int, onLine func(line string) error) error {
+	logger := serviceLogger().With("service", "SSHArtifactTransferService", "method", "StreamCommandWithPort")
+
+	if strings.TrimSpace(command) == "" {
+		return ErrRemoteCommandRequired
+	}
+	if s.clientFactory == nil {
+		logger.Warn("stream command failed: ssh client factory is nil")
+		return ErrSSHClientFactoryNil
+	}
+
+	server, err := s.resolveServerWithPort(serverName, port)
+	if err != nil {
+		logger.Error("stream command failed: resolve server failed", "server_name", serverName, "port", port, "error", err)
+		return err
+	}
+	client, err := s.clientFactory.New(server)
+	if err != nil {
+		logger.Error("stream command failed: create ssh client failed", "server_name", server.Name, "server_ip", server.IP, "error", err)
+		s.InvalidateServer(server.Name)
+		return err
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			logger.Error("stream command close client failed", "server_name", server.Name, "error", closeErr)
+		}
+	}()
+
+	logger.Info("stream command started", "server_name", server.Name, "server_ip", server.IP)
+	if err := client.StreamCommand(ctx, command, onLine); err != nil {
+		if ctx.Err() == nil {
+			logger.Error("stream command failed", "server_name", server.Name, "server_ip", server.IP, "error", err)
+		}
+		return err
+	}
+	logger.Info("stream command finished", "server_name", server.Name, "server_ip", server.IP)
+	return nil
+}
+
+// EnsureRemoteArtifactWithPort 确保构件文件存在于核心服务器的other根目录，缺失时从后端同名文件上传
+// 参数:
+//   - category: 构件类别(weights/datasets)
+//   - fileName: 构件文件名
+//   - serverName: 目标服务器名称
+//   - port: SSH端口(>0时覆盖服务器默认端口)
+//
+// 返回构件的远程位置，后端也没有该文件时返回ErrLocalSourceFileNotFound
+func (s *SSHArtifactTransferService) EnsureRemoteArtifactWithPort(category, fileName, serverName string, port int) (RemoteArtifactPlacement, error) {
+	return s.EnsureRemoteArtifactWithCredential(category, fileName, serverName, port, SSHCredential{})
+}
+
+// EnsureRemoteArtifactWithCredential 确保构件文件存在于核心服务器的other根目录，使用本次调用指定的登录凭据
+// 参数:
+//   - category: 构件类别(weights/datasets)
+//   - fileName: 构件文件名
+//   - serverName: 目标服务器名称
+//   - port: SSH端口(>0时覆盖服务器默认端口)
+//   - credential: 本次调用的登录凭据，零值时使用注册表或默认凭据
+//
+// 返回构件的远程位置，后端也没有该文件时返回ErrLocalSourceFileNotFound
+func (s *SSHArtifactTransferService) EnsureRemoteArtifactWithCredential(category, fileName, serverName string, port int, credential SSHCredential) (RemoteArtifactPlacement, error) {
+	logger := serviceLogger().With("service", "SSHArtifactTransferService", "method", "EnsureRemoteArtifactWithCredential")
+
+	if s.PathService == nil {
+		return RemoteArtifactPlacement{}, ErrArtifactPathServiceNil
+	}
+	if s.clientFactory == nil {
+		return RemoteArtifactPlacement{}, ErrSSHClientFactoryNil
+	}
+	normalizedCategory, err := s.PathService.NormalizeCategory(category)
+	if err != nil {
+		return RemoteArtifactPlacement{}, err
+	}
+	name, err := normalizeArtifactFileName(fileName)
+	if err != nil {
+		return RemoteArtifactPlacement{}, err
+	}
+	remotePath, err := s.PathService.BuildPath(normalizedCategory, StorageTargetOtherLocal, name)
+	if err != nil {
+		return RemoteArtifactPlacement{}, err
+	}
+	placement := RemoteArtifactPlacement{Category: normalizedCategory, FileName: name, RemotePath: remotePath}
+
+	server, err := s.resolveServerWithCredential(serverName, port, credential)
+	if err != nil {
+		logger.Error("ensure remote artifact failed: resolve server failed", "server_name", serverName, "port", port, "error", err)
+		return RemoteArtifactPlacement{}, err
+	}
+	client, err := s.clientFactory.New(server)
+	if err != nil {
+		logger.Error("ensure remote artifact failed: create ssh client failed", "server_name", server.Name, "server_ip", server.IP, "error", err)
+		s.InvalidateServer(server.Name)
+		return RemoteArtifactPlacement{}, err
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			logger.Error("ensure remote artifact close client failed", "server_name", server.Name, "error", closeErr)
+		}
+	}()
+
+	exists, err := client.FileExists(remotePath)
+	if err != nil {
+		logger.Error("ensure remote artifact failed: stat remote file failed", "remote_path", remotePath, "error", err)
+		return RemoteArtifactPlacement{}, err
+	}
+	if exists {
+		logger.Info("remote artifact already present", "server_name", server.Name, "remote_path", remotePath)
+		return placement, nil
+	}
+
+	localPath, err := s.PathService.BuildPath(normalizedCategory, StorageTargetBackend, name)
+	if err != nil {
+		return RemoteArtifactPlacement{}, err
+	}
+	if _, err := os.Stat(filepath.Clean(localPath)); err != nil {
+		if os.IsNotExist(err) {
+			logger.Warn("ensure remote artifact failed: backend file not found", "local_path", localPath)
+			return RemoteArtifactPlacement{}, fmt.Errorf("%w: %s", ErrLocalSourceFileNotFound, name)
+		}
+		return RemoteArtifactPlacement{}, fmt.Errorf("stat local source file failed: %w", err)
+	}
+
+	written, err := client.UploadFile(localPath, remotePath)
+	if err != nil {
+		logger.Error("ensure remote artifact failed: upload failed", "server_name", server.Name, "local_path", localPath, "remote_path", remotePath, "error", err)
+		return RemoteArtifactPlacement{}, err
+	}
+	placement.Transferred = true
+	placement.Bytes = written
+	logger.Info("remote artifact transferred", "server_name", server.Name, "remote_path", remotePath, "bytes", written)
+	return placement, nil
+}
+
+// RunCommand 在新的SSH会话中执行命令
+// 实现remoteFileClient接口的命令执行功能，退出码非0不视为错误，由ExitCode返回
+func (c *sshSFTPClient) RunCommand(command string) (RemoteCommandResult, error) {
+	session, err := c.sshClient.NewSession()
+	if err != nil {
+		return RemoteCommandResult{}, fmt.Errorf("create ssh session failed: %w", err)
+	}
+	defer session.Close()
+
+	stdout := &limitedBuffer{limit: maxRemoteCommandOutput}
+	stderr := &limitedBuffer{limit: maxRemoteCommandOutput}
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	result := RemoteCommandResult{Command: command}
+	if err := session.Run(command); err != nil {
+		var exitErr *ssh.ExitError
+		if !errors.As(err, &exitErr) {
+			return RemoteCommandResult{}, fmt.Errorf("run remote command failed: %w", err)
+		}
+		result.ExitCode = exitErr.ExitStatus()
+	}
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	return result, nil
+}
+
+// StreamCommand 在新的SSH会话中执行命令并逐行回调stdout
+// ctx取消时向远程进程发送SIGTERM并关闭会话
+func (c *sshSFTPClient) StreamCommand(ctx context.Context, command string, onLine func(line string) error) error {
+	session, err := c.sshClient.NewSession()
+	if err != nil {
+		return fmt.Errorf("create ssh session failed: %w", err)
+	}
+	defer session.Close()
+
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("open ssh stdout failed: %w", err)
+	}
+	stderr := &limitedBuffer{limit: maxRemoteCommandOutput}
+	session.Stderr = stderr
+	if err := session.Start(command); err != nil {
+		return fmt.Errorf("start remote command failed: %w", err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = session.Signal(ssh.SIGTERM)
+			_ = session.Close()
+		case <-done:
+		}
+	}()
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRemoteStreamLine)
+	scanner.Split(scanTerminalLines)
+	for scanner.Scan() {
+		if err := onLine(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read remote command output failed: %w", err)
+	}
+	if err := session.Wait(); err != nil {
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("%w: exit code %d: %s", ErrRemoteCommandFailed, exitErr.ExitStatus(), strings.TrimSpace(stderr.String()))
+		}
+		return fmt.Errorf("run remote command failed: %w", err)
+	}
+	return nil
+}
+
+// scanTerminalLines bufio.SplitFunc，\n、\r 与 \r\n 都作为行结束
+func scanTerminalLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\r' {
+			if i+1 < len(data) {
+				if data[i+1] == '\n' {
+					return i + 2, data[:i], nil
+				}
+			} else if !atEOF {
+				// 需要下一个字节判断是否为 \r\n
+				return 0, nil, nil
+			}
+		}
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// limitedBuffer 超过limit后丢弃后续写入，但仍报告写入成功，避免远程命令因管道阻塞
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.Buffer.Write(p[:remaining])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// ShellQuote 用单引号包裹参数，供拼接远程shell命令使用
+func ShellQuote(value string) string {
+	if value == "" {
+		return "''"
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -48,3 +48,21 @@ func normalizeStorageServerList(servers []string) []string {
 	}
 	return result
 }
+
+// storageServerFieldContains 判断 storage_server 字段（JSON 数组或旧单值）是否包含指定服务器。
+func storageServerFieldContains(raw, server string) bool {
+	target := strings.TrimSpace(server)
+	if target == "" {
+		return false
+	}
+	var servers []string
+	if err := json.Unmarshal([]byte(normalizeStorageServerField(raw)), &servers); err != nil {
+		return false
+	}
+	for _, value := range servers {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
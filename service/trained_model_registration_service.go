@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TrainedWeightSourceBackend    = "backend"
+	TrainedWeightSourceCoreServer = "core_server"
+)
+
+var (
+	ErrTrainingResultNotRegistrable = errors.New("only successful training results with weight_path can be registered")
+	ErrInvalidTrainedWeightPath     = errors.New("invalid trained weight path")
+)
+
+// RegisterTrainedModelRequest POST /v1/training-results/:id/register-model 请求体
+type RegisterTrainedModelRequest struct {
+	// CoreServerKey 为空时从后端 weights 目录读取 weight_path，否则通过 SSH 从该核心服务器拉取。
+	CoreServerKey string `json:"core_server_key"`
+	SSHUser       string `json:"ssh_user"`
+	// CredentialID 凭据库中的 SSH 凭据，仅在核心服务器未登记 credential_id 时可用。
+	CredentialID string `json:"credential_id"`
+
+	// Name 新模型名称，默认沿用训练所用模型的名称；VersionBump 默认 minor。
+	Name          string  `json:"name"`
+	VersionBump   string  `json:"version_bump"`
+	ArtifactName  string  `json:"artifact_name"`
+	Description   *string `json:"description"`
+	StorageTarget string  `json:"storage_target"`
+	UploadToBaidu bool    `json:"upload_to_baidu"`
+}
+
+// SSHCredential 从核心服务器拉取权重使用的 SSH 登录凭据。
+func (r RegisterTrainedModelRequest) SSHCredential() SSHCredential {
+	return SSHCredential{User: r.SSHUser, CredentialID: r.CredentialID}
+}
+
+// TrainedModelRegistration 注册结果
+type TrainedModelRegistration struct {
+	TrainingResultID uint           `json:"training_result_id"`
+	Source           string         `json:"source"`
+	SourcePath       string         `json:"source_path"`
+	SourceServer     string         `json:"source_server,omitempty"`
+	Model            *entity2.Model `json:"model"`
+	Upload           UploadResult   `json:"upload"`
+}
+
+// TrainedModelLink GET /v1/training-results/:id/registered-model 返回结构
+type TrainedModelLink struct {
+	TrainingResultID uint           `json:"training_result_id"`
+	ModelID          uint           `json:"model_id"`
+	Model            *entity2.Model `json:"model"`
+}
+
+// TrainedModelRegistrationService 将成功训练产出的权重注册为基础模型的新版本
+type TrainedModelRegistrationService struct {
+	trainingDAO     *dao.TrainingResultDAO
+	linkDAO         *dao.TrainingResultModelDAO
+	jobDAO          *dao.TrainingJobDAO
+	modelDAO        *dao.ModelDAO
+	modelService    *ModelService
+	uploadService   *UploadService
+	pathService     *ArtifactPathService
+	transferService *SSHArtifactTransferService
+}
+
+func NewTrainedModelRegistrationService(transferService *SSHArtifactTransferService) *TrainedModelRegistrationService {
+	return &TrainedModelRegistrationService{
+		trainingDAO:     dao.NewTrainingResultDAO(),
+		linkDAO:         dao.NewTrainingResultModelDAO(),
+		jobDAO:          dao.NewTrainingJobDAO(),
+		modelDAO:        dao.NewModelDAO(),
+		modelService:    NewModelService(),
+		uploadService:   NewUploadService(),
+		pathService:     NewArtifactPathService(),
+		transferService: transferService,
+	}
+}
+
+// Register 取回训练产出的权重（coreServer 为 nil 时读取后端文件），按上传规则保存后创建新模型版本，
+// base_model_id 指向训练所用模型，并记录与训练结果的关联。每条训练结果只能注册一次。
+// 任一步失败时尽量回滚已保存的文件与模型记录。
+func (s *TrainedModelRegistrationService) Register(ctx context.Context, id uint, req RegisterTrainedModelRequest, coreServer *CoreServer) (TrainedModelRegistration, error) {
+	logger := serviceLogger().With("service", "TrainedModelRegistrationService", "method", "Register")
+	bump, err := NormalizeVersionBump(req.VersionBump)
+	if err != nil {
+		return TrainedModelRegistration{}, err
+	}
+	if bump == "" {
+		bump = ModelVersionBumpMinor
+	}
+
+	result, err := s.trainingDAO.FindByID(ctx, id)
+	if err != nil {
+		return TrainedModelRegistration{}, err
+	}
+	if result.TrainingStatus != entity2.TrainingStatusSuccess || strings.TrimSpace(result.WeightPath) == "" {
+		return TrainedModelRegistration{}, ErrTrainingResultNotRegistrable
+	}
+	if _, err := s.linkDAO.FindByTrainingResultID(ctx, id); err == nil {
+		return TrainedModelRegistration{}, dao.ErrAlreadyExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return TrainedModelRegistration{}, err
+	}
+	baseModel, err := s.modelDAO.FindByID(ctx, result.ModelID)
+	if err != nil {
+		return TrainedModelRegistration{}, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = baseModel.Name
+	}
+	artifactName := strings.TrimSpace(req.ArtifactName)
+	if artifactName == "" {
+		artifactName = defaultTrainedArtifactName(name, id)
+	}
+	storedName, err := s.pathService.GenerateStoredFileName(artifactName, path.Base(filepath.ToSlash(result.WeightPath)))
+	if err != nil {
+		return TrainedModelRegistration{}, err
+	}
+	if err := s.ensureWeightNameAvailable(ctx, storedName); err != nil {
+		return TrainedModelRegistration{}, err
+	}
+
+	registration := TrainedModelRegistration{TrainingResultID: id, Source: TrainedWeightSourceBackend}
+	localPath := ""
+	if coreServer == nil {
+		localPath, err = resolveBackendTrainedWeightPath(s.pathService, result.WeightPath)
+		if err != nil {
+			return TrainedModelRegistration{}, err
+		}
+		registration.SourcePath = localPath
+	} else {
+		if s.transferService == nil {
+			return TrainedModelRegistration{}, ErrSSHClientFactoryNil
+		}
+		runDir, err := s.remoteRunDir(ctx, id, coreServer.Key)
+		if err != nil {
+			return TrainedModelRegistration{}, err
+		}
+		remotePath, err := resolveRemoteTrainedWeightPath(runDir, result.WeightPath)
+		if err != nil {
+			return TrainedModelRegistration{}, err
+		}
+		tmpDir, err := os.MkdirTemp("", "trained-weight-*")
+		if err != nil {
+			return TrainedModelRegistration{}, fmt.Errorf("create temp dir failed: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		localPath = filepath.Join(tmpDir, path.Base(remotePath))
+		if _, err := s.transferService.DownloadFileByPathWithCredential(remotePath, localPath, coreServer.Key, coreServer.Port, req.SSHCredential()); err != nil {
+			logger.Error("download trained weight failed", "training_result_id", id, "core_server_key", coreServer.Key, "remote_path", remotePath, "error", err)
+			return TrainedModelRegistration{}, err
+		}
+		registration.Source = TrainedWeightSourceCoreServer
+		registration.SourcePath = remotePath
+		registration.SourceServer = coreServer.Key
+	}
+
+	upload, err := s.uploadService.SaveModelFileFromPath(localPath, artifactName, req.StorageTarget, "", req.UploadToBaidu)
+	if err != nil {
+		return TrainedModelRegistration{}, err
+	}
+	registration.Upload = upload
+
+	model := &entity2.Model{
+		Name:          name,
+		BaseModelID:   baseModel.ID,
+		AlgorithmID:   baseModel.AlgorithmID,
+		TaskType:      baseModel.TaskType,
+		Description:   req.Description,
+		Framework:     baseModel.Framework,
+		WeightSizeMB:  bytesToMB(upload.Size),
+		Paper:         baseModel.Paper,
+		StorageServer: upload.StorageServer,
+		WeightName:    upload.FileName,
+	}
+	if err := s.modelService.CreateModelWithNextVersion(ctx, model, bump); err != nil {
+		s.removeStoredWeight(upload)
+		return TrainedModelRegistration{}, err
+	}
+	if err := s.linkDAO.Create(ctx, &entity2.TrainingResultModel{TrainingResultID: id, ModelID: model.ID}); err != nil {
+		if deleteErr := s.modelDAO.DeleteByID(ctx, model.ID); deleteErr != nil {
+			logger.Error("rollback registered model failed", "model_id", model.ID, "error", deleteErr)
+		}
+		s.removeStoredWeight(upload)
+		return TrainedModelRegistration{}, err
+	}
+
+	logger.Info("register trained model success", "training_result_id", id, "model_id", model.ID, "version", model.Version, "source", registration.Source)
+	registration.Model = model
+	return registration, nil
+}
+
+// GetRegisteredModel 查询训练结果注册出的模型，未注册时返回 gorm.ErrRecordNotFound。
+func (s *TrainedModelRegistrationService) GetRegisteredModel(ctx context.Context, id uint) (TrainedModelLink, error) {
+	if _, err := s.trainingDAO.FindByID(ctx, id); err != nil {
+		return TrainedModelLink{}, err
+	}
+	link, err := s.linkDAO.FindByTrainingResultID(ctx, id)
+	if err != nil {
+		return TrainedModelLink{}, err
+	}
+	model, err := s.modelDAO.FindByID(ctx, link.ModelID)
+	if err != nil {
+		return TrainedModelLink{}, err
+	}
+	return TrainedModelLink{TrainingResultID: id, ModelID: link.ModelID, Model: model}, nil
+}
+
+// ensureWeightNameAvailable 目标文件名已被模型引用或后端已有同名文件时拒绝，避免覆盖已有权重。
+func (s *TrainedModelRegistrationService) ensureWeightNameAvailable(ctx context.Context, fileName string) error {
+	count, err := s.modelDAO.CountByWeightName(ctx, fileName)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return dao.ErrAlreadyExists
+	}
+	target, err := s.pathService.BuildPath(ArtifactCategoryWeights, StorageTargetBackend, fileName)
+	if err != nil {
+		return err
+	}
+	if exists, err := localRegularFileExists(target); err != nil {
+		return err
+	} else if exists {
+		return dao.ErrAlreadyExists
+	}
+	return nil
+}
+
+// removeStoredWeight 回滚本次保存的全部副本：写入的本地文件以及已上传的网盘文件。
+func (s *TrainedModelRegistrationService) removeStoredWeight(upload UploadResult) {
+	if err := s.uploadService.RemoveSaved(upload); err != nil {
+		serviceLogger().Error("rollback stored weight failed", "file_name", upload.FileName, "baidu_path", upload.BaiduPath, "error", err)
+	}
+}
+
+// remoteRunDir 核心服务器上允许读取权重的目录：由本系统启动的训练取其运行目录（且必须是同一台核心服务器），
+// 否则取 other_local weights 目录。
+func (s *TrainedModelRegistrationService) remoteRunDir(ctx context.Context, id uint, coreServerKey string) (string, error) {
+	job, err := s.jobDAO.FindByTrainingResultID(ctx, id)
+	if err == nil {
+		if job.CoreServerKey != coreServerKey {
+			return "", fmt.Errorf("%w: training ran on core server %q", ErrInvalidTrainedWeightPath, job.CoreServerKey)
+		}
+		return job.RunDir, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	return s.pathService.ResolveRoot(ArtifactCategoryWeights, StorageTargetOtherLocal)
+}
+
+// defaultTrainedArtifactName 默认文件名：<模型名>_train<训练结果ID>
+func defaultTrainedArtifactName(name string, id uint) string {
+	return fmt.Sprintf("%s_train%d", strings.TrimSpace(name), id)
+}
+
+// resolveBackendTrainedWeightPath weight_path 为文件名时取后端 weights 目录下的同名文件；
+// 为路径时必须位于后端 weights 目录内。
+func resolveBackendTrainedWeightPath(pathService *ArtifactPathService, weightPath string) (string, error) {
+	root, err := pathService.ResolveRoot(ArtifactCategoryWeights, StorageTargetBackend)
+	if err != nil {
+		return "", err
+	}
+	if root == "" {
+		return "", ErrInvalidStorageTarget
+	}
+	root = filepath.Clean(root)
+
+	value := strings.TrimSpace(weightPath)
+	candidate := filepath.Clean(value)
+	if !filepath.IsAbs(candidate) {
+		if strings.ContainsAny(value, `/\`) {
+			return "", fmt.Errorf("%w: %q is not in backend weights root", ErrInvalidTrainedWeightPath, weightPath)
+		}
+		candidate = filepath.Join(root, candidate)
+	}
+	rel, err := filepath.Rel(root, candidate)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %q is not in backend weights root", ErrInvalidTrainedWeightPath, weightPath)
+	}
+
+	exists, err := localRegularFileExists(candidate)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("%w: %q does not exist on backend", ErrInvalidTrainedWeightPath, weightPath)
+	}
+	return candidate, nil
+}
+
+// resolveRemoteTrainedWeightPath 核心服务器上的 weight_path 可为绝对路径或相对 runDir 的路径，
+// 规范化后必须位于 runDir 之内，防止借注册读取核心服务器上的任意文件。
+func resolveRemoteTrainedWeightPath(runDir, weightPath string) (string, error) {
+	value := strings.TrimSpace(strings.ReplaceAll(weightPath, "\\", "/"))
+	if value == "" {
+		return "", ErrSSHFilePathRequired
+	}
+	root, err := normalizeRemoteFilePath(runDir)
+	if err != nil {
+		return "", fmt.Errorf("%w: run directory is unknown", ErrInvalidTrainedWeightPath)
+	}
+	if !strings.HasPrefix(value, "/") {
+		value = path.Join(root, value)
+	}
+	remotePath, err := normalizeRemoteFilePath(value)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(remotePath, strings.TrimSuffix(root, "/")+"/") {
+		return "", fmt.Errorf("%w: %q is not in run directory %q", ErrInvalidTrainedWeightPath, weightPath, root)
+	}
+	return remotePath, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveBackendTrainedWeightPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	pathService := &ArtifactPathService{BackendWeightsRoot: filepath.Join(tmpDir, "weights")}
+	weightFile := filepath.Join(pathService.BackendWeightsRoot, "runs", "best.pt")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(weightFile), 0o755))
+	assert.NoError(t, os.WriteFile(weightFile, []byte("weights"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(pathService.BackendWeightsRoot, "last.pt"), []byte("weights"), 0o644))
+
+	resolved, err := resolveBackendTrainedWeightPath(pathService, weightFile)
+	assert.NoError(t, err)
+	assert.Equal(t, weightFile, resolved)
+
+	resolved, err = resolveBackendTrainedWeightPath(pathService, " last.pt ")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(pathService.BackendWeightsRoot, "last.pt"), resolved)
+
+	outside := filepath.Join(tmpDir, "outside.pt")
+	assert.NoError(t, os.WriteFile(outside, []byte("weights"), 0o644))
+	for _, weightPath := range []string{
+		outside,
+		filepath.Join(pathService.BackendWeightsRoot, "..", "outside.pt"),
+		"runs/best.pt",
+		"missing.pt",
+		pathService.BackendWeightsRoot,
+	} {
+		_, err := resolveBackendTrainedWeightPath(pathService, weightPath)
+		assert.ErrorIs(t, err, ErrInvalidTrainedWeightPath, weightPath)
+	}
+}
+
+func TestResolveRemoteTrainedWeightPath(t *testing.T) {
+	runDir := "/data/runs/train3"
+
+	remotePath, err := resolveRemoteTrainedWeightPath(runDir, "/data/runs/train3/train/weights/best.pt")
+	assert.NoError(t, err)
+	assert.Equal(t, "/data/runs/train3/train/weights/best.pt", remotePath)
+
+	remotePath, err = resolveRemoteTrainedWeightPath(runDir+"/", `train\weights\best.pt`)
+	assert.NoError(t, err)
+	assert.Equal(t, "/data/runs/train3/train/weights/best.pt", remotePath)
+
+	_, err = resolveRemoteTrainedWeightPath(runDir, "  ")
+	assert.ErrorIs(t, err, ErrSSHFilePathRequired)
+
+	for _, weightPath := range []string{
+		"/etc/passwd",
+		"/data/runs/train3-other/best.pt",
+		"/data/runs/train3/../train4/best.pt",
+		"../../../etc/shadow",
+		runDir,
+	} {
+		_, err := resolveRemoteTrainedWeightPath(runDir, weightPath)
+		assert.ErrorIs(t, err, ErrInvalidTrainedWeightPath, weightPath)
+	}
+
+	_, err = resolveRemoteTrainedWeightPath("", "/data/best.pt")
+	assert.ErrorIs(t, err, ErrInvalidTrainedWeightPath)
+}
+
+func TestUploadServiceRemoveSaved(t *testing.T) {
+	savedPath := filepath.Join(t.TempDir(), "best.pt")
+	assert.NoError(t, os.WriteFile(savedPath, []byte("weights"), 0o644))
+	remover := &fakeBaiduRemover{}
+	uploadService := &UploadService{BaiduUploader: remover}
+
+	result := UploadResult{FileName: "best.pt", ResolvedPath: filepath.ToSlash(savedPath), BaiduUploaded: true, BaiduPath: "/w/best.pt"}
+	assert.NoError(t, uploadService.RemoveSaved(result))
+	_, err := os.Stat(savedPath)
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, []string{"/w/best.pt"}, remover.removed)
+
+	// 本地文件已不存在时仍回滚网盘副本，网盘删除失败时返回错误
+	remover.err = errors.New("boom")
+	assert.ErrorContains(t, uploadService.RemoveSaved(result), "boom")
+	assert.Equal(t, []string{"/w/best.pt", "/w/best.pt"}, remover.removed)
+}
+
+type fakeBaiduRemover struct {
+	removed []string
+	err     error
+}
+
+func (f *fakeBaiduRemover) Upload(localPath, remoteDir string) (BaiduUploadReceipt, error) {
+	return BaiduUploadReceipt{}, nil
+}
+
+func (f *fakeBaiduRemover) Remove(remotePath string) error {
+	f.removed = append(f.removed, remotePath)
+	return f.err
+}
+
+func TestDefaultTrainedArtifactName(t *testing.T) {
+	assert.Equal(t, "yolo11n_train42", defaultTrainedArtifactName(" yolo11n ", 42))
+}
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	DefaultTrainingCommandTemplate = "yolo train model={{.ModelPath}} data={{.DatasetConfig}} project={{.RunDir}} name=train exist_ok=True {{.Args}}"
+	DefaultTrainingRunsRoot        = "/project/luckyProject/runs"
+
+	trainingLogFileName = "train.log"
+	maxTrainingArgs     = 64
+)
+
+var (
+	ErrInvalidTrainingLaunch   = errors.New("invalid training launch request")
+	ErrInvalidTrainingTemplate = errors.New("invalid training command template")
+
+	trainingArgKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]{0,63}$`)
+)
+
+// LaunchTrainingRequest POST /v1/training-results/launch 请求体
+type LaunchTrainingRequest struct {
+	ModelID       uint   `json:"model_id"`
+	DatasetID     uint   `json:"dataset_id"`
+	CoreServerKey string `json:"core_server_key"`
+	SSHUser       string `json:"ssh_user"`
+	// CredentialID 凭据库中的 SSH 凭据，仅在核心服务器未登记 credential_id 时可用。
+	CredentialID string `json:"credential_id"`
+	// Args 追加到命令模板 {{.Args}} 处的 key=value 参数，值只能是字符串、数值或布尔。
+	Args        map[string]interface{} `json:"args"`
+	CometLogURL string                 `json:"comet_log_url"`
+}
+
+// SSHCredential 本次启动使用的 SSH 登录凭据。
+func (r LaunchTrainingRequest) SSHCredential() SSHCredential {
+	return SSHCredential{User: r.SSHUser, CredentialID: r.CredentialID}
+}
+
+// TrainingCommandVars 命令模板可用的变量，值均已做 shell 转义，可直接拼接。
+type TrainingCommandVars struct {
+	TrainingResultID string
+	ModelPath        string
+	DatasetPath      string
+	DatasetConfig    string
+	RunDir           string
+	LogPath          string
+	Args             string
+}
+
+// TrainingLaunch 启动结果
+type TrainingLaunch struct {
+	Result  *entity2.ModelTrainingResult `json:"result"`
+	Job     *entity2.TrainingJob         `json:"job"`
+	Weight  RemoteArtifactPlacement      `json:"weight"`
+	Dataset RemoteArtifactPlacement      `json:"dataset"`
+}
+
+// trainingLaunchStore 启动训练用到的持久化操作，默认由各 DAO 实现。
+type trainingLaunchStore interface {
+	FindModel(ctx context.Context, id uint) (*entity2.Model, error)
+	FindDataset(ctx context.Context, id uint) (*entity2.Dataset, error)
+	FindResult(ctx context.Context, id uint) (*entity2.ModelTrainingResult, error)
+	SaveResult(ctx context.Context, result *entity2.ModelTrainingResult) error
+	DeleteResult(ctx context.Context, id uint) error
+	FailResult(ctx context.Context, id uint, now time.Time) error
+	FindJob(ctx context.Context, id uint) (*entity2.TrainingJob, error)
+	CreateJob(ctx context.Context, job *entity2.TrainingJob) error
+	UpsertHyperparameters(ctx context.Context, record *entity2.TrainingHyperparameters) error
+}
+
+// TrainingLaunchService 在核心服务器上启动训练：确认权重与数据集已在 other_local 目录（缺失时从后端传输），
+// 按命令模板渲染训练命令后台启动，并登记一条 running 状态的训练结果及其启动参数。
+type TrainingLaunchService struct {
+	store           trainingLaunchStore
+	pathService     *ArtifactPathService
+	transferService *SSHArtifactTransferService
+	now             func() time.Time
+}
+
+func NewTrainingLaunchService(transferService *SSHArtifactTransferService) *TrainingLaunchService {
+	return &TrainingLaunchService{
+		store: &daoTrainingLaunchStore{
+			modelDAO:          dao.NewModelDAO(),
+			datasetDAO:        dao.NewDatasetDAO(),
+			trainingDAO:       dao.NewTrainingResultDAO(),
+			jobDAO:            dao.NewTrainingJobDAO(),
+			hyperparameterDAO: dao.NewTrainingHyperparameterDAO(),
+		},
+		pathService:     NewArtifactPathService(),
+		transferService: transferService,
+	}
+}
+
+// Launch 启动训练。命令启动失败时删除已登记的训练结果；进程已启动后登记训练任务失败时结束该进程，
+// 并将训练结果置为失败，避免留下无人跟踪的训练。
+func (s *TrainingLaunchService) Launch(ctx context.Context, req LaunchTrainingRequest, server CoreServer) (TrainingLaunch, error) {
+	logger := serviceLogger().With("service", "TrainingLaunchService", "method", "Launch")
+	if req.ModelID == 0 || req.DatasetID == 0 {
+		return TrainingLaunch{}, fmt.Errorf("%w: model_id and dataset_id are required", ErrInvalidTrainingLaunch)
+	}
+	if s.transferService == nil {
+		return TrainingLaunch{}, ErrSSHClientFactoryNil
+	}
+	args, err := formatTrainingArgs(req.Args)
+	if err != nil {
+		return TrainingLaunch{}, err
+	}
+	settings := currentTrainingConfig()
+	tmpl, err := parseTrainingCommandTemplate(settings.CommandTemplate)
+	if err != nil {
+		return TrainingLaunch{}, err
+	}
+
+	model, err := s.store.FindModel(ctx, req.ModelID)
+	if err != nil {
+		return TrainingLaunch{}, err
+	}
+	dataset, err := s.store.FindDataset(ctx, req.DatasetID)
+	if err != nil {
+		return TrainingLaunch{}, err
+	}
+	weightName := deriveModelWeightName(model)
+	datasetFileName := deriveFileName(dataset.FileName, dataset.DatasetPath)
+	if weightName == "" || datasetFileName == "" {
+		return TrainingLaunch{}, fmt.Errorf("%w: model or dataset has no file", ErrInvalidTrainingLaunch)
+	}
+
+	weight, err := s.transferService.EnsureRemoteArtifactWithCredential(ArtifactCategoryWeights, weightName, server.Key, server.Port, req.SSHCredential())
+	if err != nil {
+		return TrainingLaunch{}, err
+	}
+	datasetPlacement, err := s.transferService.EnsureRemoteArtifactWithCredential(ArtifactCategoryDatasets, datasetFileName, server.Key, server.Port, req.SSHCredential())
+	if err != nil {
+		return TrainingLaunch{}, err
+	}
+	datasetConfig, err := s.resolveRemoteDatasetConfig(dataset, datasetPlacement.RemotePath)
+	if err != nil {
+		return TrainingLaunch{}, err
+	}
+
+	startedAt := s.clock()
+	datasetVersion, _ := strconv.ParseFloat(strings.TrimSpace(dataset.Version), 64)
+	result := &entity2.ModelTrainingResult{
+		ModelID:        model.ID,
+		DatasetID:      dataset.ID,
+		DatasetVersion: datasetVersion,
+		TrainingStatus: entity2.TrainingStatusRunning,
+		CometLogURL:    strings.TrimSpace(req.CometLogURL),
+		TrainStartTime: &startedAt,
+	}
+	if err := s.store.SaveResult(ctx, result); err != nil {
+		return TrainingLaunch{}, err
+	}
+
+	job, err := s.start(tmpl, settings.RunsRoot, result.ID, weight.RemotePath, datasetPlacement.RemotePath, datasetConfig, args, server, req.SSHCredential())
+	if err != nil {
+		logger.Error("launch training failed", "training_result_id", result.ID, "core_server_key", server.Key, "error", err)
+		if deleteErr := s.store.DeleteResult(ctx, result.ID); deleteErr != nil {
+			logger.Error("rollback training result failed", "training_result_id", result.ID, "error", deleteErr)
+		}
+		return TrainingLaunch{}, err
+	}
+	if err := s.store.CreateJob(ctx, job); err != nil {
+		logger.Error("record training job failed, stopping launched process", "training_result_id", result.ID, "core_server_key", server.Key, "pid", job.PID, "error", err)
+		if _, killErr := s.transferService.RunCommandWithCredential(buildKillTrainingCommand(job.PID), server.Key, server.Port, req.SSHCredential()); killErr != nil {
+			logger.Error("stop launched training failed", "training_result_id", result.ID, "core_server_key", server.Key, "pid", job.PID, "error", killErr)
+		}
+		if failErr := s.store.FailResult(ctx, result.ID, s.clock()); failErr != nil {
+			logger.Error("mark training result failed failed", "training_result_id", result.ID, "error", failErr)
+		}
+		return TrainingLaunch{}, err
+	}
+
+	if len(req.Args) > 0 {
+		// 启动参数作为超参数留档，便于训练结果间对比；写入失败不影响已启动的训练
+		if raw, err := json.Marshal(req.Args); err != nil {
+			logger.Error("record launch args failed: marshal", "training_result_id", result.ID, "error", err)
+		} else if err := s.store.UpsertHyperparameters(ctx, &entity2.TrainingHyperparameters{
+			TrainingResultID: result.ID,
+			Source:           entity2.HyperparameterSourceLaunchArgs,
+			Hyperparameters:  raw,
+		}); err != nil {
+			logger.Error("record launch args failed", "training_result_id", result.ID, "error", err)
+		} else {
+			result.Hyperparameters = raw
+		}
+	}
+
+	logger.Info("launch training success", "training_result_id", result.ID, "core_server_key", server.Key, "pid", job.PID, "run_dir", job.RunDir)
+	fillTrainingDuration(result)
+	return TrainingLaunch{Result: result, Job: job, Weight: weight, Dataset: datasetPlacement}, nil
+}
+
+// start 渲染训练命令并在核心服务器上后台启动，返回待登记的训练任务。
+func (s *TrainingLaunchService) start(tmpl *template.Template, runsRoot string, id uint, modelPath, datasetPath, datasetConfig, args string, server CoreServer, credential SSHCredential) (*entity2.TrainingJob, error) {
+	runDir := path.Join(runsRoot, fmt.Sprintf("train_%d", id))
+	logPath := path.Join(runDir, trainingLogFileName)
+	command, err := renderTrainingCommand(tmpl, TrainingCommandVars{
+		TrainingResultID: ShellQuote(strconv.FormatUint(uint64(id), 10)),
+		ModelPath:        ShellQuote(modelPath),
+		DatasetPath:      ShellQuote(datasetPath),
+		DatasetConfig:    ShellQuote(datasetConfig),
+		RunDir:           ShellQuote(runDir),
+		LogPath:          ShellQuote(logPath),
+		Args:             args,
+	})
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.transferService.RunCommandWithCredential(buildDetachedCommand(runDir, logPath, command), server.Key, server.Port, credential)
+	if err != nil {
+		return nil, err
+	}
+	pid, err := parseLaunchPID(output.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	return &entity2.TrainingJob{
+		TrainingResultID: id,
+		CoreServerKey:    server.Key,
+		PID:              pid,
+		RunDir:           runDir,
+		LogPath:          logPath,
+		Command:          command,
+	}, nil
+}
+
+// GetJob 查询训练结果对应的训练进程
+func (s *TrainingLaunchService) GetJob(ctx context.Context, id uint) (*entity2.TrainingJob, error) {
+	if _, err := s.store.FindResult(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.store.FindJob(ctx, id)
+}
+
+// resolveRemoteDatasetConfig config_path 为绝对路径时直接使用，相对路径按核心服务器 datasets 目录解析，未配置时使用数据集文件本身。
+func (s *TrainingLaunchService) resolveRemoteDatasetConfig(dataset *entity2.Dataset, datasetPath string) (string, error) {
+	if dataset.ConfigPath == nil || strings.TrimSpace(*dataset.ConfigPath) == "" {
+		return datasetPath, nil
+	}
+	value := strings.TrimSpace(strings.ReplaceAll(*dataset.ConfigPath, "\\", "/"))
+	if !strings.HasPrefix(value, "/") {
+		root, err := s.pathService.ResolveRoot(ArtifactCategoryDatasets, StorageTargetOtherLocal)
+		if err != nil {
+			return "", err
+		}
+		value = path.Join(root, value)
+	}
+	return normalizeRemoteFilePath(value)
+}
+
+func (s *TrainingLaunchService) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// daoTrainingLaunchStore trainingLaunchStore 的 DAO 实现
+type daoTrainingLaunchStore struct {
+	modelDAO          *dao.ModelDAO
+	datasetDAO        *dao.DatasetDAO
+	trainingDAO       *dao.TrainingResultDAO
+	jobDAO            *dao.TrainingJobDAO
+	hyperparameterDAO *dao.TrainingHyperparameterDAO
+}
+
+func (d *daoTrainingLaunchStore) FindModel(ctx context.Context, id uint) (*entity2.Model, error) {
+	return d.modelDAO.FindByID(ctx, id)
+}
+
+func (d *daoTrainingLaunchStore) FindDataset(ctx context.Context, id uint) (*entity2.Dataset, error) {
+	return d.datasetDAO.FindByID(ctx, id)
+}
+
+func (d *daoTrainingLaunchStore) FindResult(ctx context.Context, id uint) (*entity2.ModelTrainingResult, error) {
+	return d.trainingDAO.FindByID(ctx, id)
+}
+
+func (d *daoTrainingLaunchStore) SaveResult(ctx context.Context, result *entity2.ModelTrainingResult) error {
+	return d.trainingDAO.Save(ctx, result)
+}
+
+func (d *daoTrainingLaunchStore) DeleteResult(ctx context.Context, id uint) error {
+	return d.trainingDAO.DeleteByID(ctx, id)
+}
+
+// FailResult 按状态机把训练结果置为失败并补记结束时间。
+func (d *daoTrainingLaunchStore) FailResult(ctx context.Context, id uint, now time.Time) error {
+	_, err := d.trainingDAO.UpdateWithLock(ctx, id, func(current *entity2.ModelTrainingResult) (map[string]interface{}, error) {
+		return buildTrainingResultUpdates(current, map[string]interface{}{"training_status": entity2.TrainingStatusFailed}, now)
+	})
+	return err
+}
+
+func (d *daoTrainingLaunchStore) FindJob(ctx context.Context, id uint) (*entity2.TrainingJob, error) {
+	return d.jobDAO.FindByTrainingResultID(ctx, id)
+}
+
+func (d *daoTrainingLaunchStore) CreateJob(ctx context.Context, job *entity2.TrainingJob) error {
+	return d.jobDAO.Create(ctx, job)
+}
+
+func (d *daoTrainingLaunchStore) UpsertHyperparameters(ctx context.Context, record *entity2.TrainingHyperparameters) error {
+	return d.hyperparameterDAO.Upsert(ctx, record)
+}
+
+// currentTrainingConfig 读取 training 配置，未配置的项使用默认值。
+func currentTrainingConfig() config.TrainingConfig {
+	var cfg config.TrainingConfig
+	if config.AppConfig != nil {
+		cfg = config.AppConfig.Training
+	}
+	if strings.TrimSpace(cfg.CommandTemplate) == "" {
+		cfg.CommandTemplate = DefaultTrainingCommandTemplate
+	}
+	cfg.RunsRoot = path.Clean(strings.TrimSpace(cfg.RunsRoot))
+	if cfg.RunsRoot == "." || !strings.HasPrefix(cfg.RunsRoot, "/") {
+		cfg.RunsRoot = DefaultTrainingRunsRoot
+	}
+	return cfg
+}
+
+// parseTrainingCommandTemplate 解析前把模板文本中的连续空白（含换行）折叠为单个空格，
+// 便于在配置中分行书写；变量替换后的内容不再处理，引号内的空白原样保留。
+func parseTrainingCommandTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("training").Option("missingkey=error").Parse(strings.Join(strings.Fields(text), " "))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrainingTemplate, err)
+	}
+	return tmpl, nil
+}
+
+func renderTrainingCommand(tmpl *template.Template, vars TrainingCommandVars) (string, error) {
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, vars); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTrainingTemplate, err)
+	}
+	command := strings.TrimSpace(builder.String())
+	if command == "" {
+		return "", fmt.Errorf("%w: rendered command is empty", ErrInvalidTrainingTemplate)
+	}
+	return command, nil
+}
+
+// formatTrainingArgs 按键名排序输出 key=value，值做 shell 转义。
+func formatTrainingArgs(args map[string]interface{}) (string, error) {
+	if len(args) > maxTrainingArgs {
+		return "", fmt.Errorf("%w: at most %d args", ErrInvalidTrainingLaunch, maxTrainingArgs)
+	}
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		if !trainingArgKeyPattern.MatchString(key) {
+			return "", fmt.Errorf("%w: invalid arg name %q", ErrInvalidTrainingLaunch, key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		var value string
+		switch v := args[key].(type) {
+		case string:
+			value = v
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			value = strconv.FormatBool(v)
+		default:
+			return "", fmt.Errorf("%w: arg %q must be a string, number or boolean", ErrInvalidTrainingLaunch, key)
+		}
+		parts = append(parts, key+"="+ShellQuote(value))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// buildDetachedCommand 在 runDir 中用 setsid + nohup 后台运行训练命令，输出写入日志文件，并打印进程 PID。
+// setsid 使训练进程成为新进程组的组长（PGID 即打印的 PID），结束时可连同其全部子进程一起结束。
+func buildDetachedCommand(runDir, logPath, command string) string {
+	return fmt.Sprintf(
+		"mkdir -p %s && cd %s && { setsid nohup sh -c %s > %s 2>&1 < /dev/null & echo $!; }",
+		ShellQuote(runDir), ShellQuote(runDir), ShellQuote(command), ShellQuote(logPath),
+	)
+}
+
+// buildKillTrainingCommand 结束训练进程组；不是进程组组长（setsid 之前启动）的旧进程退回到先结束子进程、再结束 sh 本身。
+// 进程已不存在时同样视为成功。
+func buildKillTrainingCommand(pid int) string {
+	return fmt.Sprintf("kill -TERM -- -%d 2>/dev/null || { pkill -TERM -P %d; kill -TERM %d; } 2>/dev/null; true", pid, pid, pid)
+}
+
+// buildTrainingAliveCommand 检查训练进程组（或旧方式启动的进程）是否仍存在，输出 alive 或 dead。
+func buildTrainingAliveCommand(pid int) string {
+	return fmt.Sprintf("if kill -0 -- -%d 2>/dev/null || kill -0 %d 2>/dev/null; then echo alive; else echo dead; fi", pid, pid)
+}
+
+func parseLaunchPID(stdout string) (int, error) {
+	lines := strings.Fields(stdout)
+	if len(lines) == 0 {
+		return 0, fmt.Errorf("%w: launch printed no pid", ErrRemoteCommandFailed)
+	}
+	pid, err := strconv.Atoi(lines[len(lines)-1])
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("%w: unexpected launch output %q", ErrRemoteCommandFailed, strings.TrimSpace(stdout))
+	}
+	return pid, nil
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"lucky_project/config"
+	entity2 "lucky_project/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestFormatTrainingArgs(t *testing.T) {
+	args, err := formatTrainingArgs(map[string]interface{}{
+		"epochs": float64(100),
+		"batch":  16.5,
+		"cos_lr": true,
+		"name":   "it's",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `batch='16.5' cos_lr='true' epochs='100' name='it'\''s'`, args)
+
+	args, err = formatTrainingArgs(nil)
+	require.NoError(t, err)
+	assert.Empty(t, args)
+
+	_, err = formatTrainingArgs(map[string]interface{}{"rm -rf": "x"})
+	assert.ErrorIs(t, err, ErrInvalidTrainingLaunch)
+	_, err = formatTrainingArgs(map[string]interface{}{"lr": []interface{}{1}})
+	assert.ErrorIs(t, err, ErrInvalidTrainingLaunch)
+}
+
+func TestRenderTrainingCommand(t *testing.T) {
+	tmpl, err := parseTrainingCommandTemplate(DefaultTrainingCommandTemplate)
+	require.NoError(t, err)
+
+	command, err := renderTrainingCommand(tmpl, TrainingCommandVars{
+		ModelPath:     ShellQuote("/w/yolo.pt"),
+		DatasetConfig: ShellQuote("/d/coco $(id).yaml"),
+		RunDir:        ShellQuote("/runs/train_7"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `yolo train model='/w/yolo.pt' data='/d/coco $(id).yaml' project='/runs/train_7' name=train exist_ok=True`, command)
+
+	// 模板中的换行与多余空白在替换前折叠，变量值中的空白保持原样
+	tmpl, err = parseTrainingCommandTemplate("python train.py\n    --data {{.DatasetConfig}}   {{.Args}}\n")
+	require.NoError(t, err)
+	command, err = renderTrainingCommand(tmpl, TrainingCommandVars{
+		DatasetConfig: ShellQuote("/d/my  data.yaml"),
+		Args:          "name=" + ShellQuote("a  b"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `python train.py --data '/d/my  data.yaml' name='a  b'`, command)
+
+	tmpl, err = parseTrainingCommandTemplate("python train.py {{.Unknown}}")
+	require.NoError(t, err)
+	_, err = renderTrainingCommand(tmpl, TrainingCommandVars{})
+	assert.ErrorIs(t, err, ErrInvalidTrainingTemplate)
+
+	_, err = parseTrainingCommandTemplate("python train.py {{.ModelPath")
+	assert.ErrorIs(t, err, ErrInvalidTrainingTemplate)
+}
+
+func TestBuildDetachedCommandAndParsePID(t *testing.T) {
+	command := buildDetachedCommand("/runs/train_7", "/runs/train_7/train.log", "yolo train model='/w/a.pt'")
+	assert.Equal(t, `mkdir -p '/runs/train_7' && cd '/runs/train_7' && { setsid nohup sh -c 'yolo train model='\''/w/a.pt'\''' > '/runs/train_7/train.log' 2>&1 < /dev/null & echo $!; }`, command)
+
+	pid, err := parseLaunchPID("motd line\n4242\n")
+	require.NoError(t, err)
+	assert.Equal(t, 4242, pid)
+
+	_, err = parseLaunchPID("")
+	assert.ErrorIs(t, err, ErrRemoteCommandFailed)
+	_, err = parseLaunchPID("nohup: not found")
+	assert.ErrorIs(t, err, ErrRemoteCommandFailed)
+}
+
+func TestCurrentTrainingConfigDefaults(t *testing.T) {
+	previous := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = previous })
+
+	config.AppConfig = &config.Config{Training: config.TrainingConfig{RunsRoot: "relative/runs"}}
+	cfg := currentTrainingConfig()
+	assert.Equal(t, DefaultTrainingCommandTemplate, cfg.CommandTemplate)
+	assert.Equal(t, DefaultTrainingRunsRoot, cfg.RunsRoot)
+
+	config.AppConfig = &config.Config{Training: config.TrainingConfig{CommandTemplate: "python train.py", RunsRoot: "/data/runs/"}}
+	cfg = currentTrainingConfig()
+	assert.Equal(t, "python train.py", cfg.CommandTemplate)
+	assert.Equal(t, "/data/runs", cfg.RunsRoot)
+}
+
+func TestTrainingLaunchServiceLaunch(t *testing.T) {
+	newService := func(client *fakeRemoteFileClient, store *fakeTrainingLaunchStore) *TrainingLaunchService {
+		return &TrainingLaunchService{
+			store:       store,
+			pathService: NewArtifactPathService(),
+			transferService: &SSHArtifactTransferService{
+				PathService:       &ArtifactPathService{OtherWeightsRoot: "/w", OtherDatasetsRoot: "/d"},
+				serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "gpu-1", IP: "10.0.0.9", Port: 22}).Lookup,
+				defaultCredential: testSSHCredential(),
+				clientFactory:     &fakeRemoteFileClientFactory{client: client},
+			},
+		}
+	}
+	newClient := func() *fakeRemoteFileClient {
+		return &fakeRemoteFileClient{
+			remoteFiles:    map[string][]byte{"/w/yolo.pt": []byte("w"), "/d/coco.zip": []byte("d")},
+			commandResults: []RemoteCommandResult{{Stdout: "4242\n"}},
+		}
+	}
+	req := LaunchTrainingRequest{ModelID: 1, DatasetID: 2, Args: map[string]interface{}{"epochs": float64(3)}}
+	server := CoreServer{Key: "gpu-1"}
+
+	client, store := newClient(), newFakeTrainingLaunchStore()
+	launch, err := newService(client, store).Launch(context.Background(), req, server)
+	require.NoError(t, err)
+	assert.Equal(t, 4242, launch.Job.PID)
+	assert.Equal(t, "/project/luckyProject/runs/train_1", launch.Job.RunDir)
+	assert.Contains(t, launch.Job.Command, "model='/w/yolo.pt' data='/d/coco.zip'")
+	assert.Equal(t, entity2.TrainingStatusRunning, store.results[1].TrainingStatus)
+	assert.NotNil(t, store.jobs[1])
+	assert.JSONEq(t, `{"epochs":3}`, string(store.hyperparameters[1]))
+	assert.Len(t, client.commands, 1)
+
+	// 进程已启动但登记训练任务失败：结束进程并把训练结果置为失败
+	client, store = newClient(), newFakeTrainingLaunchStore()
+	store.createJobErr = errors.New("db down")
+	_, err = newService(client, store).Launch(context.Background(), req, server)
+	assert.ErrorContains(t, err, "db down")
+	require.Len(t, client.commands, 2)
+	assert.Equal(t, buildKillTrainingCommand(4242), client.commands[1])
+	assert.Equal(t, []uint{1}, store.failed)
+	assert.NotNil(t, store.results[1])
+
+	// 启动命令失败：删除已登记的训练结果
+	client, store = newClient(), newFakeTrainingLaunchStore()
+	client.commandResults = []RemoteCommandResult{{Stderr: "yolo: not found", ExitCode: 127}}
+	_, err = newService(client, store).Launch(context.Background(), req, server)
+	assert.ErrorIs(t, err, ErrRemoteCommandFailed)
+	assert.Empty(t, store.results)
+	assert.Empty(t, store.failed)
+}
+
+type fakeTrainingLaunchStore struct {
+	results         map[uint]*entity2.ModelTrainingResult
+	jobs            map[uint]*entity2.TrainingJob
+	hyperparameters map[uint][]byte
+	failed          []uint
+	createJobErr    error
+	nextID          uint
+}
+
+func newFakeTrainingLaunchStore() *fakeTrainingLaunchStore {
+	return &fakeTrainingLaunchStore{
+		results:         make(map[uint]*entity2.ModelTrainingResult),
+		jobs:            make(map[uint]*entity2.TrainingJob),
+		hyperparameters: make(map[uint][]byte),
+	}
+}
+
+func (f *fakeTrainingLaunchStore) FindModel(ctx context.Context, id uint) (*entity2.Model, error) {
+	return &entity2.Model{ID: id, Name: "yolo", WeightName: "yolo.pt"}, nil
+}
+
+func (f *fakeTrainingLaunchStore) FindDataset(ctx context.Context, id uint) (*entity2.Dataset, error) {
+	return &entity2.Dataset{ID: id, Name: "coco", FileName: "coco.zip", Version: "1.0"}, nil
+}
+
+func (f *fakeTrainingLaunchStore) FindResult(ctx context.Context, id uint) (*entity2.ModelTrainingResult, error) {
+	if result, ok := f.results[id]; ok {
+		return result, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeTrainingLaunchStore) SaveResult(ctx context.Context, result *entity2.ModelTrainingResult) error {
+	f.nextID++
+	result.ID = f.nextID
+	f.results[result.ID] = result
+	return nil
+}
+
+func (f *fakeTrainingLaunchStore) DeleteResult(ctx context.Context, id uint) error {
+	delete(f.results, id)
+	return nil
+}
+
+func (f *fakeTrainingLaunchStore) FailResult(ctx context.Context, id uint, now time.Time) error {
+	f.failed = append(f.failed, id)
+	if result, ok := f.results[id]; ok {
+		result.TrainingStatus = entity2.TrainingStatusFailed
+		result.TrainEndTime = &now
+	}
+	return nil
+}
+
+func (f *fakeTrainingLaunchStore) FindJob(ctx context.Context, id uint) (*entity2.TrainingJob, error) {
+	if job, ok := f.jobs[id]; ok {
+		return job, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeTrainingLaunchStore) CreateJob(ctx context.Context, job *entity2.TrainingJob) error {
+	if f.createJobErr != nil {
+		return f.createJobErr
+	}
+	f.jobs[job.TrainingResultID] = job
+	return nil
+}
+
+func (f *fakeTrainingLaunchStore) UpsertHyperparameters(ctx context.Context, record *entity2.TrainingHyperparameters) error {
+	f.hyperparameters[record.TrainingResultID] = record.Hyperparameters
+	return nil
+}
+
+func TestSSHArtifactTransferServiceRunCommandWithPort(t *testing.T) {
+	client := &fakeRemoteFileClient{commandResults: []RemoteCommandResult{
+		{Stdout: "4242\n"},
+		{Stderr: "permission denied", ExitCode: 1},
+	}}
+	svc := &SSHArtifactTransferService{
+		PathService:       NewArtifactPathService(),
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "gpu-1", IP: "10.0.0.9", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     &fakeRemoteFileClientFactory{client: client},
+	}
+
+	result, err := svc.RunCommandWithPort("echo 4242", "gpu-1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "gpu-1", result.ServerName)
+	assert.Equal(t, "10.0.0.9", result.ServerIP)
+	assert.Equal(t, "4242\n", result.Stdout)
+
+	result, err = svc.RunCommandWithPort("mkdir /root/x", "gpu-1", 0)
+	assert.ErrorIs(t, err, ErrRemoteCommandFailed)
+	assert.Equal(t, 1, result.ExitCode)
+	assert.Equal(t, []string{"echo 4242", "mkdir /root/x"}, client.commands)
+
+	_, err = svc.RunCommandWithPort("  ", "gpu-1", 0)
+	assert.ErrorIs(t, err, ErrRemoteCommandRequired)
+}
+
+func TestSSHArtifactTransferServiceEnsureRemoteArtifactWithPort(t *testing.T) {
+	tmpDir := t.TempDir()
+	pathService := &ArtifactPathService{
+		BackendWeightsRoot:  filepath.Join(tmpDir, "backend", "weights"),
+		BackendDatasetsRoot: filepath.Join(tmpDir, "backend", "datasets"),
+		OtherWeightsRoot:    "/project/luckyProject/weights",
+		OtherDatasetsRoot:   "/project/luckyProject/datasets",
+	}
+	require.NoError(t, os.MkdirAll(pathService.BackendDatasetsRoot, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pathService.BackendDatasetsRoot, "coco.zip"), []byte("dataset"), 0o644))
+
+	client := &fakeRemoteFileClient{remoteFiles: map[string][]byte{
+		"/project/luckyProject/weights/yolo.pt": []byte("weights"),
+	}}
+	svc := &SSHArtifactTransferService{
+		PathService:       pathService,
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "gpu-1", IP: "10.0.0.9", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     &fakeRemoteFileClientFactory{client: client},
+	}
+
+	placement, err := svc.EnsureRemoteArtifactWithPort(ArtifactCategoryWeights, "yolo.pt", "gpu-1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "/project/luckyProject/weights/yolo.pt", placement.RemotePath)
+	assert.False(t, placement.Transferred)
+
+	placement, err = svc.EnsureRemoteArtifactWithPort(ArtifactCategoryDatasets, "coco.zip", "gpu-1", 0)
+	require.NoError(t, err)
+	assert.True(t, placement.Transferred)
+	assert.EqualValues(t, len("dataset"), placement.Bytes)
+	assert.Equal(t, []string{"/project/luckyProject/datasets/coco.zip"}, client.uploadedPaths)
+
+	_, err = svc.EnsureRemoteArtifactWithPort(ArtifactCategoryWeights, "missing.pt", "gpu-1", 0)
+	assert.ErrorIs(t, err, ErrLocalSourceFileNotFound)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/dao"
+	"strings"
+)
+
+const (
+	DefaultTrainingLogTailLines = 100
+	MaxTrainingLogTailLines     = 5000
+
+	// trainingLogMissingExitCode 日志文件不存在时 tail 命令的退出码
+	trainingLogMissingExitCode = 66
+)
+
+var ErrInvalidTrainingLogLines = errors.New("invalid training log lines")
+
+// TrainingLogSource 训练日志所在的核心服务器与路径，来自启动训练时登记的训练任务。
+type TrainingLogSource struct {
+	TrainingResultID uint   `json:"training_result_id"`
+	CoreServerKey    string `json:"core_server_key"`
+	LogPath          string `json:"log_path"`
+	PID              int    `json:"pid"`
+}
+
+// TrainingLogTail GET /v1/training-results/:id/logs 返回结构
+type TrainingLogTail struct {
+	TrainingLogSource
+	Lines []string `json:"lines"`
+}
+
+// TrainingLogService 通过 SSH 读取核心服务器上的训练日志
+type TrainingLogService struct {
+	trainingDAO     *dao.TrainingResultDAO
+	jobDAO          *dao.TrainingJobDAO
+	transferService *SSHArtifactTransferService
+}
+
+func NewTrainingLogService(transferService *SSHArtifactTransferService) *TrainingLogService {
+	return &TrainingLogService{
+		trainingDAO:     dao.NewTrainingResultDAO(),
+		jobDAO:          dao.NewTrainingJobDAO(),
+		transferService: transferService,
+	}
+}
+
+// GetSource 查询训练日志位置；训练结果不存在或不是通过远程启动的训练时返回 gorm.ErrRecordNotFound。
+func (s *TrainingLogService) GetSource(ctx context.Context, id uint) (TrainingLogSource, error) {
+	if _, err := s.trainingDAO.FindByID(ctx, id); err != nil {
+		return TrainingLogSource{}, err
+	}
+	job, err := s.jobDAO.FindByTrainingResultID(ctx, id)
+	if err != nil {
+		return TrainingLogSource{}, err
+	}
+	return TrainingLogSource{
+		TrainingResultID: job.TrainingResultID,
+		CoreServerKey:    job.CoreServerKey,
+		LogPath:          job.LogPath,
+		PID:              job.PID,
+	}, nil
+}
+
+// Tail 读取日志最后 lines 行；以 \r 刷新的进度条行只保留最后一次刷新的内容。
+func (s *TrainingLogService) Tail(source TrainingLogSource, lines, port int) (TrainingLogTail, error) {
+	if s.transferService == nil {
+		return TrainingLogTail{}, ErrSSHClientFactoryNil
+	}
+	if err := ValidateTrainingLogLines(lines); err != nil {
+		return TrainingLogTail{}, err
+	}
+
+	result, err := s.transferService.RunCommandWithPort(buildTrainingLogTailCommand(source.LogPath, lines), source.CoreServerKey, port)
+	if err != nil {
+		if result.ExitCode == trainingLogMissingExitCode {
+			return TrainingLogTail{}, fmt.Errorf("%w: %s", ErrRemoteArtifactNotFound, source.LogPath)
+		}
+		return TrainingLogTail{}, err
+	}
+	return TrainingLogTail{TrainingLogSource: source, Lines: splitTrainingLogLines(result.Stdout)}, nil
+}
+
+// Follow 先输出日志最后 lines 行，再持续跟随新内容，训练进程退出或 ctx 取消后返回。
+// 日志文件尚未创建时会等待其出现。
+func (s *TrainingLogService) Follow(ctx context.Context, source TrainingLogSource, lines, port int, onLine func(line string) error) error {
+	if s.transferService == nil {
+		return ErrSSHClientFactoryNil
+	}
+	if err := ValidateTrainingLogLines(lines); err != nil {
+		return err
+	}
+	return s.transferService.StreamCommandWithPort(ctx, buildTrainingLogFollowCommand(source.LogPath, source.PID, lines), source.CoreServerKey, port, onLine)
+}
+
+// ValidateTrainingLogLines 校验读取行数，0 表示只跟随新内容
+func ValidateTrainingLogLines(lines int) error {
+	if lines < 0 || lines > MaxTrainingLogTailLines {
+		return fmt.Errorf("%w: lines must be between 0 and %d", ErrInvalidTrainingLogLines, MaxTrainingLogTailLines)
+	}
+	return nil
+}
+
+func buildTrainingLogTailCommand(logPath string, lines int) string {
+	quoted := ShellQuote(logPath)
+	return fmt.Sprintf("if [ -f %s ]; then tail -n %d -- %s; else exit %d; fi", quoted, lines, quoted, trainingLogMissingExitCode)
+}
+
+// buildTrainingLogFollowCommand tail -F 在文件重建后继续跟随，--pid 使训练进程退出后 tail 随之结束。
+func buildTrainingLogFollowCommand(logPath string, pid, lines int) string {
+	if pid > 0 {
+		return fmt.Sprintf("tail -n %d -F --pid=%d -- %s 2>/dev/null", lines, pid, ShellQuote(logPath))
+	}
+	return fmt.Sprintf("tail -n %d -F -- %s 2>/dev/null", lines, ShellQuote(logPath))
+}
+
+// splitTrainingLogLines 按 \n 分行，每行只保留最后一个 \r 之后的非空内容（即终端上最终显示的内容）。
+func splitTrainingLogLines(output string) []string {
+	rawLines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	lines := make([]string, 0, len(rawLines))
+	if output == "" {
+		return lines
+	}
+	for _, raw := range rawLines {
+		segments := strings.Split(strings.TrimSuffix(raw, "\r"), "\r")
+		line := segments[len(segments)-1]
+		for i := len(segments) - 1; i >= 0 && line == ""; i-- {
+			line = segments[i]
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
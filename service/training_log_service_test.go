@@ -0,0 +1,132 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTrainingLogService(client *fakeRemoteFileClient) *TrainingLogService {
+	return &TrainingLogService{transferService: &SSHArtifactTransferService{
+		PathService:       NewArtifactPathService(),
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "rtx3090", IP: "10.0.0.9", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     &fakeRemoteFileClientFactory{client: client},
+	}}
+}
+
+func TestBuildTrainingLogCommands(t *testing.T) {
+	assert.Equal(t,
+		`if [ -f '/runs/train_7/train.log' ]; then tail -n 100 -- '/runs/train_7/train.log'; else exit 66; fi`,
+		buildTrainingLogTailCommand("/runs/train_7/train.log", 100),
+	)
+	assert.Equal(t,
+		`tail -n 50 -F --pid=4242 -- '/runs/train_7/train.log' 2>/dev/null`,
+		buildTrainingLogFollowCommand("/runs/train_7/train.log", 4242, 50),
+	)
+	assert.Equal(t,
+		`tail -n 0 -F -- '/runs/it'\''s/train.log' 2>/dev/null`,
+		buildTrainingLogFollowCommand("/runs/it's/train.log", 0, 0),
+	)
+}
+
+func TestSplitTrainingLogLines(t *testing.T) {
+	assert.Empty(t, splitTrainingLogLines(""))
+	assert.Equal(t,
+		[]string{"Epoch 1/100", "  1/100  3.1G  1.2: 100%", "", "done"},
+		splitTrainingLogLines("Epoch 1/100\n  1/100  3.1G  1.2: 10%\r  1/100  3.1G  1.2: 100%\r\n\ndone\n"),
+	)
+}
+
+func TestScanTerminalLines(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("a\r\nb\rc\nd"))
+	scanner.Split(scanTerminalLines)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, []string{"a", "b", "c", "d"}, lines)
+}
+
+func TestTrainingLogServiceTail(t *testing.T) {
+	client := &fakeRemoteFileClient{commandResults: []RemoteCommandResult{
+		{Stdout: "line 1\nline 2\n"},
+		{ExitCode: trainingLogMissingExitCode},
+	}}
+	svc := newTestTrainingLogService(client)
+	source := TrainingLogSource{TrainingResultID: 7, CoreServerKey: "rtx3090", LogPath: "/runs/train_7/train.log", PID: 4242}
+
+	tail, err := svc.Tail(source, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, source, tail.TrainingLogSource)
+	assert.Equal(t, []string{"line 1", "line 2"}, tail.Lines)
+
+	_, err = svc.Tail(source, 2, 0)
+	assert.ErrorIs(t, err, ErrRemoteArtifactNotFound)
+
+	_, err = svc.Tail(source, MaxTrainingLogTailLines+1, 0)
+	assert.ErrorIs(t, err, ErrInvalidTrainingLogLines)
+	assert.Len(t, client.commands, 2)
+}
+
+func TestTrainingLogServiceFollow(t *testing.T) {
+	client := &fakeRemoteFileClient{streamLines: []string{"Epoch 1/2", "Epoch 2/2", "Results saved"}}
+	svc := newTestTrainingLogService(client)
+	source := TrainingLogSource{TrainingResultID: 7, CoreServerKey: "rtx3090", LogPath: "/runs/train_7/train.log", PID: 4242}
+
+	var received []string
+	err := svc.Follow(context.Background(), source, 10, 0, func(line string) error {
+		received = append(received, line)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, client.streamLines, received)
+	assert.Equal(t, []string{`tail -n 10 -F --pid=4242 -- '/runs/train_7/train.log' 2>/dev/null`}, client.commands)
+
+	stop := errors.New("client gone")
+	received = nil
+	err = svc.Follow(context.Background(), source, 10, 0, func(line string) error {
+		received = append(received, line)
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []string{"Epoch 1/2"}, received)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = svc.Follow(ctx, source, 10, 0, func(string) error { return nil })
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTrainingLogServiceIgnoresPriorLaunchCredential(t *testing.T) {
+	client := &fakeRemoteFileClient{commandResults: []RemoteCommandResult{{Stdout: "4242\n"}, {Stdout: "line 1\n"}}}
+	factory := &fakeRemoteFileClientFactory{client: client}
+	transfer := &SSHArtifactTransferService{
+		PathService:       NewArtifactPathService(),
+		serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "rtx3090", IP: "10.0.0.9", Port: 22}).Lookup,
+		defaultCredential: testSSHCredential(),
+		clientFactory:     factory,
+	}
+	svc := &TrainingLogService{transferService: transfer}
+	source := TrainingLogSource{TrainingResultID: 7, CoreServerKey: "rtx3090", LogPath: "/runs/train_7/train.log", PID: 4242}
+
+	// 启动训练时指定的凭据只作用于那一次调用
+	_, err := transfer.RunCommandWithCredential("echo 4242", "rtx3090", 0, SSHCredential{User: "launcher", PrivateKeyPath: "/keys/launch"})
+	require.NoError(t, err)
+	_, err = svc.Tail(source, 10, 0)
+	require.NoError(t, err)
+	require.NoError(t, svc.Follow(context.Background(), source, 10, 0, func(line string) error { return nil }))
+
+	require.Len(t, factory.serverCalls, 3)
+	assert.Equal(t, "/keys/launch", factory.serverCalls[0].PrivateKeyPath)
+	for _, server := range factory.serverCalls[1:] {
+		assert.Equal(t, testSSHCredential().User, server.User)
+		assert.Equal(t, testSSHCredential().PrivateKeyPath, server.PrivateKeyPath)
+	}
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"math"
+	"regexp"
+	"strings"
+)
+
+const (
+	DefaultMetricMaxPoints = 500
+	MaxMetricMaxPoints     = 5000
+	// MaxMetricBatchPoints 单次上报的指标点上限
+	MaxMetricBatchPoints = 5000
+)
+
+var (
+	ErrInvalidMetricPoint = errors.New("invalid metric point")
+	ErrInvalidMaxPoints   = fmt.Errorf("max_points must be between 2 and %d", MaxMetricMaxPoints)
+
+	// 兼容 Ultralytics 的 metrics/mAP50-95(B) 这类名称
+	metricNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:/()\-]{0,127}$`)
+)
+
+// TrainingMetricPoint 上报的单个指标点；step 缺省时取 epoch。
+type TrainingMetricPoint struct {
+	Name  string   `json:"name"`
+	Step  *int64   `json:"step"`
+	Epoch *int     `json:"epoch"`
+	Value *float64 `json:"value"`
+}
+
+// TrainingMetricBatch POST /v1/training-results/:id/metrics 请求体
+type TrainingMetricBatch struct {
+	Points []TrainingMetricPoint `json:"points"`
+}
+
+// TrainingMetricIngestResult 上报结果
+type TrainingMetricIngestResult struct {
+	TrainingResultID uint     `json:"training_result_id"`
+	Accepted         int      `json:"accepted"`
+	Names            []string `json:"names"`
+}
+
+// MetricSeriesPoint 序列中的一个点
+type MetricSeriesPoint struct {
+	Step  int64   `json:"step"`
+	Epoch *int    `json:"epoch,omitempty"`
+	Value float64 `json:"value"`
+}
+
+// MetricSeries 单个指标的序列；Downsampled 表示点数超过 max_points 后做了降采样。
+type MetricSeries struct {
+	Name        string              `json:"name"`
+	TotalPoints int                 `json:"total_points"`
+	Downsampled bool                `json:"downsampled"`
+	Points      []MetricSeriesPoint `json:"points"`
+}
+
+// TrainingMetricSeries GET /v1/training-results/:id/metrics 返回结构
+type TrainingMetricSeries struct {
+	TrainingResultID uint           `json:"training_result_id"`
+	MaxPoints        int            `json:"max_points"`
+	Series           []MetricSeries `json:"series"`
+}
+
+// TrainingMetricService 训练过程指标（loss 曲线等）的上报与查询
+type TrainingMetricService struct {
+	metricDAO   *dao.TrainingMetricDAO
+	trainingDAO *dao.TrainingResultDAO
+}
+
+func NewTrainingMetricService() *TrainingMetricService {
+	return &TrainingMetricService{
+		metricDAO:   dao.NewTrainingMetricDAO(),
+		trainingDAO: dao.NewTrainingResultDAO(),
+	}
+}
+
+// Ingest 批量写入指标点；同一 (name, step) 重复上报时以最后一次为准。已结束的训练不再接受上报。
+func (s *TrainingMetricService) Ingest(ctx context.Context, id uint, batch TrainingMetricBatch) (TrainingMetricIngestResult, error) {
+	metrics, names, err := buildTrainingMetrics(id, batch.Points)
+	if err != nil {
+		return TrainingMetricIngestResult{}, err
+	}
+
+	result, err := s.trainingDAO.FindByID(ctx, id)
+	if err != nil {
+		return TrainingMetricIngestResult{}, err
+	}
+	if IsTerminalTrainingStatus(result.TrainingStatus) {
+		return TrainingMetricIngestResult{}, ErrTrainingResultFinalized
+	}
+
+	if err := s.metricDAO.UpsertBatch(ctx, metrics); err != nil {
+		return TrainingMetricIngestResult{}, err
+	}
+	return TrainingMetricIngestResult{
+		TrainingResultID: id,
+		Accepted:         len(metrics),
+		Names:            names,
+	}, nil
+}
+
+// GetSeries 查询指标序列；names 为空时返回全部指标，每条序列最多 maxPoints 个点（LTTB 降采样，保留首尾与峰谷）。
+func (s *TrainingMetricService) GetSeries(ctx context.Context, id uint, names []string, maxPoints int) (TrainingMetricSeries, error) {
+	if maxPoints == 0 {
+		maxPoints = DefaultMetricMaxPoints
+	}
+	if maxPoints < 2 || maxPoints > MaxMetricMaxPoints {
+		return TrainingMetricSeries{}, ErrInvalidMaxPoints
+	}
+
+	if _, err := s.trainingDAO.FindByID(ctx, id); err != nil {
+		return TrainingMetricSeries{}, err
+	}
+
+	names = splitMetricNames(names)
+	if len(names) == 0 {
+		var err error
+		names, err = s.metricDAO.ListNames(ctx, id)
+		if err != nil {
+			return TrainingMetricSeries{}, err
+		}
+	}
+
+	metrics, err := s.metricDAO.FindSeries(ctx, id, names)
+	if err != nil {
+		return TrainingMetricSeries{}, err
+	}
+	return TrainingMetricSeries{
+		TrainingResultID: id,
+		MaxPoints:        maxPoints,
+		Series:           groupMetricSeries(names, metrics, maxPoints),
+	}, nil
+}
+
+// buildTrainingMetrics 校验上报的点并按 (name, step) 去重，返回涉及的指标名称（按首次出现顺序）。
+func buildTrainingMetrics(id uint, points []TrainingMetricPoint) ([]entity2.TrainingMetric, []string, error) {
+	if len(points) == 0 {
+		return nil, nil, fmt.Errorf("%w: points is required", ErrInvalidMetricPoint)
+	}
+	if len(points) > MaxMetricBatchPoints {
+		return nil, nil, fmt.Errorf("%w: at most %d points per request", ErrInvalidMetricPoint, MaxMetricBatchPoints)
+	}
+
+	type metricKey struct {
+		name string
+		step int64
+	}
+	index := make(map[metricKey]int, len(points))
+	metrics := make([]entity2.TrainingMetric, 0, len(points))
+	names := make([]string, 0)
+	seenNames := make(map[string]struct{})
+	for i, point := range points {
+		name := strings.TrimSpace(point.Name)
+		if !metricNamePattern.MatchString(name) {
+			return nil, nil, fmt.Errorf("%w: points[%d].name %q is invalid", ErrInvalidMetricPoint, i, point.Name)
+		}
+		if point.Value == nil || math.IsNaN(*point.Value) || math.IsInf(*point.Value, 0) {
+			return nil, nil, fmt.Errorf("%w: points[%d].value must be a finite number", ErrInvalidMetricPoint, i)
+		}
+		var step int64
+		switch {
+		case point.Step != nil:
+			step = *point.Step
+		case point.Epoch != nil:
+			step = int64(*point.Epoch)
+		default:
+			return nil, nil, fmt.Errorf("%w: points[%d] requires step or epoch", ErrInvalidMetricPoint, i)
+		}
+		if step < 0 || (point.Epoch != nil && *point.Epoch < 0) {
+			return nil, nil, fmt.Errorf("%w: points[%d] step/epoch must be non-negative", ErrInvalidMetricPoint, i)
+		}
+
+		metric := entity2.TrainingMetric{
+			TrainingResultID: id,
+			Name:             name,
+			Step:             step,
+			Epoch:            point.Epoch,
+			Value:            *point.Value,
+		}
+		key := metricKey{name: name, step: step}
+		if existing, ok := index[key]; ok {
+			metrics[existing] = metric
+			continue
+		}
+		index[key] = len(metrics)
+		metrics = append(metrics, metric)
+		if _, ok := seenNames[name]; !ok {
+			seenNames[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	return metrics, names, nil
+}
+
+// splitMetricNames 支持 names=a,b 与重复传参
+func splitMetricNames(values []string) []string {
+	names := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			if _, ok := seen[item]; ok {
+				continue
+			}
+			seen[item] = struct{}{}
+			names = append(names, item)
+		}
+	}
+	return names
+}
+
+// groupMetricSeries 按请求的名称顺序组装序列，未上报的名称返回空序列。metrics 需按 name、step 排序。
+func groupMetricSeries(names []string, metrics []entity2.TrainingMetric, maxPoints int) []MetricSeries {
+	byName := make(map[string][]MetricSeriesPoint, len(names))
+	for _, metric := range metrics {
+		byName[metric.Name] = append(byName[metric.Name], MetricSeriesPoint{
+			Step:  metric.Step,
+			Epoch: metric.Epoch,
+			Value: metric.Value,
+		})
+	}
+
+	series := make([]MetricSeries, 0, len(names))
+	for _, name := range names {
+		points := byName[name]
+		if points == nil {
+			points = make([]MetricSeriesPoint, 0)
+		}
+		sampled := downsampleLTTB(points, maxPoints)
+		series = append(series, MetricSeries{
+			Name:        name,
+			TotalPoints: len(points),
+			Downsampled: len(sampled) < len(points),
+			Points:      sampled,
+		})
+	}
+	return series
+}
+
+// downsampleLTTB Largest-Triangle-Three-Buckets 降采样：保留首尾点，每个桶选取与相邻桶构成三角形面积最大的点，
+// 曲线形状（尖峰、拐点）比等间隔抽样保留得更好。threshold 需 >= 2。
+func downsampleLTTB(points []MetricSeriesPoint, threshold int) []MetricSeriesPoint {
+	n := len(points)
+	if threshold >= n || threshold < 2 {
+		return points
+	}
+	if threshold == 2 {
+		return []MetricSeriesPoint{points[0], points[n-1]}
+	}
+
+	sampled := make([]MetricSeriesPoint, 0, threshold)
+	sampled = append(sampled, points[0])
+	every := float64(n-2) / float64(threshold-2)
+	a := 0
+	for i := 0; i < threshold-2; i++ {
+		avgStart := int(math.Floor(float64(i+1)*every)) + 1
+		avgEnd := int(math.Floor(float64(i+2)*every)) + 1
+		if avgEnd > n {
+			avgEnd = n
+		}
+		var avgX, avgY float64
+		for j := avgStart; j < avgEnd; j++ {
+			avgX += float64(points[j].Step)
+			avgY += points[j].Value
+		}
+		if count := float64(avgEnd - avgStart); count > 0 {
+			avgX /= count
+			avgY /= count
+		}
+
+		rangeStart := int(math.Floor(float64(i)*every)) + 1
+		rangeEnd := int(math.Floor(float64(i+1)*every)) + 1
+		ax, ay := float64(points[a].Step), points[a].Value
+		maxArea := -1.0
+		next := rangeStart
+		for j := rangeStart; j < rangeEnd; j++ {
+			area := math.Abs((ax-avgX)*(points[j].Value-ay) - (ax-float64(points[j].Step))*(avgY-ay))
+			if area > maxArea {
+				maxArea = area
+				next = j
+			}
+		}
+		sampled = append(sampled, points[next])
+		a = next
+	}
+	return append(sampled, points[n-1])
+}
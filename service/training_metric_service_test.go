@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTrainingMetricsValidatesAndDeduplicates(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+	step := func(v int64) *int64 { return &v }
+	epoch := func(v int) *int { return &v }
+
+	metrics, names, err := buildTrainingMetrics(7, []TrainingMetricPoint{
+		{Name: "train/box_loss", Epoch: epoch(0), Value: value(1.2)},
+		{Name: "metrics/mAP50-95(B)", Step: step(0), Value: value(0.1)},
+		{Name: "train/box_loss", Epoch: epoch(0), Value: value(1.1)},
+		{Name: "train/box_loss", Step: step(100), Epoch: epoch(1), Value: value(0.9)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"train/box_loss", "metrics/mAP50-95(B)"}, names)
+	require.Len(t, metrics, 3)
+	assert.Equal(t, 1.1, metrics[0].Value)
+	assert.Equal(t, uint(7), metrics[0].TrainingResultID)
+	assert.Equal(t, int64(100), metrics[2].Step)
+
+	cases := [][]TrainingMetricPoint{
+		nil,
+		{{Name: "loss", Value: value(1)}},
+		{{Name: "loss", Step: step(-1), Value: value(1)}},
+		{{Name: "bad name", Step: step(0), Value: value(1)}},
+		{{Name: "loss", Step: step(0)}},
+	}
+	for _, points := range cases {
+		_, _, err := buildTrainingMetrics(7, points)
+		assert.True(t, errors.Is(err, ErrInvalidMetricPoint), "points=%v", points)
+	}
+}
+
+func TestSplitMetricNames(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, splitMetricNames([]string{"a, b", "", "a,c"}))
+}
+
+func TestDownsampleLTTB(t *testing.T) {
+	points := make([]MetricSeriesPoint, 0, 100)
+	for i := 0; i < 100; i++ {
+		points = append(points, MetricSeriesPoint{Step: int64(i), Value: 0})
+	}
+	points[42].Value = 10
+
+	sampled := downsampleLTTB(points, 10)
+	require.Len(t, sampled, 10)
+	assert.Equal(t, int64(0), sampled[0].Step)
+	assert.Equal(t, int64(99), sampled[9].Step)
+	assert.Contains(t, sampled, points[42], "spike should survive downsampling")
+	for i := 1; i < len(sampled); i++ {
+		assert.Less(t, sampled[i-1].Step, sampled[i].Step)
+	}
+
+	assert.Len(t, downsampleLTTB(points, 2), 2)
+	assert.Len(t, downsampleLTTB(points, 200), 100)
+}
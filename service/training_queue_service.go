@@ -0,0 +1,1126 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lucky_project/config"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	TrainingQueueStatusQueued      = "queued"
+	TrainingQueueStatusDispatching = "dispatching"
+	TrainingQueueStatusRunning     = "running"
+	TrainingQueueStatusFinished    = "finished"
+	TrainingQueueStatusFailed      = "failed"
+	TrainingQueueStatusCancelled   = "cancelled"
+
+	MaxTrainingQueuePriority = 100
+
+	trainingQueueJobKeyPrefix = "training-queue:job:"
+	trainingQueueJobsKey      = "training-queue:jobs"
+	trainingQueuePendingKey   = "training-queue:pending"
+	trainingQueueActiveKey    = "training-queue:active"
+	trainingQueueSeqKey       = "training-queue:seq"
+	trainingQueueLockKey      = "training-queue:lock"
+	trainingQueueClaimPrefix  = "training-queue:claim:"
+
+	defaultTrainingQueueInterval    = 30 * time.Second
+	defaultTrainingQueueMaxAttempts = 3
+	maxTrainingQueueAttempts        = 10
+	// trainingQueueLeaseTTL 调度锁与派发认领的过期时间，调度期间每 trainingQueueLeaseRenewEvery 续期一次，
+	// 进程异常退出后随之过期。
+	trainingQueueLeaseTTL        = time.Minute
+	trainingQueueLeaseRenewEvery = trainingQueueLeaseTTL / 3
+	// trainingQueueFinishedJobTTL 进入终态的任务保留时长，过期后从 Redis 中删除。
+	trainingQueueFinishedJobTTL   = 7 * 24 * time.Hour
+	maxTrainingQueueDispatchScan  = 200
+	maxTrainingQueueUpdateRetries = 5
+)
+
+var (
+	ErrInvalidTrainingQueueJob        = errors.New("invalid training queue job")
+	ErrTrainingQueueJobNotFound       = errors.New("training queue job not found")
+	ErrTrainingQueueJobNotCancellable = errors.New("training queue job cannot be cancelled")
+	ErrTrainingQueueDispatching       = errors.New("training queue dispatch is already running")
+	ErrTrainingQueueNotReady          = errors.New("training queue service is not ready")
+	errTrainingQueueJobStateChanged   = errors.New("training queue job state changed")
+	releaseTrainingQueueLeaseScript   = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) end return 0`)
+	renewTrainingQueueLeaseScript     = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) end return 0`)
+	trainingQueueValidStatuses        = map[string]struct{}{
+		TrainingQueueStatusQueued:      {},
+		TrainingQueueStatusDispatching: {},
+		TrainingQueueStatusRunning:     {},
+		TrainingQueueStatusFinished:    {},
+		TrainingQueueStatusFailed:      {},
+		TrainingQueueStatusCancelled:   {},
+	}
+)
+
+// EnqueueTrainingJobRequest POST /v1/training-queue 请求体。
+// 启动参数与 POST /v1/training-results/launch 相同，core_server_key 非空时只派发到该服务器。
+type EnqueueTrainingJobRequest struct {
+	LaunchTrainingRequest
+	// Priority 0-100，越大越先派发；同优先级按入队时间先后。
+	Priority int `json:"priority"`
+	// GPUCount 需要的 GPU 数量，默认 1；GPUMemoryMB 需要的显存总量，0 表示不限制。
+	GPUCount    int     `json:"gpu_count"`
+	GPUMemoryMB float64 `json:"gpu_memory_mb"`
+	// MaxAttempts 最大派发次数，默认取 training.queue.max_attempts。
+	MaxAttempts int `json:"max_attempts"`
+}
+
+// QueuedTrainingJob 队列中的训练任务，以 JSON 存放在 Redis。
+type QueuedTrainingJob struct {
+	ID          int64                 `json:"id"`
+	Status      string                `json:"status"`
+	Priority    int                   `json:"priority"`
+	GPUCount    int                   `json:"gpu_count"`
+	GPUMemoryMB float64               `json:"gpu_memory_mb"`
+	Attempts    int                   `json:"attempts"`
+	MaxAttempts int                   `json:"max_attempts"`
+	Request     LaunchTrainingRequest `json:"request"`
+	// CoreServerKey/TrainingResultID/PID 为当前一次派发的目标服务器、训练结果与进程，重新排队时清空。
+	CoreServerKey    string     `json:"core_server_key,omitempty"`
+	TrainingResultID uint       `json:"training_result_id,omitempty"`
+	PID              int        `json:"pid,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+	EnqueuedAt       time.Time  `json:"enqueued_at"`
+	DispatchedAt     *time.Time `json:"dispatched_at,omitempty"`
+	FinishedAt       *time.Time `json:"finished_at,omitempty"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// TrainingQueueDispatch 一次成功派发
+type TrainingQueueDispatch struct {
+	JobID            int64  `json:"job_id"`
+	CoreServerKey    string `json:"core_server_key"`
+	TrainingResultID uint   `json:"training_result_id"`
+}
+
+// TrainingQueueDispatchReport 一轮调度的汇总
+type TrainingQueueDispatchReport struct {
+	StartedAt    time.Time               `json:"started_at"`
+	FinishedAt   time.Time               `json:"finished_at"`
+	Dispatched   []TrainingQueueDispatch `json:"dispatched"`
+	Finished     []int64                 `json:"finished"`
+	Requeued     []int64                 `json:"requeued"`
+	Failed       []int64                 `json:"failed"`
+	PendingCount int64                   `json:"pending_count"`
+}
+
+// trainingLauncher 在指定核心服务器上启动一次训练，默认由 TrainingLaunchService 实现。
+type trainingLauncher interface {
+	Launch(ctx context.Context, req LaunchTrainingRequest, server CoreServer) (TrainingLaunch, error)
+}
+
+// trainingQueueResults 队列读取与推进训练结果状态的操作，默认由 TrainingResultService 实现。
+type trainingQueueResults interface {
+	GetByID(ctx context.Context, id uint) (*entity2.ModelTrainingResult, error)
+	ApplyTrainingAction(ctx context.Context, id uint, action string, req TrainingActionRequest) (*entity2.ModelTrainingResult, error)
+}
+
+// TrainingQueueService 基于 Redis 的训练任务队列。
+// 调度时按优先级遍历排队任务，结合核心服务器心跳上报的 GPU 数量/显存与已派发任务的占用，
+// 选择剩余资源最少但足够的在线服务器启动训练；运行中的训练进程退出或主机离线时回收其资源。
+// 多个后端实例通过续期的 Redis 锁保证同一时间只有一个实例在调度，每个派发中的任务另有认领键。
+type TrainingQueueService struct {
+	Enabled     bool
+	Interval    time.Duration
+	MaxAttempts int
+
+	launchService   trainingLauncher
+	trainingService trainingQueueResults
+	modelDAO        *dao.ModelDAO
+	datasetDAO      *dao.DatasetDAO
+	transferService *SSHArtifactTransferService
+	now             func() time.Time
+
+	startOnce sync.Once
+}
+
+var (
+	defaultTrainingQueueService     *TrainingQueueService
+	defaultTrainingQueueServiceOnce sync.Once
+)
+
+// DefaultTrainingQueueService 进程内共享的队列服务，定时调度与管理接口共用。
+func DefaultTrainingQueueService() *TrainingQueueService {
+	defaultTrainingQueueServiceOnce.Do(func() {
+		defaultTrainingQueueService = NewTrainingQueueServiceFromConfig()
+	})
+	return defaultTrainingQueueService
+}
+
+func NewTrainingQueueServiceFromConfig() *TrainingQueueService {
+	var cfg config.TrainingQueueConfig
+	if config.AppConfig != nil {
+		cfg = config.AppConfig.Training.Queue
+	}
+
+	transferService := NewSSHArtifactTransferService()
+	return &TrainingQueueService{
+		Enabled:         cfg.Enabled,
+		Interval:        time.Duration(cfg.IntervalSeconds) * time.Second,
+		MaxAttempts:     cfg.MaxAttempts,
+		launchService:   NewTrainingLaunchService(transferService),
+		trainingService: NewTrainingResultService(),
+		modelDAO:        dao.NewModelDAO(),
+		datasetDAO:      dao.NewDatasetDAO(),
+		transferService: transferService,
+	}
+}
+
+// Start 启用时在后台按间隔调度（启动后立即执行一次），ctx 取消后停止；重复调用无效。
+func (s *TrainingQueueService) Start(ctx context.Context) {
+	if s == nil || !s.Enabled {
+		return
+	}
+	s.startOnce.Do(func() {
+		go s.loop(ctx)
+	})
+}
+
+func (s *TrainingQueueService) loop(ctx context.Context) {
+	logger := serviceLogger().With("service", "TrainingQueueService", "method", "loop")
+	interval := s.interval()
+	logger.Info("training queue scheduler started", "interval", interval.String())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && !errors.Is(err, ErrTrainingQueueDispatching) {
+			logger.Error("training queue dispatch failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("training queue scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Enqueue 校验请求并加入队列。模型、数据集与指定的核心服务器必须存在。
+func (s *TrainingQueueService) Enqueue(ctx context.Context, req EnqueueTrainingJobRequest) (QueuedTrainingJob, error) {
+	logger := serviceLogger().With("service", "TrainingQueueService", "method", "Enqueue")
+	normalized, err := normalizeEnqueueTrainingJobRequest(req, s.maxAttempts())
+	if err != nil {
+		return QueuedTrainingJob{}, err
+	}
+	if config.RedisClient == nil {
+		return QueuedTrainingJob{}, ErrRedisNotInitialized
+	}
+	if _, err := s.modelDAO.FindByID(ctx, normalized.ModelID); err != nil {
+		return QueuedTrainingJob{}, err
+	}
+	if _, err := s.datasetDAO.FindByID(ctx, normalized.DatasetID); err != nil {
+		return QueuedTrainingJob{}, err
+	}
+	if normalized.CoreServerKey != "" {
+		server, err := GetCoreServerByKey(ctx, normalized.CoreServerKey)
+		if err != nil {
+			return QueuedTrainingJob{}, err
+		}
+		if credentialID := strings.TrimSpace(normalized.CredentialID); server.CredentialID != "" && credentialID != "" && credentialID != server.CredentialID {
+			return QueuedTrainingJob{}, fmt.Errorf("%w: %s", ErrSSHCredentialOverrideForbidden, server.Key)
+		}
+	}
+
+	id, err := config.RedisClient.Incr(ctx, trainingQueueSeqKey).Result()
+	if err != nil {
+		return QueuedTrainingJob{}, fmt.Errorf("incr %s failed: %w", trainingQueueSeqKey, err)
+	}
+	now := s.clock()
+	job := QueuedTrainingJob{
+		ID:          id,
+		Status:      TrainingQueueStatusQueued,
+		Priority:    normalized.Priority,
+		GPUCount:    normalized.GPUCount,
+		GPUMemoryMB: normalized.GPUMemoryMB,
+		MaxAttempts: normalized.MaxAttempts,
+		Request:     normalized.LaunchTrainingRequest,
+		EnqueuedAt:  now,
+		UpdatedAt:   now,
+	}
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return QueuedTrainingJob{}, fmt.Errorf("encode training queue job failed: %w", err)
+	}
+	_, err = config.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, trainingQueueJobKey(id), payload, 0)
+		pipe.ZAdd(ctx, trainingQueueJobsKey, redis.Z{Score: float64(id), Member: id})
+		syncTrainingQueueIndexes(ctx, pipe, job)
+		return nil
+	})
+	if err != nil {
+		return QueuedTrainingJob{}, fmt.Errorf("write training queue job failed: %w", err)
+	}
+
+	logger.Info("enqueue training job success", "job_id", id, "priority", job.Priority, "gpu_count", job.GPUCount, "gpu_memory_mb", job.GPUMemoryMB, "core_server_key", job.Request.CoreServerKey)
+	return job, nil
+}
+
+// Get 查询单个队列任务
+func (s *TrainingQueueService) Get(ctx context.Context, id int64) (QueuedTrainingJob, error) {
+	if config.RedisClient == nil {
+		return QueuedTrainingJob{}, ErrRedisNotInitialized
+	}
+	return readTrainingQueueJob(ctx, config.RedisClient, id)
+}
+
+// List 按入队先后倒序列出队列任务，status 非空时只返回该状态的任务。
+func (s *TrainingQueueService) List(ctx context.Context, status string) ([]QueuedTrainingJob, error) {
+	if config.RedisClient == nil {
+		return nil, ErrRedisNotInitialized
+	}
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status != "" {
+		if _, ok := trainingQueueValidStatuses[status]; !ok {
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidTrainingQueueJob, status)
+		}
+	}
+
+	ids, err := config.RedisClient.ZRevRange(ctx, trainingQueueJobsKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("zrevrange %s failed: %w", trainingQueueJobsKey, err)
+	}
+	jobs, err := s.loadJobs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	s.pruneExpiredJobs(ctx, ids, jobs)
+	result := make([]QueuedTrainingJob, 0, len(jobs))
+	for _, job := range jobs {
+		if status == "" || job.Status == status {
+			result = append(result, job)
+		}
+	}
+	return result, nil
+}
+
+// Cancel 取消排队中、派发中或运行中的任务。运行中的任务会结束核心服务器上的训练进程并将训练结果置为中断；
+// 派发中的任务由派发方在启动完成后发现已取消，再结束刚启动的进程。已结束的任务不能取消。
+func (s *TrainingQueueService) Cancel(ctx context.Context, id int64) (QueuedTrainingJob, error) {
+	logger := serviceLogger().With("service", "TrainingQueueService", "method", "Cancel")
+	if config.RedisClient == nil {
+		return QueuedTrainingJob{}, ErrRedisNotInitialized
+	}
+
+	var previous QueuedTrainingJob
+	job, err := s.updateJob(ctx, id, func(job *QueuedTrainingJob) error {
+		if isTerminalTrainingQueueStatus(job.Status) {
+			return fmt.Errorf("%w: job is %s", ErrTrainingQueueJobNotCancellable, job.Status)
+		}
+		previous = *job
+		finishedAt := s.clock()
+		job.Status = TrainingQueueStatusCancelled
+		job.FinishedAt = &finishedAt
+		return nil
+	})
+	if err != nil {
+		return QueuedTrainingJob{}, err
+	}
+
+	if previous.Status == TrainingQueueStatusRunning {
+		if stopErr := s.stopRunningJob(ctx, previous); stopErr != nil {
+			logger.Warn("stop cancelled training failed", "job_id", id, "core_server_key", previous.CoreServerKey, "pid", previous.PID, "error", stopErr)
+			if updated, err := s.updateJob(ctx, id, func(job *QueuedTrainingJob) error {
+				job.LastError = "stop training process failed: " + stopErr.Error()
+				return nil
+			}); err == nil {
+				job = updated
+			}
+		}
+	}
+	logger.Info("cancel training job success", "job_id", id, "previous_status", previous.Status)
+	return job, nil
+}
+
+// RunOnce 执行一轮调度：先回收已结束或主机离线的任务，再按优先级把排队任务派发到有空闲资源的核心服务器。
+// 其他实例正在调度时返回 ErrTrainingQueueDispatching。优先级较高但暂时放不下的任务不会阻塞后面较小的任务。
+func (s *TrainingQueueService) RunOnce(ctx context.Context) (TrainingQueueDispatchReport, error) {
+	logger := serviceLogger().With("service", "TrainingQueueService", "method", "RunOnce")
+	if s == nil || s.launchService == nil || s.transferService == nil {
+		return TrainingQueueDispatchReport{}, ErrTrainingQueueNotReady
+	}
+	if config.RedisClient == nil {
+		return TrainingQueueDispatchReport{}, ErrRedisNotInitialized
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// 派发途中调用方断开不应让任务停在半途
+	ctx = context.WithoutCancel(ctx)
+
+	lease := newTrainingQueueLease(fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano()))
+	locked, err := lease.acquire(ctx, trainingQueueLockKey)
+	if err != nil {
+		return TrainingQueueDispatchReport{}, fmt.Errorf("acquire training queue lock failed: %w", err)
+	}
+	if !locked {
+		return TrainingQueueDispatchReport{}, ErrTrainingQueueDispatching
+	}
+	// 派发涉及 SSH 传输，可能超过租约时长，调度期间持续续期锁与派发认领
+	stopRenew := lease.keepAlive(ctx)
+	defer func() {
+		stopRenew()
+		lease.release(ctx, trainingQueueLockKey)
+	}()
+
+	report := TrainingQueueDispatchReport{
+		StartedAt:  s.clock(),
+		Dispatched: make([]TrainingQueueDispatch, 0),
+		Finished:   make([]int64, 0),
+		Requeued:   make([]int64, 0),
+		Failed:     make([]int64, 0),
+	}
+
+	servers, err := ListCoreServers(ctx)
+	if err != nil {
+		return report, err
+	}
+	activeIDs, err := config.RedisClient.SMembers(ctx, trainingQueueActiveKey).Result()
+	if err != nil {
+		return report, fmt.Errorf("smembers %s failed: %w", trainingQueueActiveKey, err)
+	}
+	activeJobs, err := s.loadJobs(ctx, activeIDs)
+	if err != nil {
+		return report, err
+	}
+	active := s.reconcile(ctx, activeJobs, servers, &report)
+
+	pendingIDs, err := config.RedisClient.ZRange(ctx, trainingQueuePendingKey, 0, maxTrainingQueueDispatchScan-1).Result()
+	if err != nil {
+		return report, fmt.Errorf("zrange %s failed: %w", trainingQueuePendingKey, err)
+	}
+	pendingJobs, err := s.loadJobs(ctx, pendingIDs)
+	if err != nil {
+		return report, err
+	}
+
+	capacities := buildCoreServerCapacities(servers, active)
+	for _, job := range pendingJobs {
+		capacity := selectCoreServer(capacities, job)
+		if capacity == nil {
+			continue
+		}
+		capacity.reserve(job)
+		if dispatched, err := s.dispatch(ctx, lease, job, capacity.server, &report); !dispatched {
+			capacity.release(job)
+			// 启动失败的服务器本轮不再使用，避免连续失败
+			if err != nil && isRetryableTrainingLaunchError(err) {
+				capacity.excluded = true
+			}
+		}
+	}
+
+	if report.PendingCount, err = config.RedisClient.ZCard(ctx, trainingQueuePendingKey).Result(); err != nil {
+		logger.Warn("count pending training jobs failed", "error", err)
+	}
+	report.FinishedAt = s.clock()
+	logger.Info(
+		"training queue dispatch finished",
+		"dispatched", len(report.Dispatched),
+		"finished", len(report.Finished),
+		"requeued", len(report.Requeued),
+		"failed", len(report.Failed),
+		"pending", report.PendingCount,
+	)
+	return report, nil
+}
+
+// dispatch 认领排队任务并在 server 上启动训练。任务已被取消或认领时返回 false；启动失败时按错误类型重新排队或置为失败。
+// 派发期间持有该任务的认领键，reconcile 据此区分仍在派发与派发方已退出的任务。
+// 启动完成时任务已不在 dispatching（派发期间被取消）或无法记录时，结束刚启动的进程并将训练结果置为中断。
+func (s *TrainingQueueService) dispatch(ctx context.Context, lease *trainingQueueLease, job QueuedTrainingJob, server CoreServer, report *TrainingQueueDispatchReport) (bool, error) {
+	logger := serviceLogger().With("service", "TrainingQueueService", "method", "dispatch")
+	claimKey := trainingQueueClaimKey(job.ID)
+	owned, err := lease.acquire(ctx, claimKey)
+	if err != nil {
+		logger.Error("acquire training job claim failed", "job_id", job.ID, "error", err)
+		return false, err
+	}
+	if !owned {
+		return false, nil
+	}
+	defer lease.release(ctx, claimKey)
+
+	claimed, err := s.updateJob(ctx, job.ID, func(current *QueuedTrainingJob) error {
+		if current.Status != TrainingQueueStatusQueued {
+			return errTrainingQueueJobStateChanged
+		}
+		dispatchedAt := s.clock()
+		current.Status = TrainingQueueStatusDispatching
+		current.Attempts++
+		current.CoreServerKey = server.Key
+		current.DispatchedAt = &dispatchedAt
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errTrainingQueueJobStateChanged) || errors.Is(err, ErrTrainingQueueJobNotFound) {
+			return false, nil
+		}
+		logger.Error("claim training job failed", "job_id", job.ID, "error", err)
+		return false, err
+	}
+
+	launch, launchErr := s.launch(ctx, claimed, server)
+	if launchErr != nil {
+		logger.Error("launch queued training failed", "job_id", job.ID, "core_server_key", server.Key, "attempts", claimed.Attempts, "error", launchErr)
+		retryable := isRetryableTrainingLaunchError(launchErr)
+		updated, err := s.updateJob(ctx, job.ID, func(current *QueuedTrainingJob) error {
+			if current.Status != TrainingQueueStatusDispatching {
+				return errTrainingQueueJobStateChanged
+			}
+			applyTrainingQueueRetry(current, launchErr.Error(), retryable, s.clock())
+			return nil
+		})
+		switch {
+		case errors.Is(err, errTrainingQueueJobStateChanged):
+			// 派发期间已被取消，无需再记录
+		case err != nil:
+			logger.Error("record training launch failure failed", "job_id", job.ID, "error", err)
+		default:
+			appendTrainingQueueOutcome(report, updated)
+		}
+		return false, launchErr
+	}
+
+	launched := claimed
+	launched.TrainingResultID = launch.Result.ID
+	launched.PID = launch.Job.PID
+	_, err = s.updateJob(ctx, job.ID, func(current *QueuedTrainingJob) error {
+		if current.Status != TrainingQueueStatusDispatching {
+			return errTrainingQueueJobStateChanged
+		}
+		current.Status = TrainingQueueStatusRunning
+		current.TrainingResultID = launch.Result.ID
+		current.PID = launch.Job.PID
+		current.LastError = ""
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errTrainingQueueJobStateChanged) {
+			logger.Info("training job left dispatching during launch, stopping launched process", "job_id", job.ID, "training_result_id", launch.Result.ID, "pid", launch.Job.PID)
+		} else {
+			logger.Error("record dispatched training job failed, stopping launched process", "job_id", job.ID, "training_result_id", launch.Result.ID, "error", err)
+		}
+		if stopErr := s.stopRunningJob(ctx, launched); stopErr != nil {
+			logger.Error("stop launched training failed", "job_id", job.ID, "core_server_key", server.Key, "pid", launch.Job.PID, "error", stopErr)
+		}
+		if errors.Is(err, errTrainingQueueJobStateChanged) {
+			return false, nil
+		}
+		return false, err
+	}
+	logger.Info("training job dispatched", "job_id", job.ID, "core_server_key", server.Key, "training_result_id", launch.Result.ID, "pid", launch.Job.PID)
+	report.Dispatched = append(report.Dispatched, TrainingQueueDispatch{
+		JobID:            job.ID,
+		CoreServerKey:    server.Key,
+		TrainingResultID: launch.Result.ID,
+	})
+	return true, nil
+}
+
+func (s *TrainingQueueService) launch(ctx context.Context, job QueuedTrainingJob, server CoreServer) (TrainingLaunch, error) {
+	req := job.Request
+	req.CoreServerKey = server.Key
+	return s.launchService.Launch(ctx, req, server)
+}
+
+// reconcile 回收已派发的任务，返回仍占用资源的任务：
+//   - 训练结果已结束的任务置为 finished；训练结果已删除或为成功以外的终态时先结束训练进程，确认结束后才释放 GPU；
+//   - 训练进程已退出但训练结果仍为 running 的任务，训练结果与任务均置为失败；
+//   - 所在主机离线时先结束训练进程，成功后中断训练并重新排队，结束失败则保留到下一轮重试，避免同一训练跑两份；
+//     服务器已从注册表移除时无法再连接，直接中断并重新排队；
+//   - dispatching 任务的认领键已过期（派发方已退出）时重新排队，认领仍在时视为仍在派发。
+func (s *TrainingQueueService) reconcile(ctx context.Context, jobs []QueuedTrainingJob, servers []CoreServer, report *TrainingQueueDispatchReport) []QueuedTrainingJob {
+	logger := serviceLogger().With("service", "TrainingQueueService", "method", "reconcile")
+	serverByKey := make(map[string]CoreServer, len(servers))
+	for _, server := range servers {
+		serverByKey[server.Key] = server
+	}
+
+	active := make([]QueuedTrainingJob, 0, len(jobs))
+	for _, job := range jobs {
+		switch job.Status {
+		case TrainingQueueStatusRunning:
+			if s.reconcileRunning(ctx, job, serverByKey, report) {
+				active = append(active, job)
+			}
+		case TrainingQueueStatusDispatching:
+			claimed, err := config.RedisClient.Exists(ctx, trainingQueueClaimKey(job.ID)).Result()
+			if err != nil {
+				logger.Error("check training job claim failed", "job_id", job.ID, "error", err)
+				active = append(active, job)
+				continue
+			}
+			if claimed > 0 {
+				active = append(active, job)
+				continue
+			}
+			s.transition(ctx, job, report, func(current *QueuedTrainingJob) {
+				applyTrainingQueueRetry(current, "dispatch did not complete", true, s.clock())
+			})
+		default:
+			if err := config.RedisClient.SRem(ctx, trainingQueueActiveKey, job.ID).Err(); err != nil {
+				logger.Warn("remove stale active training job failed", "job_id", job.ID, "error", err)
+			}
+		}
+	}
+	return active
+}
+
+// reconcileRunning 处理一个 running 任务，返回其是否仍占用资源。
+func (s *TrainingQueueService) reconcileRunning(ctx context.Context, job QueuedTrainingJob, serverByKey map[string]CoreServer, report *TrainingQueueDispatchReport) bool {
+	logger := serviceLogger().With("service", "TrainingQueueService", "method", "reconcileRunning")
+	result, err := s.trainingService.GetByID(ctx, job.TrainingResultID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Error("load training result failed", "job_id", job.ID, "training_result_id", job.TrainingResultID, "error", err)
+		return true
+	}
+	server, registered := serverByKey[job.CoreServerKey]
+	if err != nil || result.TrainingStatus != entity2.TrainingStatusRunning {
+		// 训练结果被删除或经 interrupt/fail 置为终态时训练进程可能仍在运行：先结束进程组，
+		// 确认结束（或进程已不存在）后才释放 GPU，否则保留到下一轮重试；已移除的服务器无法再连接，直接释放。
+		if (err != nil || result.TrainingStatus != entity2.TrainingStatusSuccess) && registered && !s.stopOrphanedProcess(job) {
+			return true
+		}
+		s.markJobFinished(ctx, job, report)
+		return false
+	}
+
+	if !registered {
+		logger.Warn("core server of running training was unregistered", "job_id", job.ID, "core_server_key", job.CoreServerKey)
+		s.interruptTrainingResult(ctx, job.TrainingResultID)
+		s.transition(ctx, job, report, func(current *QueuedTrainingJob) {
+			applyTrainingQueueRetry(current, fmt.Sprintf("core server %s was unregistered", job.CoreServerKey), true, s.clock())
+		})
+		return false
+	}
+	if server.Status == CoreServerStatusOffline {
+		if err := s.killTrainingProcess(job); err != nil {
+			logger.Warn("stop training on offline core server failed, will retry", "job_id", job.ID, "core_server_key", job.CoreServerKey, "pid", job.PID, "error", err)
+			return true
+		}
+		logger.Warn("core server of running training is offline, requeue", "job_id", job.ID, "core_server_key", job.CoreServerKey, "pid", job.PID)
+		s.interruptTrainingResult(ctx, job.TrainingResultID)
+		s.transition(ctx, job, report, func(current *QueuedTrainingJob) {
+			applyTrainingQueueRetry(current, fmt.Sprintf("core server %s went offline", job.CoreServerKey), true, s.clock())
+		})
+		return false
+	}
+
+	alive, err := s.trainingProcessAlive(job)
+	if err != nil {
+		logger.Warn("check training process failed", "job_id", job.ID, "core_server_key", job.CoreServerKey, "pid", job.PID, "error", err)
+		return true
+	}
+	if alive {
+		return true
+	}
+	logger.Warn("training process exited without reporting a result", "job_id", job.ID, "core_server_key", job.CoreServerKey, "pid", job.PID, "training_result_id", job.TrainingResultID)
+	if _, err := s.trainingService.ApplyTrainingAction(ctx, job.TrainingResultID, TrainingActionFail, TrainingActionRequest{}); err != nil {
+		switch {
+		case errors.Is(err, ErrIllegalTrainingTransition), errors.Is(err, gorm.ErrRecordNotFound):
+			// 进程退出前已上报结果，按正常结束处理
+			s.markJobFinished(ctx, job, report)
+			return false
+		default:
+			logger.Error("fail training result failed", "job_id", job.ID, "training_result_id", job.TrainingResultID, "error", err)
+			return true
+		}
+	}
+	s.transition(ctx, job, report, func(current *QueuedTrainingJob) {
+		finishedAt := s.clock()
+		current.Status = TrainingQueueStatusFailed
+		current.FinishedAt = &finishedAt
+		current.LastError = fmt.Sprintf("training process %d exited without reporting a result", job.PID)
+	})
+	return false
+}
+
+func (s *TrainingQueueService) markJobFinished(ctx context.Context, job QueuedTrainingJob, report *TrainingQueueDispatchReport) {
+	s.transition(ctx, job, report, func(current *QueuedTrainingJob) {
+		finishedAt := s.clock()
+		current.Status = TrainingQueueStatusFinished
+		current.FinishedAt = &finishedAt
+	})
+}
+
+// transition 在任务状态未被其他请求改变时应用 mutate，并把结果计入报告。
+func (s *TrainingQueueService) transition(ctx context.Context, job QueuedTrainingJob, report *TrainingQueueDispatchReport, mutate func(current *QueuedTrainingJob)) {
+	updated, err := s.updateJob(ctx, job.ID, func(current *QueuedTrainingJob) error {
+		if current.Status != job.Status {
+			return errTrainingQueueJobStateChanged
+		}
+		mutate(current)
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, errTrainingQueueJobStateChanged) {
+			serviceLogger().Error("update training queue job failed", "job_id", job.ID, "error", err)
+		}
+		return
+	}
+	appendTrainingQueueOutcome(report, updated)
+}
+
+// stopRunningJob 结束核心服务器上的训练进程组并将训练结果置为中断。
+func (s *TrainingQueueService) stopRunningJob(ctx context.Context, job QueuedTrainingJob) error {
+	stopErr := s.killTrainingProcess(job)
+	s.interruptTrainingResult(ctx, job.TrainingResultID)
+	return stopErr
+}
+
+// killTrainingProcess 通过 SSH 结束任务的训练进程组，进程已不存在时视为成功。
+func (s *TrainingQueueService) killTrainingProcess(job QueuedTrainingJob) error {
+	if job.PID <= 0 || job.CoreServerKey == "" {
+		return nil
+	}
+	_, err := s.transferService.RunCommandWithCredential(buildKillTrainingCommand(job.PID), job.CoreServerKey, 0, job.Request.SSHCredential())
+	return err
+}
+
+// stopOrphanedProcess 结束训练结果已不再 running 的任务进程组，返回进程是否已确认结束。
+// kill 失败时再探测一次存活，进程已不存在同样视为结束。
+func (s *TrainingQueueService) stopOrphanedProcess(job QueuedTrainingJob) bool {
+	logger := serviceLogger().With("service", "TrainingQueueService", "method", "stopOrphanedProcess")
+	killErr := s.killTrainingProcess(job)
+	if killErr == nil {
+		logger.Info("stopped training process of finished result", "job_id", job.ID, "core_server_key", job.CoreServerKey, "pid", job.PID, "training_result_id", job.TrainingResultID)
+		return true
+	}
+	alive, err := s.trainingProcessAlive(job)
+	if err == nil && !alive {
+		return true
+	}
+	logger.Warn("stop training process of finished result failed, keep gpu reserved and retry", "job_id", job.ID, "core_server_key", job.CoreServerKey, "pid", job.PID, "kill_error", killErr, "probe_error", err)
+	return false
+}
+
+// trainingProcessAlive 通过 SSH 执行 kill -0 检查任务的训练进程是否仍存在。
+func (s *TrainingQueueService) trainingProcessAlive(job QueuedTrainingJob) (bool, error) {
+	if job.PID <= 0 {
+		return false, nil
+	}
+	output, err := s.transferService.RunCommandWithCredential(buildTrainingAliveCommand(job.PID), job.CoreServerKey, 0, job.Request.SSHCredential())
+	if err != nil {
+		return false, err
+	}
+	switch fields := strings.Fields(output.Stdout); {
+	case len(fields) > 0 && fields[len(fields)-1] == "alive":
+		return true, nil
+	case len(fields) > 0 && fields[len(fields)-1] == "dead":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: unexpected liveness output %q", ErrRemoteCommandFailed, strings.TrimSpace(output.Stdout))
+	}
+}
+
+func (s *TrainingQueueService) interruptTrainingResult(ctx context.Context, id uint) {
+	if id == 0 {
+		return
+	}
+	if _, err := s.trainingService.ApplyTrainingAction(ctx, id, TrainingActionInterrupt, TrainingActionRequest{}); err != nil &&
+		!errors.Is(err, ErrIllegalTrainingTransition) && !errors.Is(err, gorm.ErrRecordNotFound) {
+		serviceLogger().Error("interrupt training result failed", "training_result_id", id, "error", err)
+	}
+}
+
+// updateJob 以 WATCH 乐观锁读取-修改-写回任务，并按新状态维护 pending/active 索引；
+// 进入终态的任务设置 trainingQueueFinishedJobTTL 过期。
+func (s *TrainingQueueService) updateJob(ctx context.Context, id int64, mutate func(job *QueuedTrainingJob) error) (QueuedTrainingJob, error) {
+	key := trainingQueueJobKey(id)
+	var updated QueuedTrainingJob
+	for attempt := 0; attempt < maxTrainingQueueUpdateRetries; attempt++ {
+		err := config.RedisClient.Watch(ctx, func(tx *redis.Tx) error {
+			job, err := readTrainingQueueJob(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if err := mutate(&job); err != nil {
+				return err
+			}
+			job.UpdatedAt = s.clock()
+			payload, err := json.Marshal(job)
+			if err != nil {
+				return fmt.Errorf("encode training queue job failed: %w", err)
+			}
+			var ttl time.Duration
+			if isTerminalTrainingQueueStatus(job.Status) {
+				ttl = trainingQueueFinishedJobTTL
+			}
+			if _, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, payload, ttl)
+				syncTrainingQueueIndexes(ctx, pipe, job)
+				return nil
+			}); err != nil {
+				return err
+			}
+			updated = job
+			return nil
+		}, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		return updated, err
+	}
+	return QueuedTrainingJob{}, fmt.Errorf("update training queue job %d failed: too many concurrent updates", id)
+}
+
+func (s *TrainingQueueService) loadJobs(ctx context.Context, ids []string) ([]QueuedTrainingJob, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, trainingQueueJobKeyPrefix+id)
+	}
+	values, err := config.RedisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("mget training queue jobs failed: %w", err)
+	}
+
+	jobs := make([]QueuedTrainingJob, 0, len(values))
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var job QueuedTrainingJob
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			serviceLogger().Warn("skip unreadable training queue job", "key", keys[i], "error", err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// pruneExpiredJobs 从任务列表索引中移除已过期删除的任务。
+func (s *TrainingQueueService) pruneExpiredJobs(ctx context.Context, ids []string, jobs []QueuedTrainingJob) {
+	if len(ids) == len(jobs) {
+		return
+	}
+	loaded := make(map[string]struct{}, len(jobs))
+	for _, job := range jobs {
+		loaded[strconv.FormatInt(job.ID, 10)] = struct{}{}
+	}
+	expired := make([]interface{}, 0, len(ids)-len(jobs))
+	for _, id := range ids {
+		if _, ok := loaded[id]; !ok {
+			expired = append(expired, id)
+		}
+	}
+	if err := config.RedisClient.ZRem(ctx, trainingQueueJobsKey, expired...).Err(); err != nil {
+		serviceLogger().Warn("prune expired training queue jobs failed", "error", err)
+	}
+}
+
+func (s *TrainingQueueService) interval() time.Duration {
+	if s.Interval > 0 {
+		return s.Interval
+	}
+	return defaultTrainingQueueInterval
+}
+
+func (s *TrainingQueueService) maxAttempts() int {
+	if s.MaxAttempts > 0 {
+		return s.MaxAttempts
+	}
+	return defaultTrainingQueueMaxAttempts
+}
+
+func (s *TrainingQueueService) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+func trainingQueueJobKey(id int64) string {
+	return trainingQueueJobKeyPrefix + strconv.FormatInt(id, 10)
+}
+
+func trainingQueueClaimKey(id int64) string {
+	return trainingQueueClaimPrefix + strconv.FormatInt(id, 10)
+}
+
+func isTerminalTrainingQueueStatus(status string) bool {
+	switch status {
+	case TrainingQueueStatusFinished, TrainingQueueStatusFailed, TrainingQueueStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+func readTrainingQueueJob(ctx context.Context, client redis.Cmdable, id int64) (QueuedTrainingJob, error) {
+	raw, err := client.Get(ctx, trainingQueueJobKey(id)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return QueuedTrainingJob{}, ErrTrainingQueueJobNotFound
+		}
+		return QueuedTrainingJob{}, fmt.Errorf("get training queue job %d failed: %w", id, err)
+	}
+	var job QueuedTrainingJob
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		return QueuedTrainingJob{}, fmt.Errorf("parse training queue job %d failed: %w", id, err)
+	}
+	return job, nil
+}
+
+// syncTrainingQueueIndexes queued 任务进入 pending 有序集合，dispatching/running 任务进入 active 集合，其余状态两处都移除。
+func syncTrainingQueueIndexes(ctx context.Context, pipe redis.Pipeliner, job QueuedTrainingJob) {
+	switch job.Status {
+	case TrainingQueueStatusQueued:
+		pipe.ZAdd(ctx, trainingQueuePendingKey, redis.Z{Score: trainingQueueScore(job.Priority, job.EnqueuedAt), Member: job.ID})
+		pipe.SRem(ctx, trainingQueueActiveKey, job.ID)
+	case TrainingQueueStatusDispatching, TrainingQueueStatusRunning:
+		pipe.ZRem(ctx, trainingQueuePendingKey, job.ID)
+		pipe.SAdd(ctx, trainingQueueActiveKey, job.ID)
+	default:
+		pipe.ZRem(ctx, trainingQueuePendingKey, job.ID)
+		pipe.SRem(ctx, trainingQueueActiveKey, job.ID)
+	}
+}
+
+// trainingQueueScore 分数越小越先派发：优先级高的在前，同优先级按入队时间先后。
+// 重新排队的任务沿用原入队时间，不会排到同优先级新任务之后。
+func trainingQueueScore(priority int, enqueuedAt time.Time) float64 {
+	return float64(MaxTrainingQueuePriority-priority)*1e13 + float64(enqueuedAt.UnixMilli())
+}
+
+// applyTrainingQueueRetry 派发失败或主机故障后的处理：可重试且未达最大次数时重新排队，否则置为失败。
+func applyTrainingQueueRetry(job *QueuedTrainingJob, reason string, retryable bool, now time.Time) {
+	job.LastError = reason
+	job.CoreServerKey = ""
+	job.TrainingResultID = 0
+	job.PID = 0
+	job.DispatchedAt = nil
+	if retryable && job.Attempts < job.MaxAttempts {
+		job.Status = TrainingQueueStatusQueued
+		return
+	}
+	job.Status = TrainingQueueStatusFailed
+	job.FinishedAt = &now
+}
+
+func appendTrainingQueueOutcome(report *TrainingQueueDispatchReport, job QueuedTrainingJob) {
+	switch job.Status {
+	case TrainingQueueStatusQueued:
+		report.Requeued = append(report.Requeued, job.ID)
+	case TrainingQueueStatusFailed:
+		report.Failed = append(report.Failed, job.ID)
+	case TrainingQueueStatusFinished:
+		report.Finished = append(report.Finished, job.ID)
+	}
+}
+
+// isRetryableTrainingLaunchError 请求本身有误（模型/数据集不存在、参数或模板非法等）时重试无意义，
+// 其余错误（SSH 连接、远程命令失败等）视为主机侧问题，可换服务器重试。
+func isRetryableTrainingLaunchError(err error) bool {
+	switch {
+	case errors.Is(err, ErrInvalidTrainingLaunch),
+		errors.Is(err, ErrInvalidTrainingTemplate),
+		errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, ErrLocalSourceFileNotFound),
+		errors.Is(err, ErrLocalSourcePathNotRegularFile),
+		errors.Is(err, ErrInvalidStorageTarget),
+		errors.Is(err, ErrSSHFilePathRequired),
+		errors.Is(err, ErrSSHCredentialOverrideForbidden),
+		errors.Is(err, ErrCredentialNotFound),
+		errors.Is(err, ErrCredentialInvalid):
+		return false
+	}
+	return true
+}
+
+// normalizeEnqueueTrainingJobRequest 校验并补全入队请求的默认值。
+func normalizeEnqueueTrainingJobRequest(req EnqueueTrainingJobRequest, defaultMaxAttempts int) (EnqueueTrainingJobRequest, error) {
+	req.CoreServerKey = strings.TrimSpace(req.CoreServerKey)
+	switch {
+	case req.ModelID == 0 || req.DatasetID == 0:
+		return req, fmt.Errorf("%w: model_id and dataset_id are required", ErrInvalidTrainingQueueJob)
+	case req.Priority < 0 || req.Priority > MaxTrainingQueuePriority:
+		return req, fmt.Errorf("%w: priority must be between 0 and %d", ErrInvalidTrainingQueueJob, MaxTrainingQueuePriority)
+	case req.GPUCount < 0:
+		return req, fmt.Errorf("%w: gpu_count must be >= 0", ErrInvalidTrainingQueueJob)
+	case req.GPUMemoryMB < 0 || math.IsNaN(req.GPUMemoryMB) || math.IsInf(req.GPUMemoryMB, 0):
+		return req, fmt.Errorf("%w: gpu_memory_mb must be >= 0", ErrInvalidTrainingQueueJob)
+	case req.MaxAttempts < 0 || req.MaxAttempts > maxTrainingQueueAttempts:
+		return req, fmt.Errorf("%w: max_attempts must be between 1 and %d", ErrInvalidTrainingQueueJob, maxTrainingQueueAttempts)
+	}
+	if _, err := formatTrainingArgs(req.Args); err != nil {
+		return req, err
+	}
+	if req.GPUCount == 0 {
+		req.GPUCount = 1
+	}
+	if req.MaxAttempts == 0 {
+		req.MaxAttempts = defaultMaxAttempts
+	}
+	return req, nil
+}
+
+// coreServerCapacity 核心服务器本轮调度可用的 GPU 与显存
+type coreServerCapacity struct {
+	server       CoreServer
+	freeGPUs     int
+	freeMemoryMB float64
+	excluded     bool
+}
+
+// buildCoreServerCapacities 只统计有心跳的在线服务器。空闲 GPU = 上报 GPU 数 - 已派发任务申请的 GPU 数；
+// 空闲显存 = 显存总量 - max(上报已用显存, 已派发任务申请的显存)，刚启动尚未占满显存的任务也按申请量计算。
+func buildCoreServerCapacities(servers []CoreServer, active []QueuedTrainingJob) []*coreServerCapacity {
+	reservedGPUs := make(map[string]int)
+	reservedMemory := make(map[string]float64)
+	for _, job := range active {
+		if job.CoreServerKey == "" {
+			continue
+		}
+		reservedGPUs[job.CoreServerKey] += job.GPUCount
+		reservedMemory[job.CoreServerKey] += job.GPUMemoryMB
+	}
+
+	capacities := make([]*coreServerCapacity, 0, len(servers))
+	for _, server := range servers {
+		if server.Status != CoreServerStatusOnline || server.Heartbeat == nil {
+			continue
+		}
+		heartbeat := server.Heartbeat
+		usedMemory := math.Max(heartbeat.GPUMemoryUsedMB, reservedMemory[server.Key])
+		capacities = append(capacities, &coreServerCapacity{
+			server:       server,
+			freeGPUs:     heartbeat.GPUCount - reservedGPUs[server.Key],
+			freeMemoryMB: math.Max(heartbeat.GPUMemoryTotalMB-usedMemory, 0),
+		})
+	}
+	sort.SliceStable(capacities, func(i, j int) bool {
+		return capacities[i].server.Key < capacities[j].server.Key
+	})
+	return capacities
+}
+
+func (c *coreServerCapacity) fits(job QueuedTrainingJob) bool {
+	return !c.excluded && c.freeGPUs >= job.GPUCount && c.freeMemoryMB >= job.GPUMemoryMB
+}
+
+func (c *coreServerCapacity) reserve(job QueuedTrainingJob) {
+	c.freeGPUs -= job.GPUCount
+	c.freeMemoryMB -= job.GPUMemoryMB
+}
+
+func (c *coreServerCapacity) release(job QueuedTrainingJob) {
+	c.freeGPUs += job.GPUCount
+	c.freeMemoryMB += job.GPUMemoryMB
+}
+
+// selectCoreServer 在放得下任务的服务器中选空闲 GPU 最少（其次空闲显存最少）的一台，尽量给大任务留出整机；
+// 任务指定了 core_server_key 时只考虑该服务器。
+func selectCoreServer(capacities []*coreServerCapacity, job QueuedTrainingJob) *coreServerCapacity {
+	pinned := strings.TrimSpace(job.Request.CoreServerKey)
+	var best *coreServerCapacity
+	for _, capacity := range capacities {
+		if pinned != "" && capacity.server.Key != pinned {
+			continue
+		}
+		if !capacity.fits(job) {
+			continue
+		}
+		if best == nil ||
+			capacity.freeGPUs < best.freeGPUs ||
+			(capacity.freeGPUs == best.freeGPUs && capacity.freeMemoryMB < best.freeMemoryMB) {
+			best = capacity
+		}
+	}
+	return best
+}
+
+// trainingQueueLease 调度锁与派发认领共用的租约：以同一 token 持有多个键，调度期间后台续期，用完按 token 释放。
+type trainingQueueLease struct {
+	token string
+	mu    sync.Mutex
+	keys  map[string]struct{}
+}
+
+func newTrainingQueueLease(token string) *trainingQueueLease {
+	return &trainingQueueLease{token: token, keys: make(map[string]struct{})}
+}
+
+func (l *trainingQueueLease) acquire(ctx context.Context, key string) (bool, error) {
+	ok, err := config.RedisClient.SetNX(ctx, key, l.token, trainingQueueLeaseTTL).Result()
+	if err != nil || !ok {
+		return false, err
+	}
+	l.mu.Lock()
+	l.keys[key] = struct{}{}
+	l.mu.Unlock()
+	return true, nil
+}
+
+func (l *trainingQueueLease) release(ctx context.Context, key string) {
+	l.mu.Lock()
+	delete(l.keys, key)
+	l.mu.Unlock()
+	if err := releaseTrainingQueueLeaseScript.Run(ctx, config.RedisClient, []string{key}, l.token).Err(); err != nil {
+		serviceLogger().Error("release training queue lease failed", "key", key, "error", err)
+	}
+}
+
+func (l *trainingQueueLease) renew(ctx context.Context) {
+	l.mu.Lock()
+	keys := make([]string, 0, len(l.keys))
+	for key := range l.keys {
+		keys = append(keys, key)
+	}
+	l.mu.Unlock()
+
+	for _, key := range keys {
+		renewed, err := renewTrainingQueueLeaseScript.Run(ctx, config.RedisClient, []string{key}, l.token, trainingQueueLeaseTTL.Milliseconds()).Int()
+		if err != nil {
+			serviceLogger().Error("renew training queue lease failed", "key", key, "error", err)
+		} else if renewed == 0 {
+			serviceLogger().Warn("training queue lease lost", "key", key)
+		}
+	}
+}
+
+// keepAlive 在后台按间隔续期所有持有的键，返回的函数停止续期并等待后台协程退出。
+func (l *trainingQueueLease) keepAlive(ctx context.Context) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(trainingQueueLeaseRenewEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				l.renew(ctx)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
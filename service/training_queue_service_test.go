@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"lucky_project/config"
+	entity2 "lucky_project/entity"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestNormalizeEnqueueTrainingJobRequest(t *testing.T) {
+	req, err := normalizeEnqueueTrainingJobRequest(EnqueueTrainingJobRequest{
+		LaunchTrainingRequest: LaunchTrainingRequest{ModelID: 1, DatasetID: 2, CoreServerKey: " gpu-1 "},
+		Priority:              80,
+	}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "gpu-1", req.CoreServerKey)
+	assert.Equal(t, 1, req.GPUCount)
+	assert.Equal(t, 3, req.MaxAttempts)
+
+	for _, invalid := range []EnqueueTrainingJobRequest{
+		{LaunchTrainingRequest: LaunchTrainingRequest{DatasetID: 2}},
+		{LaunchTrainingRequest: LaunchTrainingRequest{ModelID: 1, DatasetID: 2}, Priority: 101},
+		{LaunchTrainingRequest: LaunchTrainingRequest{ModelID: 1, DatasetID: 2}, GPUCount: -1},
+		{LaunchTrainingRequest: LaunchTrainingRequest{ModelID: 1, DatasetID: 2}, GPUMemoryMB: -1},
+		{LaunchTrainingRequest: LaunchTrainingRequest{ModelID: 1, DatasetID: 2}, MaxAttempts: 11},
+	} {
+		_, err := normalizeEnqueueTrainingJobRequest(invalid, 3)
+		assert.ErrorIs(t, err, ErrInvalidTrainingQueueJob, fmt.Sprintf("%+v", invalid))
+	}
+
+	_, err = normalizeEnqueueTrainingJobRequest(EnqueueTrainingJobRequest{
+		LaunchTrainingRequest: LaunchTrainingRequest{ModelID: 1, DatasetID: 2, Args: map[string]interface{}{"rm -rf": "x"}},
+	}, 3)
+	assert.ErrorIs(t, err, ErrInvalidTrainingLaunch)
+}
+
+func TestTrainingQueueScoreOrdersByPriorityThenTime(t *testing.T) {
+	base := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	urgentLate := trainingQueueScore(90, base.Add(time.Hour))
+	normalEarly := trainingQueueScore(10, base)
+	normalLate := trainingQueueScore(10, base.Add(time.Second))
+
+	assert.Less(t, urgentLate, normalEarly)
+	assert.Less(t, normalEarly, normalLate)
+	assert.Less(t, trainingQueueScore(MaxTrainingQueuePriority, base), trainingQueueScore(0, base))
+}
+
+func TestBuildCoreServerCapacitiesAndSelectCoreServer(t *testing.T) {
+	servers := []CoreServer{
+		{Key: "gpu-a", Status: CoreServerStatusOnline, Heartbeat: &CoreServerHeartbeat{GPUCount: 8, GPUMemoryTotalMB: 8 * 24000, GPUMemoryUsedMB: 10000}},
+		{Key: "gpu-b", Status: CoreServerStatusOnline, Heartbeat: &CoreServerHeartbeat{GPUCount: 2, GPUMemoryTotalMB: 2 * 24000}},
+		{Key: "gpu-c", Status: CoreServerStatusOffline},
+		{Key: "gpu-d", Status: CoreServerStatusUnknown},
+	}
+	active := []QueuedTrainingJob{
+		{ID: 1, CoreServerKey: "gpu-a", GPUCount: 4, GPUMemoryMB: 60000},
+		{ID: 2, CoreServerKey: "gpu-c", GPUCount: 1},
+	}
+
+	capacities := buildCoreServerCapacities(servers, active)
+	require.Len(t, capacities, 2)
+	assert.Equal(t, "gpu-a", capacities[0].server.Key)
+	assert.Equal(t, 4, capacities[0].freeGPUs)
+	// 已派发任务申请的 60000MB 大于上报已用的 10000MB，按申请量扣除
+	assert.InDelta(t, 8*24000-60000, capacities[0].freeMemoryMB, 0.001)
+	assert.Equal(t, 2, capacities[1].freeGPUs)
+
+	// 两台都放得下时选空闲 GPU 更少的一台
+	small := QueuedTrainingJob{ID: 3, GPUCount: 1, GPUMemoryMB: 16000}
+	selected := selectCoreServer(capacities, small)
+	require.NotNil(t, selected)
+	assert.Equal(t, "gpu-b", selected.server.Key)
+
+	selected.reserve(small)
+	selected.reserve(small)
+	assert.Equal(t, 0, selected.freeGPUs)
+	selected = selectCoreServer(capacities, small)
+	require.NotNil(t, selected)
+	assert.Equal(t, "gpu-a", selected.server.Key)
+
+	assert.Nil(t, selectCoreServer(capacities, QueuedTrainingJob{GPUCount: 6}))
+	assert.Nil(t, selectCoreServer(capacities, QueuedTrainingJob{GPUCount: 1, GPUMemoryMB: 200000}))
+
+	pinned := QueuedTrainingJob{GPUCount: 1, Request: LaunchTrainingRequest{CoreServerKey: "gpu-b"}}
+	assert.Nil(t, selectCoreServer(capacities, pinned))
+	capacities[1].release(small)
+	selected = selectCoreServer(capacities, pinned)
+	require.NotNil(t, selected)
+	assert.Equal(t, "gpu-b", selected.server.Key)
+
+	capacities[1].excluded = true
+	assert.Nil(t, selectCoreServer(capacities, pinned))
+}
+
+func TestApplyTrainingQueueRetry(t *testing.T) {
+	now := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	dispatchedAt := now.Add(-time.Hour)
+	job := QueuedTrainingJob{
+		Status:           TrainingQueueStatusRunning,
+		Attempts:         1,
+		MaxAttempts:      2,
+		CoreServerKey:    "gpu-a",
+		TrainingResultID: 7,
+		PID:              4242,
+		DispatchedAt:     &dispatchedAt,
+	}
+
+	applyTrainingQueueRetry(&job, "core server gpu-a went offline", true, now)
+	assert.Equal(t, TrainingQueueStatusQueued, job.Status)
+	assert.Empty(t, job.CoreServerKey)
+	assert.Zero(t, job.TrainingResultID)
+	assert.Zero(t, job.PID)
+	assert.Nil(t, job.DispatchedAt)
+	assert.Nil(t, job.FinishedAt)
+	assert.Equal(t, "core server gpu-a went offline", job.LastError)
+
+	job.Attempts = 2
+	applyTrainingQueueRetry(&job, "ssh: handshake failed", true, now)
+	assert.Equal(t, TrainingQueueStatusFailed, job.Status)
+	require.NotNil(t, job.FinishedAt)
+	assert.Equal(t, now, *job.FinishedAt)
+
+	job = QueuedTrainingJob{Status: TrainingQueueStatusDispatching, Attempts: 1, MaxAttempts: 3}
+	applyTrainingQueueRetry(&job, "model not found", false, now)
+	assert.Equal(t, TrainingQueueStatusFailed, job.Status)
+}
+
+func TestIsRetryableTrainingLaunchError(t *testing.T) {
+	assert.True(t, isRetryableTrainingLaunchError(errors.New("dial tcp 10.0.0.9:22: connect: connection refused")))
+	assert.True(t, isRetryableTrainingLaunchError(fmt.Errorf("%w: exit code 1", ErrRemoteCommandFailed)))
+	assert.False(t, isRetryableTrainingLaunchError(gorm.ErrRecordNotFound))
+	assert.False(t, isRetryableTrainingLaunchError(fmt.Errorf("%w: model has no file", ErrInvalidTrainingLaunch)))
+	assert.False(t, isRetryableTrainingLaunchError(fmt.Errorf("%w: coco.zip", ErrLocalSourceFileNotFound)))
+	assert.False(t, isRetryableTrainingLaunchError(fmt.Errorf("%w: cred-1", ErrCredentialNotFound)))
+	assert.False(t, isRetryableTrainingLaunchError(fmt.Errorf("%w: gpu-1", ErrSSHCredentialOverrideForbidden)))
+}
+
+func TestBuildKillTrainingCommand(t *testing.T) {
+	assert.Equal(t, "kill -TERM -- -4242 2>/dev/null || { pkill -TERM -P 4242; kill -TERM 4242; } 2>/dev/null; true", buildKillTrainingCommand(4242))
+	assert.Equal(t, "if kill -0 -- -4242 2>/dev/null || kill -0 4242 2>/dev/null; then echo alive; else echo dead; fi", buildTrainingAliveCommand(4242))
+}
+
+// trainingQueueHarness 基于 miniredis 的队列测试环境：核心服务器 gpu-1 已登记，SSH 与启动流程均为假实现。
+type trainingQueueHarness struct {
+	svc      *TrainingQueueService
+	redis    *miniredis.Miniredis
+	client   *fakeRemoteFileClient
+	factory  *fakeRemoteFileClientFactory
+	launcher *fakeTrainingLauncher
+	results  *fakeTrainingQueueResults
+}
+
+func newTrainingQueueHarness(t *testing.T) *trainingQueueHarness {
+	mr := miniredis.RunT(t)
+	previous := config.RedisClient
+	config.RedisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = config.RedisClient.Close()
+		config.RedisClient = previous
+	})
+	mr.HSet(coreServersHashKey, "gpu-1", `{"ip":"10.0.0.9","port":22}`)
+
+	client := &fakeRemoteFileClient{}
+	factory := &fakeRemoteFileClientFactory{client: client}
+	results := &fakeTrainingQueueResults{statuses: make(map[uint]int8)}
+	launcher := &fakeTrainingLauncher{results: results, nextResultID: 7, pid: 4242}
+	return &trainingQueueHarness{
+		svc: &TrainingQueueService{
+			launchService:   launcher,
+			trainingService: results,
+			transferService: &SSHArtifactTransferService{
+				PathService:       NewArtifactPathService(),
+				serverLookup:      newFakeCoreServerRegistry(CoreServer{Key: "gpu-1", IP: "10.0.0.9", Port: 22}).Lookup,
+				defaultCredential: testSSHCredential(),
+				clientFactory:     factory,
+			},
+		},
+		redis:    mr,
+		client:   client,
+		factory:  factory,
+		launcher: launcher,
+		results:  results,
+	}
+}
+
+func (h *trainingQueueHarness) setOnline(t *testing.T, gpuCount int) {
+	_, err := ReportCoreServerHeartbeat(context.Background(), "gpu-1", CoreServerHeartbeat{GPUCount: gpuCount})
+	require.NoError(t, err)
+}
+
+func (h *trainingQueueHarness) setOffline() {
+	h.redis.Del(coreServerHeartbeatKeyPrefix + "gpu-1")
+	h.redis.HSet(coreServerLastSeenHashKey, "gpu-1", time.Now().Add(-time.Hour).Format(time.RFC3339))
+}
+
+func (h *trainingQueueHarness) put(t *testing.T, job QueuedTrainingJob) {
+	ctx := context.Background()
+	payload, err := json.Marshal(job)
+	require.NoError(t, err)
+	_, err = config.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, trainingQueueJobKey(job.ID), payload, 0)
+		pipe.ZAdd(ctx, trainingQueueJobsKey, redis.Z{Score: float64(job.ID), Member: job.ID})
+		syncTrainingQueueIndexes(ctx, pipe, job)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func (h *trainingQueueHarness) get(t *testing.T, id int64) QueuedTrainingJob {
+	job, err := h.svc.Get(context.Background(), id)
+	require.NoError(t, err)
+	return job
+}
+
+func (h *trainingQueueHarness) activeCount(t *testing.T) int64 {
+	count, err := config.RedisClient.SCard(context.Background(), trainingQueueActiveKey).Result()
+	require.NoError(t, err)
+	return count
+}
+
+func TestTrainingQueueCancelDuringDispatch(t *testing.T) {
+	h := newTrainingQueueHarness(t)
+	h.setOnline(t, 2)
+	h.put(t, QueuedTrainingJob{ID: 1, Status: TrainingQueueStatusQueued, GPUCount: 1, MaxAttempts: 3, EnqueuedAt: time.Now()})
+	h.launcher.onLaunch = func() {
+		assert.True(t, h.redis.Exists(trainingQueueClaimKey(1)), "dispatch should hold the job claim")
+		cancelled, err := h.svc.Cancel(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, TrainingQueueStatusCancelled, cancelled.Status)
+	}
+
+	report, err := h.svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Dispatched)
+	job := h.get(t, 1)
+	assert.Equal(t, TrainingQueueStatusCancelled, job.Status)
+	// 刚启动的进程被结束，训练结果置为中断
+	assert.Equal(t, []string{buildKillTrainingCommand(4242)}, h.client.commands)
+	assert.Equal(t, entity2.TrainingStatusInterrupted, h.results.statuses[7])
+	assert.Zero(t, h.activeCount(t))
+	assert.False(t, h.redis.Exists(trainingQueueClaimKey(1)))
+	assert.False(t, h.redis.Exists(trainingQueueLockKey))
+	assert.Greater(t, h.redis.TTL(trainingQueueJobKey(1)), time.Duration(0))
+}
+
+func TestTrainingQueueReconcileDeadProcess(t *testing.T) {
+	h := newTrainingQueueHarness(t)
+	h.setOnline(t, 2)
+	h.results.statuses[5] = entity2.TrainingStatusRunning
+	h.put(t, QueuedTrainingJob{ID: 1, Status: TrainingQueueStatusRunning, GPUCount: 1, MaxAttempts: 3, CoreServerKey: "gpu-1", TrainingResultID: 5, PID: 3131, EnqueuedAt: time.Now()})
+	h.put(t, QueuedTrainingJob{ID: 2, Status: TrainingQueueStatusQueued, GPUCount: 2, MaxAttempts: 3, EnqueuedAt: time.Now()})
+
+	// 进程仍在：任务 1 继续占用 GPU，任务 2 放不下
+	h.client.commandResults = []RemoteCommandResult{{Stdout: "alive\n"}}
+	report, err := h.svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{buildTrainingAliveCommand(3131)}, h.client.commands)
+	assert.Empty(t, report.Dispatched)
+	assert.Equal(t, TrainingQueueStatusRunning, h.get(t, 1).Status)
+
+	// 进程已退出且未上报结果：训练结果与任务置为失败并释放 GPU，任务 2 随即派发
+	h.client.commandResults = []RemoteCommandResult{{Stdout: "dead\n"}}
+	report, err = h.svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, report.Failed)
+	assert.Equal(t, entity2.TrainingStatusFailed, h.results.statuses[5])
+	job := h.get(t, 1)
+	assert.Equal(t, TrainingQueueStatusFailed, job.Status)
+	assert.Contains(t, job.LastError, "exited without reporting a result")
+	assert.Greater(t, h.redis.TTL(trainingQueueJobKey(1)), time.Duration(0))
+	require.Len(t, report.Dispatched, 1)
+	assert.Equal(t, int64(2), report.Dispatched[0].JobID)
+	assert.Equal(t, TrainingQueueStatusRunning, h.get(t, 2).Status)
+	assert.Zero(t, h.redis.TTL(trainingQueueJobKey(2)))
+}
+
+func TestTrainingQueueReconcileStopsProcessOfFinishedResult(t *testing.T) {
+	h := newTrainingQueueHarness(t)
+	h.setOnline(t, 1)
+	h.results.statuses[5] = entity2.TrainingStatusInterrupted
+	h.put(t, QueuedTrainingJob{ID: 1, Status: TrainingQueueStatusRunning, GPUCount: 1, MaxAttempts: 3, CoreServerKey: "gpu-1", TrainingResultID: 5, PID: 3131, EnqueuedAt: time.Now()})
+	h.put(t, QueuedTrainingJob{ID: 2, Status: TrainingQueueStatusRunning, GPUCount: 0, MaxAttempts: 3, CoreServerKey: "gpu-1", TrainingResultID: 6, PID: 3232, EnqueuedAt: time.Now()})
+	h.put(t, QueuedTrainingJob{ID: 3, Status: TrainingQueueStatusQueued, GPUCount: 1, MaxAttempts: 3, EnqueuedAt: time.Now()})
+
+	// 训练结果已中断（任务 1）或已删除（任务 2），但无法结束进程且无法确认进程已退出：保留 GPU，任务 3 不派发
+	h.client.commandErr = errors.New("ssh: handshake failed")
+	report, err := h.svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Dispatched)
+	assert.Equal(t, TrainingQueueStatusRunning, h.get(t, 1).Status)
+	assert.Equal(t, TrainingQueueStatusRunning, h.get(t, 2).Status)
+
+	// 结束进程成功后才置为 finished 并释放 GPU
+	h.client.commandErr = nil
+	h.client.commands = nil
+	report, err = h.svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{buildKillTrainingCommand(3131), buildKillTrainingCommand(3232)}, h.client.commands[:2])
+	assert.Equal(t, TrainingQueueStatusFinished, h.get(t, 1).Status)
+	assert.Equal(t, TrainingQueueStatusFinished, h.get(t, 2).Status)
+	require.Len(t, report.Dispatched, 1)
+	assert.Equal(t, int64(3), report.Dispatched[0].JobID)
+}
+
+func TestTrainingQueueUsesPerJobSSHCredential(t *testing.T) {
+	h := newTrainingQueueHarness(t)
+	h.setOnline(t, 2)
+	h.results.statuses[5] = entity2.TrainingStatusInterrupted
+	h.results.statuses[6] = entity2.TrainingStatusInterrupted
+	h.put(t, QueuedTrainingJob{ID: 1, Status: TrainingQueueStatusRunning, GPUCount: 1, MaxAttempts: 3, CoreServerKey: "gpu-1", TrainingResultID: 5, PID: 3131, EnqueuedAt: time.Now(),
+		Request: LaunchTrainingRequest{ModelID: 1, DatasetID: 1, SSHUser: "trainer"}})
+	h.put(t, QueuedTrainingJob{ID: 2, Status: TrainingQueueStatusRunning, GPUCount: 1, MaxAttempts: 3, CoreServerKey: "gpu-1", TrainingResultID: 6, PID: 3232, EnqueuedAt: time.Now(),
+		Request: LaunchTrainingRequest{ModelID: 1, DatasetID: 1}})
+
+	_, err := h.svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	// 任务 2 未指定凭据，不沿用任务 1 的用户
+	require.Len(t, h.factory.serverCalls, 2)
+	assert.Equal(t, "trainer", h.factory.serverCalls[0].User)
+	assert.Equal(t, testSSHCredential().User, h.factory.serverCalls[1].User)
+	assert.Equal(t, testSSHCredential().PrivateKeyPath, h.factory.serverCalls[1].PrivateKeyPath)
+}
+
+func TestTrainingQueueRequeueOfflineHost(t *testing.T) {
+	h := newTrainingQueueHarness(t)
+	h.setOffline()
+	h.results.statuses[5] = entity2.TrainingStatusRunning
+	h.put(t, QueuedTrainingJob{ID: 1, Status: TrainingQueueStatusRunning, GPUCount: 1, Attempts: 1, MaxAttempts: 3, CoreServerKey: "gpu-1", TrainingResultID: 5, PID: 3131, EnqueuedAt: time.Now()})
+
+	// 无法连上主机结束进程：保留任务，下一轮重试，不重新排队
+	h.factory.newErr = errors.New("dial tcp 10.0.0.9:22: i/o timeout")
+	report, err := h.svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Requeued)
+	assert.Equal(t, TrainingQueueStatusRunning, h.get(t, 1).Status)
+	assert.Equal(t, entity2.TrainingStatusRunning, h.results.statuses[5])
+
+	// 结束进程成功后才中断训练并重新排队
+	h.factory.newErr = nil
+	report, err = h.svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, report.Requeued)
+	assert.Equal(t, []string{buildKillTrainingCommand(3131)}, h.client.commands)
+	assert.Equal(t, entity2.TrainingStatusInterrupted, h.results.statuses[5])
+	job := h.get(t, 1)
+	assert.Equal(t, TrainingQueueStatusQueued, job.Status)
+	assert.Empty(t, job.CoreServerKey)
+	assert.Zero(t, job.PID)
+	assert.Zero(t, h.launcher.launches)
+}
+
+func TestTrainingQueueDispatchingJobRequeuedOnlyWithoutClaim(t *testing.T) {
+	h := newTrainingQueueHarness(t)
+	h.redis.HDel(coreServersHashKey, "gpu-1")
+	h.put(t, QueuedTrainingJob{ID: 1, Status: TrainingQueueStatusDispatching, GPUCount: 1, Attempts: 1, MaxAttempts: 3, CoreServerKey: "gpu-1", EnqueuedAt: time.Now()})
+
+	// 认领仍在（派发方仍在启动，即使已持续很久）：不重新排队
+	require.NoError(t, h.redis.Set(trainingQueueClaimKey(1), "other-instance"))
+	report, err := h.svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Requeued)
+	assert.Equal(t, TrainingQueueStatusDispatching, h.get(t, 1).Status)
+
+	// 认领过期（派发方已退出）：重新排队
+	h.redis.Del(trainingQueueClaimKey(1))
+	report, err = h.svc.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, report.Requeued)
+	assert.Equal(t, TrainingQueueStatusQueued, h.get(t, 1).Status)
+}
+
+func TestTrainingQueueListPrunesExpiredJobs(t *testing.T) {
+	h := newTrainingQueueHarness(t)
+	h.put(t, QueuedTrainingJob{ID: 1, Status: TrainingQueueStatusQueued, GPUCount: 1, MaxAttempts: 3, EnqueuedAt: time.Now()})
+	h.put(t, QueuedTrainingJob{ID: 2, Status: TrainingQueueStatusQueued, GPUCount: 1, MaxAttempts: 3, EnqueuedAt: time.Now()})
+
+	_, err := h.svc.Cancel(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, trainingQueueFinishedJobTTL, h.redis.TTL(trainingQueueJobKey(1)))
+	h.redis.FastForward(trainingQueueFinishedJobTTL + time.Second)
+
+	jobs, err := h.svc.List(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, int64(2), jobs[0].ID)
+	members, err := h.redis.ZMembers(trainingQueueJobsKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, members)
+}
+
+type fakeTrainingLauncher struct {
+	results      *fakeTrainingQueueResults
+	onLaunch     func()
+	err          error
+	launches     int
+	nextResultID uint
+	pid          int
+}
+
+func (f *fakeTrainingLauncher) Launch(ctx context.Context, req LaunchTrainingRequest, server CoreServer) (TrainingLaunch, error) {
+	f.launches++
+	if f.err != nil {
+		return TrainingLaunch{}, f.err
+	}
+	id := f.nextResultID
+	f.nextResultID++
+	f.results.statuses[id] = entity2.TrainingStatusRunning
+	if f.onLaunch != nil {
+		f.onLaunch()
+	}
+	return TrainingLaunch{
+		Result: &entity2.ModelTrainingResult{ID: id, TrainingStatus: entity2.TrainingStatusRunning},
+		Job:    &entity2.TrainingJob{TrainingResultID: id, CoreServerKey: server.Key, PID: f.pid},
+	}, nil
+}
+
+type fakeTrainingQueueResults struct {
+	statuses map[uint]int8
+}
+
+func (f *fakeTrainingQueueResults) GetByID(ctx context.Context, id uint) (*entity2.ModelTrainingResult, error) {
+	status, ok := f.statuses[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &entity2.ModelTrainingResult{ID: id, TrainingStatus: status}, nil
+}
+
+func (f *fakeTrainingQueueResults) ApplyTrainingAction(ctx context.Context, id uint, action string, req TrainingActionRequest) (*entity2.ModelTrainingResult, error) {
+	status, ok := f.statuses[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	target := trainingActionTargets[action]
+	if err := validateTrainingTransition(status, target); err != nil {
+		return nil, err
+	}
+	f.statuses[id] = target
+	return &entity2.ModelTrainingResult{ID: id, TrainingStatus: target}, nil
+}
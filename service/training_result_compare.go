@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	entity2 "lucky_project/entity"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	MinTrainingCompareRuns = 2
+	MaxTrainingCompareRuns = 20
+)
+
+var ErrInvalidTrainingCompare = errors.New("invalid training compare request")
+
+// TrainingCompareRun 参与对比的训练结果概要，顺序与请求中的 ids 一致。
+type TrainingCompareRun struct {
+	ID                   uint    `json:"id"`
+	ModelID              uint    `json:"model_id"`
+	DatasetID            uint    `json:"dataset_id"`
+	DatasetVersion       float64 `json:"dataset_version"`
+	TrainingStatus       int8    `json:"training_status"`
+	DurationSeconds      *int64  `json:"duration_seconds"`
+	HyperparameterSource string  `json:"hyperparameter_source"` // 未记录超参数时为空
+}
+
+// TrainingCompareRow 对比表的一行；Values 与 Runs 一一对应，该次训练没有此键时为 null。
+type TrainingCompareRow struct {
+	Key     string        `json:"key"`
+	Values  []interface{} `json:"values"`
+	Changed bool          `json:"changed"`
+}
+
+// TrainingResultComparison GET /v1/training-results/compare 返回结构。
+// 嵌套对象展开为以 . 连接的键，数组整体作为一个值比较。
+type TrainingResultComparison struct {
+	Runs                   []TrainingCompareRun `json:"runs"`
+	SameModel              bool                 `json:"same_model"`
+	SameDataset            bool                 `json:"same_dataset"`
+	ChangedHyperparameters []string             `json:"changed_hyperparameters"`
+	ChangedMetrics         []string             `json:"changed_metrics"`
+	Hyperparameters        []TrainingCompareRow `json:"hyperparameters"`
+	Metrics                []TrainingCompareRow `json:"metrics"`
+}
+
+// CompareResults 并排对比多次训练的超参数与最终指标（metric_detail）；任一 ID 不存在时返回 gorm.ErrRecordNotFound。
+// onlyChanged 为 true 时只返回取值不同的行。
+func (s *TrainingResultService) CompareResults(ctx context.Context, ids []uint, onlyChanged bool) (TrainingResultComparison, error) {
+	if err := validateTrainingCompareIDs(ids); err != nil {
+		return TrainingResultComparison{}, err
+	}
+
+	results, err := s.trainingDAO.FindByIDs(ctx, ids)
+	if err != nil {
+		return TrainingResultComparison{}, err
+	}
+	byID := make(map[uint]entity2.ModelTrainingResult, len(results))
+	for _, result := range results {
+		byID[result.ID] = result
+	}
+	ordered := make([]entity2.ModelTrainingResult, 0, len(ids))
+	for _, id := range ids {
+		result, ok := byID[id]
+		if !ok {
+			return TrainingResultComparison{}, fmt.Errorf("training result %d: %w", id, gorm.ErrRecordNotFound)
+		}
+		ordered = append(ordered, result)
+	}
+
+	records, err := s.hyperparameterDAO.FindByTrainingResultIDs(ctx, ids)
+	if err != nil {
+		return TrainingResultComparison{}, err
+	}
+	hyperparameters := make(map[uint]entity2.TrainingHyperparameters, len(records))
+	for _, record := range records {
+		hyperparameters[record.TrainingResultID] = record
+	}
+
+	return buildTrainingResultComparison(ordered, hyperparameters, onlyChanged), nil
+}
+
+// ParseTrainingCompareIDs 解析 ids 查询参数，支持 ids=1,2,3 与 ids=1&ids=2 两种写法，重复的 ID 只保留第一次出现。
+func ParseTrainingCompareIDs(values []string) ([]uint, error) {
+	ids := make([]uint, 0, len(values))
+	seen := make(map[uint]struct{}, len(values))
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := strconv.ParseUint(part, 10, 64)
+			if err != nil || id == 0 {
+				return nil, fmt.Errorf("%w: ids must be positive integers", ErrInvalidTrainingCompare)
+			}
+			if _, ok := seen[uint(id)]; ok {
+				continue
+			}
+			seen[uint(id)] = struct{}{}
+			ids = append(ids, uint(id))
+		}
+	}
+	if err := validateTrainingCompareIDs(ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func validateTrainingCompareIDs(ids []uint) error {
+	if len(ids) < MinTrainingCompareRuns || len(ids) > MaxTrainingCompareRuns {
+		return fmt.Errorf("%w: between %d and %d distinct ids are required", ErrInvalidTrainingCompare, MinTrainingCompareRuns, MaxTrainingCompareRuns)
+	}
+	return nil
+}
+
+func buildTrainingResultComparison(results []entity2.ModelTrainingResult, hyperparameters map[uint]entity2.TrainingHyperparameters, onlyChanged bool) TrainingResultComparison {
+	comparison := TrainingResultComparison{
+		Runs:        make([]TrainingCompareRun, 0, len(results)),
+		SameModel:   true,
+		SameDataset: true,
+	}
+	paramValues := make([]map[string]interface{}, 0, len(results))
+	metricValues := make([]map[string]interface{}, 0, len(results))
+	for i := range results {
+		result := results[i]
+		fillTrainingDuration(&result)
+		record := hyperparameters[result.ID]
+		comparison.Runs = append(comparison.Runs, TrainingCompareRun{
+			ID:                   result.ID,
+			ModelID:              result.ModelID,
+			DatasetID:            result.DatasetID,
+			DatasetVersion:       result.DatasetVersion,
+			TrainingStatus:       result.TrainingStatus,
+			DurationSeconds:      result.DurationSeconds,
+			HyperparameterSource: record.Source,
+		})
+		if result.ModelID != results[0].ModelID {
+			comparison.SameModel = false
+		}
+		if result.DatasetID != results[0].DatasetID || result.DatasetVersion != results[0].DatasetVersion {
+			comparison.SameDataset = false
+		}
+		paramValues = append(paramValues, flattenCompareJSON(record.Hyperparameters))
+		metricValues = append(metricValues, flattenCompareJSON(result.MetricDetail))
+	}
+
+	comparison.Hyperparameters, comparison.ChangedHyperparameters = buildTrainingCompareRows(paramValues, onlyChanged)
+	comparison.Metrics, comparison.ChangedMetrics = buildTrainingCompareRows(metricValues, onlyChanged)
+	return comparison
+}
+
+// buildTrainingCompareRows 按键名排序生成对比行，同时返回取值不同的键。
+// 某次训练缺少该键也视为不同。
+func buildTrainingCompareRows(values []map[string]interface{}, onlyChanged bool) ([]TrainingCompareRow, []string) {
+	keySet := make(map[string]struct{})
+	for _, flattened := range values {
+		for key := range flattened {
+			keySet[key] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rows := make([]TrainingCompareRow, 0, len(keys))
+	changed := make([]string, 0)
+	for _, key := range keys {
+		row := TrainingCompareRow{Key: key, Values: make([]interface{}, len(values))}
+		for i, flattened := range values {
+			value, ok := flattened[key]
+			if ok {
+				row.Values[i] = value
+			}
+			if i > 0 && !row.Changed {
+				_, firstOK := values[0][key]
+				row.Changed = ok != firstOK || !reflect.DeepEqual(value, values[0][key])
+			}
+		}
+		if row.Changed {
+			changed = append(changed, key)
+		}
+		if onlyChanged && !row.Changed {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, changed
+}
+
+// flattenCompareJSON 将 JSON 对象展开为 键路径 -> 叶子值；非对象或无法解析时返回空。
+func flattenCompareJSON(raw json.RawMessage) map[string]interface{} {
+	flattened := make(map[string]interface{})
+	if len(raw) == 0 {
+		return flattened
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return flattened
+	}
+	flattenCompareObject("", object, flattened)
+	return flattened
+}
+
+func flattenCompareObject(prefix string, object map[string]interface{}, out map[string]interface{}) {
+	for key, value := range object {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenCompareObject(path, nested, out)
+			continue
+		}
+		out[path] = value
+	}
+}
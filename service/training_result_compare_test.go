@@ -0,0 +1,95 @@
+package service
+
+import (
+	"encoding/json"
+	entity2 "lucky_project/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrainingCompareIDs(t *testing.T) {
+	ids, err := ParseTrainingCompareIDs([]string{"3, 1", "3", "7"})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3, 1, 7}, ids)
+
+	for _, invalid := range [][]string{
+		nil,
+		{"5"},
+		{"5,5"},
+		{"1,abc"},
+		{"0,1"},
+	} {
+		_, err := ParseTrainingCompareIDs(invalid)
+		assert.ErrorIs(t, err, ErrInvalidTrainingCompare, invalid)
+	}
+
+	tooMany := make([]uint, MaxTrainingCompareRuns+1)
+	for i := range tooMany {
+		tooMany[i] = uint(i + 1)
+	}
+	assert.ErrorIs(t, validateTrainingCompareIDs(tooMany), ErrInvalidTrainingCompare)
+}
+
+func TestNormalizeHyperparameters(t *testing.T) {
+	normalized, err := normalizeHyperparameters(json.RawMessage(" { \"lr0\": 0.01, \"augment\": {\"mosaic\": 1} } "))
+	require.NoError(t, err)
+	assert.Equal(t, `{"lr0":0.01,"augment":{"mosaic":1}}`, string(normalized))
+
+	for _, empty := range []string{"", "  ", "null"} {
+		normalized, err := normalizeHyperparameters(json.RawMessage(empty))
+		require.NoError(t, err)
+		assert.Nil(t, normalized)
+	}
+	for _, invalid := range []string{"[1,2]", "42", `"lr0=0.01"`, "{"} {
+		_, err := normalizeHyperparameters(json.RawMessage(invalid))
+		assert.ErrorIs(t, err, ErrInvalidHyperparameters, invalid)
+	}
+}
+
+func TestBuildTrainingResultComparison(t *testing.T) {
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	results := []entity2.ModelTrainingResult{
+		{ID: 9, ModelID: 1, DatasetID: 2, DatasetVersion: 1, TrainStartTime: &start, TrainEndTime: &end,
+			MetricDetail: json.RawMessage(`{"mAP50":0.61,"loss":{"box":1.2,"cls":0.8}}`)},
+		{ID: 4, ModelID: 1, DatasetID: 2, DatasetVersion: 2,
+			MetricDetail: json.RawMessage(`{"mAP50":0.65,"loss":{"box":1.2}}`)},
+	}
+	hyperparameters := map[uint]entity2.TrainingHyperparameters{
+		9: {TrainingResultID: 9, Source: entity2.HyperparameterSourceLaunchArgs, Hyperparameters: json.RawMessage(`{"epochs":100,"lr0":0.01,"imgsz":640}`)},
+		4: {TrainingResultID: 4, Source: entity2.HyperparameterSourceManual, Hyperparameters: json.RawMessage(`{"epochs":100,"lr0":0.02,"optimizer":"AdamW"}`)},
+	}
+
+	comparison := buildTrainingResultComparison(results, hyperparameters, false)
+	require.Len(t, comparison.Runs, 2)
+	assert.Equal(t, uint(9), comparison.Runs[0].ID)
+	assert.Equal(t, entity2.HyperparameterSourceLaunchArgs, comparison.Runs[0].HyperparameterSource)
+	require.NotNil(t, comparison.Runs[0].DurationSeconds)
+	assert.Equal(t, int64(5400), *comparison.Runs[0].DurationSeconds)
+	assert.Nil(t, comparison.Runs[1].DurationSeconds)
+	assert.True(t, comparison.SameModel)
+	assert.False(t, comparison.SameDataset)
+
+	assert.Equal(t, []string{"imgsz", "lr0", "optimizer"}, comparison.ChangedHyperparameters)
+	assert.Equal(t, []TrainingCompareRow{
+		{Key: "epochs", Values: []interface{}{float64(100), float64(100)}},
+		{Key: "imgsz", Values: []interface{}{float64(640), nil}, Changed: true},
+		{Key: "lr0", Values: []interface{}{0.01, 0.02}, Changed: true},
+		{Key: "optimizer", Values: []interface{}{nil, "AdamW"}, Changed: true},
+	}, comparison.Hyperparameters)
+	assert.Equal(t, []string{"loss.cls", "mAP50"}, comparison.ChangedMetrics)
+	assert.Len(t, comparison.Metrics, 3)
+
+	onlyChanged := buildTrainingResultComparison(results, hyperparameters, true)
+	assert.Len(t, onlyChanged.Hyperparameters, 3)
+	assert.Len(t, onlyChanged.Metrics, 2)
+	assert.Equal(t, comparison.ChangedHyperparameters, onlyChanged.ChangedHyperparameters)
+
+	// 没有记录超参数的训练：所有键都视为变化
+	withoutParams := buildTrainingResultComparison(results, map[uint]entity2.TrainingHyperparameters{9: hyperparameters[9]}, false)
+	assert.Equal(t, []string{"epochs", "imgsz", "lr0"}, withoutParams.ChangedHyperparameters)
+	assert.Empty(t, withoutParams.Runs[1].HyperparameterSource)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	entity2 "lucky_project/entity"
+	"strings"
+	"time"
+)
+
+const (
+	TrainingActionStart     = "start"
+	TrainingActionFinish    = "finish"
+	TrainingActionFail      = "fail"
+	TrainingActionInterrupt = "interrupt"
+)
+
+var (
+	ErrInvalidTrainingStatus     = errors.New("invalid training_status, expected 0|1|2|3|4")
+	ErrIllegalTrainingTransition = errors.New("training status transition is not allowed")
+	ErrTrainingResultFinalized   = errors.New("training run has finished, metrics can no longer be updated")
+)
+
+// allowedTrainingTransitions 合法的状态变更；终态（成功/失败/中断）不能再变更，需重新登记一条训练结果。
+var allowedTrainingTransitions = map[int8][]int8{
+	entity2.TrainingStatusPending: {entity2.TrainingStatusRunning, entity2.TrainingStatusFailed, entity2.TrainingStatusInterrupted},
+	entity2.TrainingStatusRunning: {entity2.TrainingStatusSuccess, entity2.TrainingStatusFailed, entity2.TrainingStatusInterrupted},
+}
+
+// trainingActionTargets 各动作对应的目标状态
+var trainingActionTargets = map[string]int8{
+	TrainingActionStart:     entity2.TrainingStatusRunning,
+	TrainingActionFinish:    entity2.TrainingStatusSuccess,
+	TrainingActionFail:      entity2.TrainingStatusFailed,
+	TrainingActionInterrupt: entity2.TrainingStatusInterrupted,
+}
+
+// TrainingActionRequest POST /v1/training-results/:id/{start|finish|fail|interrupt} 可选请求体
+type TrainingActionRequest struct {
+	MetricDetail json.RawMessage `json:"metric_detail"`
+	WeightPath   *string         `json:"weight_path"`
+	CometLogURL  *string         `json:"comet_log_url"`
+}
+
+// ValidateTrainingStatus 校验状态取值
+func ValidateTrainingStatus(status int8) error {
+	if status < entity2.TrainingStatusPending || status > entity2.TrainingStatusInterrupted {
+		return ErrInvalidTrainingStatus
+	}
+	return nil
+}
+
+// IsTerminalTrainingStatus 成功、失败、中断为终态
+func IsTerminalTrainingStatus(status int8) bool {
+	return status == entity2.TrainingStatusSuccess ||
+		status == entity2.TrainingStatusFailed ||
+		status == entity2.TrainingStatusInterrupted
+}
+
+func validateTrainingTransition(from, to int8) error {
+	for _, allowed := range allowedTrainingTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d -> %d", ErrIllegalTrainingTransition, from, to)
+}
+
+// ApplyTrainingAction 执行 start/finish/fail/interrupt 动作，校验状态并记录开始/结束时间。
+func (s *TrainingResultService) ApplyTrainingAction(ctx context.Context, id uint, action string, req TrainingActionRequest) (*entity2.ModelTrainingResult, error) {
+	target, ok := trainingActionTargets[strings.ToLower(strings.TrimSpace(action))]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown action %q", ErrIllegalTrainingTransition, action)
+	}
+
+	updates := make(map[string]interface{})
+	if len(req.MetricDetail) > 0 {
+		if !json.Valid(req.MetricDetail) {
+			return nil, fmt.Errorf("metric_detail must be valid json")
+		}
+		updates["metric_detail"] = req.MetricDetail
+	}
+	if req.WeightPath != nil {
+		updates["weight_path"] = strings.TrimSpace(*req.WeightPath)
+	}
+	if req.CometLogURL != nil {
+		updates["comet_log_url"] = strings.TrimSpace(*req.CometLogURL)
+	}
+	updates["training_status"] = target
+
+	return withTrainingDuration(s.trainingDAO.UpdateWithLock(ctx, id, func(current *entity2.ModelTrainingResult) (map[string]interface{}, error) {
+		return buildTrainingResultUpdates(current, updates, s.clock())
+	}))
+}
+
+// buildTrainingResultUpdates 在当前记录上校验一次更新：
+// 状态变更必须合法，进入 running 时补记 train_start_time，进入终态时补记 train_end_time（请求中显式传入的时间优先）；
+// 已处于终态的记录拒绝更新 metric_detail。
+func buildTrainingResultUpdates(current *entity2.ModelTrainingResult, requested map[string]interface{}, now time.Time) (map[string]interface{}, error) {
+	updates := make(map[string]interface{}, len(requested)+1)
+	for key, value := range requested {
+		updates[key] = value
+	}
+
+	if _, ok := updates["metric_detail"]; ok && IsTerminalTrainingStatus(current.TrainingStatus) {
+		return nil, ErrTrainingResultFinalized
+	}
+
+	rawStatus, ok := updates["training_status"]
+	if !ok {
+		return updates, nil
+	}
+	target, ok := rawStatus.(int8)
+	if !ok {
+		return nil, ErrInvalidTrainingStatus
+	}
+	if err := ValidateTrainingStatus(target); err != nil {
+		return nil, err
+	}
+	if target == current.TrainingStatus {
+		delete(updates, "training_status")
+		return updates, nil
+	}
+	if err := validateTrainingTransition(current.TrainingStatus, target); err != nil {
+		return nil, err
+	}
+
+	if _, ok := updates["train_start_time"]; !ok && target == entity2.TrainingStatusRunning && current.TrainStartTime == nil {
+		updates["train_start_time"] = now
+	}
+	if _, ok := updates["train_end_time"]; !ok && IsTerminalTrainingStatus(target) {
+		updates["train_end_time"] = now
+	}
+	return updates, nil
+}
+
+func (s *TrainingResultService) clock() time.Time {
+	if s != nil && s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
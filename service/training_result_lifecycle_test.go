@@ -0,0 +1,66 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	entity2 "lucky_project/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTrainingResultUpdatesStampsTimes(t *testing.T) {
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+	pending := &entity2.ModelTrainingResult{TrainingStatus: entity2.TrainingStatusPending}
+	updates, err := buildTrainingResultUpdates(pending, map[string]interface{}{"training_status": entity2.TrainingStatusRunning}, now)
+	require.NoError(t, err)
+	assert.Equal(t, now, updates["train_start_time"])
+	assert.NotContains(t, updates, "train_end_time")
+
+	startedAt := now.Add(-time.Hour)
+	running := &entity2.ModelTrainingResult{TrainingStatus: entity2.TrainingStatusRunning, TrainStartTime: &startedAt}
+	updates, err = buildTrainingResultUpdates(running, map[string]interface{}{
+		"training_status": entity2.TrainingStatusSuccess,
+		"metric_detail":   json.RawMessage(`{"mAP50":0.9}`),
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, now, updates["train_end_time"])
+	assert.NotContains(t, updates, "train_start_time")
+
+	explicitEnd := now.Add(-time.Minute)
+	updates, err = buildTrainingResultUpdates(running, map[string]interface{}{
+		"training_status": entity2.TrainingStatusFailed,
+		"train_end_time":  &explicitEnd,
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, &explicitEnd, updates["train_end_time"])
+}
+
+func TestBuildTrainingResultUpdatesRejectsIllegalChanges(t *testing.T) {
+	now := time.Now()
+	success := &entity2.ModelTrainingResult{TrainingStatus: entity2.TrainingStatusSuccess}
+
+	_, err := buildTrainingResultUpdates(success, map[string]interface{}{"training_status": entity2.TrainingStatusRunning}, now)
+	assert.ErrorIs(t, err, ErrIllegalTrainingTransition)
+
+	_, err = buildTrainingResultUpdates(success, map[string]interface{}{"metric_detail": json.RawMessage(`{}`)}, now)
+	assert.ErrorIs(t, err, ErrTrainingResultFinalized)
+
+	pending := &entity2.ModelTrainingResult{TrainingStatus: entity2.TrainingStatusPending}
+	_, err = buildTrainingResultUpdates(pending, map[string]interface{}{"training_status": entity2.TrainingStatusSuccess}, now)
+	assert.ErrorIs(t, err, ErrIllegalTrainingTransition)
+
+	_, err = buildTrainingResultUpdates(pending, map[string]interface{}{"training_status": int8(7)}, now)
+	assert.ErrorIs(t, err, ErrInvalidTrainingStatus)
+
+	// 终态记录仍可更新 weight_path 等非指标字段；重复设置当前状态视为无变更。
+	updates, err := buildTrainingResultUpdates(success, map[string]interface{}{
+		"training_status": entity2.TrainingStatusSuccess,
+		"weight_path":     "/data/best.pt",
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"weight_path": "/data/best.pt"}, updates)
+}
@@ -1,23 +1,64 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"lucky_project/dao"
 	entity2 "lucky_project/entity"
+	"time"
+
+	"gorm.io/gorm"
 )
 
+var ErrInvalidHyperparameters = errors.New("invalid hyperparameters, expected a json object")
+
 type TrainingResultService struct {
-	trainingDAO *dao.TrainingResultDAO
+	trainingDAO       *dao.TrainingResultDAO
+	hyperparameterDAO *dao.TrainingHyperparameterDAO
+	now               func() time.Time
 }
 
 func NewTrainingResultService() *TrainingResultService {
 	return &TrainingResultService{
-		trainingDAO: dao.NewTrainingResultDAO(),
+		trainingDAO:       dao.NewTrainingResultDAO(),
+		hyperparameterDAO: dao.NewTrainingHyperparameterDAO(),
 	}
 }
 
+// CreateTrainingResult 登记训练结果；以 running 状态登记且未传开始时间时记为当前时间。
 func (s *TrainingResultService) CreateTrainingResult(ctx context.Context, result *entity2.ModelTrainingResult) error {
-	return s.trainingDAO.Save(ctx, result)
+	if result == nil {
+		return dao.ErrNilEntity
+	}
+	if err := ValidateTrainingStatus(result.TrainingStatus); err != nil {
+		return err
+	}
+	if result.TrainingStatus == entity2.TrainingStatusRunning && result.TrainStartTime == nil {
+		startedAt := s.clock()
+		result.TrainStartTime = &startedAt
+	}
+	hyperparameters, err := normalizeHyperparameters(result.Hyperparameters)
+	if err != nil {
+		return err
+	}
+	if err := s.trainingDAO.Save(ctx, result); err != nil {
+		return err
+	}
+	if hyperparameters != nil {
+		if err := s.saveHyperparameters(ctx, result.ID, hyperparameters); err != nil {
+			if deleteErr := s.trainingDAO.DeleteByID(ctx, result.ID); deleteErr != nil {
+				serviceLogger().With("service", "TrainingResultService", "method", "CreateTrainingResult").
+					Error("rollback training result failed", "training_result_id", result.ID, "error", deleteErr)
+			}
+			return err
+		}
+	}
+	result.Hyperparameters = hyperparameters
+	fillTrainingDuration(result)
+	return nil
 }
 
 func (s *TrainingResultService) GetAllResults(ctx context.Context, params entity2.QueryParams) (entity2.PageResult, error) {
@@ -25,8 +66,146 @@ func (s *TrainingResultService) GetAllResults(ctx context.Context, params entity
 	if err != nil {
 		return entity2.PageResult{}, err
 	}
+	for i := range results {
+		fillTrainingDuration(&results[i])
+	}
 	return entity2.PageResult{
 		Total: total,
 		List:  results,
 	}, nil
 }
+
+// GetByID 查询单条训练结果，附带已记录的超参数。
+func (s *TrainingResultService) GetByID(ctx context.Context, id uint) (*entity2.ModelTrainingResult, error) {
+	result, err := withTrainingDuration(s.trainingDAO.FindByID(ctx, id))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachHyperparameters(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateTrainingResult 部分更新训练结果；training_status 的变更同样受状态机约束。
+// hyperparameters 为对象时整体覆盖已记录的超参数（来源记为 manual），为 null 时删除，与列更新在同一事务内写入。
+func (s *TrainingResultService) UpdateTrainingResult(ctx context.Context, id uint, updates map[string]interface{}) (*entity2.ModelTrainingResult, error) {
+	if len(updates) == 0 {
+		return nil, dao.ErrNilEntity
+	}
+
+	columns := make(map[string]interface{}, len(updates))
+	for key, value := range updates {
+		columns[key] = value
+	}
+	rawHyperparameters, hasHyperparameters := columns["hyperparameters"]
+	delete(columns, "hyperparameters")
+	var hyperparameters json.RawMessage
+	if hasHyperparameters {
+		raw, ok := rawHyperparameters.(json.RawMessage)
+		if rawHyperparameters != nil && !ok {
+			return nil, ErrInvalidHyperparameters
+		}
+		normalized, err := normalizeHyperparameters(raw)
+		if err != nil {
+			return nil, err
+		}
+		hyperparameters = normalized
+	}
+
+	// 列更新与超参数写入在同一事务内完成，任一步失败整体回滚
+	var build func(current *entity2.ModelTrainingResult) (map[string]interface{}, error)
+	if len(columns) > 0 {
+		build = func(current *entity2.ModelTrainingResult) (map[string]interface{}, error) {
+			return buildTrainingResultUpdates(current, columns, s.clock())
+		}
+	}
+	var writes dao.TrainingResultRelatedWrites
+	if hasHyperparameters {
+		if hyperparameters == nil {
+			writes.DeleteHyperparameters = true
+		} else {
+			writes.Hyperparameters = &entity2.TrainingHyperparameters{
+				Source:          entity2.HyperparameterSourceManual,
+				Hyperparameters: hyperparameters,
+			}
+		}
+	}
+	result, err := s.trainingDAO.UpdateWithRelated(ctx, id, build, writes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachHyperparameters(ctx, result); err != nil {
+		return nil, err
+	}
+	fillTrainingDuration(result)
+	return result, nil
+}
+
+// GetHyperparameters 查询训练结果的超参数；训练结果或超参数不存在时返回 gorm.ErrRecordNotFound。
+func (s *TrainingResultService) GetHyperparameters(ctx context.Context, id uint) (*entity2.TrainingHyperparameters, error) {
+	if _, err := s.trainingDAO.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.hyperparameterDAO.FindByTrainingResultID(ctx, id)
+}
+
+func (s *TrainingResultService) DeleteByID(ctx context.Context, id uint) error {
+	return s.trainingDAO.DeleteByID(ctx, id)
+}
+
+func (s *TrainingResultService) saveHyperparameters(ctx context.Context, id uint, hyperparameters json.RawMessage) error {
+	return s.hyperparameterDAO.Upsert(ctx, &entity2.TrainingHyperparameters{
+		TrainingResultID: id,
+		Source:           entity2.HyperparameterSourceManual,
+		Hyperparameters:  hyperparameters,
+	})
+}
+
+// attachHyperparameters 填充 result.Hyperparameters，未记录超参数时保持为空。
+func (s *TrainingResultService) attachHyperparameters(ctx context.Context, result *entity2.ModelTrainingResult) error {
+	record, err := s.hyperparameterDAO.FindByTrainingResultID(ctx, result.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.Hyperparameters = nil
+			return nil
+		}
+		return err
+	}
+	result.Hyperparameters = record.Hyperparameters
+	return nil
+}
+
+// normalizeHyperparameters 校验超参数必须是 JSON 对象并压缩空白；空值或 null 返回 nil。
+func normalizeHyperparameters(raw json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil, nil
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(trimmed, &object); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHyperparameters, err)
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, trimmed); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHyperparameters, err)
+	}
+	return json.RawMessage(compacted.Bytes()), nil
+}
+
+// fillTrainingDuration 计算 duration_seconds；开始或结束时间缺失时保持为空。
+func fillTrainingDuration(result *entity2.ModelTrainingResult) {
+	if result == nil || result.TrainStartTime == nil || result.TrainEndTime == nil {
+		return
+	}
+	seconds := int64(result.TrainEndTime.Sub(*result.TrainStartTime) / time.Second)
+	result.DurationSeconds = &seconds
+}
+
+func withTrainingDuration(result *entity2.ModelTrainingResult, err error) (*entity2.ModelTrainingResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	fillTrainingDuration(result)
+	return result, nil
+}
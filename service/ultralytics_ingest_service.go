@@ -0,0 +1,312 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lucky_project/dao"
+	entity2 "lucky_project/entity"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaxUltralyticsFileSize results.csv / args.yaml 的大小上限
+const MaxUltralyticsFileSize = 10 << 20
+
+var (
+	ErrInvalidUltralyticsFile  = errors.New("invalid ultralytics file")
+	ErrUltralyticsFileTooLarge = errors.New("ultralytics file is too large")
+)
+
+// ultralyticsFinalMetrics metric_detail 中写入的最终指标及其在 results.csv 中的列；优先检测框 (B)，分割任务退回掩码 (M)。
+var ultralyticsFinalMetrics = []struct {
+	Key     string
+	Columns []string
+}{
+	{Key: "precision", Columns: []string{"metrics/precision(B)", "metrics/precision(M)"}},
+	{Key: "recall", Columns: []string{"metrics/recall(B)", "metrics/recall(M)"}},
+	{Key: "mAP50", Columns: []string{"metrics/mAP50(B)", "metrics/mAP50(M)"}},
+	{Key: "mAP50-95", Columns: []string{"metrics/mAP50-95(B)", "metrics/mAP50-95(M)"}},
+}
+
+// UltralyticsEpochRow results.csv 中的一行
+type UltralyticsEpochRow struct {
+	Epoch  int
+	Values map[string]float64
+}
+
+// UltralyticsResults 解析后的 results.csv；Columns 不含 epoch 列。
+type UltralyticsResults struct {
+	Columns []string
+	Rows    []UltralyticsEpochRow
+}
+
+// UltralyticsIngestResult POST /v1/training-results/:id/ultralytics 返回结构
+type UltralyticsIngestResult struct {
+	TrainingResultID uint                         `json:"training_result_id"`
+	Epochs           int                          `json:"epochs"`
+	MetricPoints     int                          `json:"metric_points"`
+	Hyperparameters  int                          `json:"hyperparameters"`
+	Result           *entity2.ModelTrainingResult `json:"result"`
+}
+
+// UltralyticsIngestService 导入 Ultralytics 训练产出的 results.csv 与 args.yaml
+type UltralyticsIngestService struct {
+	trainingDAO *dao.TrainingResultDAO
+}
+
+func NewUltralyticsIngestService() *UltralyticsIngestService {
+	return &UltralyticsIngestService{
+		trainingDAO: dao.NewTrainingResultDAO(),
+	}
+}
+
+// Ingest 导入 results.csv（逐 epoch 指标 + 最终指标合并进 metric_detail）与 args.yaml（超参数），两者至少传一个。
+// 文件即训练产物本身，因此不受终态记录不可改 metric_detail 的限制，可在训练结束后补录。
+func (s *UltralyticsIngestService) Ingest(ctx context.Context, id uint, resultsFile, argsFile io.Reader) (UltralyticsIngestResult, error) {
+	if resultsFile == nil && argsFile == nil {
+		return UltralyticsIngestResult{}, fmt.Errorf("%w: results or args file is required", ErrInvalidUltralyticsFile)
+	}
+
+	var results UltralyticsResults
+	if resultsFile != nil {
+		parsed, err := ParseUltralyticsResults(resultsFile)
+		if err != nil {
+			return UltralyticsIngestResult{}, err
+		}
+		results = parsed
+	}
+	var hyperparameters map[string]interface{}
+	if argsFile != nil {
+		parsed, err := ParseUltralyticsArgs(argsFile)
+		if err != nil {
+			return UltralyticsIngestResult{}, err
+		}
+		hyperparameters = parsed
+	}
+
+	ingest := UltralyticsIngestResult{TrainingResultID: id}
+	writes := dao.TrainingResultRelatedWrites{}
+	if hyperparameters != nil {
+		raw, err := json.Marshal(hyperparameters)
+		if err != nil {
+			return UltralyticsIngestResult{}, fmt.Errorf("%w: args: %v", ErrInvalidUltralyticsFile, err)
+		}
+		writes.Hyperparameters = &entity2.TrainingHyperparameters{
+			TrainingResultID: id,
+			Source:           entity2.HyperparameterSourceUltralyticsArgs,
+			Hyperparameters:  raw,
+		}
+		ingest.Hyperparameters = len(hyperparameters)
+	}
+
+	var build func(current *entity2.ModelTrainingResult) (map[string]interface{}, error)
+	if len(results.Rows) > 0 {
+		writes.Metrics = buildUltralyticsMetrics(id, results)
+		ingest.Epochs = len(results.Rows)
+		ingest.MetricPoints = len(writes.Metrics)
+
+		summary := summarizeUltralyticsResults(results)
+		build = func(current *entity2.ModelTrainingResult) (map[string]interface{}, error) {
+			detail, err := mergeMetricDetail(current.MetricDetail, summary)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"metric_detail": detail}, nil
+		}
+	}
+
+	// 超参数、过程指标与 metric_detail 在同一事务内写入，任一步失败都不会留下部分导入的数据。
+	updated, err := s.trainingDAO.UpdateWithRelated(ctx, id, build, writes)
+	if err != nil {
+		return UltralyticsIngestResult{}, err
+	}
+	ingest.Result = updated
+	return ingest, nil
+}
+
+// readUltralyticsFile 读取至多 MaxUltralyticsFileSize 字节；超出时返回 ErrUltralyticsFileTooLarge，而不是截断后继续解析。
+func readUltralyticsFile(r io.Reader, name string) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, MaxUltralyticsFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: read %s: %v", ErrInvalidUltralyticsFile, name, err)
+	}
+	if len(content) > MaxUltralyticsFileSize {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrUltralyticsFileTooLarge, name, MaxUltralyticsFileSize)
+	}
+	return content, nil
+}
+
+// ParseUltralyticsResults 解析 results.csv；列名两侧的空格对齐会被去掉，空值与 nan 单元格跳过。
+func ParseUltralyticsResults(r io.Reader) (UltralyticsResults, error) {
+	content, err := readUltralyticsFile(r, "results.csv")
+	if err != nil {
+		return UltralyticsResults{}, err
+	}
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return UltralyticsResults{}, fmt.Errorf("%w: results.csv header: %v", ErrInvalidUltralyticsFile, err)
+	}
+	epochIndex := -1
+	columns := make([]string, len(header))
+	for i, name := range header {
+		columns[i] = strings.TrimSpace(name)
+		if columns[i] == "epoch" {
+			epochIndex = i
+		}
+	}
+	if epochIndex < 0 {
+		return UltralyticsResults{}, fmt.Errorf("%w: results.csv has no epoch column", ErrInvalidUltralyticsFile)
+	}
+
+	results := UltralyticsResults{Columns: make([]string, 0, len(columns)-1)}
+	for i, name := range columns {
+		if i != epochIndex && name != "" {
+			results.Columns = append(results.Columns, name)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return UltralyticsResults{}, fmt.Errorf("%w: results.csv line %d: %v", ErrInvalidUltralyticsFile, line, err)
+		}
+		if len(record) != len(columns) {
+			return UltralyticsResults{}, fmt.Errorf("%w: results.csv line %d has %d fields, expected %d", ErrInvalidUltralyticsFile, line, len(record), len(columns))
+		}
+
+		epoch, err := strconv.ParseFloat(strings.TrimSpace(record[epochIndex]), 64)
+		if err != nil || epoch < 0 || epoch != math.Trunc(epoch) {
+			return UltralyticsResults{}, fmt.Errorf("%w: results.csv line %d has invalid epoch %q", ErrInvalidUltralyticsFile, line, record[epochIndex])
+		}
+		row := UltralyticsEpochRow{Epoch: int(epoch), Values: make(map[string]float64, len(columns)-1)}
+		for i, cell := range record {
+			if i == epochIndex || columns[i] == "" {
+				continue
+			}
+			value, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+			if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			row.Values[columns[i]] = value
+		}
+		results.Rows = append(results.Rows, row)
+	}
+
+	if len(results.Rows) == 0 {
+		return UltralyticsResults{}, fmt.Errorf("%w: results.csv has no epochs", ErrInvalidUltralyticsFile)
+	}
+	return results, nil
+}
+
+// ParseUltralyticsArgs 解析 args.yaml，顶层必须是映射。
+func ParseUltralyticsArgs(r io.Reader) (map[string]interface{}, error) {
+	content, err := readUltralyticsFile(r, "args.yaml")
+	if err != nil {
+		return nil, err
+	}
+	var args map[string]interface{}
+	if err := yaml.Unmarshal(content, &args); err != nil {
+		return nil, fmt.Errorf("%w: args.yaml: %v", ErrInvalidUltralyticsFile, err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: args.yaml is empty", ErrInvalidUltralyticsFile)
+	}
+	return args, nil
+}
+
+// buildUltralyticsMetrics 将每一列展开为指标点，step 与 epoch 均取 results.csv 的 epoch 列。
+func buildUltralyticsMetrics(id uint, results UltralyticsResults) []entity2.TrainingMetric {
+	metrics := make([]entity2.TrainingMetric, 0, len(results.Rows)*len(results.Columns))
+	for _, row := range results.Rows {
+		for _, name := range results.Columns {
+			value, ok := row.Values[name]
+			if !ok || !metricNamePattern.MatchString(name) {
+				continue
+			}
+			epoch := row.Epoch
+			metrics = append(metrics, entity2.TrainingMetric{
+				TrainingResultID: id,
+				Name:             name,
+				Step:             int64(row.Epoch),
+				Epoch:            &epoch,
+				Value:            value,
+			})
+		}
+	}
+	return metrics
+}
+
+// summarizeUltralyticsResults 取 fitness（0.1*mAP50 + 0.9*mAP50-95，与 Ultralytics 选 best.pt 的规则一致）最高的一行作为最终指标；
+// 缺少 mAP 列时取最后一行。
+func summarizeUltralyticsResults(results UltralyticsResults) map[string]interface{} {
+	best := results.Rows[len(results.Rows)-1]
+	bestFitness := math.Inf(-1)
+	for _, row := range results.Rows {
+		map50, ok50 := ultralyticsValue(row, "mAP50")
+		map5095, ok5095 := ultralyticsValue(row, "mAP50-95")
+		if !ok50 || !ok5095 {
+			continue
+		}
+		if fitness := 0.1*map50 + 0.9*map5095; fitness > bestFitness {
+			bestFitness = fitness
+			best = row
+		}
+	}
+
+	summary := map[string]interface{}{
+		"best_epoch": best.Epoch,
+		"epochs":     len(results.Rows),
+	}
+	for _, metric := range ultralyticsFinalMetrics {
+		if value, ok := ultralyticsValue(best, metric.Key); ok {
+			summary[metric.Key] = value
+		}
+	}
+	return summary
+}
+
+func ultralyticsValue(row UltralyticsEpochRow, key string) (float64, bool) {
+	for _, metric := range ultralyticsFinalMetrics {
+		if metric.Key != key {
+			continue
+		}
+		for _, column := range metric.Columns {
+			if value, ok := row.Values[column]; ok {
+				return value, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// mergeMetricDetail 将 summary 合并进已有的 metric_detail 对象，同名键以 summary 为准；原值不是对象时直接替换。
+func mergeMetricDetail(current json.RawMessage, summary map[string]interface{}) (json.RawMessage, error) {
+	detail := make(map[string]interface{}, len(summary))
+	if len(current) > 0 {
+		var existing map[string]interface{}
+		if err := json.Unmarshal(current, &existing); err == nil {
+			for key, value := range existing {
+				detail[key] = value
+			}
+		}
+	}
+	for key, value := range summary {
+		detail[key] = value
+	}
+	return json.Marshal(detail)
+}
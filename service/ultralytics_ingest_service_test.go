@@ -0,0 +1,90 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleUltralyticsResults = `                  epoch,      train/box_loss,      train/cls_loss,   metrics/precision(B),      metrics/recall(B),       metrics/mAP50(B),    metrics/mAP50-95(B),                 lr/pg0
+                      1,              1.5,              2.1,                 0.50,                 0.40,                 0.45,                 0.20,                  0.07
+                      2,              1.2,              1.6,                 0.70,                 0.60,                 0.66,                 0.41,                   nan
+                      3,              1.1,              1.4,                 0.68,                 0.62,                 0.65,                 0.39,                  0.01
+`
+
+func TestParseUltralyticsResults(t *testing.T) {
+	results, err := ParseUltralyticsResults(strings.NewReader(sampleUltralyticsResults))
+	require.NoError(t, err)
+	assert.NotContains(t, results.Columns, "epoch")
+	assert.Contains(t, results.Columns, "metrics/mAP50-95(B)")
+	require.Len(t, results.Rows, 3)
+	assert.Equal(t, 2, results.Rows[1].Epoch)
+	assert.Equal(t, 1.2, results.Rows[1].Values["train/box_loss"])
+	assert.NotContains(t, results.Rows[1].Values, "lr/pg0", "nan cells are skipped")
+
+	metrics := buildUltralyticsMetrics(9, results)
+	assert.Len(t, metrics, 3*7-1)
+	assert.Equal(t, int64(1), metrics[0].Step)
+	assert.Equal(t, 1, *metrics[0].Epoch)
+
+	for _, content := range []string{"", "loss,value\n1,2\n", "epoch,loss\n", "epoch,loss\nx,1\n", "epoch,loss\n1,2,3\n"} {
+		_, err := ParseUltralyticsResults(strings.NewReader(content))
+		assert.True(t, errors.Is(err, ErrInvalidUltralyticsFile), "content=%q", content)
+	}
+}
+
+func TestSummarizeUltralyticsResultsPicksBestFitness(t *testing.T) {
+	results, err := ParseUltralyticsResults(strings.NewReader(sampleUltralyticsResults))
+	require.NoError(t, err)
+
+	summary := summarizeUltralyticsResults(results)
+	assert.Equal(t, 2, summary["best_epoch"])
+	assert.Equal(t, 3, summary["epochs"])
+	assert.Equal(t, 0.66, summary["mAP50"])
+	assert.Equal(t, 0.41, summary["mAP50-95"])
+	assert.Equal(t, 0.70, summary["precision"])
+	assert.Equal(t, 0.60, summary["recall"])
+}
+
+func TestParseUltralyticsArgs(t *testing.T) {
+	args, err := ParseUltralyticsArgs(strings.NewReader("task: detect\nepochs: 100\nlr0: 0.01\nimgsz: 640\naugment: false\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "detect", args["task"])
+	assert.Equal(t, 100, args["epochs"])
+	raw, err := json.Marshal(args)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"task":"detect","epochs":100,"lr0":0.01,"imgsz":640,"augment":false}`, string(raw))
+
+	for _, content := range []string{"", "- a\n- b\n", "a: [1"} {
+		_, err := ParseUltralyticsArgs(strings.NewReader(content))
+		assert.True(t, errors.Is(err, ErrInvalidUltralyticsFile), "content=%q", content)
+	}
+}
+
+func TestUltralyticsFilesOverLimitAreRejected(t *testing.T) {
+	header := "epoch,loss\n1,2\n"
+	oversized := header + strings.Repeat("#", MaxUltralyticsFileSize-len(header)+1)
+	_, err := ParseUltralyticsResults(strings.NewReader(oversized))
+	assert.ErrorIs(t, err, ErrUltralyticsFileTooLarge)
+
+	_, err = ParseUltralyticsArgs(strings.NewReader("task: detect\n" + strings.Repeat(" ", MaxUltralyticsFileSize)))
+	assert.ErrorIs(t, err, ErrUltralyticsFileTooLarge)
+
+	content, err := readUltralyticsFile(strings.NewReader(strings.Repeat("a", MaxUltralyticsFileSize)), "args.yaml")
+	require.NoError(t, err)
+	assert.Len(t, content, MaxUltralyticsFileSize)
+}
+
+func TestMergeMetricDetail(t *testing.T) {
+	merged, err := mergeMetricDetail(json.RawMessage(`{"mAP50":0.1,"note":"manual"}`), map[string]interface{}{"mAP50": 0.9})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"mAP50":0.9,"note":"manual"}`, string(merged))
+
+	merged, err = mergeMetricDetail(json.RawMessage(`null`), map[string]interface{}{"epochs": 3})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"epochs":3}`, string(merged))
+}
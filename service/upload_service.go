@@ -40,10 +40,17 @@ type UploadResult struct {
 	UploadToBaidu bool          `json:"upload_to_baidu"`
 	BaiduUploaded bool          `json:"baidu_uploaded"`
 	BaiduPath     string        `json:"baidu_path,omitempty"`
+	// BaiduReceipt 网盘上传的秒传/校验结果，仅在 BaiduUploaded 时非空。
+	BaiduReceipt *BaiduUploadReceipt `json:"baidu_receipt,omitempty"`
 }
 
 type BaiduUploader interface {
-	Upload(localPath, remoteDir string) (string, error)
+	Upload(localPath, remoteDir string) (BaiduUploadReceipt, error)
+}
+
+// BaiduRemover 可选能力：删除网盘文件，供保存后续步骤失败时回滚。
+type BaiduRemover interface {
+	Remove(remotePath string) error
 }
 
 type UploadService struct {
@@ -66,10 +73,29 @@ func (s *UploadService) SaveDatasetFile(file *multipart.FileHeader, artifactName
 	return s.save(file, uploadCategoryDatasets, artifactName, storageTarget, storageServer, uploadToBaidu)
 }
 
+// SaveModelFileFromPath 按与上传相同的规则保存本地已有的权重文件（如训练产出、从核心服务器拉回的临时文件）。
+func (s *UploadService) SaveModelFileFromPath(localPath, artifactName, storageTarget, storageServer string, uploadToBaidu bool) (UploadResult, error) {
+	localPath = strings.TrimSpace(localPath)
+	if localPath == "" {
+		return UploadResult{}, ErrInvalidUploadFile
+	}
+	open := func() (io.ReadCloser, error) {
+		return os.Open(localPath)
+	}
+	return s.saveFrom(filepath.Base(localPath), open, uploadCategoryModels, artifactName, storageTarget, storageServer, uploadToBaidu)
+}
+
 func (s *UploadService) save(file *multipart.FileHeader, category, artifactName, storageTarget, storageServer string, uploadToBaidu bool) (UploadResult, error) {
 	if file == nil || strings.TrimSpace(file.Filename) == "" {
 		return UploadResult{}, ErrInvalidUploadFile
 	}
+	open := func() (io.ReadCloser, error) {
+		return file.Open()
+	}
+	return s.saveFrom(file.Filename, open, category, artifactName, storageTarget, storageServer, uploadToBaidu)
+}
+
+func (s *UploadService) saveFrom(originalFilename string, open func() (io.ReadCloser, error), category, artifactName, storageTarget, storageServer string, uploadToBaidu bool) (UploadResult, error) {
 	if s.PathService == nil {
 		return UploadResult{}, ErrArtifactPathServiceNil
 	}
@@ -80,7 +106,7 @@ func (s *UploadService) save(file *multipart.FileHeader, category, artifactName,
 		return UploadResult{}, err
 	}
 
-	storedFileName, err := s.PathService.GenerateStoredFileName(artifactName, file.Filename)
+	storedFileName, err := s.PathService.GenerateStoredFileName(artifactName, originalFilename)
 	if err != nil {
 		return UploadResult{}, err
 	}
@@ -102,7 +128,7 @@ func (s *UploadService) save(file *multipart.FileHeader, category, artifactName,
 		return UploadResult{}, fmt.Errorf("create upload dir failed: %w", err)
 	}
 
-	src, err := file.Open()
+	src, err := open()
 	if err != nil {
 		return UploadResult{}, fmt.Errorf("open upload file failed: %w", err)
 	}
@@ -142,16 +168,37 @@ func (s *UploadService) save(file *multipart.FileHeader, category, artifactName,
 		return UploadResult{}, err
 	}
 
-	remotePath, err := s.BaiduUploader.Upload(resolvedPath, baiduRemoteDir)
+	receipt, err := s.BaiduUploader.Upload(resolvedPath, baiduRemoteDir)
 	if err != nil {
 		return UploadResult{}, err
 	}
 	result.BaiduUploaded = true
-	result.BaiduPath = remotePath
+	result.BaiduPath = receipt.RemotePath
+	result.BaiduReceipt = &receipt
 
 	return result, nil
 }
 
+// RemoveSaved 删除一次保存产生的全部副本（写入的本地文件与已上传的网盘文件），用于后续步骤失败时回滚。
+// 文件已不存在时视为成功；各副本分别尝试，返回合并后的错误。
+func (s *UploadService) RemoveSaved(result UploadResult) error {
+	var errs []error
+	if localPath := strings.TrimSpace(result.ResolvedPath); localPath != "" {
+		if err := os.Remove(filepath.FromSlash(localPath)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("remove saved file failed: %w", err))
+		}
+	}
+	if result.BaiduUploaded && strings.TrimSpace(result.BaiduPath) != "" {
+		remover, ok := s.BaiduUploader.(BaiduRemover)
+		if !ok {
+			errs = append(errs, fmt.Errorf("remove baidu copy %q failed: uploader cannot delete files", result.BaiduPath))
+		} else if err := remover.Remove(result.BaiduPath); err != nil {
+			errs = append(errs, fmt.Errorf("remove baidu copy %q failed: %w", result.BaiduPath, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func sanitizeFileName(name string) string {
 	var b strings.Builder
 	for _, r := range strings.TrimSpace(name) {
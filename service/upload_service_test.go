@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	entity2 "lucky_project/entity"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,10 +20,13 @@ type fakeBaiduUploader struct {
 	remoteDir string
 }
 
-func (f *fakeBaiduUploader) Upload(localPath, remoteDir string) (string, error) {
+func (f *fakeBaiduUploader) Upload(localPath, remoteDir string) (BaiduUploadReceipt, error) {
 	f.localPath = localPath
 	f.remoteDir = remoteDir
-	return filepath.ToSlash(filepath.Join(remoteDir, filepath.Base(localPath))), nil
+	return BaiduUploadReceipt{
+		RemotePath: filepath.ToSlash(filepath.Join(remoteDir, filepath.Base(localPath))),
+		Status:     entity2.ReplicaStatusVerified,
+	}, nil
 }
 
 func TestUploadServiceSaveModelFileBaiduFlow(t *testing.T) {
@@ -57,6 +62,9 @@ func TestUploadServiceSaveModelFileBaiduFlow(t *testing.T) {
 	assert.Equal(t, filepath.ToSlash(filepath.Join(pathService.BaiduWeightsRoot, result.FileName)), result.BaiduPath)
 	assert.Equal(t, filepath.ToSlash(filepath.Join(pathService.BackendWeightsRoot, result.FileName)), result.ResolvedPath)
 	assert.Equal(t, result.ResolvedPath, filepath.ToSlash(uploader.localPath))
+	if assert.NotNil(t, result.BaiduReceipt) {
+		assert.Equal(t, entity2.ReplicaStatusVerified, result.BaiduReceipt.Status)
+	}
 
 	_, err = os.Stat(result.ResolvedPath)
 	assert.NoError(t, err)